@@ -0,0 +1,57 @@
+// Command reindex rebuilds the events search index from the database and
+// swaps it in under the configured alias, like POST /admin/search/reindex.
+// Events changed by the running service meanwhile are picked up once the
+// alias moves.
+//
+//	go run ./cmd/reindex -config configs/app.yaml
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/event"
+	searchidx "ticket-booking/internal/search"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/db"
+	"ticket-booking/pkg/search"
+)
+
+func main() {
+	configPath := flag.String("config", "configs/app.yaml", "config file; APP_ENV selects an app-<env>.yaml next to it when present")
+	flag.Parse()
+
+	loader, err := config.NewLoader(*configPath)
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		log.Fatalf("config: %v", err)
+	}
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("logger: %v", err)
+	}
+	defer logger.Sync()
+
+	client, err := search.New(cfg.Elasticsearch.URL)
+	if err != nil {
+		log.Fatalf("elasticsearch: %v", err)
+	}
+	events := event.NewEventRepository(db.MustOpen(cfg.Postgres.DSN))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	index, n, err := searchidx.NewIndexer(client, cfg.Elasticsearch.Index, logger).Rebuild(ctx, events)
+	if err != nil {
+		logger.Fatal("Failed to rebuild search index", zap.Error(err))
+	}
+	fmt.Printf("indexed %d events into %s, now served as %q\n", n, index, cfg.Elasticsearch.Index)
+}
@@ -30,6 +30,9 @@ INSERT INTO users (id, email, password_hash, role, full_name, created_at, update
 (uuid_generate_v4(), 'ryan.anderson@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'USER', 'Ryan Anderson', NOW() - INTERVAL '2 days', NOW()),
 (uuid_generate_v4(), 'olivia.taylor@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'USER', 'Olivia Taylor', NOW() - INTERVAL '1 day', NOW());
 
+-- Seeded accounts can log in without confirming their email
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
 -- ===========================================
 -- EVENTS DATA
 -- ===========================================
@@ -17,6 +17,21 @@ else
     echo "WARNING: internal/event/repository.go not found, skipping..."
 fi
 
+echo "Generating venue repository mock..."
+mockgen -source=internal/venue/repository.go -destination=internal/mocks/mock_venue_repository.go -package=mocks
+
+echo "Generating organizer repository mock..."
+mockgen -source=internal/organizer/repository.go -destination=internal/mocks/mock_organizer_repository.go -package=mocks
+
+echo "Generating series repository mock..."
+mockgen -source=internal/series/repository.go -destination=internal/mocks/mock_series_repository.go -package=mocks
+
+echo "Generating role repository mock..."
+mockgen -source=internal/rbac/repository.go -destination=internal/mocks/mock_role_repository.go -package=mocks
+
+echo "Generating audit repository mock..."
+mockgen -source=internal/audit/repository.go -destination=internal/mocks/mock_audit_repository.go -package=mocks
+
 echo "Generating rabbit MQ mock..."
 if [ -f "pkg/mq/rabbit.go" ]; then
     mockgen -source=pkg/mq/rabbit.go -destination=internal/mocks/mock_rabbit.go -package=mocks
@@ -37,6 +52,9 @@ mockgen -source=internal/database/database.go -destination=internal/mocks/mock_d
 echo "Generating event reserver mock..."
 mockgen -destination=internal/mocks/event_reserver.go -package=mocks ticket-booking/internal/booking EventReserver
 
+echo "Generating event service mock..."
+mockgen -destination=internal/mocks/event_service.go -package=mocks -mock_names ServiceInterface=MockEventService ticket-booking/internal/event ServiceInterface
+
 echo "✅ Mocks regenerated successfully!"
 echo ""
 echo "🧪 Running tests to verify..."
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"ticket-booking/pkg/config"
+)
+
+// ChainKey derives the key of the hash chain from security.audit_secret,
+// falling back to the refresh token secret when none is set. The key never
+// reaches the database, so write access to audit_log is not enough to
+// rewrite entries and reseal the chain after them.
+func ChainKey(cfg *config.Security) []byte {
+	secret := cfg.AuditSecret
+	if secret == "" {
+		secret = cfg.JWTRefreshSecret
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("audit-chain"))
+	return mac.Sum(nil)
+}
+
+// Seal places e after prev, nil when e is the first entry, and sets its hash
+// under key
+func (e *Entry) Seal(prev *Entry, key []byte) {
+	e.Seq, e.PrevHash = 1, ""
+	if prev != nil {
+		e.Seq, e.PrevHash = prev.Seq+1, prev.Hash
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	// Postgres keeps microseconds; hash exactly what will be read back
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	e.Hash = e.ComputeHash(key)
+}
+
+// ComputeHash returns the hex HMAC-SHA256 under key over e's content and
+// PrevHash. The ID is left out: it is assigned by the database after the hash
+// is taken.
+func (e *Entry) ComputeHash(key []byte) string {
+	detail, changes := e.Detail, e.Changes
+	// Empty and nil are stored alike, as NULL
+	if len(detail) == 0 {
+		detail = nil
+	}
+	if len(changes) == 0 {
+		changes = nil
+	}
+	// Field order is fixed by the struct and map keys are sorted by encoding/json
+	payload, _ := json.Marshal(struct {
+		Seq        int64          `json:"seq"`
+		PrevHash   string         `json:"prev_hash"`
+		ActorID    *string        `json:"actor_id"`
+		ActorRole  string         `json:"actor_role"`
+		Action     string         `json:"action"`
+		TargetType string         `json:"target_type"`
+		TargetID   string         `json:"target_id"`
+		RequestID  string         `json:"request_id"`
+		IP         string         `json:"ip"`
+		Detail     map[string]any `json:"detail"`
+		Changes    Changes        `json:"changes"`
+		CreatedAt  string         `json:"created_at"`
+	}{
+		e.Seq, e.PrevHash, e.ActorID, e.ActorRole, e.Action, e.TargetType, e.TargetID,
+		e.RequestID, e.IP, detail, changes, e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Diff lists the top-level JSON fields that differ between before and after,
+// usually two values of one type. A nil before records a creation.
+func Diff(before, after any) Changes {
+	b, a := jsonFields(before), jsonFields(after)
+	out := Changes{}
+	for k, av := range a {
+		if bv, ok := b[k]; !ok || !reflect.DeepEqual(bv, av) {
+			out[k] = Change{From: b[k], To: av}
+		}
+	}
+	for k, bv := range b {
+		if _, ok := a[k]; !ok {
+			out[k] = Change{From: bv}
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// jsonFields decodes v's JSON form into a map, so values compare and store
+// the way they read back from the database
+func jsonFields(v any) map[string]any {
+	var m map[string]any
+	if v == nil {
+		return m
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
@@ -0,0 +1,6 @@
+package audit
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// filter reads the query parameters shared by List and Export
+func filter(c *gin.Context) (Filter, error) {
+	f := Filter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+	}
+	if f.ActorID != "" {
+		if _, err := uuid.Parse(f.ActorID); err != nil {
+			return f, fmt.Errorf("invalid actor_id")
+		}
+	}
+	for key, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		if v := c.Query(key); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return f, fmt.Errorf("invalid %s, expected RFC 3339", key)
+			}
+			*dst = &t
+		}
+	}
+	return f, nil
+}
+
+// ===== List =====
+// @Summary List audit entries
+// @Description Audit entries, newest first (requires reports:read)
+// @Tags admin-audit
+// @Produce json
+// @Param actor_id query string false "Only entries by this user"
+// @Param action query string false "Only this action, e.g. booking.refund"
+// @Param target_type query string false "Only this target type, e.g. booking"
+// @Param target_id query string false "Only this target"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param limit query int false "Max items to return (default 50, max 200)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} Entry
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/audit [get]
+func (h *Handler) List(c *gin.Context) {
+	f, err := filter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	f.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	f.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if f.Limit <= 0 || f.Limit > 200 {
+		f.Limit = 50
+	}
+	if f.Offset < 0 {
+		f.Offset = 0
+	}
+	entries, err := h.svc.List(c, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	if entries == nil {
+		entries = []*Entry{}
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// exportColumns are the CSV header; hashes come last so the file can be checked offline
+var exportColumns = []string{
+	"seq", "created_at", "actor_id", "actor_role", "action", "target_type", "target_id",
+	"request_id", "ip", "detail", "changes", "prev_hash", "hash",
+}
+
+// ===== Export =====
+// @Summary Export audit entries
+// @Description Download every matching entry, oldest first, as CSV or JSON lines (requires reports:read)
+// @Tags admin-audit
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "csv (default) or jsonl"
+// @Param actor_id query string false "Only entries by this user"
+// @Param action query string false "Only this action"
+// @Param target_type query string false "Only this target type"
+// @Param target_id query string false "Only this target"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/audit/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	f, err := filter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+	name := "audit-" + time.Now().UTC().Format("20060102T150405Z")
+
+	var write func(*Entry) error
+	var flush func() error
+	switch format {
+	case "csv":
+		c.Header("Content-Type", "text/csv")
+		w := csv.NewWriter(c.Writer)
+		write = func(e *Entry) error {
+			actor := ""
+			if e.ActorID != nil {
+				actor = *e.ActorID
+			}
+			return w.Write([]string{
+				strconv.FormatInt(e.Seq, 10), e.CreatedAt.UTC().Format(time.RFC3339Nano), actor, e.ActorRole,
+				e.Action, e.TargetType, e.TargetID, e.RequestID, e.IP,
+				jsonCell(e.Detail), jsonCell(e.Changes), e.PrevHash, e.Hash,
+			})
+		}
+		flush = func() error { w.Flush(); return w.Error() }
+		_ = w.Write(exportColumns)
+	case "jsonl":
+		c.Header("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(c.Writer)
+		write = func(e *Entry) error { return enc.Encode(e) }
+		flush = func() error { return nil }
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "format must be csv or jsonl"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Status(http.StatusOK)
+
+	// Headers are sent once rows stream, so a failure part way can only be logged
+	if err := h.svc.Export(c, f, write); err != nil {
+		h.logger.Error("Audit export aborted", zap.Error(err))
+	}
+	if err := flush(); err != nil {
+		h.logger.Error("Audit export flush failed", zap.Error(err))
+	}
+}
+
+// jsonCell renders a JSON column for CSV, empty when there is nothing to show
+func jsonCell[T ~map[string]V, V any](m T) string {
+	if len(m) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+// ===== Verify =====
+// @Summary Verify audit chain
+// @Description Recompute the hash chain and report the first entry that was altered or removed (requires reports:read)
+// @Tags admin-audit
+// @Produce json
+// @Success 200 {object} VerifyResult
+// @Security BearerAuth
+// @Router /admin/audit/verify [get]
+func (h *Handler) Verify(c *gin.Context) {
+	res, err := h.svc.Verify(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
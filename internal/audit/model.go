@@ -0,0 +1,54 @@
+// Package audit records security-relevant and privileged actions.
+package audit
+
+import "time"
+
+// Entry is one append-only audit record. Entries form a hash chain in Seq
+// order: Hash covers the entry's content and PrevHash, the Hash of the entry
+// before it, so editing or removing a row is detectable (see Service.Verify).
+type Entry struct {
+	ID         string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Seq        int64          `gorm:"not null;uniqueIndex" json:"seq"`                     // Position in the hash chain, from 1
+	ActorID    *string        `gorm:"type:uuid" json:"actor_id,omitempty"`                 // User who acted; nil for the system or anonymous callers
+	ActorRole  string         `gorm:"type:text" json:"actor_role,omitempty"`               // Role of the actor at the time
+	Action     string         `gorm:"type:text;not null" json:"action"`                    // e.g. auth.lockout
+	TargetType string         `gorm:"type:text;not null" json:"target_type"`               // e.g. account, ip, user
+	TargetID   string         `gorm:"type:text;not null" json:"target_id"`                 // Identifier within TargetType
+	RequestID  string         `gorm:"type:text" json:"request_id,omitempty"`               // X-Request-ID of the triggering request
+	IP         string         `gorm:"type:text" json:"ip,omitempty"`                       // Client IP of the triggering request
+	Detail     map[string]any `gorm:"type:jsonb;serializer:json" json:"detail,omitempty"`  // Action-specific fields
+	Changes    Changes        `gorm:"type:jsonb;serializer:json" json:"changes,omitempty"` // Changed fields with before and after values
+	CreatedAt  time.Time      `json:"created_at"`
+	PrevHash   string         `gorm:"type:text;not null;default:''" json:"prev_hash"` // Hash of the entry before; empty for the first
+	Hash       string         `gorm:"type:text;not null;default:''" json:"hash"`      // HMAC-SHA256 (hex) over the entry and PrevHash, see ChainKey
+}
+
+// Change is one field's value before and after an action
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Changes maps field names to how they changed
+type Changes map[string]Change
+
+// TableName keeps audit records in their own table
+func (Entry) TableName() string { return "audit_log" }
+
+// Actions
+const (
+	ActionLockout           = "auth.lockout"
+	ActionUnlock            = "auth.unlock"
+	ActionUserRole          = "user.role_change"
+	ActionUserSuspend       = "user.suspend"
+	ActionUserReactivate    = "user.reactivate"
+	ActionUserPasswordReset = "user.password_reset"
+	ActionBookingConfirm    = "booking.confirm"
+	ActionBookingCancel     = "booking.cancel"
+	ActionBookingRefund     = "booking.refund"
+	ActionBookingMove       = "booking.move"
+	ActionEventCreate       = "event.create"
+	ActionEventUpdate       = "event.update"
+	ActionEventDelete       = "event.delete"
+	ActionEventRestore      = "event.restore"
+)
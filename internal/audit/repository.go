@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Filter narrows audit listings and exports. Zero values mean "no constraint".
+type Filter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time // Created at or after
+	To         *time.Time // Created before
+	Limit      int
+	Offset     int
+}
+
+type AuditRepository interface {
+	// Create links e to the latest entry and appends it
+	Create(e *Entry) error
+	// List returns entries matching f, newest first
+	List(f Filter) ([]*Entry, error)
+	// ListAfter returns up to limit entries matching f with Seq above afterSeq, oldest first
+	ListAfter(f Filter, afterSeq int64, limit int) ([]*Entry, error)
+}
+
+// chainLock is the advisory lock key serialising appends, so that each entry
+// links to the one before it
+const chainLock = 0x61756469 // "audi"
+
+type repo struct {
+	db  *gorm.DB
+	key []byte // See ChainKey
+}
+
+// NewAuditRepository seals appended entries with key
+func NewAuditRepository(db *gorm.DB, key []byte) AuditRepository { return &repo{db, key} }
+
+func (r *repo) Create(e *Entry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLock).Error; err != nil {
+			return err
+		}
+		var last []*Entry
+		if err := tx.Order("seq desc").Limit(1).Find(&last).Error; err != nil {
+			return err
+		}
+		var prev *Entry
+		if len(last) == 1 {
+			prev = last[0]
+		}
+		e.Seal(prev, r.key)
+		return tx.Create(e).Error
+	})
+}
+
+func (r *repo) List(f Filter) ([]*Entry, error) {
+	var out []*Entry
+	q := r.filter(f).Order("seq desc")
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.Offset > 0 {
+		q = q.Offset(f.Offset)
+	}
+	return out, q.Find(&out).Error
+}
+
+func (r *repo) ListAfter(f Filter, afterSeq int64, limit int) ([]*Entry, error) {
+	var out []*Entry
+	return out, r.filter(f).Where("seq > ?", afterSeq).Order("seq asc").Limit(limit).Find(&out).Error
+}
+
+func (r *repo) filter(f Filter) *gorm.DB {
+	q := r.db.Model(&Entry{})
+	if f.ActorID != "" {
+		q = q.Where("actor_id = ?", f.ActorID)
+	}
+	if f.Action != "" {
+		q = q.Where("action = ?", f.Action)
+	}
+	if f.TargetType != "" {
+		q = q.Where("target_type = ?", f.TargetType)
+	}
+	if f.TargetID != "" {
+		q = q.Where("target_id = ?", f.TargetID)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	return q
+}
@@ -0,0 +1,9 @@
+package audit
+
+import "github.com/gin-gonic/gin"
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/audit", h.List)
+	r.GET("/audit/export", h.Export)
+	r.GET("/audit/verify", h.Verify)
+}
@@ -0,0 +1,141 @@
+package audit
+
+import (
+	"context"
+	"errors"
+
+	"ticket-booking/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Service writes audit records.
+type Service struct {
+	repo   AuditRepository
+	key    []byte // Checks the hash chain; the repository's key, see ChainKey
+	logger *zap.Logger
+}
+
+// NewService creates a new audit service verifying the chain with key
+func NewService(r AuditRepository, key []byte, logger *zap.Logger) *Service {
+	return &Service{repo: r, key: key, logger: logger}
+}
+
+// Record stores e. When ctx is the request's gin context, the actor, role,
+// request ID and client IP are filled in from it unless already set.
+func (s *Service) Record(ctx context.Context, e *Entry) error {
+	if c, ok := ctx.(*gin.Context); ok {
+		if e.ActorID == nil {
+			if uid := c.GetString(auth.CtxUserID); uid != "" {
+				e.ActorID = &uid
+			}
+		}
+		if e.ActorRole == "" {
+			e.ActorRole = c.GetString(auth.CtxRole)
+		}
+		if e.RequestID == "" {
+			e.RequestID = c.GetString(auth.CtxReqID)
+		}
+		if e.IP == "" {
+			e.IP = c.ClientIP()
+		}
+	}
+	if err := s.repo.Create(e); err != nil {
+		s.logger.Error("Failed to write audit entry",
+			zap.String("action", e.Action), zap.String("target_type", e.TargetType),
+			zap.String("target_id", e.TargetID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// batchSize is how many entries Export and Verify read at a time
+const batchSize = 500
+
+// List returns entries matching f, newest first
+func (s *Service) List(ctx context.Context, f Filter) ([]*Entry, error) {
+	entries, err := s.repo.List(f)
+	if err != nil {
+		s.logger.Error("Failed to list audit entries", zap.Error(err))
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Export calls fn with every entry matching f, oldest first, reading in
+// batches so large logs are streamed rather than loaded whole
+func (s *Service) Export(ctx context.Context, f Filter, fn func(*Entry) error) error {
+	var after int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := s.repo.ListAfter(f, after, batchSize)
+		if err != nil {
+			s.logger.Error("Failed to read audit entries for export", zap.Int64("after_seq", after), zap.Error(err))
+			return err
+		}
+		for _, e := range batch {
+			if err := fn(e); err != nil {
+				return err
+			}
+			after = e.Seq
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
+
+// VerifyResult reports on a walk of the hash chain
+type VerifyResult struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`             // Entries whose links and hash were checked
+	Unsealed int    `json:"unsealed"`            // Entries from before hash chaining, not covered
+	LastSeq  int64  `json:"last_seq"`            // Head of the chain; keep it elsewhere to detect truncation
+	LastHash string `json:"last_hash,omitempty"` // Hash at LastSeq
+	BrokenAt *int64 `json:"broken_at,omitempty"` // Seq of the first entry that fails
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Verify walks the whole chain, recomputing every hash and link, and stops at
+// the first entry that does not match. Removing the newest entries leaves a
+// valid shorter chain; compare LastSeq and LastHash with a copy kept elsewhere.
+func (s *Service) Verify(ctx context.Context) (*VerifyResult, error) {
+	res := &VerifyResult{Valid: true}
+	var prev *Entry
+	err := s.Export(ctx, Filter{}, func(e *Entry) error {
+		broken := func(reason string) error {
+			res.Valid, res.BrokenAt, res.Reason = false, &e.Seq, reason
+			return errStop
+		}
+		switch {
+		case prev == nil && e.Seq != 1, prev != nil && e.Seq != prev.Seq+1:
+			return broken("gap in sequence")
+		case e.Hash == "" && (prev == nil || prev.Hash == ""):
+			// Written before chaining started
+			res.Unsealed++
+		case e.Hash == "":
+			return broken("entry is not sealed")
+		case prev != nil && e.PrevHash != prev.Hash, prev == nil && e.PrevHash != "":
+			return broken("link to previous entry does not match")
+		case e.Hash != e.ComputeHash(s.key):
+			return broken("content does not match hash")
+		default:
+			res.Checked++
+		}
+		res.LastSeq, res.LastHash, prev = e.Seq, e.Hash, e
+		return nil
+	})
+	if err != nil && !errors.Is(err, errStop) {
+		return nil, err
+	}
+	if !res.Valid {
+		s.logger.Error("Audit chain broken", zap.Int64("seq", *res.BrokenAt), zap.String("reason", res.Reason))
+	}
+	return res, nil
+}
+
+// errStop ends an Export early without reporting a failure
+var errStop = errors.New("stop")
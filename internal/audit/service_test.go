@@ -0,0 +1,224 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/auth"
+	"ticket-booking/pkg/config"
+)
+
+var testKey = audit.ChainKey(&config.Security{JWTRefreshSecret: "0123456789abcdef"})
+
+// memLog is an in-memory AuditRepository. It keeps JSON copies of entries,
+// the way they read back from the database.
+type memLog struct{ entries []*audit.Entry }
+
+func (m *memLog) Create(e *audit.Entry) error {
+	var prev *audit.Entry
+	if n := len(m.entries); n > 0 {
+		prev = m.entries[n-1]
+	}
+	e.Seal(prev, testKey)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var stored audit.Entry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	m.entries = append(m.entries, &stored)
+	return nil
+}
+
+func (m *memLog) match(f audit.Filter, e *audit.Entry) bool {
+	return (f.Action == "" || e.Action == f.Action) && (f.TargetID == "" || e.TargetID == f.TargetID)
+}
+
+func (m *memLog) List(f audit.Filter) ([]*audit.Entry, error) {
+	var out []*audit.Entry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.match(f, m.entries[i]) {
+			out = append(out, m.entries[i])
+		}
+	}
+	return out, nil
+}
+
+func (m *memLog) ListAfter(f audit.Filter, afterSeq int64, limit int) ([]*audit.Entry, error) {
+	var out []*audit.Entry
+	for _, e := range m.entries {
+		if e.Seq > afterSeq && m.match(f, e) && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// record appends n entries for targets b1..bn
+func record(t *testing.T, svc *audit.Service, n int) {
+	t.Helper()
+	actor := "8d0a1f6e-4a7e-4b5e-9c51-2f1d3e4a5b6c"
+	for i := 1; i <= n; i++ {
+		require.NoError(t, svc.Record(context.Background(), &audit.Entry{
+			ActorID: &actor, ActorRole: "ADMIN", Action: audit.ActionBookingRefund,
+			TargetType: "booking", TargetID: "b" + string(rune('0'+i)),
+			Detail:  map[string]any{"reason": "duplicate", "amount_cents": 3000},
+			Changes: audit.Changes{"status": {From: "CONFIRMED", To: "REFUNDED"}},
+		}))
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	log := &memLog{}
+	svc := audit.NewService(log, testKey, zap.NewNop())
+	record(t, svc, 4)
+
+	res, err := svc.Verify(context.Background())
+	require.NoError(t, err)
+	require.True(t, res.Valid, res.Reason)
+	require.Equal(t, 4, res.Checked)
+	require.Equal(t, int64(4), res.LastSeq)
+	require.Equal(t, log.entries[3].Hash, res.LastHash)
+	require.Equal(t, log.entries[0].Hash, log.entries[1].PrevHash)
+
+	// An edited row no longer matches its hash
+	log.entries[1].Changes["status"] = audit.Change{From: "CONFIRMED", To: "CANCELLED"}
+	res, err = svc.Verify(context.Background())
+	require.NoError(t, err)
+	require.False(t, res.Valid)
+	require.Equal(t, int64(2), *res.BrokenAt)
+	require.Contains(t, res.Reason, "hash")
+
+	// Without the key, the edit cannot be resealed
+	other := audit.ChainKey(&config.Security{JWTRefreshSecret: "0123456789abcdef", AuditSecret: "fedcba9876543210"})
+	require.NotEqual(t, testKey, other, "audit_secret takes precedence")
+	log.entries[1].Hash = log.entries[1].ComputeHash(other)
+	res, _ = svc.Verify(context.Background())
+	require.False(t, res.Valid)
+	require.Equal(t, int64(2), *res.BrokenAt)
+
+	// Re-hashing the edit with the key breaks the next link instead
+	log.entries[1].Hash = log.entries[1].ComputeHash(testKey)
+	res, _ = svc.Verify(context.Background())
+	require.False(t, res.Valid)
+	require.Equal(t, int64(3), *res.BrokenAt)
+
+	// A removed row leaves a gap
+	log = &memLog{}
+	svc = audit.NewService(log, testKey, zap.NewNop())
+	record(t, svc, 3)
+	log.entries = append(log.entries[:1], log.entries[2:]...)
+	res, _ = svc.Verify(context.Background())
+	require.False(t, res.Valid)
+	require.Equal(t, int64(3), *res.BrokenAt)
+}
+
+func TestVerify_SkipsEntriesFromBeforeChaining(t *testing.T) {
+	log := &memLog{entries: []*audit.Entry{
+		{Seq: 1, Action: audit.ActionLockout, TargetType: "account", TargetID: "a"},
+		{Seq: 2, Action: audit.ActionUnlock, TargetType: "account", TargetID: "a"},
+	}}
+	svc := audit.NewService(log, testKey, zap.NewNop())
+	record(t, svc, 2)
+	require.Equal(t, int64(3), log.entries[2].Seq)
+	require.Empty(t, log.entries[2].PrevHash)
+
+	res, err := svc.Verify(context.Background())
+	require.NoError(t, err)
+	require.True(t, res.Valid, res.Reason)
+	require.Equal(t, 2, res.Unsealed)
+	require.Equal(t, 2, res.Checked)
+
+	// Once the chain has started every entry must be sealed
+	log.entries[3].Hash = ""
+	res, _ = svc.Verify(context.Background())
+	require.False(t, res.Valid)
+	require.Equal(t, int64(4), *res.BrokenAt)
+}
+
+func TestDiff(t *testing.T) {
+	type ev struct {
+		Name     string  `json:"name"`
+		Capacity int     `json:"capacity"`
+		Venue    *string `json:"venue,omitempty"`
+	}
+	hall := "hall"
+	require.Nil(t, audit.Diff(ev{Name: "a", Capacity: 10}, ev{Name: "a", Capacity: 10}))
+	require.Equal(t, audit.Changes{
+		"capacity": {From: float64(10), To: float64(20)},
+		"venue":    {From: nil, To: "hall"},
+	}, audit.Diff(ev{Name: "a", Capacity: 10}, ev{Name: "a", Capacity: 20, Venue: &hall}))
+	require.Equal(t, audit.Changes{"venue": {From: "hall"}}, audit.Diff(ev{Venue: &hall}, ev{}))
+	require.Len(t, audit.Diff(nil, ev{Name: "a"}), 2, "a creation lists every field")
+}
+
+func TestHandler_ListAndExport(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := &memLog{}
+	svc := audit.NewService(log, testKey, zap.NewNop())
+	r := gin.New()
+	audit.RegisterAdminRoutes(r.Group("/admin"), audit.NewHandler(svc, zap.NewNop()))
+
+	// Actor, request ID and IP come from the request
+	r.POST("/act", func(c *gin.Context) {
+		c.Set(auth.CtxUserID, "8d0a1f6e-4a7e-4b5e-9c51-2f1d3e4a5b6c")
+		c.Set(auth.CtxReqID, "req-1")
+		_ = svc.Record(c, &audit.Entry{Action: audit.ActionEventDelete, TargetType: "event", TargetID: "e1"})
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/act", nil))
+	record(t, svc, 2)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w = get("/admin/audit?action=event.delete")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list []audit.Entry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 1)
+	require.Equal(t, "req-1", list[0].RequestID)
+	require.Equal(t, "192.0.2.1", list[0].IP)
+
+	require.Equal(t, http.StatusBadRequest, get("/admin/audit?actor_id=root").Code)
+	require.Equal(t, http.StatusBadRequest, get("/admin/audit?from=today").Code)
+	require.Equal(t, http.StatusBadRequest, get("/admin/audit/export?format=xml").Code)
+
+	w = get("/admin/audit/export")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Header().Get("Content-Disposition"), ".csv")
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Equal(t, "seq", rows[0][0])
+	require.Equal(t, []string{"1", "2", "3"}, []string{rows[1][0], rows[2][0], rows[3][0]})
+	require.Equal(t, log.entries[2].Hash, rows[3][len(rows[3])-1])
+	require.JSONEq(t, `{"status":{"from":"CONFIRMED","to":"REFUNDED"}}`, rows[3][10])
+
+	w = get("/admin/audit/export?format=jsonl&action=booking.refund")
+	require.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	var first audit.Entry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.Equal(t, first.Hash, first.ComputeHash(testKey), "exported entries can be checked offline with the key")
+
+	w = get("/admin/audit/verify")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"valid":true`)
+}
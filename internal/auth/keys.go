@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// ErrUnknownKey is returned for a token whose kid is not in the key set
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minRSABits is the smallest RSA modulus accepted for signing keys
+const minRSABits = 2048
+
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer // nil for verify-only keys
+	public  crypto.PublicKey
+}
+
+// KeySet holds the asymmetric keys access tokens are signed and verified with.
+// Keys are PEM files in one directory, each named <kid>.pem:
+//   - a private key (PKCS#8, or PKCS#1 for RSA) signs and verifies
+//   - a public key (PKIX) only verifies; keep a retired key this way until
+//     every token it signed has expired
+//
+// RSA keys sign with RS256, Ed25519 keys with EdDSA. New tokens are signed
+// with the configured kid, or the lexicographically last private key when
+// none is set, so naming keys by date (2026-10.pem) rotates by dropping in a
+// new file. Reload re-reads the directory without a restart.
+type KeySet struct {
+	dir        string
+	signingKID string
+
+	mu     sync.RWMutex
+	keys   map[string]*signingKey
+	signer *signingKey
+}
+
+// LoadKeySet reads every key in dir. signingKID pins the key that signs new
+// tokens; empty picks the newest private key.
+func LoadKeySet(dir, signingKID string) (*KeySet, error) {
+	k := &KeySet{dir: dir, signingKID: signingKID}
+	if err := k.Reload(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Reload re-reads the key directory. On error the current keys stay in use.
+func (k *KeySet) Reload() error {
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		return fmt.Errorf("read key dir: %w", err)
+	}
+
+	keys := map[string]*signingKey{}
+	var privateKIDs []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".pem") {
+			continue
+		}
+		kid := strings.TrimSuffix(e.Name(), ".pem")
+		raw, err := os.ReadFile(filepath.Join(k.dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("read key %s: %w", kid, err)
+		}
+		sk, err := parseKey(kid, raw)
+		if err != nil {
+			return fmt.Errorf("parse key %s: %w", kid, err)
+		}
+		keys[kid] = sk
+		if sk.private != nil {
+			privateKIDs = append(privateKIDs, kid)
+		}
+	}
+
+	var signer *signingKey
+	if k.signingKID != "" {
+		signer = keys[k.signingKID]
+		if signer == nil || signer.private == nil {
+			return fmt.Errorf("signing key %q: no private key in %s", k.signingKID, k.dir)
+		}
+	} else {
+		if len(privateKIDs) == 0 {
+			return fmt.Errorf("no private key in %s", k.dir)
+		}
+		sort.Strings(privateKIDs)
+		signer = keys[privateKIDs[len(privateKIDs)-1]]
+	}
+
+	k.mu.Lock()
+	k.keys, k.signer = keys, signer
+	k.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the key set every interval until ctx is done
+func (k *KeySet) Watch(ctx context.Context, interval time.Duration, logger *zap.Logger) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			before := k.SigningKID()
+			if err := k.Reload(); err != nil {
+				logger.Error("Failed to reload JWT keys, keeping current set", zap.String("dir", k.dir), zap.Error(err))
+				continue
+			}
+			if after := k.SigningKID(); after != before {
+				logger.Info("JWT signing key rotated", zap.String("from", before), zap.String("to", after))
+			}
+		}
+	}
+}
+
+// SigningKID returns the kid new tokens are signed with
+func (k *KeySet) SigningKID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.signer.kid
+}
+
+func (k *KeySet) sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	s := k.signer
+	k.mu.RUnlock()
+
+	tok := jwt.NewWithClaims(s.method, claims)
+	tok.Header["kid"] = s.kid
+	return tok.SignedString(s.private)
+}
+
+// keyFunc picks the verification key named by the token's kid header and
+// refuses tokens whose alg does not match that key
+func (k *KeySet) keyFunc(t *jwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	k.mu.RLock()
+	sk := k.keys[kid]
+	k.mu.RUnlock()
+	if sk == nil {
+		return nil, ErrUnknownKey
+	}
+	if t.Method.Alg() != sk.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", t.Method.Alg(), kid)
+	}
+	return sk.public, nil
+}
+
+func parseKey(kid string, raw []byte) (*signingKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sk := &signingKey{kid: kid}
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		sk.private, sk.public = key, &key.PublicKey
+	case ed25519.PrivateKey:
+		sk.private, sk.public = key, key.Public()
+	case *rsa.PublicKey, ed25519.PublicKey:
+		sk.public = key
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (want RSA or Ed25519)", parsed)
+	}
+
+	switch pub := sk.public.(type) {
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key is %d bits, need at least %d", pub.N.BitLen(), minRSABits)
+		}
+		sk.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		sk.method = jwt.SigningMethodEdDSA
+	}
+	return sk, nil
+}
+
+// JWK is a public key in JSON Web Key form (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS is the document served at /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public half of every key, signing and verify-only
+func (k *KeySet) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	out := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+	for _, sk := range k.keys {
+		j := JWK{Kid: sk.kid, Use: "sig", Alg: sk.method.Alg()}
+		switch pub := sk.public.(type) {
+		case *rsa.PublicKey:
+			j.Kty = "RSA"
+			j.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			j.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			j.Kty, j.Crv = "OKP", "Ed25519"
+			j.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		out.Keys = append(out.Keys, j)
+	}
+	sort.Slice(out.Keys, func(i, j int) bool { return out.Keys[i].Kid < out.Keys[j].Kid })
+	return out
+}
+
+// JWKSHandler serves the key set for services verifying our access tokens.
+// Clients should refetch on an unknown kid; the short max-age bounds how long
+// a newly rotated key can be missing from their cache.
+func (k *KeySet) JWKSHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, k.JWKS())
+	}
+}
@@ -0,0 +1,147 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/pkg/config"
+)
+
+func writeKey(t *testing.T, dir, kid string, key any, public bool) {
+	var block *pem.Block
+	if public {
+		der, err := x509.MarshalPKIXPublicKey(key)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PUBLIC KEY", Bytes: der}
+	} else {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		require.NoError(t, err)
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0o600))
+}
+
+func kidOf(t *testing.T, token string) string {
+	tok, _, err := jwt.NewParser().ParseUnverified(token, &auth.AccessClaims{})
+	require.NoError(t, err)
+	return tok.Header["kid"].(string)
+}
+
+func TestKeySet_SignsWithNewestKeyAndRotates(t *testing.T) {
+	dir := t.TempDir()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	writeKey(t, dir, "2026-01", rsaKey, false)
+
+	keys, err := auth.LoadKeySet(dir, "")
+	require.NoError(t, err)
+	old, err := auth.GenerateTokens(testCfg, keys, "u1", "USER", nil, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, "2026-01", kidOf(t, old.AccessToken))
+
+	// Rotate: add an Ed25519 key and retire the RSA key to verify-only
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	writeKey(t, dir, "2026-07", edKey, false)
+	writeKey(t, dir, "2026-01", &rsaKey.PublicKey, true)
+	require.NoError(t, keys.Reload())
+	require.Equal(t, "2026-07", keys.SigningKID())
+
+	fresh, err := auth.GenerateTokens(testCfg, keys, "u1", "USER", nil, nil, "")
+	require.NoError(t, err)
+	claims, err := auth.ValidateAccessToken(testCfg, keys, fresh.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "u1", claims.UserID)
+
+	_, err = auth.ValidateAccessToken(testCfg, keys, old.AccessToken)
+	require.NoError(t, err, "tokens signed by a retired key verify until it is removed")
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "RSA", jwks.Keys[0].Kty)
+	require.Equal(t, "RS256", jwks.Keys[0].Alg)
+	require.Equal(t, "OKP", jwks.Keys[1].Kty)
+	require.Equal(t, "EdDSA", jwks.Keys[1].Alg)
+
+	require.NoError(t, os.Remove(filepath.Join(dir, "2026-01.pem")))
+	require.NoError(t, keys.Reload())
+	_, err = auth.ValidateAccessToken(testCfg, keys, old.AccessToken)
+	require.ErrorIs(t, err, auth.ErrUnknownKey)
+}
+
+func TestKeySet_PinnedKIDAndBadDirKeepCurrentKeys(t *testing.T) {
+	dir := t.TempDir()
+	_, a, _ := ed25519.GenerateKey(rand.Reader)
+	_, b, _ := ed25519.GenerateKey(rand.Reader)
+	writeKey(t, dir, "a", a, false)
+	writeKey(t, dir, "b", b, false)
+
+	keys, err := auth.LoadKeySet(dir, "a")
+	require.NoError(t, err)
+	require.Equal(t, "a", keys.SigningKID())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "c.pem"), []byte("garbage"), 0o600))
+	require.Error(t, keys.Reload())
+	require.Equal(t, "a", keys.SigningKID())
+
+	_, err = auth.LoadKeySet(dir, "missing")
+	require.Error(t, err)
+}
+
+func TestValidateAccessToken_Strict(t *testing.T) {
+	dir := t.TempDir()
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	writeKey(t, dir, "k1", edKey, false)
+	keys, err := auth.LoadKeySet(dir, "")
+	require.NoError(t, err)
+
+	now := time.Now()
+	sign := func(claims auth.AccessClaims) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+		tok.Header["kid"] = "k1"
+		s, err := tok.SignedString(edKey)
+		require.NoError(t, err)
+		return s
+	}
+	valid := jwt.RegisteredClaims{
+		Issuer:    config.DefaultJWTIssuer,
+		Audience:  []string{config.DefaultJWTAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}
+
+	_, err = auth.ValidateAccessToken(testCfg, keys, sign(auth.AccessClaims{UserID: "u1", RegisteredClaims: valid}))
+	require.NoError(t, err)
+
+	wrongIss := valid
+	wrongIss.Issuer = "someone-else"
+	_, err = auth.ValidateAccessToken(testCfg, keys, sign(auth.AccessClaims{RegisteredClaims: wrongIss}))
+	require.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+
+	wrongAud := valid
+	wrongAud.Audience = []string{"other-client"}
+	_, err = auth.ValidateAccessToken(testCfg, keys, sign(auth.AccessClaims{RegisteredClaims: wrongAud}))
+	require.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+
+	noExp := valid
+	noExp.ExpiresAt = nil
+	_, err = auth.ValidateAccessToken(testCfg, keys, sign(auth.AccessClaims{RegisteredClaims: noExp}))
+	require.Error(t, err)
+
+	// An HS256 token signed with the (public) secret must not pass once keys are set
+	hs, err := auth.GenerateTokens(testCfg, nil, "u1", "ADMIN", nil, nil, "")
+	require.NoError(t, err)
+	_, err = auth.ValidateAccessToken(testCfg, keys, hs.AccessToken)
+	require.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+}
@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"sync"
@@ -19,9 +20,11 @@ import (
 
 // Context keys
 const (
-	CtxUserID = "userID"
-	CtxRole   = "role"
-	CtxReqID  = "requestID"
+	CtxUserID      = "userID"
+	CtxRole        = "role"
+	CtxPermissions = "permissions"
+	CtxClaims      = "claims" // *AccessClaims of the current request
+	CtxReqID       = "requestID"
 )
 
 // Middleware holds dependencies for middleware functions
@@ -29,14 +32,18 @@ type Middleware struct {
 	logger       *zap.Logger // Application logger for business logic
 	accessLogger *zap.Logger // Access logger for HTTP requests
 	cfg          *config.Security
+	keys         *KeySet   // Access token keys; nil verifies with the HS256 secret
+	sessions     *Sessions // Revoked access tokens
 }
 
 // NewMiddleware creates a new Middleware instance
-func NewMiddleware(logger *zap.Logger, accessLogger *zap.Logger, cfg *config.Security) *Middleware {
+func NewMiddleware(logger *zap.Logger, accessLogger *zap.Logger, cfg *config.Security, keys *KeySet, sessions *Sessions) *Middleware {
 	return &Middleware{
 		logger:       logger,
 		accessLogger: accessLogger,
 		cfg:          cfg,
+		keys:         keys,
+		sessions:     sessions,
 	}
 }
 
@@ -102,7 +109,8 @@ func (m *Middleware) AccessLog() gin.HandlerFunc {
 	}
 }
 
-// Authn validates access token from Authorization header
+// Authn validates access token from Authorization header and rejects tokens
+// revoked by logout or logout-all, and every token of a suspended account
 func (m *Middleware) Authn() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reqID, _ := c.Get(CtxReqID)
@@ -116,7 +124,7 @@ func (m *Middleware) Authn() gin.HandlerFunc {
 		}
 		token := strings.TrimPrefix(ah, "Bearer ")
 
-		claims, err := ValidateAccessToken(m.cfg, token)
+		claims, err := ValidateAccessToken(m.cfg, m.keys, token)
 		if err != nil {
 			m.logger.Warn("Invalid access token",
 				zap.String("request_id", reqID.(string)),
@@ -124,9 +132,25 @@ func (m *Middleware) Authn() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
 			return
 		}
+		if err := m.sessions.CheckAccess(c, claims); errors.Is(err, ErrAccountSuspended) {
+			m.logger.Warn("Access token of suspended account",
+				zap.String("request_id", reqID.(string)),
+				zap.String("user_id", claims.UserID))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		} else if err != nil {
+			m.logger.Warn("Revoked access token",
+				zap.String("request_id", reqID.(string)),
+				zap.String("user_id", claims.UserID),
+				zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid access token"})
+			return
+		}
 
+		c.Set(CtxClaims, claims)
 		c.Set(CtxUserID, claims.UserID)
 		c.Set(CtxRole, claims.Role)
+		c.Set(CtxPermissions, claims.Permissions)
 		m.logger.Debug("Access token validated",
 			zap.String("request_id", reqID.(string)),
 			zap.String("user_id", claims.UserID),
@@ -135,7 +159,8 @@ func (m *Middleware) Authn() gin.HandlerFunc {
 	}
 }
 
-// Authorize checks if user role is allowed
+// Authorize checks if user role is allowed.
+// Prefer Require: permissions can be regranted without code changes.
 func (m *Middleware) Authorize(roles ...string) gin.HandlerFunc {
 	allow := map[string]struct{}{}
 	for _, r := range roles {
@@ -168,6 +193,54 @@ func (m *Middleware) Authorize(roles ...string) gin.HandlerFunc {
 	}
 }
 
+// Require passes when the caller's token carries any of perms
+func (m *Middleware) Require(perms ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqID, _ := c.Get(CtxReqID)
+		userID := c.GetString(CtxUserID)
+		if !HasPermission(c.GetStringSlice(CtxPermissions), perms...) {
+			m.logger.Warn("Missing permission",
+				zap.String("request_id", reqID.(string)),
+				zap.String("user_id", userID),
+				zap.Strings("required", perms))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		m.logger.Debug("Permission granted",
+			zap.String("request_id", reqID.(string)),
+			zap.String("user_id", userID),
+			zap.Strings("required", perms))
+		c.Next()
+	}
+}
+
+// RequireMFA refuses callers whose role is in security.mfa_required_roles
+// unless their login included a one-time code. Runs after Authn.
+func (m *Middleware) RequireMFA() gin.HandlerFunc {
+	required := map[string]struct{}{}
+	for _, r := range m.cfg.MFARequiredRoles {
+		required[r] = struct{}{}
+	}
+	return func(c *gin.Context) {
+		claims, _ := c.Get(CtxClaims)
+		ac, ok := claims.(*AccessClaims)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing/invalid Authorization"})
+			return
+		}
+		if _, need := required[ac.Role]; need && !ac.HasAMR(AMROTP) {
+			reqID, _ := c.Get(CtxReqID)
+			m.logger.Warn("Two-factor login required",
+				zap.String("request_id", reqID.(string)),
+				zap.String("user_id", ac.UserID),
+				zap.String("role", ac.Role))
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "two-factor authentication required"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // Rate limit: per-user (if authn ran before) else per-IP
 var (
 	limits   = map[string]*rate.Limiter{}
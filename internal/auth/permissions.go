@@ -0,0 +1,46 @@
+package auth
+
+// Permissions gate routes via Middleware.Require. Roles map to permissions in
+// the role_permissions table (see internal/rbac); a role's permissions are
+// resolved when tokens are issued and carried in AccessClaims.Permissions.
+const (
+	PermEventsWrite    = "events:write"    // Create, update, archive and restore any event or series
+	PermEventsOwn      = "events:own"      // Manage only the events the caller owns (/organizer)
+	PermVenuesWrite    = "venues:write"    // Manage venues and organizers
+	PermSearchReindex  = "search:reindex"  // Rebuild the search index
+	PermBookingsRead   = "bookings:read"   // View any booking
+	PermBookingsRefund = "bookings:refund" // Refund bookings
+	PermBookingsManage = "bookings:manage" // Confirm, cancel and move any booking
+	PermReportsRead    = "reports:read"    // Read sales and audit reports
+	PermRolesManage    = "roles:manage"    // Manage roles and their permissions
+	PermUsersManage    = "users:manage"    // Manage user accounts
+	PermTicketsScan    = "tickets:scan"    // Check tickets in at the door and download scanner manifests
+)
+
+// PermissionCatalog lists every permission the API checks, with a description
+// for the admin role API. Roles may only be granted permissions listed here.
+var PermissionCatalog = map[string]string{
+	PermEventsWrite:    "Create, update, archive and restore any event or series",
+	PermEventsOwn:      "Manage only the events the caller owns",
+	PermVenuesWrite:    "Manage venues and organizers",
+	PermSearchReindex:  "Rebuild the search index",
+	PermBookingsRead:   "View any booking",
+	PermBookingsRefund: "Refund bookings",
+	PermBookingsManage: "Confirm, cancel and move any booking",
+	PermReportsRead:    "Read sales and audit reports",
+	PermRolesManage:    "Manage roles and their permissions",
+	PermUsersManage:    "Manage user accounts",
+	PermTicketsScan:    "Check tickets in at the door and download scanner manifests",
+}
+
+// HasPermission reports whether perms contains any of want
+func HasPermission(perms []string, want ...string) bool {
+	for _, p := range perms {
+		for _, w := range want {
+			if p == w {
+				return true
+			}
+		}
+	}
+	return false
+}
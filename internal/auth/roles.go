@@ -1,6 +1,8 @@
 package auth
 
 const (
-	RoleUser  = "USER"
-	RoleAdmin = "ADMIN"
+	RoleUser      = "USER"
+	RoleAdmin     = "ADMIN"
+	RoleOrganizer = "ORGANIZER" // Manages only the events they own
+	RoleScanner   = "SCANNER"   // Event staff checking tickets at the door
 )
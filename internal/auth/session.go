@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"ticket-booking/pkg/cache"
+	"ticket-booking/pkg/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// ErrSessionRevoked is returned for a refresh token whose family was logged
+	// out, expired, or issued before a logout-all
+	ErrSessionRevoked = errors.New("session revoked")
+	// ErrRefreshReused is returned when an already rotated refresh token is
+	// presented again; the whole token family is revoked
+	ErrRefreshReused = errors.New("refresh token reused")
+	// ErrAccountSuspended is returned for any token of a suspended account
+	ErrAccountSuspended = errors.New("account suspended")
+)
+
+// Redis keys:
+//
+//	auth:family:<family>  -> jti of the only refresh token of that family still usable
+//	auth:deny:<jti>       -> access token revoked before its expiry
+//	auth:revoked:<userID> -> unix time in microseconds; tokens issued up to it are rejected
+//	auth:suspended:<userID> -> set while an admin has the account suspended
+const (
+	familyKeyPrefix    = "auth:family:"
+	denyKeyPrefix      = "auth:deny:"
+	revokedKeyPrefix   = "auth:revoked:"
+	suspendedKeyPrefix = "auth:suspended:"
+)
+
+// Sessions tracks refresh token families and revoked access tokens in Redis.
+// Each login starts a family; every refresh rotates it to a new jti, so a
+// refresh token can be used exactly once. Presenting a rotated token again
+// means it leaked, and the family is revoked for both holders.
+type Sessions struct {
+	cache cache.Cache
+	cfg   *config.Security
+}
+
+// NewSessions creates a session store backed by c
+func NewSessions(c cache.Cache, cfg *config.Security) *Sessions {
+	return &Sessions{cache: c, cfg: cfg}
+}
+
+func (s *Sessions) refreshTTL() time.Duration {
+	return time.Minute * time.Duration(s.cfg.RefreshTTLMinute)
+}
+
+// Start records the refresh token of a freshly issued pair as its family's current token
+func (s *Sessions) Start(ctx context.Context, t *Tokens) error {
+	return s.cache.Set(ctx, familyKeyPrefix+t.Family, t.RefreshID, s.refreshTTL())
+}
+
+// Rotate accepts old only if it is its family's current token, then makes next
+// the current one. A stale jti revokes the family and returns ErrRefreshReused.
+// next must continue old's family.
+func (s *Sessions) Rotate(ctx context.Context, old *RefreshClaims, next *Tokens) error {
+	if err := s.checkIssuedAfterRevokeAll(ctx, old.UserID, old.IssuedAt); err != nil {
+		return err
+	}
+	// One compare-and-set, so two concurrent refreshes cannot both rotate
+	rotated, err := s.cache.CompareAndSet(ctx, familyKeyPrefix+old.Family, old.ID, next.RefreshID, s.refreshTTL())
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionRevoked
+	}
+	if err != nil {
+		return err
+	}
+	if !rotated {
+		if err := s.Revoke(ctx, old.Family); err != nil {
+			return err
+		}
+		return ErrRefreshReused
+	}
+	return nil
+}
+
+// Revoke ends a token family; its refresh tokens can no longer be rotated
+func (s *Sessions) Revoke(ctx context.Context, family string) error {
+	return s.cache.Del(ctx, familyKeyPrefix+family)
+}
+
+// RevokeAll rejects every access and refresh token issued to userID until now.
+// Tokens carry their issue time to the microsecond, so one issued right after
+// the call is accepted.
+func (s *Sessions) RevokeAll(ctx context.Context, userID string) error {
+	return s.cache.Set(ctx, revokedKeyPrefix+userID, time.Now().UnixMicro(), s.refreshTTL())
+}
+
+// Suspend rejects every token of userID until Reactivate, and revokes the
+// ones issued so far so they stay dead after reactivation
+func (s *Sessions) Suspend(ctx context.Context, userID string) error {
+	if err := s.cache.Set(ctx, suspendedKeyPrefix+userID, time.Now().Unix(), 0); err != nil {
+		return err
+	}
+	return s.RevokeAll(ctx, userID)
+}
+
+// Reactivate lifts a suspension; the account has to log in again
+func (s *Sessions) Reactivate(ctx context.Context, userID string) error {
+	return s.cache.Del(ctx, suspendedKeyPrefix+userID)
+}
+
+// Deny revokes an access token for the rest of its lifetime
+func (s *Sessions) Deny(ctx context.Context, claims *AccessClaims) error {
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	return s.cache.Set(ctx, denyKeyPrefix+claims.ID, 1, ttl)
+}
+
+// CheckAccess returns ErrSessionRevoked when the access token was denied or
+// issued before the user's last logout-all, and ErrAccountSuspended while the
+// account is suspended
+func (s *Sessions) CheckAccess(ctx context.Context, claims *AccessClaims) error {
+	if claims.ID != "" {
+		_, err := s.cache.Get(ctx, denyKeyPrefix+claims.ID)
+		if err == nil {
+			return ErrSessionRevoked
+		}
+		if !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+	_, err := s.cache.Get(ctx, suspendedKeyPrefix+claims.UserID)
+	if err == nil {
+		return ErrAccountSuspended
+	}
+	if !errors.Is(err, redis.Nil) {
+		return err
+	}
+	return s.checkIssuedAfterRevokeAll(ctx, claims.UserID, claims.IssuedAt)
+}
+
+func (s *Sessions) checkIssuedAfterRevokeAll(ctx context.Context, userID string, iat *jwt.NumericDate) error {
+	raw, err := s.cache.Get(ctx, revokedKeyPrefix+userID)
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	cutoff, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return err
+	}
+	if iat == nil || iat.UnixMicro() <= cutoff {
+		return ErrSessionRevoked
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package auth_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/pkg/config"
+)
+
+var testCfg = &config.Security{JWTAccessSecret: "a", JWTRefreshSecret: "r", AccessTTLMinute: 15, RefreshTTLMinute: 60}
+
+func refreshClaims(t *testing.T, tokens *auth.Tokens) *auth.RefreshClaims {
+	claims, err := auth.ValidateRefreshToken(testCfg, tokens.RefreshToken)
+	require.NoError(t, err)
+	return claims
+}
+
+func TestRotate_AcceptsCurrentToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockCache(ctrl)
+	s := auth.NewSessions(c, testCfg)
+
+	first, err := auth.GenerateTokens(testCfg, nil, "u1", "USER", nil, nil, "")
+	require.NoError(t, err)
+	next, err := auth.GenerateTokens(testCfg, nil, "u1", "USER", nil, nil, first.Family)
+	require.NoError(t, err)
+	require.Equal(t, first.Family, next.Family)
+
+	c.EXPECT().Get(gomock.Any(), "auth:revoked:u1").Return("", redis.Nil)
+	c.EXPECT().CompareAndSet(gomock.Any(), "auth:family:"+first.Family, first.RefreshID, next.RefreshID, time.Hour).Return(true, nil)
+
+	require.NoError(t, s.Rotate(context.Background(), refreshClaims(t, first), next))
+}
+
+func TestRotate_ReuseRevokesFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockCache(ctrl)
+	s := auth.NewSessions(c, testCfg)
+
+	stale, err := auth.GenerateTokens(testCfg, nil, "u1", "USER", nil, nil, "")
+	require.NoError(t, err)
+
+	c.EXPECT().Get(gomock.Any(), "auth:revoked:u1").Return("", redis.Nil)
+	c.EXPECT().CompareAndSet(gomock.Any(), "auth:family:"+stale.Family, stale.RefreshID, stale.RefreshID, time.Hour).Return(false, nil)
+	c.EXPECT().Del(gomock.Any(), "auth:family:"+stale.Family).Return(nil)
+
+	err = s.Rotate(context.Background(), refreshClaims(t, stale), stale)
+	require.ErrorIs(t, err, auth.ErrRefreshReused)
+}
+
+func TestRotate_RevokedFamily(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockCache(ctrl)
+	s := auth.NewSessions(c, testCfg)
+
+	tokens, err := auth.GenerateTokens(testCfg, nil, "u1", "USER", nil, nil, "")
+	require.NoError(t, err)
+
+	c.EXPECT().Get(gomock.Any(), "auth:revoked:u1").Return("", redis.Nil)
+	c.EXPECT().CompareAndSet(gomock.Any(), "auth:family:"+tokens.Family, tokens.RefreshID, tokens.RefreshID, time.Hour).Return(false, redis.Nil)
+
+	err = s.Rotate(context.Background(), refreshClaims(t, tokens), tokens)
+	require.ErrorIs(t, err, auth.ErrSessionRevoked)
+}
+
+func TestCheckAccess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockCache(ctrl)
+	s := auth.NewSessions(c, testCfg)
+	ctx := context.Background()
+
+	issued := time.Now().Add(-time.Minute)
+	claims := &auth.AccessClaims{UserID: "u1", RegisteredClaims: jwt.RegisteredClaims{
+		ID:       "jti-1",
+		IssuedAt: jwt.NewNumericDate(issued),
+	}}
+
+	// Denylisted
+	c.EXPECT().Get(ctx, "auth:deny:jti-1").Return("1", nil)
+	require.ErrorIs(t, s.CheckAccess(ctx, claims), auth.ErrSessionRevoked)
+
+	// Account suspended
+	c.EXPECT().Get(ctx, "auth:deny:jti-1").Return("", redis.Nil)
+	c.EXPECT().Get(ctx, "auth:suspended:u1").Return("1", nil)
+	require.ErrorIs(t, s.CheckAccess(ctx, claims), auth.ErrAccountSuspended)
+
+	// Issued before logout-all
+	c.EXPECT().Get(ctx, "auth:deny:jti-1").Return("", redis.Nil)
+	c.EXPECT().Get(ctx, "auth:suspended:u1").Return("", redis.Nil)
+	c.EXPECT().Get(ctx, "auth:revoked:u1").Return(strconv.FormatInt(time.Now().UnixMicro(), 10), nil)
+	require.ErrorIs(t, s.CheckAccess(ctx, claims), auth.ErrSessionRevoked)
+
+	// Issued after logout-all
+	c.EXPECT().Get(ctx, "auth:deny:jti-1").Return("", redis.Nil)
+	c.EXPECT().Get(ctx, "auth:suspended:u1").Return("", redis.Nil)
+	c.EXPECT().Get(ctx, "auth:revoked:u1").Return("1", nil)
+	require.NoError(t, s.CheckAccess(ctx, claims))
+}
+
+func TestRevokeAll_SameSecond(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := mocks.NewMockCache(ctrl)
+	s := auth.NewSessions(c, testCfg)
+	ctx := context.Background()
+
+	var cutoff string
+	c.EXPECT().Set(ctx, "auth:revoked:u1", gomock.Any(), time.Hour).DoAndReturn(
+		func(_ context.Context, _ string, v any, _ time.Duration) error {
+			cutoff = strconv.FormatInt(v.(int64), 10)
+			return nil
+		})
+	c.EXPECT().Get(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key string) (string, error) {
+		if key == "auth:revoked:u1" {
+			return cutoff, nil
+		}
+		return "", redis.Nil
+	}).AnyTimes()
+
+	access := func() *auth.AccessClaims {
+		tokens, err := auth.GenerateTokens(testCfg, nil, "u1", "USER", nil, nil, "")
+		require.NoError(t, err)
+		claims, err := auth.ValidateAccessToken(testCfg, nil, tokens.AccessToken)
+		require.NoError(t, err)
+		return claims
+	}
+
+	before := access()
+	require.NoError(t, s.RevokeAll(ctx, "u1"))
+	time.Sleep(time.Microsecond)
+	after := access()
+	require.ErrorIs(t, s.CheckAccess(ctx, before), auth.ErrSessionRevoked)
+	require.NoError(t, s.CheckAccess(ctx, after), "issued right after the logout-all")
+}
@@ -7,6 +7,7 @@ import (
 	"ticket-booking/pkg/config"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // --- Interface for tokenClaims ---
@@ -15,38 +16,79 @@ type TokenClaims interface {
 	IsAccess() bool
 }
 
+// Authentication method references for the amr claim (RFC 8176)
+const (
+	AMRPassword  = "pwd" // Password
+	AMROTP       = "otp" // One-time code: TOTP or a recovery code
+	AMRMFA       = "mfa" // More than one factor
+	AMRFederated = "fed" // External identity provider (OIDC)
+)
+
 // --- Claims ---
 type AccessClaims struct {
-	UserID string `json:"uid"`
-	Role   string `json:"role"`
+	UserID      string   `json:"uid"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"perms,omitempty"` // Resolved from the role at issue time
+	AMR         []string `json:"amr,omitempty"`   // How the login was authenticated
 	jwt.RegisteredClaims
 }
 
 func (a AccessClaims) IsAccess() bool { return true }
 
+// HasAMR reports whether the login used method
+func (a AccessClaims) HasAMR(method string) bool {
+	for _, m := range a.AMR {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
 type RefreshClaims struct {
-	UserID string `json:"uid"`
+	UserID string   `json:"uid"`
+	Family string   `json:"fam"`           // Shared by every rotation of one login
+	AMR    []string `json:"amr,omitempty"` // Carried to every access token of the login
 	jwt.RegisteredClaims
 }
 
 func (r RefreshClaims) IsAccess() bool { return false }
 
+// Issue times are kept to the microsecond, so a logout-all revokes the tokens
+// issued before it without catching the one issued right after
+func init() { jwt.TimePrecision = time.Microsecond }
+
 // --- Generate Tokens ---
 type Tokens struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+	Family       string `json:"-"` // Refresh token family
+	RefreshID    string `json:"-"` // jti of RefreshToken
 }
 
-// GenerateTokens creates both AccessToken and RefreshToken using separate secrets and TTLs
-func GenerateTokens(cfg *config.Security, userID, role string) (*Tokens, error) {
+// GenerateTokens creates both AccessToken and RefreshToken using separate keys and TTLs.
+// Access tokens are signed with keys when set (RS256/EdDSA, verifiable by other
+// services via JWKS) and with the HS256 access secret otherwise. Refresh tokens
+// are only read by this service and always use the HS256 refresh secret.
+// perms are the role's permissions at issue time; role changes apply on the next issue.
+// amr records how the login was authenticated and is kept across refreshes.
+// family continues an existing refresh token family; empty starts a new one.
+func GenerateTokens(cfg *config.Security, keys *KeySet, userID, role string, perms, amr []string, family string) (*Tokens, error) {
 	now := time.Now()
+	if family == "" {
+		family = uuid.NewString()
+	}
+	refreshID := uuid.NewString()
 
 	accessClaims := AccessClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		Permissions: perms,
+		AMR:         amr,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "ticket-booking",
-			Audience:  []string{"ticket-booking-client"},
+			ID:        uuid.NewString(),
+			Issuer:    issuer(cfg),
+			Audience:  []string{audience(cfg)},
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute * time.Duration(cfg.AccessTTLMinute))),
 			NotBefore: jwt.NewNumericDate(now),
@@ -55,16 +97,25 @@ func GenerateTokens(cfg *config.Security, userID, role string) (*Tokens, error)
 
 	refreshClaims := RefreshClaims{
 		UserID: userID,
+		Family: family,
+		AMR:    amr,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Issuer:    "ticket-booking",
-			Audience:  []string{"ticket-booking-client"},
+			ID:        refreshID,
+			Issuer:    issuer(cfg),
+			Audience:  []string{audience(cfg)},
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute * time.Duration(cfg.RefreshTTLMinute))),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	at, err := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(cfg.JWTAccessSecret))
+	var at string
+	var err error
+	if keys != nil {
+		at, err = keys.sign(accessClaims)
+	} else {
+		at, err = jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims).SignedString([]byte(cfg.JWTAccessSecret))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -73,54 +124,73 @@ func GenerateTokens(cfg *config.Security, userID, role string) (*Tokens, error)
 		return nil, err
 	}
 
-	return &Tokens{AccessToken: at, RefreshToken: rt}, nil
+	return &Tokens{AccessToken: at, RefreshToken: rt, Family: family, RefreshID: refreshID}, nil
 }
 
-// --- Validate Tokens ---
-func ValidateAccessToken(cfg *config.Security, token string) (*AccessClaims, error) {
-	claims, err := validateToken(token, true, cfg)
-	if err != nil {
-		return nil, err
+func issuer(cfg *config.Security) string {
+	if cfg.JWTIssuer != "" {
+		return cfg.JWTIssuer
 	}
-	return claims.(*AccessClaims), nil
+	return config.DefaultJWTIssuer
 }
 
-func ValidateRefreshToken(cfg *config.Security, token string) (*RefreshClaims, error) {
-	claims, err := validateToken(token, false, cfg)
-	if err != nil {
-		return nil, err
+func audience(cfg *config.Security) string {
+	if cfg.JWTAudience != "" {
+		return cfg.JWTAudience
 	}
-	return claims.(*RefreshClaims), nil
+	return config.DefaultJWTAudience
 }
 
-// --- Internal helper ---
-func validateToken(token string, isAccess bool, cfg *config.Security) (TokenClaims, error) {
-	var secret string
-	if isAccess {
-		secret = cfg.JWTAccessSecret
-	} else {
-		secret = cfg.JWTRefreshSecret
-	}
+// --- Validate Tokens ---
 
-	if secret == "" {
-		return nil, errors.New("JWT secret not set")
+// ValidateAccessToken verifies an access token against keys, or the HS256
+// access secret when keys is nil. Only the matching algorithm is accepted.
+func ValidateAccessToken(cfg *config.Security, keys *KeySet, token string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	if keys != nil {
+		if err := parse(cfg, token, claims, keys.keyFunc, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()); err != nil {
+			return nil, err
+		}
+		return claims, nil
+	}
+	if err := parse(cfg, token, claims, hmacKey(cfg.JWTAccessSecret), jwt.SigningMethodHS256.Alg()); err != nil {
+		return nil, err
 	}
+	return claims, nil
+}
 
-	var claims TokenClaims
-	if isAccess {
-		claims = &AccessClaims{}
-	} else {
-		claims = &RefreshClaims{}
+func ValidateRefreshToken(cfg *config.Security, token string) (*RefreshClaims, error) {
+	claims := &RefreshClaims{}
+	if err := parse(cfg, token, claims, hmacKey(cfg.JWTRefreshSecret), jwt.SigningMethodHS256.Alg()); err != nil {
+		return nil, err
 	}
+	return claims, nil
+}
 
-	tok, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+// --- Internal helpers ---
+func hmacKey(secret string) jwt.Keyfunc {
+	return func(*jwt.Token) (any, error) {
+		if secret == "" {
+			return nil, errors.New("JWT secret not set")
+		}
 		return []byte(secret), nil
-	})
+	}
+}
+
+// parse verifies signature, algorithm, issuer, audience and expiry
+func parse(cfg *config.Security, token string, claims TokenClaims, key jwt.Keyfunc, algs ...string) error {
+	tok, err := jwt.ParseWithClaims(token, claims, key,
+		jwt.WithValidMethods(algs),
+		jwt.WithIssuer(issuer(cfg)),
+		jwt.WithAudience(audience(cfg)),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if !tok.Valid {
-		return nil, errors.New("invalid token")
+		return errors.New("invalid token")
 	}
-	return claims, nil
+	return nil
 }
@@ -0,0 +1,454 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"ticket-booking/internal/audit"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidTransition is returned when a booking's status does not allow the change
+	ErrInvalidTransition = errors.New("booking status does not allow this change")
+	// ErrSameEvent is returned when a booking is moved to the event it is already for
+	ErrSameEvent = errors.New("booking is already for this event")
+	// ErrEventNotFound is returned when a booking is moved to an event that does not exist
+	ErrEventNotFound = errors.New("event not found")
+	// ErrResoldTickets is returned when a booking with tickets resold to other
+	// users is refunded, cancelled or moved: the buyers hold those tickets and
+	// the seller has been paid for them
+	ErrResoldTickets = errors.New("booking has resold tickets")
+)
+
+// BookingFilter narrows the admin booking search. Zero values mean "no constraint".
+type BookingFilter struct {
+	UserEmail string     // Case-insensitive exact match on the booker's email
+	EventID   string     // Only bookings for this event
+	Status    Status     // Only bookings in this status
+	From      *time.Time // Created at or after
+	To        *time.Time // Created before
+	Limit     int
+	Offset    int
+}
+
+// BookingRefundedMessage is published when support refunds a booking, for
+// the payment side to pay AmountCents back. Consumers must be idempotent by
+// BookingID: a failed refund is retried with the same message.
+type BookingRefundedMessage struct {
+	BookingID   string `json:"booking_id"`   // UUID of the refunded booking
+	UserID      string `json:"user_id"`      // UUID of the user to pay back
+	EventID     string `json:"event_id"`     // UUID of the booked event
+	AmountCents int64  `json:"amount_cents"` // Quantity times the unit price paid
+}
+
+// --- Service ---
+
+// Search lists bookings of every user matching f, newest first
+func (s *Service) Search(ctx context.Context, f BookingFilter) ([]*Booking, error) {
+	bookings, err := s.repo.Search(ctx, f)
+	if err != nil {
+		s.logger.Error("Failed to search bookings", zap.Error(err))
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// transition loads a booking and checks its status may move to to
+func (s *Service) transition(id string, to Status) (*Booking, error) {
+	b, err := s.repo.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !CanTransition(b.Status, to) {
+		return nil, ErrInvalidTransition
+	}
+	return b, nil
+}
+
+// AdminConfirm confirms a PENDING booking without waiting for payment.
+// It returns the booking as it was before the change.
+func (s *Service) AdminConfirm(ctx context.Context, id string) (*Booking, error) {
+	b, err := s.transition(id, StatusConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	before := *b
+	return &before, s.confirm(ctx, b)
+}
+
+// AdminCancel cancels a PENDING or CONFIRMED booking and releases its seats
+// without paying anything back. Like Refund it claims the status change
+// before looking for resold tickets, so no resale can complete in between;
+// a booking with resold tickets goes back to its status and is refused.
+// It returns the booking as it was before the change.
+func (s *Service) AdminCancel(ctx context.Context, id string) (*Booking, error) {
+	b, err := s.transition(id, StatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStatus(ctx, b, StatusCancelled); err != nil {
+		if !errors.Is(err, ErrInvalidTransition) {
+			s.logger.Error("AdminCancel: update status failed", zap.String("booking_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+	if err := s.checkResold(ctx, b.ID); err != nil {
+		s.unclaim(ctx, b, StatusCancelled)
+		return nil, err
+	}
+	s.cancelled(ctx, b)
+	return b, nil
+}
+
+// Refund marks a CONFIRMED booking REFUNDED, releases its seats and publishes
+// booking.refunded for the payment side. The status change is claimed first,
+// so concurrent refunds pay out once and no resale can complete afterwards;
+// a booking with resold tickets, or whose message cannot be published, goes
+// back to CONFIRMED and nothing is paid. It returns the booking as it was
+// before the change.
+func (s *Service) Refund(ctx context.Context, id string) (*Booking, error) {
+	b, err := s.transition(id, StatusRefunded)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStatus(ctx, b, StatusRefunded); err != nil {
+		if !errors.Is(err, ErrInvalidTransition) {
+			s.logger.Error("Refund: update status failed", zap.String("booking_id", id), zap.Error(err))
+		}
+		return nil, err
+	}
+	if err := s.checkResold(ctx, b.ID); err != nil {
+		s.unclaim(ctx, b, StatusRefunded)
+		return nil, err
+	}
+	msg := BookingRefundedMessage{
+		BookingID:   b.ID,
+		UserID:      b.UserID,
+		EventID:     b.EventID,
+		AmountCents: int64(b.Quantity) * b.UnitPriceCents,
+	}
+	if err := s.publisher.Publish("booking.refunded", msg); err != nil {
+		s.logger.Error("Failed to publish booking refunded message", zap.String("booking_id", id), zap.Error(err))
+		s.unclaim(ctx, b, StatusRefunded)
+		return nil, err
+	}
+	s.release(ctx, b)
+	s.voidTickets(ctx, id)
+
+	s.logger.Info("Booking refunded", zap.String("booking_id", id), zap.Int64("amount_cents", msg.AmountCents))
+	return b, nil
+}
+
+// unclaim puts b, moved to status by this request, back to the status it was read with
+func (s *Service) unclaim(ctx context.Context, b *Booking, status Status) {
+	claimed := *b
+	claimed.Status = status
+	if err := s.repo.UpdateStatus(ctx, &claimed, b.Status); err != nil {
+		s.logger.Error("Restoring booking status failed", zap.String("booking_id", b.ID),
+			zap.String("status", string(b.Status)), zap.Error(err))
+	}
+}
+
+// checkResold fails with ErrResoldTickets when tickets of the booking were resold
+func (s *Service) checkResold(ctx context.Context, bookingID string) error {
+	n, err := s.resale.SoldTickets(ctx, bookingID)
+	if err != nil {
+		s.logger.Error("Failed to count resold tickets", zap.String("booking_id", bookingID), zap.Error(err))
+		return err
+	}
+	if n > 0 {
+		return ErrResoldTickets
+	}
+	return nil
+}
+
+// Move points a PENDING or CONFIRMED booking at another event, reserving its
+// seats there and releasing them on the old one. The unit price paid is kept.
+// It returns the booking as it was before the change.
+func (s *Service) Move(ctx context.Context, id, eventID string) (*Booking, error) {
+	var b *Booking
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// The row lock keeps the booking from being cancelled or refunded
+		// while its seats change events
+		var err error
+		if b, err = s.repo.GetForUpdate(tx, id); err != nil {
+			return err
+		}
+		if b.Status != StatusPending && b.Status != StatusConfirmed {
+			return ErrInvalidTransition
+		}
+		if b.EventID == eventID {
+			return ErrSameEvent
+		}
+		if err := s.checkResold(ctx, b.ID); err != nil {
+			return err
+		}
+		ok, err := s.reserver.ReserveTx(tx, eventID, b.Quantity)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+		if !ok {
+			return ErrNotEnoughTickets
+		}
+		return s.repo.UpdateEvent(tx, b, eventID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrSameEvent),
+			errors.Is(err, ErrEventNotFound), errors.Is(err, ErrNotEnoughTickets), errors.Is(err, ErrResoldTickets):
+		default:
+			s.logger.Error("Failed to move booking", zap.String("booking_id", id), zap.String("event_id", eventID), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	s.release(ctx, b)
+	if b.Status == StatusConfirmed {
+		if err := s.updateEventStatsCache(ctx, eventID); err != nil {
+			s.logger.Warn("Move: update stats cache failed", zap.String("event_id", eventID), zap.Error(err))
+		}
+		// Tickets name their event, so the old ones are replaced
+		s.voidTickets(ctx, id)
+		moved := *b
+		moved.EventID = eventID
+		if err := s.tickets.Issue(ctx, &moved); err != nil {
+			s.logger.Error("Move: issue tickets failed", zap.String("booking_id", id), zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Booking moved", zap.String("booking_id", id), zap.String("from_event", b.EventID), zap.String("to_event", eventID))
+	return b, nil
+}
+
+// --- HTTP ---
+
+// AdminHandler serves /admin/bookings. Every change is written to the audit log.
+type AdminHandler struct {
+	svc    *Service
+	audit  *audit.Service
+	logger *zap.Logger
+}
+
+// NewAdminHandler creates the admin booking console handler
+func NewAdminHandler(s *Service, a *audit.Service, logger *zap.Logger) *AdminHandler {
+	return &AdminHandler{svc: s, audit: a, logger: logger}
+}
+
+// parseTime reads an optional RFC 3339 query parameter
+func parseTime(c *gin.Context, key string) (*time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ===== List =====
+// @Summary Search bookings
+// @Description Search bookings of every user, newest first (requires bookings:read)
+// @Tags admin-bookings
+// @Produce json
+// @Param email query string false "Booker's email (case-insensitive, exact)"
+// @Param event_id query string false "Only this event"
+// @Param status query string false "PENDING, CONFIRMED, CANCELLED or REFUNDED"
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param limit query int false "Max items to return (default 50, max 200)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} Booking
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/bookings [get]
+func (a *AdminHandler) List(c *gin.Context) {
+	f := BookingFilter{
+		UserEmail: strings.TrimSpace(c.Query("email")),
+		EventID:   c.Query("event_id"),
+		Status:    Status(strings.ToUpper(c.Query("status"))),
+	}
+	switch f.Status {
+	case "", StatusPending, StatusConfirmed, StatusCancelled, StatusRefunded:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid status"})
+		return
+	}
+	var err error
+	if f.From, err = parseTime(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid from, expected RFC 3339"})
+		return
+	}
+	if f.To, err = parseTime(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid to, expected RFC 3339"})
+		return
+	}
+	f.Limit, f.Offset = pagination(c)
+
+	bookings, err := a.svc.Search(c, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	if bookings == nil {
+		bookings = []*Booking{}
+	}
+	c.JSON(http.StatusOK, bookings)
+}
+
+// ===== Get =====
+// @Summary Get any booking
+// @Description A booking of any user (requires bookings:read)
+// @Tags admin-bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {object} Booking
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/bookings/{id} [get]
+func (a *AdminHandler) Get(c *gin.Context) {
+	a.respond(c, c.Param("id"))
+}
+
+// ===== Confirm =====
+// @Summary Force-confirm booking
+// @Description Confirm a PENDING booking without waiting for payment (requires bookings:manage)
+// @Tags admin-bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body AdminActionRequest false "Reason, kept in the audit log"
+// @Success 200 {object} Booking
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Status does not allow it"
+// @Security BearerAuth
+// @Router /admin/bookings/{id}/confirm [post]
+func (a *AdminHandler) Confirm(c *gin.Context) {
+	a.changeStatus(c, audit.ActionBookingConfirm, StatusConfirmed, a.svc.AdminConfirm)
+}
+
+// ===== Cancel =====
+// @Summary Cancel booking
+// @Description Cancel a PENDING or CONFIRMED booking and release its seats; nothing is paid back (requires bookings:manage)
+// @Tags admin-bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body AdminActionRequest false "Reason, kept in the audit log"
+// @Success 200 {object} Booking
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Status does not allow it or tickets were resold"
+// @Security BearerAuth
+// @Router /admin/bookings/{id}/cancel [post]
+func (a *AdminHandler) Cancel(c *gin.Context) {
+	a.changeStatus(c, audit.ActionBookingCancel, StatusCancelled, a.svc.AdminCancel)
+}
+
+// ===== Refund =====
+// @Summary Refund booking
+// @Description Refund a CONFIRMED booking: release its seats and ask payment to pay it back (requires bookings:refund)
+// @Tags admin-bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body AdminActionRequest false "Reason, kept in the audit log"
+// @Success 200 {object} Booking
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Status does not allow it or tickets were resold"
+// @Security BearerAuth
+// @Router /admin/bookings/{id}/refund [post]
+func (a *AdminHandler) Refund(c *gin.Context) {
+	a.changeStatus(c, audit.ActionBookingRefund, StatusRefunded, a.svc.Refund)
+}
+
+// ===== Move =====
+// @Summary Move booking
+// @Description Move a PENDING or CONFIRMED booking to another event at the price paid (requires bookings:manage)
+// @Tags admin-bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body MoveBookingRequest true "Target event"
+// @Success 200 {object} Booking
+// @Failure 400 {object} ErrorResponse "Invalid request or unknown event"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Status does not allow it, same event, not enough tickets or tickets were resold"
+// @Security BearerAuth
+// @Router /admin/bookings/{id}/move [post]
+func (a *AdminHandler) Move(c *gin.Context) {
+	var req MoveBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	id := c.Param("id")
+	before, err := a.svc.Move(c, id, req.EventID)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	a.record(c, audit.ActionBookingMove, id, audit.Changes{"event_id": {From: before.EventID, To: req.EventID}}, req.Reason)
+	a.respond(c, id)
+}
+
+// changeStatus runs one status change and writes it to the audit log
+func (a *AdminHandler) changeStatus(c *gin.Context, action string, to Status, do func(context.Context, string) (*Booking, error)) {
+	var req AdminActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+	id := c.Param("id")
+	before, err := do(c, id)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	a.record(c, action, id, audit.Changes{"status": {From: string(before.Status), To: string(to)}}, req.Reason)
+	a.respond(c, id)
+}
+
+func (a *AdminHandler) record(c *gin.Context, action, id string, changes audit.Changes, reason string) {
+	var detail map[string]any
+	if reason != "" {
+		detail = map[string]any{"reason": reason}
+	}
+	_ = a.audit.Record(c, &audit.Entry{Action: action, TargetType: "booking", TargetID: id, Changes: changes, Detail: detail})
+}
+
+func (a *AdminHandler) respond(c *gin.Context, id string) {
+	b, err := a.svc.Get(c, id)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, b)
+}
+
+func (a *AdminHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+	case errors.Is(err, ErrEventNotFound):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrInvalidTransition), errors.Is(err, ErrSameEvent), errors.Is(err, ErrNotEnoughTickets),
+		errors.Is(err, ErrResoldTickets):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		a.logger.Error("Admin booking action failed", zap.String("booking_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+	}
+}
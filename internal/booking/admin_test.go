@@ -0,0 +1,420 @@
+package booking_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/mocks"
+)
+
+// dryRunDB stands in for the stats queries, which then fail and are only logged
+func dryRunDB(t *testing.T, db *mocks.MockDatabase) {
+	gdb, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true, Logger: logger.Discard})
+	require.NoError(t, err)
+	db.EXPECT().WithContext(gomock.Any()).Return(gdb).AnyTimes()
+}
+
+// inTx runs transactions straight through with a nil tx
+func inTx(db *mocks.MockDatabase) {
+	db.EXPECT().Transaction(gomock.Any()).DoAndReturn(func(fn func(*gorm.DB) error, _ ...*sql.TxOptions) error {
+		return fn(nil)
+	})
+}
+
+// bookingID matches a *booking.Booking by its ID
+type bookingID string
+
+func (id bookingID) Matches(x any) bool {
+	b, ok := x.(*booking.Booking)
+	return ok && b.ID == string(id)
+}
+
+func (id bookingID) String() string { return "booking " + string(id) }
+
+// bookingIn matches a *booking.Booking by its ID and the status it was read with
+func bookingIn(id string, status booking.Status) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		b, ok := x.(*booking.Booking)
+		return ok && b.ID == id && b.Status == status
+	})
+}
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from, to booking.Status
+		ok       bool
+	}{
+		{booking.StatusPending, booking.StatusConfirmed, true},
+		{booking.StatusPending, booking.StatusCancelled, true},
+		{booking.StatusPending, booking.StatusRefunded, false},
+		{booking.StatusConfirmed, booking.StatusCancelled, true},
+		{booking.StatusConfirmed, booking.StatusRefunded, true},
+		{booking.StatusCancelled, booking.StatusConfirmed, false},
+		{booking.StatusRefunded, booking.StatusCancelled, false},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.ok, booking.CanTransition(tc.from, tc.to), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestAdminConfirm(t *testing.T) {
+	svc, repo, _, publisher, cache, db := createTestService(t)
+	dryRunDB(t, db)
+
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusPending}, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b1"), booking.StatusConfirmed).Return(nil)
+	cache.EXPECT().Del(gomock.Any(), "booking:pending:b1").Return(nil)
+	publisher.EXPECT().Publish("booking.confirmed", booking.BookingConfirmedMessage{BookingID: "b1", EventID: "e1"}).Return(nil)
+
+	before, err := svc.AdminConfirm(context.Background(), "b1")
+	require.NoError(t, err)
+	require.Equal(t, booking.StatusPending, before.Status)
+
+	repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", Status: booking.StatusCancelled}, nil)
+	_, err = svc.AdminConfirm(context.Background(), "b2")
+	require.ErrorIs(t, err, booking.ErrInvalidTransition)
+}
+
+func TestRefund_ClaimsBeforePublishing(t *testing.T) {
+	svc, repo, reserver, publisher, _, db := createTestService(t)
+	dryRunDB(t, db)
+
+	b := &booking.Booking{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, UnitPriceCents: 1500, Status: booking.StatusConfirmed}
+	repo.EXPECT().Get("b1").Return(b, nil)
+	gomock.InOrder(
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusConfirmed), booking.StatusRefunded).Return(nil),
+		publisher.EXPECT().Publish("booking.refunded", booking.BookingRefundedMessage{
+			BookingID: "b1", UserID: "u1", EventID: "e1", AmountCents: 3000,
+		}).Return(nil),
+		reserver.EXPECT().Release(gomock.Any(), "e1", 2).Return(nil),
+	)
+
+	before, err := svc.Refund(context.Background(), "b1")
+	require.NoError(t, err)
+	require.Equal(t, booking.StatusConfirmed, before.Status)
+}
+
+func TestRefund_PublishFailureKeepsBooking(t *testing.T) {
+	svc, repo, _, publisher, _, _ := createTestService(t)
+
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", Status: booking.StatusConfirmed}, nil)
+	gomock.InOrder(
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusConfirmed), booking.StatusRefunded).Return(nil),
+		publisher.EXPECT().Publish("booking.refunded", gomock.Any()).Return(assert.AnError),
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusRefunded), booking.StatusConfirmed).Return(nil),
+	)
+
+	_, err := svc.Refund(context.Background(), "b1")
+	require.ErrorIs(t, err, assert.AnError)
+
+	// A concurrent refund won the claim: nothing is published
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", Status: booking.StatusConfirmed}, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b1"), booking.StatusRefunded).Return(booking.ErrInvalidTransition)
+	_, err = svc.Refund(context.Background(), "b1")
+	require.ErrorIs(t, err, booking.ErrInvalidTransition)
+
+	repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", Status: booking.StatusPending}, nil)
+	_, err = svc.Refund(context.Background(), "b2")
+	require.ErrorIs(t, err, booking.ErrInvalidTransition, "only confirmed bookings were paid")
+}
+
+func TestMove(t *testing.T) {
+	svc, repo, reserver, _, _, db := createTestService(t)
+	dryRunDB(t, db)
+	confirmed := func() *booking.Booking {
+		return &booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed}
+	}
+
+	// Seats are taken on the new event before they are given back on the old one
+	inTx(db)
+	gomock.InOrder(
+		repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil),
+		reserver.EXPECT().ReserveTx(gomock.Nil(), "e2", 2).Return(true, nil),
+		repo.EXPECT().UpdateEvent(gomock.Nil(), bookingID("b1"), "e2").Return(nil),
+		reserver.EXPECT().Release(gomock.Any(), "e1", 2).Return(nil),
+	)
+	before, err := svc.Move(context.Background(), "b1", "e2")
+	require.NoError(t, err)
+	require.Equal(t, "e1", before.EventID)
+
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil)
+	reserver.EXPECT().ReserveTx(gomock.Nil(), "e3", 2).Return(false, nil)
+	_, err = svc.Move(context.Background(), "b1", "e3")
+	require.ErrorIs(t, err, booking.ErrNotEnoughTickets)
+
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil)
+	reserver.EXPECT().ReserveTx(gomock.Nil(), "missing", 2).Return(false, gorm.ErrRecordNotFound)
+	_, err = svc.Move(context.Background(), "b1", "missing")
+	require.ErrorIs(t, err, booking.ErrEventNotFound)
+
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil)
+	_, err = svc.Move(context.Background(), "b1", "e1")
+	require.ErrorIs(t, err, booking.ErrSameEvent)
+
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b2").Return(&booking.Booking{ID: "b2", EventID: "e1", Status: booking.StatusRefunded}, nil)
+	_, err = svc.Move(context.Background(), "b2", "e2")
+	require.ErrorIs(t, err, booking.ErrInvalidTransition)
+}
+
+// ticketLog records the calls the booking service makes to its ticket
+// issuer, notifier and resale market
+type ticketLog struct {
+	calls []string
+	sold  map[string]int64 // Resold tickets by booking
+}
+
+func (l *ticketLog) BookingConfirmed(_ context.Context, b *booking.Booking) error {
+	l.calls = append(l.calls, "notify "+b.ID+" "+string(b.Status))
+	return nil
+}
+
+func (l *ticketLog) Issue(_ context.Context, b *booking.Booking) error {
+	l.calls = append(l.calls, "issue "+b.ID+" "+b.EventID)
+	return nil
+}
+
+func (l *ticketLog) Void(_ context.Context, bookingID string) error {
+	l.calls = append(l.calls, "void "+bookingID)
+	return nil
+}
+
+func (l *ticketLog) RemoveListings(_ context.Context, bookingID string) error {
+	l.calls = append(l.calls, "delist "+bookingID)
+	return nil
+}
+
+func (l *ticketLog) SoldTickets(_ context.Context, bookingID string) (int64, error) {
+	return l.sold[bookingID], nil
+}
+
+func TestRefund_RefusesResoldTickets(t *testing.T) {
+	svc, repo, _, _, _, db := createTestService(t)
+	dryRunDB(t, db)
+	tickets := &ticketLog{sold: map[string]int64{"b1": 1}}
+	svc.SetTickets(tickets)
+	svc.SetResale(tickets)
+	confirmed := func() *booking.Booking {
+		return &booking.Booking{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed}
+	}
+
+	// The claim stops further sales; one that completed first puts the booking back
+	repo.EXPECT().Get("b1").Return(confirmed(), nil)
+	gomock.InOrder(
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusConfirmed), booking.StatusRefunded).Return(nil),
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusRefunded), booking.StatusConfirmed).Return(nil),
+	)
+	_, err := svc.Refund(context.Background(), "b1")
+	require.ErrorIs(t, err, booking.ErrResoldTickets)
+
+	repo.EXPECT().Get("b1").Return(confirmed(), nil)
+	gomock.InOrder(
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusConfirmed), booking.StatusCancelled).Return(nil),
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusCancelled), booking.StatusConfirmed).Return(nil),
+	)
+	_, err = svc.AdminCancel(context.Background(), "b1")
+	require.ErrorIs(t, err, booking.ErrResoldTickets)
+
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil)
+	_, err = svc.Move(context.Background(), "b1", "e2")
+	require.ErrorIs(t, err, booking.ErrResoldTickets)
+	require.Empty(t, tickets.calls, "the buyer's ticket stays valid")
+}
+
+func TestAdminCancel_ClaimsBeforeCheckingResale(t *testing.T) {
+	svc, repo, _, _, _, db := createTestService(t)
+	dryRunDB(t, db)
+	tickets := &ticketLog{sold: map[string]int64{}}
+	svc.SetTickets(tickets)
+	svc.SetResale(tickets)
+
+	// A resale completing just before the claim is seen and the cancellation backs out
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed}, nil)
+	gomock.InOrder(
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusConfirmed), booking.StatusCancelled).
+			DoAndReturn(func(context.Context, *booking.Booking, booking.Status) error {
+				tickets.sold["b1"] = 1
+				return nil
+			}),
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingIn("b1", booking.StatusCancelled), booking.StatusConfirmed).Return(nil),
+	)
+	_, err := svc.AdminCancel(context.Background(), "b1")
+	require.ErrorIs(t, err, booking.ErrResoldTickets)
+	require.Empty(t, tickets.calls, "the buyer's ticket stays valid")
+}
+
+func TestTickets_FollowBookingStatus(t *testing.T) {
+	svc, repo, reserver, publisher, cache, db := createTestService(t)
+	dryRunDB(t, db)
+	tickets := &ticketLog{}
+	svc.SetTickets(tickets)
+	svc.SetNotifier(tickets)
+	svc.SetResale(tickets)
+	confirmed := func() *booking.Booking {
+		return &booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed}
+	}
+
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusPending}, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b1"), booking.StatusConfirmed).Return(nil)
+	cache.EXPECT().Del(gomock.Any(), "booking:pending:b1").Return(nil)
+	var sent any
+	publisher.EXPECT().Publish("booking.confirmed", gomock.Any()).DoAndReturn(func(_ string, msg any) error {
+		sent = msg
+		return nil
+	})
+	require.NoError(t, svc.ConfirmBooking(context.Background(), "b1"))
+	require.Equal(t, []string{"issue b1 e1"}, tickets.calls, "the email waits for the booking.confirmed consumer")
+
+	repo.EXPECT().Get("b1").Return(confirmed(), nil)
+	body, _ := json.Marshal(sent)
+	require.NoError(t, svc.HandleBookingConfirmed(context.Background(), body))
+
+	// A move replaces the tickets, which name their event
+	inTx(db)
+	repo.EXPECT().GetForUpdate(gomock.Nil(), "b1").Return(confirmed(), nil)
+	reserver.EXPECT().ReserveTx(gomock.Nil(), "e2", 2).Return(true, nil)
+	repo.EXPECT().UpdateEvent(gomock.Nil(), bookingID("b1"), "e2").Return(nil)
+	reserver.EXPECT().Release(gomock.Any(), "e1", 2).Return(nil)
+	_, err := svc.Move(context.Background(), "b1", "e2")
+	require.NoError(t, err)
+
+	repo.EXPECT().Get("b1").Return(confirmed(), nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b1"), booking.StatusRefunded).Return(nil)
+	publisher.EXPECT().Publish("booking.refunded", gomock.Any()).Return(nil)
+	reserver.EXPECT().Release(gomock.Any(), "e1", 2).Return(nil)
+	_, err = svc.Refund(context.Background(), "b1")
+	require.NoError(t, err)
+
+	repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", EventID: "e1", Quantity: 1, Status: booking.StatusConfirmed}, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b2"), booking.StatusCancelled).Return(nil)
+	reserver.EXPECT().Release(gomock.Any(), "e1", 1).Return(nil)
+	cache.EXPECT().Del(gomock.Any(), "booking:pending:b2").Return(nil)
+	require.NoError(t, svc.CancelBooking(context.Background(), "b2"))
+
+	require.Equal(t, []string{
+		"issue b1 e1", "notify b1 CONFIRMED",
+		"delist b1", "void b1", "issue b1 e2",
+		"delist b1", "void b1",
+		"delist b2", "void b2",
+	}, tickets.calls)
+}
+
+func TestSettle_LeavesFinishedBookingsAlone(t *testing.T) {
+	svc, repo, _, _, cache, _ := createTestService(t)
+	tickets := &ticketLog{}
+	svc.SetTickets(tickets)
+	svc.SetNotifier(tickets)
+
+	// A redelivered booking.created must not revive a refunded booking
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusRefunded}, nil)
+	cache.EXPECT().Del(gomock.Any(), "booking:pending:b1").Return(nil)
+	body, _ := json.Marshal(booking.BookingCreatedMessage{BookingID: "b1", EventID: "e1", Quantity: 2})
+	require.NoError(t, svc.HandleBookingCreated(context.Background(), body))
+
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", EventID: "e1", Quantity: 2, Status: booking.StatusCancelled}, nil)
+	require.NoError(t, svc.CancelBooking(context.Background(), "b1"))
+	require.Empty(t, tickets.calls)
+
+	// A booking refunded between read and update is read again and left alone
+	gomock.InOrder(
+		repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", EventID: "e1", Quantity: 1, Status: booking.StatusPending}, nil),
+		repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b2"), booking.StatusConfirmed).Return(booking.ErrInvalidTransition),
+		repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", EventID: "e1", Quantity: 1, Status: booking.StatusRefunded}, nil),
+	)
+	require.NoError(t, svc.ConfirmBooking(context.Background(), "b2"))
+	require.Empty(t, tickets.calls)
+
+	// A booking.confirmed for a booking refunded since sends no email
+	repo.EXPECT().Get("b2").Return(&booking.Booking{ID: "b2", EventID: "e1", Quantity: 1, Status: booking.StatusRefunded}, nil)
+	body, _ = json.Marshal(booking.BookingConfirmedMessage{BookingID: "b2", EventID: "e1"})
+	require.NoError(t, svc.HandleBookingConfirmed(context.Background(), body))
+	require.Empty(t, tickets.calls)
+}
+
+func TestAdminHandler_AuditsChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo, reserver, _, cache, db := createTestService(t)
+	dryRunDB(t, db)
+	auditRepo := mocks.NewMockAuditRepository(gomock.NewController(t))
+	h := booking.NewAdminHandler(svc, audit.NewService(auditRepo, nil, zap.NewNop()), zap.NewNop())
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(auth.CtxUserID, "admin-1"); c.Set(auth.CtxRole, "ADMIN") })
+	pass := func(c *gin.Context) {}
+	booking.RegisterAdminRoutes(r.Group("/admin"), h, pass, pass)
+
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/admin/bookings?status=LOST", nil).Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/admin/bookings?from=yesterday", nil).Code)
+
+	repo.EXPECT().Search(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, f booking.BookingFilter) ([]*booking.Booking, error) {
+		require.Equal(t, "jane@example.com", f.UserEmail)
+		require.Equal(t, booking.StatusConfirmed, f.Status)
+		require.Equal(t, 2024, f.From.Year())
+		require.Nil(t, f.To)
+		return nil, nil
+	})
+	w := send(http.MethodGet, "/admin/bookings?email=jane@example.com&status=confirmed&from=2024-01-01T00:00:00Z", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `[]`, w.Body.String())
+
+	// Cancel a confirmed booking with a reason
+	b := &booking.Booking{ID: "b1", EventID: "e1", Quantity: 1, Status: booking.StatusConfirmed}
+	repo.EXPECT().Get("b1").Return(b, nil)
+	repo.EXPECT().UpdateStatus(gomock.Any(), bookingID("b1"), booking.StatusCancelled).Return(nil)
+	reserver.EXPECT().Release(gomock.Any(), "e1", 1).Return(nil)
+	cache.EXPECT().Del(gomock.Any(), "booking:pending:b1").Return(nil)
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", EventID: "e1", Quantity: 1, Status: booking.StatusCancelled}, nil)
+	var entry *audit.Entry
+	auditRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(e *audit.Entry) error { entry = e; return nil })
+
+	w = send(http.MethodPost, "/admin/bookings/b1/cancel", booking.AdminActionRequest{Reason: "duplicate"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, w.Body.String(), `"status":"CANCELLED"`)
+	require.Equal(t, audit.ActionBookingCancel, entry.Action)
+	require.Equal(t, "b1", entry.TargetID)
+	require.Equal(t, "admin-1", *entry.ActorID)
+	require.Equal(t, audit.Changes{"status": {From: "CONFIRMED", To: "CANCELLED"}}, entry.Changes)
+	require.Equal(t, map[string]any{"reason": "duplicate"}, entry.Detail)
+
+	// A refused change is not audited
+	repo.EXPECT().Get("b1").Return(&booking.Booking{ID: "b1", Status: booking.StatusCancelled}, nil)
+	require.Equal(t, http.StatusConflict, send(http.MethodPost, "/admin/bookings/b1/confirm", nil).Code)
+
+	repo.EXPECT().Get("nope").Return(nil, gorm.ErrRecordNotFound)
+	require.Equal(t, http.StatusNotFound, send(http.MethodPost, "/admin/bookings/nope/refund", nil).Code)
+
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/admin/bookings/b1/move", booking.MoveBookingRequest{EventID: "not-a-uuid"}).Code)
+}
@@ -0,0 +1,189 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/event"
+	"ticket-booking/pkg/config"
+)
+
+var (
+	// ErrAttendeesClosed is returned when the booking is no longer active or
+	// the event is within the attendee cutoff
+	ErrAttendeesClosed = errors.New("attendee details can no longer be changed")
+	// ErrInvalidAttendee is returned for seats outside the booking, repeated
+	// seats and answers that do not match the event's attendee fields
+	ErrInvalidAttendee = errors.New("invalid attendee details")
+)
+
+// Events looks up the event a booking is for
+type Events interface {
+	Get(ctx context.Context, id string) (*event.Event, error)
+}
+
+// AttendeeService keeps who uses each seat of a booking. The booker fills
+// the details in until the attendee cutoff; organisers export them.
+type AttendeeService struct {
+	repo     AttendeeRepository
+	bookings BookingRepository
+	events   Events
+	cutoff   time.Duration
+	logger   *zap.Logger
+}
+
+func NewAttendeeService(r AttendeeRepository, bookings BookingRepository, events Events, cfg config.Booking, logger *zap.Logger) *AttendeeService {
+	return &AttendeeService{
+		repo:     r,
+		bookings: bookings,
+		events:   events,
+		cutoff:   time.Duration(cfg.AttendeeCutoffHours) * time.Hour,
+		logger:   logger,
+	}
+}
+
+// Booking loads a booking for access checks
+func (s *AttendeeService) Booking(ctx context.Context, id string) (*Booking, error) {
+	return s.bookings.Get(id)
+}
+
+// Seats returns one entry per seat of b; seats without details carry only
+// their number
+func (s *AttendeeService) Seats(ctx context.Context, b *Booking) ([]*Attendee, error) {
+	stored, err := s.repo.ListByBooking(ctx, b.ID)
+	if err != nil {
+		s.logger.Error("Failed to load attendees", zap.String("booking_id", b.ID), zap.Error(err))
+		return nil, err
+	}
+	seats := make([]*Attendee, b.Quantity)
+	for i := range seats {
+		seats[i] = &Attendee{BookingID: b.ID, Number: i + 1}
+	}
+	for _, a := range stored {
+		if a.Number >= 1 && a.Number <= b.Quantity {
+			seats[a.Number-1] = a
+		}
+	}
+	return seats, nil
+}
+
+// Save stores the details of the given seats of b, replacing what they
+// had; other seats keep theirs. Answers must use the keys of the event's
+// attendee fields and fill in every required one.
+func (s *AttendeeService) Save(ctx context.Context, b *Booking, in []AttendeeInput) ([]*Attendee, error) {
+	if b.Status != StatusPending && b.Status != StatusConfirmed {
+		return nil, ErrAttendeesClosed
+	}
+	e, err := s.events.Get(ctx, b.EventID)
+	if err != nil {
+		s.logger.Error("Failed to load event for attendees", zap.String("booking_id", b.ID), zap.Error(err))
+		return nil, err
+	}
+	if !time.Now().Before(e.StartsAt.Add(-s.cutoff)) {
+		return nil, ErrAttendeesClosed
+	}
+
+	now := time.Now()
+	seen := make(map[int]bool, len(in))
+	attendees := make([]*Attendee, 0, len(in))
+	for _, a := range in {
+		if a.Number < 1 || a.Number > b.Quantity {
+			return nil, fmt.Errorf("%w: booking has no seat %d", ErrInvalidAttendee, a.Number)
+		}
+		if seen[a.Number] {
+			return nil, fmt.Errorf("%w: seat %d given twice", ErrInvalidAttendee, a.Number)
+		}
+		seen[a.Number] = true
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: seat %d needs a name", ErrInvalidAttendee, a.Number)
+		}
+		fields, err := answers(e.AttendeeFields, a.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("%w: seat %d: %s", ErrInvalidAttendee, a.Number, err)
+		}
+		attendees = append(attendees, &Attendee{
+			BookingID: b.ID,
+			Number:    a.Number,
+			Name:      name,
+			Email:     strings.ToLower(strings.TrimSpace(a.Email)),
+			Fields:    fields,
+			UpdatedAt: now,
+		})
+	}
+	if err := s.repo.Save(ctx, attendees); err != nil {
+		s.logger.Error("Failed to save attendees", zap.String("booking_id", b.ID), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("Attendees saved", zap.String("booking_id", b.ID), zap.Int("seats", len(attendees)))
+	return s.Seats(ctx, b)
+}
+
+// answers checks given against the event's fields and drops blank answers
+func answers(fields []event.AttendeeField, given map[string]string) (map[string]string, error) {
+	known := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		known[f.Key] = true
+	}
+	out := make(map[string]string, len(given))
+	for k, v := range given {
+		if !known[k] {
+			return nil, fmt.Errorf("unknown field %q", k)
+		}
+		if v = strings.TrimSpace(v); v != "" {
+			out[k] = v
+		}
+	}
+	for _, f := range fields {
+		if f.Required && out[f.Key] == "" {
+			return nil, fmt.Errorf("%s is required", f.Label)
+		}
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// Export lists every seat of the event's CONFIRMED bookings, oldest booking
+// first, with whatever details were given
+func (s *AttendeeService) Export(ctx context.Context, eventID string) (*event.Event, []AttendeeRow, error) {
+	e, err := s.events.Get(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookings, err := s.bookings.ListConfirmedByEvent(ctx, eventID)
+	if err != nil {
+		s.logger.Error("Failed to load bookings for attendee export", zap.String("event_id", eventID), zap.Error(err))
+		return nil, nil, err
+	}
+	stored, err := s.repo.ListByEvent(ctx, eventID)
+	if err != nil {
+		s.logger.Error("Failed to load attendees for export", zap.String("event_id", eventID), zap.Error(err))
+		return nil, nil, err
+	}
+	details := make(map[string]*Attendee, len(stored))
+	for _, a := range stored {
+		details[a.BookingID+"/"+strconv.Itoa(a.Number)] = a
+	}
+
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].CreatedAt.Before(bookings[j].CreatedAt) })
+	var rows []AttendeeRow
+	for _, b := range bookings {
+		for n := 1; n <= b.Quantity; n++ {
+			row := AttendeeRow{BookingID: b.ID, Number: n, UserID: b.UserID}
+			if a := details[b.ID+"/"+strconv.Itoa(n)]; a != nil {
+				row.Name, row.Email, row.Fields = a.Name, a.Email, a.Fields
+			}
+			rows = append(rows, row)
+		}
+	}
+	return e, rows, nil
+}
@@ -0,0 +1,170 @@
+package booking
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+)
+
+type AttendeeHandler struct {
+	svc    *AttendeeService
+	logger *zap.Logger
+}
+
+func NewAttendeeHandler(s *AttendeeService, logger *zap.Logger) *AttendeeHandler {
+	return &AttendeeHandler{svc: s, logger: logger}
+}
+
+// booking loads the booking in :id if the caller may see it; edit further
+// limits it to the booker
+func (h *AttendeeHandler) booking(c *gin.Context, edit bool) (*Booking, bool) {
+	userID := c.GetString(auth.CtxUserID)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return nil, false
+	}
+	b, err := h.svc.Booking(c, c.Param("id"))
+	if err != nil || !CanView(b, userID, c.GetStringSlice(auth.CtxPermissions)) || (edit && b.UserID != userID) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// List godoc
+// @Summary List attendees
+// @Description Attendee details of every seat of a booking; seats without details have only their number (booking owner or bookings:read)
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Success 200 {array} Attendee
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/attendees [get]
+func (h *AttendeeHandler) List(c *gin.Context) {
+	b, ok := h.booking(c, false)
+	if !ok {
+		return
+	}
+	seats, err := h.svc.Seats(c, b)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, seats)
+}
+
+// Save godoc
+// @Summary Set attendee details
+// @Description Name, optional email and the event's custom fields for some seats of the caller's booking. Seats left out keep their details. Closes when the booking is cancelled or refunded, and booking.attendee_cutoff_hours before the event starts.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param input body SaveAttendeesRequest true "Attendee details"
+// @Success 200 {array} Attendee
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Booking inactive or past the attendee cutoff"
+// @Security BearerAuth
+// @Router /bookings/{id}/attendees [put]
+func (h *AttendeeHandler) Save(c *gin.Context) {
+	var req SaveAttendeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	b, ok := h.booking(c, true)
+	if !ok {
+		return
+	}
+	seats, err := h.svc.Save(c, b, req.Attendees)
+	switch {
+	case errors.Is(err, ErrInvalidAttendee):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrAttendeesClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+	default:
+		c.JSON(http.StatusOK, seats)
+	}
+}
+
+// Export godoc
+// @Summary Export the attendee list
+// @Description Every seat of an owned event's CONFIRMED bookings with its attendee details, as JSON or CSV with one column per custom field
+// @Tags organizer
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {array} AttendeeRow
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Event owned by another organizer"
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /organizer/events/{id}/attendees [get]
+func (h *AttendeeHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "format must be json or csv"})
+		return
+	}
+	e, rows, err := h.svc.Export(c, c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "event not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	if format == "json" {
+		if rows == nil {
+			rows = []AttendeeRow{}
+		}
+		c.JSON(http.StatusOK, rows)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="attendees-%s.csv"`, e.ID))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	header := []string{"booking_id", "seat", "booked_by", "name", "email"}
+	for _, f := range e.AttendeeFields {
+		header = append(header, f.Key)
+	}
+	_ = w.Write(header)
+	for _, r := range rows {
+		record := []string{r.BookingID, strconv.Itoa(r.Number), r.UserID, csvCell(r.Name), csvCell(r.Email)}
+		for _, f := range e.AttendeeFields {
+			record = append(record, csvCell(r.Fields[f.Key]))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.Error("Attendee export failed", zap.String("event_id", e.ID), zap.Error(err))
+	}
+}
+
+// csvCell defuses text typed in by users before it goes into a CSV export:
+// a cell starting with =, +, -, @, tab or carriage return would run as a
+// formula in spreadsheet apps, so it is prefixed with a quote
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,189 @@
+package booking_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/pkg/config"
+)
+
+// memAttendees keeps attendees by booking and seat
+type memAttendees struct {
+	byKey    map[string]*booking.Attendee
+	bookings map[string]*booking.Booking
+}
+
+func key(bookingID string, n int) string { return bookingID + "/" + string(rune('0'+n)) }
+
+func (m *memAttendees) ListByBooking(_ context.Context, bookingID string) ([]*booking.Attendee, error) {
+	var out []*booking.Attendee
+	for n := 1; n <= 9; n++ {
+		if a := m.byKey[key(bookingID, n)]; a != nil {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (m *memAttendees) ListByEvent(ctx context.Context, eventID string) ([]*booking.Attendee, error) {
+	var out []*booking.Attendee
+	for _, b := range m.bookings {
+		if b.EventID == eventID && b.Status == booking.StatusConfirmed {
+			as, _ := m.ListByBooking(ctx, b.ID)
+			out = append(out, as...)
+		}
+	}
+	return out, nil
+}
+
+func (m *memAttendees) Save(_ context.Context, attendees []*booking.Attendee) error {
+	for _, a := range attendees {
+		m.byKey[key(a.BookingID, a.Number)] = a
+	}
+	return nil
+}
+
+type eventMap map[string]*event.Event
+
+func (m eventMap) Get(_ context.Context, id string) (*event.Event, error) {
+	if e, ok := m[id]; ok {
+		return e, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// attendeeSetup gives u1 a confirmed booking of three seats for an event in
+// two days that asks for a required company and an optional diet
+func attendeeSetup(t *testing.T) (*booking.AttendeeService, *event.Event, map[string]*booking.Booking) {
+	repo := mocks.NewMockBookingRepository(gomock.NewController(t))
+	e := &event.Event{ID: "e1", StartsAt: time.Now().Add(48 * time.Hour), AttendeeFields: []event.AttendeeField{
+		{Key: "company", Label: "Company", Required: true},
+		{Key: "diet", Label: "Dietary needs"},
+	}}
+	now := time.Now()
+	bookings := map[string]*booking.Booking{
+		"b1": {ID: "b1", UserID: "u1", EventID: "e1", Quantity: 3, Status: booking.StatusConfirmed, CreatedAt: now.Add(-time.Hour)},
+		"b2": {ID: "b2", UserID: "u2", EventID: "e1", Quantity: 1, Status: booking.StatusConfirmed, CreatedAt: now},
+		"b3": {ID: "b3", UserID: "u1", EventID: "e1", Quantity: 1, Status: booking.StatusCancelled, CreatedAt: now},
+	}
+	repo.EXPECT().Get(gomock.Any()).DoAndReturn(func(id string) (*booking.Booking, error) {
+		if b, ok := bookings[id]; ok {
+			return b, nil
+		}
+		return nil, errors.New("not found")
+	}).AnyTimes()
+	repo.EXPECT().ListConfirmedByEvent(gomock.Any(), "e1").Return([]*booking.Booking{bookings["b2"], bookings["b1"]}, nil).AnyTimes()
+
+	attendees := &memAttendees{byKey: map[string]*booking.Attendee{}, bookings: bookings}
+	svc := booking.NewAttendeeService(attendees, repo, eventMap{"e1": e}, config.Booking{AttendeeCutoffHours: 2}, zap.NewNop())
+	return svc, e, bookings
+}
+
+func TestAttendees_SaveValidatesAgainstEventFields(t *testing.T) {
+	svc, e, bookings := attendeeSetup(t)
+	ctx := context.Background()
+	b := bookings["b1"]
+
+	seats, err := svc.Seats(ctx, b)
+	require.NoError(t, err)
+	require.Len(t, seats, 3)
+	require.Empty(t, seats[0].Name)
+
+	for _, in := range [][]booking.AttendeeInput{
+		{{Number: 4, Name: "Jane Doe", Fields: map[string]string{"company": "Acme"}}},
+		{{Number: 1, Name: "Jane Doe", Fields: map[string]string{"company": "Acme"}}, {Number: 1, Name: "John Roe", Fields: map[string]string{"company": "Acme"}}},
+		{{Number: 1, Name: "  ", Fields: map[string]string{"company": "Acme"}}},
+		{{Number: 1, Name: "Jane Doe", Fields: map[string]string{"company": " "}}},
+		{{Number: 1, Name: "Jane Doe", Fields: map[string]string{"company": "Acme", "shoe_size": "42"}}},
+	} {
+		_, err = svc.Save(ctx, b, in)
+		require.ErrorIs(t, err, booking.ErrInvalidAttendee)
+	}
+
+	seats, err = svc.Save(ctx, b, []booking.AttendeeInput{
+		{Number: 2, Name: " Jane Doe ", Email: "Jane@Example.com", Fields: map[string]string{"company": "Acme", "diet": ""}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Jane Doe", seats[1].Name)
+	require.Equal(t, "jane@example.com", seats[1].Email)
+	require.Equal(t, map[string]string{"company": "Acme"}, seats[1].Fields)
+	require.Empty(t, seats[0].Name, "seats left out keep their details")
+
+	_, err = svc.Save(ctx, bookings["b3"], []booking.AttendeeInput{{Number: 1, Name: "Jane Doe"}})
+	require.ErrorIs(t, err, booking.ErrAttendeesClosed)
+	e.StartsAt = time.Now().Add(time.Hour)
+	_, err = svc.Save(ctx, b, []booking.AttendeeInput{{Number: 1, Name: "Jane Doe", Fields: map[string]string{"company": "Acme"}}})
+	require.ErrorIs(t, err, booking.ErrAttendeesClosed)
+}
+
+func TestAttendees_ExportListsEverySeat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _, _ := attendeeSetup(t)
+	h := booking.NewAttendeeHandler(svc, zap.NewNop())
+
+	do := func(method, path, userID string, perms []string, body any) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(auth.CtxUserID, userID)
+			c.Set(auth.CtxPermissions, perms)
+		})
+		booking.RegisterAttendeeRoutes(r.Group(""), h)
+		booking.RegisterAttendeeExportRoutes(r.Group("/organizer"), h, func(*gin.Context) {})
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w
+	}
+
+	save := map[string]any{"attendees": []map[string]any{
+		{"number": 1, "name": "Jane Doe", "fields": map[string]string{"company": "Acme", "diet": "vegan"}},
+		{"number": 3, "name": "=HYPERLINK(\"http://x\")", "fields": map[string]string{"company": "@SUM(A1)"}},
+	}}
+	require.Equal(t, http.StatusNotFound, do(http.MethodPut, "/bookings/b1/attendees", "u2", nil, save).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodPut, "/bookings/b1/attendees", "staff", []string{auth.PermBookingsRead}, save).Code,
+		"only the booker edits")
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPut, "/bookings/b1/attendees", "u1", nil, map[string]any{"attendees": []any{}}).Code)
+	w := do(http.MethodPut, "/bookings/b1/attendees", "u1", nil, save)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/bookings/b1/attendees", "staff", []string{auth.PermBookingsRead}, nil).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/bookings/b1/attendees", "u2", nil, nil).Code)
+
+	w = do(http.MethodGet, "/organizer/events/e1/attendees", "org", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var rows []booking.AttendeeRow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rows))
+	require.Len(t, rows, 4, "three seats of b1 and one of b2; b3 was cancelled")
+	require.Equal(t, booking.AttendeeRow{BookingID: "b1", Number: 1, UserID: "u1", Name: "Jane Doe",
+		Fields: map[string]string{"company": "Acme", "diet": "vegan"}}, rows[0])
+	require.Equal(t, "b2", rows[3].BookingID)
+
+	w = do(http.MethodGet, "/organizer/events/e1/attendees?format=csv", "org", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"booking_id", "seat", "booked_by", "name", "email", "company", "diet"}, records[0])
+	require.Equal(t, []string{"b1", "1", "u1", "Jane Doe", "", "Acme", "vegan"}, records[1])
+	require.Equal(t, []string{"b1", "2", "u1", "", "", "", ""}, records[2])
+	require.Equal(t, []string{"b1", "3", "u1", `'=HYPERLINK("http://x")`, "", "'@SUM(A1)", ""}, records[3],
+		"cells that would run as formulas are quoted")
+	require.Len(t, records, 5)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/organizer/events/e1/attendees?format=xml", "org", nil, nil).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/organizer/events/nope/attendees", "org", nil, nil).Code)
+}
@@ -2,8 +2,9 @@ package booking
 
 // CreateBookingRequest input for creating a booking
 type CreateBookingRequest struct {
-	EventID  string `json:"event_id" binding:"required,uuid4" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Quantity int    `json:"quantity" binding:"required,min=1,max=10" example:"2"`
+	EventID  string         `json:"event_id" binding:"required,uuid4" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Quantity int            `json:"quantity" binding:"required,min=1,max=10" example:"2"`
+	Answers  map[string]any `json:"answers" binding:"max=30"` // Answers to the event's registration questions, by key
 }
 
 // CreateBookingResponse output after creating a booking
@@ -14,14 +15,49 @@ type CreateBookingResponse struct {
 
 // BookingResponse represents a booking record
 type BookingResponse struct {
-	ID       string `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	EventID  string `json:"event_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	UserID   string `json:"user_id" example:"42e1d21e-1111-2222-3333-444455556666"`
-	Quantity int    `json:"quantity" example:"2"`
-	Status   Status `json:"status" example:"CONFIRMED"`
+	ID       string         `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EventID  string         `json:"event_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID   string         `json:"user_id" example:"42e1d21e-1111-2222-3333-444455556666"`
+	Quantity int            `json:"quantity" example:"2"`
+	Status   Status         `json:"status" example:"CONFIRMED"`
+	Answers  map[string]any `json:"answers,omitempty"`
 }
 
 // ErrorResponse standard error model
 type ErrorResponse struct {
 	Error string `json:"error" example:"invalid request"`
 }
+
+// AdminActionRequest optional input for an admin status change
+type AdminActionRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"Customer paid by bank transfer"`
+}
+
+// MoveBookingRequest input for moving a booking to another event
+type MoveBookingRequest struct {
+	EventID string `json:"event_id" binding:"required,uuid4" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Reason  string `json:"reason" binding:"max=500" example:"Show rescheduled"`
+}
+
+// AttendeeInput details of the person using one seat
+type AttendeeInput struct {
+	Number int               `json:"number" binding:"required,min=1" example:"1"`
+	Name   string            `json:"name" binding:"required,max=100" example:"Jane Doe"`
+	Email  string            `json:"email" binding:"omitempty,email,max=254" example:"jane@example.com"`
+	Fields map[string]string `json:"fields" binding:"max=20,dive,keys,max=40,endkeys,max=200"` // Answers keyed by the event's attendee field keys
+}
+
+// SaveAttendeesRequest input for setting attendee details; seats left out keep theirs
+type SaveAttendeesRequest struct {
+	Attendees []AttendeeInput `json:"attendees" binding:"required,min=1,max=10,dive"`
+}
+
+// AttendeeRow one seat of the attendee list export
+type AttendeeRow struct {
+	BookingID string            `json:"booking_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Number    int               `json:"number" example:"1"`
+	UserID    string            `json:"user_id" example:"42e1d21e-1111-2222-3333-444455556666"` // Who booked the seat
+	Name      string            `json:"name" example:"Jane Doe"`                                // Empty until the booker gives details
+	Email     string            `json:"email,omitempty" example:"jane@example.com"`
+	Fields    map[string]string `json:"fields,omitempty"`
+}
@@ -1,13 +1,20 @@
 package booking
 
 import (
+	"encoding/csv"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"ticket-booking/internal/auth"
+	"ticket-booking/internal/event"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"errors"
+	"gorm.io/gorm"
 )
 
 type Handler struct {
@@ -27,7 +34,7 @@ func NewHandler(s BookingService, logger *zap.Logger) *Handler {
 // @Produce json
 // @Param input body CreateBookingRequest true "Booking request"
 // @Success 201 {object} CreateBookingResponse
-// @Failure 400 {object} ErrorResponse "Invalid request data"
+// @Failure 400 {object} ErrorResponse "Invalid request data or answers"
 // @Failure 409 {object} ErrorResponse "Conflict (e.g., overbooking)"
 // @Failure 500 {object} ErrorResponse "Internal server error"
 // @Security BearerAuth
@@ -45,8 +52,12 @@ func (h *Handler) Create(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
 		return
 	}
-	id, err := h.svc.CreateBooking(c, userID, req.EventID, req.Quantity)
+	id, err := h.svc.CreateBooking(c, userID, req.EventID, req.Quantity, req.Answers)
 	if err != nil {
+		if errors.Is(err, event.ErrInvalidAnswers) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		if errors.Is(err, ErrNotEnoughTickets) {
 			h.logger.Warn("Not enough tickets", zap.String("user_id", userID), zap.String("event_id", req.EventID), zap.Int("quantity", req.Quantity))
 			c.JSON(http.StatusConflict, ErrorResponse{Error: "not enough tickets"})
@@ -88,3 +99,118 @@ func (h *Handler) Get(c *gin.Context) {
 	h.logger.Info("Booking retrieved", zap.String("booking_id", id), zap.String("user_id", userID), zap.String("event_id", b.EventID))
 	c.JSON(http.StatusOK, b)
 }
+
+// ListByEvent godoc
+// @Summary List event bookings
+// @Description List bookings of an event owned by the calling organizer (admins may list any event)
+// @Tags organizer
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param status query string false "Filter by status: PENDING, CONFIRMED, CANCELLED or REFUNDED"
+// @Param limit query int false "Max items to return (default 50, max 200)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} BookingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Event owned by another organizer"
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /organizer/events/{id}/bookings [get]
+func (h *Handler) ListByEvent(c *gin.Context) {
+	eventID := c.Param("id")
+	status := Status(strings.ToUpper(c.Query("status")))
+	switch status {
+	case "", StatusPending, StatusConfirmed, StatusCancelled, StatusRefunded:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid status"})
+		return
+	}
+	limit, offset := pagination(c)
+	bookings, err := h.svc.ListByEvent(c, eventID, status, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	out := make([]BookingResponse, 0, len(bookings))
+	for _, b := range bookings {
+		out = append(out, BookingResponse{ID: b.ID, EventID: b.EventID, UserID: b.UserID, Quantity: b.Quantity, Status: b.Status, Answers: b.Answers})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Registrations godoc
+// @Summary Export registration answers
+// @Description Answers to the registration questions of every CONFIRMED booking of an owned event, oldest first, as JSON or CSV with one column per question
+// @Tags organizer
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Event ID"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {array} BookingResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Event owned by another organizer"
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /organizer/events/{id}/registrations [get]
+func (h *Handler) Registrations(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "format must be json or csv"})
+		return
+	}
+	ev, bookings, err := h.svc.Registrations(c, c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "event not found"})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to export registrations", zap.String("event_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		return
+	}
+	if format == "json" {
+		out := make([]BookingResponse, 0, len(bookings))
+		for _, b := range bookings {
+			out = append(out, BookingResponse{ID: b.ID, EventID: b.EventID, UserID: b.UserID, Quantity: b.Quantity, Status: b.Status, Answers: b.Answers})
+		}
+		c.JSON(http.StatusOK, out)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="registrations-%s.csv"`, ev.ID))
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	header := []string{"booking_id", "booked_by", "quantity", "created_at"}
+	for _, q := range ev.Questions {
+		header = append(header, q.Key)
+	}
+	_ = w.Write(header)
+	for _, b := range bookings {
+		record := []string{csvCell(b.ID), csvCell(b.UserID), strconv.Itoa(b.Quantity), b.CreatedAt.UTC().Format(time.RFC3339)}
+		for _, q := range ev.Questions {
+			cell := ""
+			if v, ok := b.Answers[q.Key]; ok {
+				cell = csvCell(fmt.Sprint(v))
+			}
+			record = append(record, cell)
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.Error("Registration export failed", zap.String("event_id", ev.ID), zap.Error(err))
+	}
+}
+
+// pagination reads limit and offset, defaulting to 50 and capping at 200
+func pagination(c *gin.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -2,10 +2,15 @@
 // for the ticket booking system.
 package booking
 
-import "time"
+import (
+	"time"
+
+	"ticket-booking/internal/auth"
+)
 
 // Status represents the lifecycle states of a booking.
-// Bookings transition: PENDING -> CONFIRMED (on payment) or CANCELLED (on timeout/failure)
+// Bookings transition: PENDING -> CONFIRMED (on payment) or CANCELLED (on timeout/failure);
+// support staff may also cancel or refund a CONFIRMED booking.
 type Status string
 
 const (
@@ -15,17 +20,54 @@ const (
 	StatusConfirmed Status = "CONFIRMED"
 	// StatusCancelled indicates booking was cancelled due to payment failure or timeout
 	StatusCancelled Status = "CANCELLED"
+	// StatusRefunded indicates a confirmed booking was paid back and its seats released
+	StatusRefunded Status = "REFUNDED"
 )
 
+// transitions lists the statuses each status may move to; CANCELLED and
+// REFUNDED are final
+var transitions = map[Status][]Status{
+	StatusPending:   {StatusConfirmed, StatusCancelled},
+	StatusConfirmed: {StatusCancelled, StatusRefunded},
+}
+
+// CanTransition reports whether a booking in status from may move to status to
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
 // Booking represents a ticket reservation for an event.
 // Captures pricing at booking time to handle price changes gracefully.
 type Booking struct {
-	ID             string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
-	UserID         string    `gorm:"type:uuid;not null" json:"user_id"`                        // Foreign key to users table
-	EventID        string    `gorm:"type:uuid;not null" json:"event_id"`                       // Foreign key to events table
-	Quantity       int       `gorm:"not null" json:"quantity"`                                 // Number of tickets booked (must be > 0)
-	UnitPriceCents int64     `gorm:"column:unit_price_cents;not null" json:"unit_price_cents"` // Price per ticket in cents (captured at booking time)
-	Status         Status    `gorm:"type:text;not null" json:"status"`                         // Current booking state
-	CreatedAt      time.Time `json:"created_at"`                                               // When booking was created
-	UpdatedAt      time.Time `json:"updated_at"`                                               // Last status change timestamp
+	ID             string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID         string         `gorm:"type:uuid;not null" json:"user_id"`                        // Foreign key to users table
+	EventID        string         `gorm:"type:uuid;not null" json:"event_id"`                       // Foreign key to events table
+	Quantity       int            `gorm:"not null" json:"quantity"`                                 // Number of tickets booked (must be > 0)
+	UnitPriceCents int64          `gorm:"column:unit_price_cents;not null" json:"unit_price_cents"` // Price per ticket in cents (captured at booking time)
+	Status         Status         `gorm:"type:text;not null" json:"status"`                         // Current booking state
+	Answers        map[string]any `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`      // Answers to the event's registration questions
+	CreatedAt      time.Time      `json:"created_at"`                                               // When booking was created
+	UpdatedAt      time.Time      `json:"updated_at"`                                               // Last status change timestamp
+}
+
+// CanView reports whether the caller may see b and its documents: its owner
+// may, and so may anyone with bookings:read.
+func CanView(b *Booking, userID string, perms []string) bool {
+	return (userID != "" && b.UserID == userID) || auth.HasPermission(perms, auth.PermBookingsRead)
+}
+
+// Attendee holds who will use one seat of a booking, for badges and ID
+// checks at the door. Seats without details have no row.
+type Attendee struct {
+	BookingID string            `gorm:"type:uuid;primaryKey" json:"booking_id"`
+	Number    int               `gorm:"primaryKey" json:"number"`                           // Seat within the booking, 1 to Quantity
+	Name      string            `gorm:"type:text;not null" json:"name"`                     // Full name as printed on the badge
+	Email     string            `gorm:"type:text" json:"email,omitempty"`                   // Optional contact address
+	Fields    map[string]string `gorm:"type:jsonb;serializer:json" json:"fields,omitempty"` // Answers to the event's custom attendee fields
+	UpdatedAt time.Time         `json:"updated_at"`                                         // Last edit
 }
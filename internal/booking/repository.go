@@ -4,14 +4,26 @@ import (
 	"context"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookingRepository interface {
 	Create(tx *gorm.DB, b *Booking) error
 	Get(id string) (*Booking, error)
-	UpdateStatus(ctx context.Context, id string, status Status) error
+	// GetForUpdate loads a booking and locks its row until tx ends
+	GetForUpdate(tx *gorm.DB, id string) (*Booking, error)
+	// UpdateStatus moves b to status. It fails with ErrInvalidTransition,
+	// changing nothing, when the booking's status or event changed since b
+	// was read.
+	UpdateStatus(ctx context.Context, b *Booking, status Status) error
 	ListConfirmedByEvent(ctx context.Context, eventID string) ([]*Booking, error)
 	ListPendingOlderThan(ctx context.Context, cutoff string) ([]*Booking, error)
+	ListByEvent(ctx context.Context, eventID string, status Status, limit, offset int) ([]*Booking, error)
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]*Booking, error)
+	Search(ctx context.Context, f BookingFilter) ([]*Booking, error)
+	// UpdateEvent points b at another event within tx, under the same
+	// condition as UpdateStatus
+	UpdateEvent(tx *gorm.DB, b *Booking, eventID string) error
 }
 
 type repo struct{ db *gorm.DB }
@@ -30,12 +42,18 @@ func (r *repo) Get(id string) (*Booking, error) {
 	return &b, nil
 }
 
-// UpdateStatus sets booking status to any of [Pending, Confirmed, Cancelled]
-func (r *repo) UpdateStatus(ctx context.Context, id string, status Status) error {
-	return r.db.WithContext(ctx).
-		Model(&Booking{}).
-		Where("id = ?", id).
-		Update("status", status).Error
+func (r *repo) GetForUpdate(tx *gorm.DB, id string) (*Booking, error) {
+	var b Booking
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&b, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// UpdateStatus sets booking status to any of [Pending, Confirmed, Cancelled, Refunded]
+// if the booking is still as b was read
+func (r *repo) UpdateStatus(ctx context.Context, b *Booking, status Status) error {
+	return unchanged(r.db.WithContext(ctx), b, "status", status)
 }
 
 // ListConfirmedByEvent returns all confirmed bookings for a specific event
@@ -59,3 +77,124 @@ func (r *repo) ListPendingOlderThan(ctx context.Context, cutoff string) ([]*Book
 	}
 	return bookings, nil
 }
+
+// ListByEvent returns an event's bookings, newest first, optionally filtered by status
+func (r *repo) ListByEvent(ctx context.Context, eventID string, status Status, limit, offset int) ([]*Booking, error) {
+	var bookings []*Booking
+	q := r.db.WithContext(ctx).Where("event_id = ?", eventID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	if err := q.Order("created_at desc, id asc").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// ListByUser returns a user's bookings in every state, newest first
+func (r *repo) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*Booking, error) {
+	var bookings []*Booking
+	q := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	if err := q.Order("created_at desc, id asc").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// Search returns bookings matching f, newest first
+func (r *repo) Search(ctx context.Context, f BookingFilter) ([]*Booking, error) {
+	var bookings []*Booking
+	q := r.db.WithContext(ctx).Model(&Booking{}).Select("bookings.*")
+	if f.UserEmail != "" {
+		q = q.Joins("JOIN users ON users.id = bookings.user_id").
+			Where("LOWER(users.email) = LOWER(?)", f.UserEmail)
+	}
+	if f.EventID != "" {
+		q = q.Where("bookings.event_id = ?", f.EventID)
+	}
+	if f.Status != "" {
+		q = q.Where("bookings.status = ?", f.Status)
+	}
+	if f.From != nil {
+		q = q.Where("bookings.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("bookings.created_at < ?", *f.To)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.Offset > 0 {
+		q = q.Offset(f.Offset)
+	}
+	if err := q.Order("bookings.created_at desc, bookings.id asc").Find(&bookings).Error; err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// UpdateEvent points a booking at another event within tx
+func (r *repo) UpdateEvent(tx *gorm.DB, b *Booking, eventID string) error {
+	return unchanged(tx, b, "event_id", eventID)
+}
+
+// unchanged sets column to value on b's row while its status and event are
+// still those b was read with
+func unchanged(db *gorm.DB, b *Booking, column string, value any) error {
+	res := db.Model(&Booking{}).Where("id = ? AND status = ? AND event_id = ?", b.ID, b.Status, b.EventID).
+		Update(column, value)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return ErrInvalidTransition
+	}
+	return nil
+}
+
+// AttendeeRepository stores the per-seat attendee details of bookings
+type AttendeeRepository interface {
+	// ListByBooking returns the attendees of a booking by seat number
+	ListByBooking(ctx context.Context, bookingID string) ([]*Attendee, error)
+	// ListByEvent returns the attendees of an event's CONFIRMED bookings,
+	// ordered by booking and seat number
+	ListByEvent(ctx context.Context, eventID string) ([]*Attendee, error)
+	// Save creates the given seats or replaces their details
+	Save(ctx context.Context, attendees []*Attendee) error
+}
+
+type attendeeRepo struct{ db *gorm.DB }
+
+func NewAttendeeRepository(db *gorm.DB) AttendeeRepository { return &attendeeRepo{db} }
+
+func (r *attendeeRepo) ListByBooking(ctx context.Context, bookingID string) ([]*Attendee, error) {
+	var out []*Attendee
+	return out, r.db.WithContext(ctx).Where("booking_id = ?", bookingID).Order("number asc").Find(&out).Error
+}
+
+func (r *attendeeRepo) ListByEvent(ctx context.Context, eventID string) ([]*Attendee, error) {
+	var out []*Attendee
+	return out, r.db.WithContext(ctx).Select("attendees.*").
+		Joins("JOIN bookings ON bookings.id = attendees.booking_id").
+		Where("bookings.event_id = ? AND bookings.status = ?", eventID, StatusConfirmed).
+		Order("attendees.booking_id asc, attendees.number asc").Find(&out).Error
+}
+
+func (r *attendeeRepo) Save(ctx context.Context, attendees []*Attendee) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "booking_id"}, {Name: "number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "email", "fields", "updated_at"}),
+	}).Create(&attendees).Error
+}
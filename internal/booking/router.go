@@ -6,3 +6,35 @@ func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
 	r.POST("/bookings", h.Create)
 	r.GET("/bookings/:id", h.Get)
 }
+
+// RegisterOrganizerRoutes exposes an event's bookings and registration
+// answers; ownEvent must reject callers who may not manage the event
+// identified by :id.
+func RegisterOrganizerRoutes(r *gin.RouterGroup, h *Handler, ownEvent gin.HandlerFunc) {
+	r.GET("/events/:id/bookings", ownEvent, h.ListByEvent)
+	r.GET("/events/:id/registrations", ownEvent, h.Registrations)
+}
+
+// RegisterAttendeeRoutes exposes per-seat attendee details to bookers; r must
+// require authentication
+func RegisterAttendeeRoutes(r *gin.RouterGroup, h *AttendeeHandler) {
+	r.GET("/bookings/:id/attendees", h.List)
+	r.PUT("/bookings/:id/attendees", h.Save)
+}
+
+// RegisterAttendeeExportRoutes exposes an event's attendee list; ownEvent must
+// reject callers who may not manage the event identified by :id.
+func RegisterAttendeeExportRoutes(r *gin.RouterGroup, h *AttendeeHandler, ownEvent gin.HandlerFunc) {
+	r.GET("/events/:id/attendees", ownEvent, h.Export)
+}
+
+// RegisterAdminRoutes exposes the booking console. r must require
+// bookings:read; manage and refund guard the status changes on top of it.
+func RegisterAdminRoutes(r *gin.RouterGroup, h *AdminHandler, manage, refund gin.HandlerFunc) {
+	r.GET("/bookings", h.List)
+	r.GET("/bookings/:id", h.Get)
+	r.POST("/bookings/:id/confirm", manage, h.Confirm)
+	r.POST("/bookings/:id/cancel", manage, h.Cancel)
+	r.POST("/bookings/:id/move", manage, h.Move)
+	r.POST("/bookings/:id/refund", refund, h.Refund)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"ticket-booking/internal/database"
@@ -37,21 +38,68 @@ type Publisher interface {
 	Publish(topic string, v interface{}) error
 }
 
+// TicketIssuer creates and invalidates the door tickets of bookings.
+// Failures are logged and never fail the status change that triggered them.
+type TicketIssuer interface {
+	// Issue creates the tickets of a confirmed booking; repeat calls are no-ops
+	Issue(ctx context.Context, b *Booking) error
+	// Void invalidates every valid ticket of a booking
+	Void(ctx context.Context, bookingID string) error
+}
+
+type noopTickets struct{}
+
+func (noopTickets) Issue(context.Context, *Booking) error { return nil }
+func (noopTickets) Void(context.Context, string) error    { return nil }
+
+// Notifier tells customers about their bookings. It runs from the
+// booking.confirmed consumer, off the confirmation path, so rendering and
+// sending never hold up or fail the status change that triggered them.
+type Notifier interface {
+	// BookingConfirmed runs once a booking is CONFIRMED and its tickets issued
+	BookingConfirmed(ctx context.Context, b *Booking) error
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) BookingConfirmed(context.Context, *Booking) error { return nil }
+
+// Resale takes a booking's tickets off the resale market. Failures are
+// logged and never fail the status change that triggered them.
+type Resale interface {
+	// RemoveListings withdraws the open listings of a booking whose tickets were voided
+	RemoveListings(ctx context.Context, bookingID string) error
+	// SoldTickets returns how many tickets of a booking were resold to other users
+	SoldTickets(ctx context.Context, bookingID string) (int64, error)
+}
+
+type noopResale struct{}
+
+func (noopResale) RemoveListings(context.Context, string) error       { return nil }
+func (noopResale) SoldTickets(context.Context, string) (int64, error) { return 0, nil }
+
 // BookingService defines the core booking business logic interface.
 // Handles the complete booking lifecycle: creation, confirmation, cancellation.
 // Ensures data consistency through database transactions and handles concurrency.
 type BookingService interface {
 	// CreateBooking creates a new PENDING booking with seat reservation.
-	// Returns booking ID or ErrNotEnoughTickets if insufficient capacity.
-	CreateBooking(ctx context.Context, userID, eventID string, qty int) (string, error)
+	// Returns booking ID, ErrNotEnoughTickets if insufficient capacity or
+	// event.ErrInvalidAnswers if answers do not fit the event's questions.
+	CreateBooking(ctx context.Context, userID, eventID string, qty int, answers map[string]any) (string, error)
 	// Get retrieves a booking by ID
 	Get(ctx context.Context, id string) (*Booking, error)
 	// HandleBookingCreated processes booking.created messages from queue
 	HandleBookingCreated(ctx context.Context, body []byte) error
+	// HandleBookingConfirmed sends the confirmation for booking.confirmed messages from queue
+	HandleBookingConfirmed(ctx context.Context, body []byte) error
 	// ConfirmBooking transitions booking to CONFIRMED status after payment
 	ConfirmBooking(ctx context.Context, bookingID string) error
 	// CancelBooking transitions booking to CANCELLED and releases seats
 	CancelBooking(ctx context.Context, bookingID string) error
+	// ListByEvent lists an event's bookings for its owner or an admin
+	ListByEvent(ctx context.Context, eventID string, status Status, limit, offset int) ([]*Booking, error)
+	// Registrations returns an event with its CONFIRMED bookings, oldest first, for exporting answers
+	Registrations(ctx context.Context, eventID string) (*event.Event, []*Booking, error)
 }
 
 // EventReserver provides seat reservation operations for booking service.
@@ -77,6 +125,9 @@ type Service struct {
 	reserver  EventReserver     // Event seat reservation operations
 	publisher Publisher         // Message queue publisher for async processing
 	cache     Cache             // Redis cache for performance and TTL management
+	tickets   TicketIssuer      // Door tickets (no-op unless configured)
+	notifier  Notifier          // Customer emails (no-op unless configured)
+	resale    Resale            // Resale listings (no-op unless configured)
 	logger    *zap.Logger       // Structured logger
 }
 
@@ -95,10 +146,38 @@ func NewService(db database.Database, r BookingRepository, er EventReserver, pub
 		reserver:  er,
 		publisher: pub,
 		cache:     cache,
+		tickets:   noopTickets{},
+		notifier:  noopNotifier{},
+		resale:    noopResale{},
 		logger:    logger,
 	}
 }
 
+// SetTickets enables ticket issuance on confirmation and voiding on cancellation.
+func (s *Service) SetTickets(t TicketIssuer) {
+	if t == nil {
+		t = noopTickets{}
+	}
+	s.tickets = t
+}
+
+// SetNotifier enables customer emails, such as the confirmation with the
+// tickets attached, sent by HandleBookingConfirmed.
+func (s *Service) SetNotifier(n Notifier) {
+	if n == nil {
+		n = noopNotifier{}
+	}
+	s.notifier = n
+}
+
+// SetResale removes resale listings when a booking's tickets are voided.
+func (s *Service) SetResale(r Resale) {
+	if r == nil {
+		r = noopResale{}
+	}
+	s.resale = r
+}
+
 // Ensure *Service implements BookingService
 var _ BookingService = (*Service)(nil)
 
@@ -112,24 +191,49 @@ type BookingCreatedMessage struct {
 	Quantity  int    `json:"quantity"`   // Number of tickets booked
 }
 
+// BookingConfirmedMessage is published on booking.confirmed once a booking
+// is CONFIRMED and its tickets issued. Consumers must be idempotent by
+// BookingID.
+type BookingConfirmedMessage struct {
+	BookingID string `json:"booking_id"` // UUID of the confirmed booking
+	UserID    string `json:"user_id"`    // UUID of the booking's owner
+	EventID   string `json:"event_id"`   // UUID of the booked event
+}
+
 // ErrNotEnoughTickets is returned when reservation cannot be satisfied
 var ErrNotEnoughTickets = errors.New("not enough tickets")
 
 // CreateBooking creates a new booking with transactional safety and concurrency handling.
 //
 // Process flow:
-// 1. Uses database transaction as source of truth for seat reservation
-// 2. Locks event row to prevent race conditions
-// 3. Creates booking record with current event pricing
-// 4. Publishes booking.created event for async payment processing
-// 5. Sets Redis TTL for automatic cancellation after 15 minutes
+// 1. Checks registration answers before any seat is reserved
+// 2. Uses database transaction as source of truth for seat reservation
+// 3. Locks event row to prevent race conditions
+// 4. Creates booking record with current event pricing
+// 5. Publishes booking.created event for async payment processing
+// 6. Sets Redis TTL for automatic cancellation after 15 minutes
 //
-// Returns booking ID on success or ErrNotEnoughTickets if insufficient capacity.
+// Returns booking ID on success, ErrNotEnoughTickets if insufficient capacity
+// or event.ErrInvalidAnswers if the answers do not fit the event's questions.
 // All operations are atomic - if any step fails, the entire booking is rolled back.
-func (s *Service) CreateBooking(ctx context.Context, userID, eventID string, qty int) (string, error) {
-	var id string
+func (s *Service) CreateBooking(ctx context.Context, userID, eventID string, qty int, answers map[string]any) (string, error) {
+	// 1. Rejected answers must not reach the reservation, whose cache and
+	// search index updates outlive a rolled back transaction
+	ev, err := s.reserver.Get(ctx, eventID)
+	if err != nil {
+		s.logger.Error("Failed to load event for booking",
+			zap.String("event_id", eventID), zap.Error(err))
+		return "", err
+	}
+	checked, err := ev.CheckAnswers(answers)
+	if err != nil {
+		s.logger.Warn("Invalid registration answers",
+			zap.String("event_id", eventID), zap.Error(err))
+		return "", err
+	}
 
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	var id string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Reserve using DB transaction as the source of truth
 		okDB, errDB := s.reserver.ReserveTx(tx, eventID, qty)
 		if errDB != nil {
@@ -143,21 +247,14 @@ func (s *Service) CreateBooking(ctx context.Context, userID, eventID string, qty
 			return ErrNotEnoughTickets
 		}
 
-		// 2. Get event to copy current ticket price (cents)
-		ev, err := s.reserver.Get(ctx, eventID)
-		if err != nil {
-			s.logger.Error("Failed to load event for booking",
-				zap.String("event_id", eventID), zap.Error(err))
-			return err
-		}
-
-		// 3. Create booking record with unit price cents
+		// 4. Create booking record with unit price cents
 		b := &Booking{
 			UserID:         userID,
 			EventID:        eventID,
 			Quantity:       qty,
 			UnitPriceCents: ev.TicketPriceCents,
 			Status:         StatusPending,
+			Answers:        checked,
 		}
 		if err := s.repo.Create(tx, b); err != nil {
 			s.logger.Error("Failed to create booking",
@@ -172,7 +269,7 @@ func (s *Service) CreateBooking(ctx context.Context, userID, eventID string, qty
 		return "", err
 	}
 
-	// 4. Publish booking.created event for async payment processing
+	// 5. Publish booking.created event for async payment processing
 	msg := BookingCreatedMessage{
 		BookingID: id,
 		UserID:    userID,
@@ -185,7 +282,7 @@ func (s *Service) CreateBooking(ctx context.Context, userID, eventID string, qty
 		return "", err
 	}
 
-	// 5. Set Redis TTL for automatic cancellation after 15 minutes if payment not completed
+	// 6. Set Redis TTL for automatic cancellation after 15 minutes if payment not completed
 	if err := s.cache.Set(ctx, "booking:pending:"+id, "1", 15*time.Minute); err != nil {
 		s.logger.Warn("Failed to set pending booking in cache",
 			zap.String("booking_id", id), zap.Error(err))
@@ -218,23 +315,78 @@ func (s *Service) HandleBookingCreated(ctx context.Context, body []byte) error {
 	return nil
 }
 
-// ConfirmBooking transitions a booking from PENDING to CONFIRMED status.
-// Updates event statistics cache and cleans up pending booking TTL.
-// Idempotent - safe to call multiple times on the same booking.
-func (s *Service) ConfirmBooking(ctx context.Context, bookingID string) error {
-	b, err := s.repo.Get(bookingID)
+// HandleBookingConfirmed processes booking.confirmed messages from the
+// message queue and sends the customer's confirmation. Bookings that are no
+// longer CONFIRMED by the time the message arrives get no email.
+func (s *Service) HandleBookingConfirmed(ctx context.Context, body []byte) error {
+	var msg BookingConfirmedMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+
+	b, err := s.repo.Get(msg.BookingID)
 	if err != nil {
-		s.logger.Error("ConfirmBooking: get booking failed", zap.String("booking_id", bookingID), zap.Error(err))
+		s.logger.Error("HandleBookingConfirmed: get booking failed", zap.String("booking_id", msg.BookingID), zap.Error(err))
 		return err
 	}
-	if b.Status == StatusConfirmed {
+	if b.Status != StatusConfirmed {
+		s.logger.Info("HandleBookingConfirmed: booking no longer confirmed", zap.String("booking_id", b.ID), zap.String("status", string(b.Status)))
 		return nil
 	}
+	if err := s.notifier.BookingConfirmed(ctx, b); err != nil {
+		s.logger.Error("HandleBookingConfirmed: confirmation email failed", zap.String("booking_id", b.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ConfirmBooking transitions a booking from PENDING to CONFIRMED status.
+// Updates event statistics cache and cleans up pending booking TTL.
+// Idempotent - safe to call multiple times on the same booking; bookings
+// CANCELLED or REFUNDED meanwhile are left alone, so a redelivered message
+// cannot bring them back.
+func (s *Service) ConfirmBooking(ctx context.Context, bookingID string) error {
+	return s.settle(ctx, "ConfirmBooking", bookingID, StatusConfirmed, s.confirm)
+}
+
+// settleAttempts bounds how often settle re-reads a booking that changed
+// under it
+const settleAttempts = 3
+
+// settle applies change to a booking unless its status rules out moving to
+// status. When a concurrent change wins the conditional update, the booking
+// is read again and re-checked.
+func (s *Service) settle(ctx context.Context, op, bookingID string, status Status, change func(context.Context, *Booking) error) error {
+	for attempt := 1; ; attempt++ {
+		b, err := s.repo.Get(bookingID)
+		if err != nil {
+			s.logger.Error(op+": get booking failed", zap.String("booking_id", bookingID), zap.Error(err))
+			return err
+		}
+		if !CanTransition(b.Status, status) {
+			if b.Status != status {
+				s.logger.Info(op+": booking left alone", zap.String("booking_id", bookingID), zap.String("status", string(b.Status)))
+			}
+			return nil
+		}
+		err = change(ctx, b)
+		if !errors.Is(err, ErrInvalidTransition) || attempt == settleAttempts {
+			return err
+		}
+	}
+}
 
-	if err := s.repo.UpdateStatus(ctx, bookingID, StatusConfirmed); err != nil {
-		s.logger.Error("ConfirmBooking: update status failed", zap.String("booking_id", bookingID), zap.Error(err))
+// confirm marks b CONFIRMED and refreshes its event's statistics. It fails
+// with ErrInvalidTransition when b changed since it was read.
+func (s *Service) confirm(ctx context.Context, b *Booking) error {
+	bookingID := b.ID
+	if err := s.repo.UpdateStatus(ctx, b, StatusConfirmed); err != nil {
+		if !errors.Is(err, ErrInvalidTransition) {
+			s.logger.Error("ConfirmBooking: update status failed", zap.String("booking_id", bookingID), zap.Error(err))
+		}
 		return err
 	}
+	b.Status = StatusConfirmed
 
 	// update event stats cache (tickets sold + revenue)
 	if err := s.updateEventStatsCache(ctx, b.EventID); err != nil {
@@ -244,43 +396,74 @@ func (s *Service) ConfirmBooking(ctx context.Context, bookingID string) error {
 	// delete pending key if exists
 	_ = s.cache.Del(ctx, "booking:pending:"+bookingID)
 
+	if err := s.tickets.Issue(ctx, b); err != nil {
+		s.logger.Error("ConfirmBooking: issue tickets failed", zap.String("booking_id", bookingID), zap.Error(err))
+	}
+	msg := BookingConfirmedMessage{BookingID: bookingID, UserID: b.UserID, EventID: b.EventID}
+	if err := s.publisher.Publish("booking.confirmed", msg); err != nil {
+		s.logger.Error("ConfirmBooking: publish booking confirmed failed", zap.String("booking_id", bookingID), zap.Error(err))
+	}
+
 	s.logger.Info("Booking confirmed", zap.String("booking_id", bookingID), zap.String("event_id", b.EventID))
 	return nil
 }
 
 // CancelBooking transitions a booking from PENDING to CANCELLED status.
 // Releases reserved seats back to the event capacity and updates statistics.
-// Idempotent - safe to call multiple times on the same booking.
+// Idempotent - safe to call multiple times on the same booking; REFUNDED
+// bookings are left alone, so their seats are never released twice.
 func (s *Service) CancelBooking(ctx context.Context, bookingID string) error {
-	b, err := s.repo.Get(bookingID)
-	if err != nil {
-		s.logger.Error("CancelBooking: get booking failed", zap.String("booking_id", bookingID), zap.Error(err))
-		return err
-	}
-	if b.Status == StatusCancelled {
-		return nil
-	}
+	return s.settle(ctx, "CancelBooking", bookingID, StatusCancelled, s.cancel)
+}
 
-	if err := s.repo.UpdateStatus(ctx, bookingID, StatusCancelled); err != nil {
-		s.logger.Error("CancelBooking: update status failed", zap.String("booking_id", bookingID), zap.Error(err))
+// cancel marks b CANCELLED and gives its seats back. It fails with
+// ErrInvalidTransition when b changed since it was read.
+func (s *Service) cancel(ctx context.Context, b *Booking) error {
+	bookingID := b.ID
+	if err := s.repo.UpdateStatus(ctx, b, StatusCancelled); err != nil {
+		if !errors.Is(err, ErrInvalidTransition) {
+			s.logger.Error("CancelBooking: update status failed", zap.String("booking_id", bookingID), zap.Error(err))
+		}
 		return err
 	}
+	s.cancelled(ctx, b)
+	return nil
+}
+
+// cancelled gives back the seats of b, just moved to CANCELLED, and voids
+// its tickets
+func (s *Service) cancelled(ctx context.Context, b *Booking) {
+	s.release(ctx, b)
+	s.voidTickets(ctx, b.ID)
 
+	// remove pending key if any
+	_ = s.cache.Del(ctx, "booking:pending:"+b.ID)
+
+	s.logger.Info("Booking cancelled", zap.String("booking_id", b.ID), zap.String("event_id", b.EventID))
+}
+
+// release returns b's seats to its event and refreshes the event's statistics.
+// Failures are logged: the booking's new status stands either way.
+func (s *Service) release(ctx context.Context, b *Booking) {
 	// release seats in DB and sync cache via event reserver
 	if err := s.reserver.Release(ctx, b.EventID, b.Quantity); err != nil {
-		s.logger.Warn("CancelBooking: failed to release seats via reserver", zap.String("event_id", b.EventID), zap.Int("qty", b.Quantity), zap.Error(err))
+		s.logger.Warn("Failed to release seats via reserver", zap.String("booking_id", b.ID), zap.String("event_id", b.EventID), zap.Int("qty", b.Quantity), zap.Error(err))
 	}
 
 	// update stats cache as well
 	if err := s.updateEventStatsCache(ctx, b.EventID); err != nil {
-		s.logger.Warn("CancelBooking: update stats cache failed", zap.String("event_id", b.EventID), zap.Error(err))
+		s.logger.Warn("Update stats cache failed", zap.String("booking_id", b.ID), zap.String("event_id", b.EventID), zap.Error(err))
 	}
+}
 
-	// remove pending key if any
-	_ = s.cache.Del(ctx, "booking:pending:"+bookingID)
-
-	s.logger.Info("Booking cancelled", zap.String("booking_id", bookingID), zap.String("event_id", b.EventID))
-	return nil
+// voidTickets invalidates a booking's tickets and takes them off resale, logging failures
+func (s *Service) voidTickets(ctx context.Context, bookingID string) {
+	if err := s.resale.RemoveListings(ctx, bookingID); err != nil {
+		s.logger.Error("Remove resale listings failed", zap.String("booking_id", bookingID), zap.Error(err))
+	}
+	if err := s.tickets.Void(ctx, bookingID); err != nil {
+		s.logger.Error("Void tickets failed", zap.String("booking_id", bookingID), zap.Error(err))
+	}
 }
 
 // updateEventStatsCache recalculates and caches event statistics (tickets sold, revenue).
@@ -335,3 +518,39 @@ func (s *Service) updateEventStatsCache(ctx context.Context, eventID string) err
 func (s *Service) Get(ctx context.Context, id string) (*Booking, error) {
 	return s.repo.Get(id)
 }
+
+// ListByEvent lists bookings of one event, newest first.
+// An empty status returns bookings in every state.
+func (s *Service) ListByEvent(ctx context.Context, eventID string, status Status, limit, offset int) ([]*Booking, error) {
+	bookings, err := s.repo.ListByEvent(ctx, eventID, status, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list event bookings", zap.String("event_id", eventID), zap.Error(err))
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// Registrations returns an event with its CONFIRMED bookings, oldest first
+func (s *Service) Registrations(ctx context.Context, eventID string) (*event.Event, []*Booking, error) {
+	ev, err := s.reserver.Get(ctx, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bookings, err := s.repo.ListConfirmedByEvent(ctx, eventID)
+	if err != nil {
+		s.logger.Error("Failed to list event registrations", zap.String("event_id", eventID), zap.Error(err))
+		return nil, nil, err
+	}
+	sort.SliceStable(bookings, func(i, j int) bool { return bookings[i].CreatedAt.Before(bookings[j].CreatedAt) })
+	return ev, bookings, nil
+}
+
+// ListByUser lists a user's bookings, newest first
+func (s *Service) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*Booking, error) {
+	bookings, err := s.repo.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list user bookings", zap.String("user_id", userID), zap.Error(err))
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -2,18 +2,25 @@ package booking_test
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
 	"ticket-booking/internal/booking"
 	"ticket-booking/internal/database"
+	"ticket-booking/internal/event"
 	"ticket-booking/internal/mocks"
 )
 
@@ -303,6 +310,16 @@ func TestCreateBooking_ErrorHandling(t *testing.T) {
 	require.Contains(t, err.Error(), "not enough tickets")
 }
 
+func TestCreateBooking_InvalidAnswersReserveNothing(t *testing.T) {
+	svc, _, reserver, _, _, _ := createTestService(t)
+	e := &event.Event{ID: "e1", Questions: []event.Question{{Key: "size", Type: event.QuestionChoice, Options: []string{"S", "M"}, Required: true}}}
+	reserver.EXPECT().Get(gomock.Any(), "e1").Return(e, nil)
+
+	// No ReserveTx expectation: the strict mock fails the test if seats are touched
+	_, err := svc.CreateBooking(context.Background(), "u1", "e1", 2, map[string]any{"size": "XL"})
+	require.ErrorIs(t, err, event.ErrInvalidAnswers)
+}
+
 // Test concurrent message publishing (simulated)
 func TestCreateBooking_ConcurrentMessagePublishing(t *testing.T) {
 	_, _, _, publisher, cache, _ := createTestService(t)
@@ -376,6 +393,63 @@ func TestCreateBooking_QuantityEdgeCases(t *testing.T) {
 	}
 }
 
+func TestListByEvent_PassesFilter(t *testing.T) {
+	svc, repo, _, _, _, _ := createTestService(t)
+
+	repo.EXPECT().ListByEvent(gomock.Any(), "e1", booking.StatusConfirmed, 50, 0).
+		Return([]*booking.Booking{{ID: "b1", EventID: "e1", Status: booking.StatusConfirmed}}, nil)
+
+	out, err := svc.ListByEvent(context.Background(), "e1", booking.StatusConfirmed, 50, 0)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "b1", out[0].ID)
+}
+
+func TestRegistrations_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo, reserver, _, _, _ := createTestService(t)
+	h := booking.NewHandler(svc, zap.NewNop())
+	r := gin.New()
+	booking.RegisterOrganizerRoutes(r.Group("/organizer"), h, func(*gin.Context) {})
+
+	e := &event.Event{ID: "e1", Questions: []event.Question{
+		{Key: "size", Type: event.QuestionChoice, Options: []string{"S", "M"}},
+		{Key: "terms", Type: event.QuestionCheckbox},
+		{Key: "company", Type: event.QuestionText},
+	}}
+	created := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	reserver.EXPECT().Get(gomock.Any(), "e1").Return(e, nil).Times(2)
+	repo.EXPECT().ListConfirmedByEvent(gomock.Any(), "e1").Return([]*booking.Booking{
+		{ID: "b2", UserID: "u2", EventID: "e1", Quantity: 1, Status: booking.StatusConfirmed, CreatedAt: created.Add(time.Hour)},
+		{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed, CreatedAt: created,
+			Answers: map[string]any{"size": "M", "terms": true, "company": "=HYPERLINK(\"http://x\")"}},
+	}, nil).Times(2)
+	reserver.EXPECT().Get(gomock.Any(), "nope").Return(nil, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/organizer/events/e1/registrations?format=csv", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err := csv.NewReader(w.Body).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{
+		{"booking_id", "booked_by", "quantity", "created_at", "size", "terms", "company"},
+		{"b1", "u1", "2", "2026-05-01T09:00:00Z", "M", "true", `'=HYPERLINK("http://x")`},
+		{"b2", "u2", "1", "2026-05-01T10:00:00Z", "", "", ""},
+	}, records, "cells that would run as formulas are quoted")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/organizer/events/e1/registrations", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var out []booking.BookingResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	require.Len(t, out, 2)
+	require.Equal(t, "=HYPERLINK(\"http://x\")", out[0].Answers["company"], "JSON keeps answers as given")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/organizer/events/nope/registrations", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
 // Test booking ID generation logic
 func TestCreateBooking_IDGeneration(t *testing.T) {
 	// Test that booking IDs are properly formatted
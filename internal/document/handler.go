@@ -0,0 +1,64 @@
+package document
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+)
+
+// Bookings looks up the booking a document is for
+type Bookings interface {
+	Get(ctx context.Context, id string) (*booking.Booking, error)
+}
+
+type Handler struct {
+	svc      *Service
+	bookings Bookings
+	logger   *zap.Logger
+}
+
+func NewHandler(s *Service, b Bookings, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, bookings: b, logger: logger}
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+// PDF godoc
+// @Summary Booking PDF
+// @Description Printable receipt with the price breakdown, followed by one page per ticket with its QR code (booking owner, or bookings:read)
+// @Tags bookings
+// @Produce application/pdf
+// @Param id path string true "Booking ID"
+// @Success 200 {file} file
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/pdf [get]
+func (h *Handler) PDF(c *gin.Context) {
+	userID := c.GetString(auth.CtxUserID)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+	b, err := h.bookings.Get(c, c.Param("id"))
+	if err != nil || !booking.CanView(b, userID, c.GetStringSlice(auth.CtxPermissions)) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	pdf, err := h.svc.BookingPDF(c, b)
+	if err != nil {
+		h.logger.Error("Failed to render booking PDF", zap.String("booking_id", b.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+Filename(b)+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
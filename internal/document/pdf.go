@@ -0,0 +1,209 @@
+// Package document renders printable booking documents: a PDF with the
+// receipt and one page per ticket with its QR code.
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/ticket"
+)
+
+// TicketType is printed for every ticket; bookings have no seats or tiers
+const TicketType = "General admission"
+
+// BookingDocument is everything printed for one booking
+type BookingDocument struct {
+	Booking     *booking.Booking
+	Event       *event.Event // Venue preloaded when the event has one
+	HolderName  string
+	HolderEmail string
+	Tickets     []TicketPage
+	GeneratedAt time.Time
+}
+
+// TicketPage is one ticket and its signed QR payload; Payload is empty for
+// tickets that no longer admit
+type TicketPage struct {
+	Ticket  *ticket.Ticket
+	Payload string
+}
+
+// Money formats cents as a decimal amount
+func Money(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// location is the venue's time zone, UTC when unknown
+func (d *BookingDocument) location() *time.Location {
+	if v := d.Event.Venue; v != nil && v.Timezone != "" {
+		if loc, err := time.LoadLocation(v.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// When is the event's start and end in the venue's time zone
+func (d *BookingDocument) When() string {
+	loc := d.location()
+	start, end := d.Event.StartsAt.In(loc), d.Event.EndsAt.In(loc)
+	s := start.Format("Mon 2 Jan 2006, 15:04")
+	if end.After(start) {
+		if end.YearDay() == start.YearDay() && end.Year() == start.Year() {
+			s += end.Format(" - 15:04")
+		} else {
+			s += end.Format(" - Mon 2 Jan 2006, 15:04")
+		}
+	}
+	return s + " " + start.Format("MST")
+}
+
+// venueLines is the venue's name and address, one line each
+func (d *BookingDocument) venueLines() []string {
+	v := d.Event.Venue
+	if v == nil {
+		return nil
+	}
+	lines := []string{v.Name, v.AddressLine1}
+	if v.AddressLine2 != nil && *v.AddressLine2 != "" {
+		lines = append(lines, *v.AddressLine2)
+	}
+	city := v.City
+	if v.PostalCode != nil && *v.PostalCode != "" {
+		city = *v.PostalCode + " " + city
+	}
+	if v.Region != nil && *v.Region != "" {
+		city += ", " + *v.Region
+	}
+	return append(lines, city+", "+v.Country)
+}
+
+// Render draws the receipt on the first page and each ticket on a page of
+// its own. The built-in fonts cover Latin-1; other characters print as "?".
+func Render(d *BookingDocument) ([]byte, error) {
+	b := d.Booking
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Booking "+b.ID, true)
+	pdf.SetCreator("Ticket Booking", true)
+	pdf.SetMargins(20, 20, 20)
+	pdf.SetAutoPageBreak(true, 20)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	heading := func(size float64, text string) {
+		pdf.SetFont("Helvetica", "B", size)
+		pdf.CellFormat(0, size*0.5, tr(text), "", 1, "L", false, 0, "")
+		pdf.Ln(2)
+	}
+	field := func(label, value string) {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(35, 6, tr(label), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(value), "", "L", false)
+	}
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Booking %s - generated %s - page %d", b.ID,
+			d.GeneratedAt.UTC().Format("2006-01-02 15:04 UTC"), pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	// Receipt
+	pdf.AddPage()
+	heading(20, "Receipt")
+	field("Booking", b.ID)
+	field("Status", string(b.Status))
+	field("Booked on", b.CreatedAt.In(d.location()).Format("2 Jan 2006, 15:04 MST"))
+	holder := d.HolderEmail
+	switch {
+	case d.HolderName != "" && holder != "":
+		holder = d.HolderName + " <" + holder + ">"
+	case holder == "":
+		holder = "Deleted account"
+	}
+	field("Billed to", holder)
+	pdf.Ln(4)
+
+	heading(14, d.Event.Name)
+	field("When", d.When())
+	if lines := d.venueLines(); lines != nil {
+		field("Where", strings.Join(lines, "\n"))
+	}
+	pdf.Ln(4)
+
+	// Price breakdown
+	cols := []float64{80, 20, 35, 35}
+	row := func(style string, cells ...string) {
+		pdf.SetFont("Helvetica", style, 10)
+		for i, c := range cells {
+			align := "R"
+			if i == 0 {
+				align = "L"
+			}
+			pdf.CellFormat(cols[i], 7, tr(c), "B", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	total := int64(b.Quantity) * b.UnitPriceCents
+	row("B", "Item", "Qty", "Unit price", "Amount")
+	row("", TicketType, fmt.Sprint(b.Quantity), Money(b.UnitPriceCents), Money(total))
+	row("B", "Total", "", "", Money(total))
+	if b.Status == booking.StatusRefunded {
+		row("", "Refunded", "", "", Money(-total))
+		row("B", "Balance", "", "", Money(0))
+	}
+
+	// Tickets
+	for _, p := range d.Tickets {
+		t := p.Ticket
+		pdf.AddPage()
+		heading(16, d.Event.Name)
+		field("When", d.When())
+		if lines := d.venueLines(); lines != nil {
+			field("Where", strings.Join(lines, "\n"))
+		}
+		field("Ticket", fmt.Sprintf("%d of %d, %s", t.Number, b.Quantity, TicketType))
+		field("Holder", holder)
+		field("Code", t.Code)
+		pdf.Ln(6)
+
+		if p.Payload == "" {
+			pdf.SetFont("Helvetica", "B", 28)
+			pdf.SetTextColor(180, 0, 0)
+			pdf.CellFormat(0, 20, string(t.Status), "1", 1, "C", false, 0, "")
+			pdf.SetTextColor(0, 0, 0)
+			pdf.SetFont("Helvetica", "", 10)
+			pdf.MultiCell(0, 6, "This ticket no longer admits entry.", "", "C", false)
+			continue
+		}
+		img, _, err := ticket.RenderQR(p.Payload, ticket.FormatPNG)
+		if err != nil {
+			return nil, err
+		}
+		name := "qr-" + t.Code
+		opts := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(img))
+		const size = 80
+		w, _ := pdf.GetPageSize()
+		pdf.ImageOptions(name, (w-size)/2, pdf.GetY(), size, size, true, opts, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.MultiCell(0, 5, "Show this code at the door. Each ticket admits one person once.", "", "C", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
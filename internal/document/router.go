@@ -0,0 +1,8 @@
+package document
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes exposes booking PDFs to their owners; r must require authentication
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/bookings/:id/pdf", h.PDF)
+}
@@ -0,0 +1,143 @@
+package document
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+// Events looks up the event a booking is for, with its venue
+type Events interface {
+	Get(ctx context.Context, id string) (*event.Event, error)
+}
+
+// Users looks up the booking's holder
+type Users interface {
+	Get(ctx context.Context, id string) (*user.User, error)
+}
+
+// Tickets lists a booking's tickets and signs their QR payloads
+type Tickets interface {
+	ForBooking(ctx context.Context, b *booking.Booking) ([]*ticket.Ticket, error)
+	Payload(t *ticket.Ticket) string
+}
+
+// Service builds booking PDFs and mails them on confirmation. It plugs into
+// the booking service through booking.Service.SetNotifier and runs from the
+// booking.confirmed consumer (booking.Service.HandleBookingConfirmed).
+type Service struct {
+	events  Events
+	users   Users
+	tickets Tickets
+	mailer  mail.Mailer
+	tmpl    *mail.Templates
+	logger  *zap.Logger
+}
+
+func NewService(events Events, users Users, tickets Tickets, mailer mail.Mailer, cfg config.Email, logger *zap.Logger) *Service {
+	return &Service{
+		events:  events,
+		users:   users,
+		tickets: tickets,
+		mailer:  mailer,
+		tmpl:    mail.NewTemplates(cfg.TemplateDir),
+		logger:  logger,
+	}
+}
+
+// Ensure *Service can be handed to booking.Service.SetNotifier
+var _ booking.Notifier = (*Service)(nil)
+
+// Build gathers what is printed for b
+func (s *Service) Build(ctx context.Context, b *booking.Booking) (*BookingDocument, error) {
+	e, err := s.events.Get(ctx, b.EventID)
+	if err != nil {
+		return nil, err
+	}
+	u, err := s.users.Get(ctx, b.UserID)
+	if err != nil {
+		return nil, err
+	}
+	tickets, err := s.tickets.ForBooking(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	d := &BookingDocument{Booking: b, Event: e, GeneratedAt: time.Now()}
+	// A deleted account's placeholder address is not printed
+	if u.AnonymizedAt == nil {
+		d.HolderEmail = u.Email
+	}
+	if u.FullName != nil {
+		d.HolderName = *u.FullName
+	}
+	for _, t := range tickets {
+		// Tickets left over from before a move belong to another event
+		if t.EventID != b.EventID {
+			continue
+		}
+		// Seats transferred away, and the codes a transfer replaced, are not printed
+		if t.HolderID != b.UserID || (t.TransferID != nil && t.Status == ticket.StatusVoid) {
+			continue
+		}
+		p := TicketPage{Ticket: t}
+		if t.Status == ticket.StatusValid {
+			p.Payload = s.tickets.Payload(t)
+		}
+		d.Tickets = append(d.Tickets, p)
+	}
+	return d, nil
+}
+
+// BookingPDF renders the receipt and tickets of b
+func (s *Service) BookingPDF(ctx context.Context, b *booking.Booking) ([]byte, error) {
+	d, err := s.Build(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	return Render(d)
+}
+
+// Filename is the name booking PDFs are served and attached under
+func Filename(b *booking.Booking) string {
+	return "booking-" + b.ID + ".pdf"
+}
+
+// BookingConfirmed mails the holder their tickets and receipt as a PDF.
+// Deleted accounts have no address left and are skipped.
+func (s *Service) BookingConfirmed(ctx context.Context, b *booking.Booking) error {
+	d, err := s.Build(ctx, b)
+	if err != nil {
+		return err
+	}
+	if d.HolderEmail == "" {
+		return nil
+	}
+	pdf, err := Render(d)
+	if err != nil {
+		return err
+	}
+	msg, err := s.tmpl.Render("booking_confirmed", d.HolderEmail, map[string]any{
+		"Name":      d.HolderName,
+		"EventName": d.Event.Name,
+		"StartsAt":  d.When(),
+		"Quantity":  b.Quantity,
+		"BookingID": b.ID,
+	})
+	if err != nil {
+		return err
+	}
+	msg.Attachments = []mail.Attachment{{Filename: Filename(b), ContentType: "application/pdf", Data: pdf}}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+	s.logger.Info("Confirmation email sent", zap.String("booking_id", b.ID))
+	return nil
+}
@@ -0,0 +1,140 @@
+package document_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/document"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/internal/user"
+	"ticket-booking/internal/venue"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+type events map[string]*event.Event
+
+func (m events) Get(_ context.Context, id string) (*event.Event, error) {
+	if e, ok := m[id]; ok {
+		return e, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type users map[string]*user.User
+
+func (m users) Get(_ context.Context, id string) (*user.User, error) {
+	if u, ok := m[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type bookings map[string]*booking.Booking
+
+func (m bookings) Get(_ context.Context, id string) (*booking.Booking, error) {
+	if b, ok := m[id]; ok {
+		return b, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// fixedTickets serves two tickets per booking, the second one void
+type fixedTickets struct{}
+
+func (fixedTickets) ForBooking(_ context.Context, b *booking.Booking) ([]*ticket.Ticket, error) {
+	return []*ticket.Ticket{
+		{ID: "t1", BookingID: b.ID, EventID: b.EventID, HolderID: b.UserID, Number: 1, Code: "7K3QX9M2TD", Status: ticket.StatusValid},
+		{ID: "t2", BookingID: b.ID, EventID: b.EventID, HolderID: b.UserID, Number: 2, Code: "Q4W8E2R6T0", Status: ticket.StatusVoid},
+	}, nil
+}
+
+func (fixedTickets) Payload(t *ticket.Ticket) string {
+	return "TB1." + t.EventID + "." + t.Code + ".sig"
+}
+
+func fixture() (*document.Service, *mail.MemoryOutbox, users, bookings) {
+	name := "Nguyễn Văn An"
+	us := users{"u1": {ID: "u1", Email: "an@example.com", FullName: &name}}
+	bs := bookings{"b1": {ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, UnitPriceCents: 125050, Status: booking.StatusConfirmed, CreatedAt: time.Now()}}
+	starts := time.Date(2026, 11, 20, 12, 0, 0, 0, time.UTC)
+	es := events{"e1": {ID: "e1", Name: "Jazz Night", StartsAt: starts, EndsAt: starts.Add(3 * time.Hour), Venue: &venue.Venue{
+		Name: "Opera House", AddressLine1: "7 Cong Truong Lam Son", City: "Ho Chi Minh City", Country: "VN", Timezone: "Asia/Ho_Chi_Minh",
+	}}}
+	outbox := mail.NewMemoryOutbox()
+	return document.NewService(es, us, fixedTickets{}, outbox, config.Email{}, zap.NewNop()), outbox, us, bs
+}
+
+func TestBookingPDF(t *testing.T) {
+	svc, _, _, bs := fixture()
+	d, err := svc.Build(context.Background(), bs["b1"])
+	require.NoError(t, err)
+	require.Equal(t, "Fri 20 Nov 2026, 19:00 - 22:00 +07", d.When(), "times are shown in the venue's zone")
+	require.Len(t, d.Tickets, 2)
+	require.Empty(t, d.Tickets[1].Payload, "void tickets get no QR code")
+
+	pdf, err := document.Render(d)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-")))
+	require.Equal(t, 3, bytes.Count(pdf, []byte("/Type /Page\n")), "a receipt page and a page per ticket")
+
+	require.Equal(t, "2501.00", document.Money(2*125050))
+	require.Equal(t, "-0.05", document.Money(-5))
+}
+
+func TestBookingConfirmed_MailsPDF(t *testing.T) {
+	svc, outbox, us, bs := fixture()
+	require.NoError(t, svc.BookingConfirmed(context.Background(), bs["b1"]))
+	msgs := outbox.Messages()
+	require.Len(t, msgs, 1)
+	require.Equal(t, "an@example.com", msgs[0].To)
+	require.Equal(t, "Your tickets for Jazz Night", msgs[0].Subject)
+	require.Contains(t, msgs[0].Body, "2 ticket(s)")
+	require.Len(t, msgs[0].Attachments, 1)
+	require.Equal(t, "booking-b1.pdf", msgs[0].Attachments[0].Filename)
+	require.True(t, bytes.HasPrefix(msgs[0].Attachments[0].Data, []byte("%PDF-")))
+
+	// Deleted accounts have no address to mail
+	now := time.Now()
+	us["u1"].AnonymizedAt = &now
+	require.NoError(t, svc.BookingConfirmed(context.Background(), bs["b1"]))
+	require.Len(t, outbox.Messages(), 1)
+}
+
+func TestHandler_PDF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _, _, bs := fixture()
+	h := document.NewHandler(svc, bs, zap.NewNop())
+	get := func(path, userID string, perms ...string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(auth.CtxUserID, userID)
+			c.Set(auth.CtxPermissions, perms)
+		})
+		document.RegisterRoutes(r.Group(""), h)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	w := get("/bookings/b1/pdf", "u1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	require.Contains(t, w.Header().Get("Content-Disposition"), "booking-b1.pdf")
+
+	require.Equal(t, http.StatusNotFound, get("/bookings/b1/pdf", "u2").Code)
+	require.Equal(t, http.StatusOK, get("/bookings/b1/pdf", "u2", auth.PermBookingsRead).Code)
+	require.Equal(t, http.StatusNotFound, get("/bookings/b9/pdf", "u1").Code)
+}
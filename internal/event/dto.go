@@ -2,37 +2,56 @@ package event
 
 import (
 	"time"
+
+	"ticket-booking/internal/organizer"
+	"ticket-booking/internal/venue"
 )
 
 // CreateEventRequest input for creating a new event
 type CreateEventRequest struct {
-	Name             string    `json:"name" binding:"required" example:"Tech Conference 2025"`
-	Description      *string   `json:"description" example:"A conference about future tech"`
-	StartsAt         time.Time `json:"starts_at" example:"2025-09-01T09:00:00Z"`
-	EndsAt           time.Time `json:"ends_at" example:"2025-09-01T17:00:00Z"`
-	Capacity         int       `json:"capacity" binding:"required,min=1" example:"100"`
-	TicketPriceCents int64     `json:"ticket_price_cents" binding:"required,min=0" example:"5000"`
+	Name             string          `json:"name" binding:"required" example:"Tech Conference 2025"`
+	Description      *string         `json:"description" example:"A conference about future tech"`
+	StartsAt         time.Time       `json:"starts_at" example:"2025-09-01T09:00:00Z"`
+	EndsAt           time.Time       `json:"ends_at" example:"2025-09-01T17:00:00Z"`
+	Capacity         int             `json:"capacity" binding:"omitempty,min=1" example:"100"` // Defaults to the venue's default capacity
+	TicketPriceCents int64           `json:"ticket_price_cents" binding:"required,min=0" example:"5000"`
+	ResaleCapPercent int             `json:"resale_cap_percent" binding:"min=0,max=500" example:"110"` // Resale price cap, percent of the price paid; 0 disables resale
+	AttendeeFields   []AttendeeField `json:"attendee_fields" binding:"max=20,dive"`
+	Questions        []Question      `json:"questions" binding:"max=30,dive"`
+	VenueID          *string         `json:"venue_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	OrganizerID      *string         `json:"organizer_id" binding:"omitempty,uuid" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
 }
 
 // UpdateEventRequest input for updating event info
 type UpdateEventRequest struct {
-	Name             *string    `json:"name" example:"Updated Conference"`
-	Description      *string    `json:"description" example:"Updated description"`
-	StartsAt         *time.Time `json:"starts_at" example:"2025-09-02T09:00:00Z"`
-	EndsAt           *time.Time `json:"ends_at" example:"2025-09-02T17:00:00Z"`
-	Capacity         *int       `json:"capacity" binding:"gte=0" example:"150"`
-	TicketPriceCents *int64     `json:"ticket_price_cents" binding:"gte=0" example:"6000"`
+	Name             *string          `json:"name" example:"Updated Conference"`
+	Description      *string          `json:"description" example:"Updated description"`
+	StartsAt         *time.Time       `json:"starts_at" example:"2025-09-02T09:00:00Z"`
+	EndsAt           *time.Time       `json:"ends_at" example:"2025-09-02T17:00:00Z"`
+	Capacity         *int             `json:"capacity" binding:"gte=0" example:"150"`
+	TicketPriceCents *int64           `json:"ticket_price_cents" binding:"gte=0" example:"6000"`
+	ResaleCapPercent *int             `json:"resale_cap_percent" binding:"omitempty,min=0,max=500" example:"100"`
+	AttendeeFields   *[]AttendeeField `json:"attendee_fields" binding:"omitempty,max=20,dive"`
+	Questions        *[]Question      `json:"questions" binding:"omitempty,max=30,dive"`                   // Replaces the whole form
+	VenueID          *string          `json:"venue_id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`     // Empty string detaches the venue
+	OrganizerID      *string          `json:"organizer_id" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"` // Empty string detaches the organizer
 }
 
 // EventResponse represents event output
 type EventResponse struct {
-	ID           string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Name         string    `json:"name" example:"Tech Conference 2025"`
-	Description  *string   `json:"description" example:"A conference about future tech"`
-	DateTime     time.Time `json:"date_time" example:"2025-09-02T09:00:00+07:00"`
-	TotalTickets int       `json:"total_tickets" example:"100"`
-	TicketPrice  float64   `json:"ticket_price" example:"50.00"`
-	Remaining    int       `json:"remaining" example:"95"`
+	ID             string                       `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string                       `json:"name" example:"Tech Conference 2025"`
+	Description    *string                      `json:"description" example:"A conference about future tech"`
+	DateTime       time.Time                    `json:"date_time" example:"2025-09-02T09:00:00+07:00"`
+	TotalTickets   int                          `json:"total_tickets" example:"100"`
+	TicketPrice    float64                      `json:"ticket_price" example:"50.00"`
+	Remaining      int                          `json:"remaining" example:"95"`
+	ResaleCap      int                          `json:"resale_cap_percent" example:"110"` // 0 when resale is disabled
+	AttendeeFields []AttendeeField              `json:"attendee_fields,omitempty"`
+	Questions      []Question                   `json:"questions,omitempty"`
+	SeriesID       *string                      `json:"series_id,omitempty" example:"3f2b8c1a-5d6e-4f70-8a9b-0c1d2e3f4a5b"`
+	Venue          *venue.VenueResponse         `json:"venue,omitempty"`
+	Organizer      *organizer.OrganizerResponse `json:"organizer,omitempty"`
 }
 
 // ErrorResponse standard error model
@@ -0,0 +1,124 @@
+package event
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListFilter narrows and orders the public event listing.
+// Zero values mean "no constraint"; Sort must be one of the keys in sortColumns.
+type ListFilter struct {
+	Limit         int
+	Offset        int
+	Query         string     // Case-insensitive match on name/description (trigram indexed)
+	From          *time.Time // Events starting at or after
+	To            *time.Time // Events starting at or before
+	MinPriceCents *int64     // Inclusive lower bound on ticket_price_cents
+	MaxPriceCents *int64     // Inclusive upper bound on ticket_price_cents
+	Available     bool       // Only events with remaining > 0
+	SeriesID      string     // Only occurrences of this recurring series
+	OwnerID       string     // Only events owned by this user (organizer dashboard)
+	Sort          string     // e.g. "starts_at", "-price"
+}
+
+// sortColumns whitelists user-facing sort keys to ORDER BY clauses.
+// A leading "-" on the key selects descending order.
+var sortColumns = map[string]string{
+	"starts_at": "starts_at",
+	"price":     "ticket_price_cents",
+	"name":      "name",
+	"remaining": "remaining",
+	"created":   "created_at",
+}
+
+// DefaultSort keeps the historical starts_at ascending ordering
+const DefaultSort = "starts_at"
+
+// ErrInvalidFilter is returned for malformed or contradictory filter values
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Validate checks ranges and the sort key
+func (f ListFilter) Validate() error {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return errors.Join(ErrInvalidFilter, errors.New("from must be before to"))
+	}
+	if f.MinPriceCents != nil && *f.MinPriceCents < 0 {
+		return errors.Join(ErrInvalidFilter, errors.New("min_price_cents must be >= 0"))
+	}
+	if f.MinPriceCents != nil && f.MaxPriceCents != nil && *f.MinPriceCents > *f.MaxPriceCents {
+		return errors.Join(ErrInvalidFilter, errors.New("min_price_cents must be <= max_price_cents"))
+	}
+	if _, ok := sortColumns[strings.TrimPrefix(f.sortKey(), "-")]; !ok {
+		return errors.Join(ErrInvalidFilter, errors.New("unknown sort: "+f.Sort))
+	}
+	return nil
+}
+
+func (f ListFilter) sortKey() string {
+	if f.Sort == "" {
+		return DefaultSort
+	}
+	return f.Sort
+}
+
+// OrderBy returns the SQL ORDER BY clause for the filter's sort key.
+// Ties are broken by id so pagination stays stable.
+func (f ListFilter) OrderBy() string {
+	key := f.sortKey()
+	dir := "asc"
+	if strings.HasPrefix(key, "-") {
+		key, dir = key[1:], "desc"
+	}
+	col, ok := sortColumns[key]
+	if !ok {
+		col = sortColumns[DefaultSort]
+	}
+	return col + " " + dir + ", id asc"
+}
+
+// CacheKey builds the per-page Redis key. Unfiltered pages keep the historical
+// "events:list:<limit>:<offset>" shape; filters are appended in canonical order.
+func (f ListFilter) CacheKey() string {
+	key := "events:list:" + strconv.Itoa(f.Limit) + ":" + strconv.Itoa(f.Offset)
+	v := url.Values{}
+	if f.Query != "" {
+		v.Set("q", strings.ToLower(f.Query))
+	}
+	if f.From != nil {
+		v.Set("from", f.From.UTC().Format(time.RFC3339))
+	}
+	if f.To != nil {
+		v.Set("to", f.To.UTC().Format(time.RFC3339))
+	}
+	if f.MinPriceCents != nil {
+		v.Set("min", strconv.FormatInt(*f.MinPriceCents, 10))
+	}
+	if f.MaxPriceCents != nil {
+		v.Set("max", strconv.FormatInt(*f.MaxPriceCents, 10))
+	}
+	if f.Available {
+		v.Set("available", "1")
+	}
+	if f.SeriesID != "" {
+		v.Set("series", f.SeriesID)
+	}
+	if f.OwnerID != "" {
+		v.Set("owner", f.OwnerID)
+	}
+	if f.Sort != "" && f.Sort != DefaultSort {
+		v.Set("sort", f.Sort)
+	}
+	if len(v) == 0 {
+		return key
+	}
+	return key + ":" + v.Encode()
+}
+
+// likePattern escapes LIKE wildcards in user input and wraps it for substring match
+func likePattern(q string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(q) + "%"
+}
@@ -1,37 +1,82 @@
 package event
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/organizer"
+	"ticket-booking/internal/venue"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
 	svc    ServiceInterface
+	audit  *audit.Service // Records every create, update, archive and restore
 	logger *zap.Logger
 }
 
-func NewHandler(s ServiceInterface, logger *zap.Logger) *Handler {
-	return &Handler{svc: s, logger: logger}
+func NewHandler(s ServiceInterface, a *audit.Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, audit: a, logger: logger}
 }
 
 // List godoc
 // @Summary List events
-// @Description Get all available events
+// @Description Get available events with optional text search, date/price range and availability filters
 // @Tags events
 // @Produce json
 // @Param limit query int false "Max items to return (default 20, max 100)"
 // @Param offset query int false "Offset for pagination (default 0)"
+// @Param q query string false "Search text matched against name and description"
+// @Param from query string false "Only events starting at or after (RFC3339)"
+// @Param to query string false "Only events starting at or before (RFC3339)"
+// @Param min_price_cents query int false "Minimum ticket price in cents"
+// @Param max_price_cents query int false "Maximum ticket price in cents"
+// @Param available query bool false "Only events with remaining tickets"
+// @Param series_id query string false "Only occurrences of this recurring series"
+// @Param sort query string false "Sort key: starts_at, price, name, remaining, created (prefix with - for descending)"
 // @Success 200 {array} EventResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /events [get]
 func (h *Handler) List(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-	limit, _ := strconv.Atoi(limitStr)
-	offset, _ := strconv.Atoi(offsetStr)
+	f, err := parseListFilter(c)
+	if err == nil {
+		err = f.Validate()
+	}
+	if err != nil {
+		h.logger.Warn("Invalid event list filter", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	evts, err := h.svc.ListPage(c, f)
+	if err != nil {
+		h.logger.Error("Failed to list events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		return
+	}
+	// map to response
+	out := make([]EventResponse, 0, len(evts))
+	for i := range evts {
+		out = append(out, eventToResponse(&evts[i]))
+	}
+	h.logger.Info("Events listed", zap.Int("count", len(evts)), zap.Int("limit", f.Limit), zap.Int("offset", f.Offset))
+	c.JSON(http.StatusOK, out)
+}
+
+// parseListFilter reads pagination and filter query parameters.
+// Pagination is clamped silently (as before); malformed filters are rejected.
+func parseListFilter(c *gin.Context) (ListFilter, error) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 	if limit <= 0 {
 		limit = 20
 	}
@@ -42,21 +87,116 @@ func (h *Handler) List(c *gin.Context) {
 		offset = 0
 	}
 
-	evts, err := h.svc.ListPage(c, limit, offset)
+	f := ListFilter{
+		Limit:  limit,
+		Offset: offset,
+		Query:  strings.TrimSpace(c.Query("q")),
+		Sort:   c.Query("sort"),
+	}
+	if len(f.Query) > 100 {
+		return f, errors.New("q too long (max 100)")
+	}
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.New("from must be RFC3339")
+		}
+		f.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, errors.New("to must be RFC3339")
+		}
+		f.To = &t
+	}
+	if v := c.Query("min_price_cents"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return f, errors.New("min_price_cents must be an integer")
+		}
+		f.MinPriceCents = &n
+	}
+	if v := c.Query("max_price_cents"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return f, errors.New("max_price_cents must be an integer")
+		}
+		f.MaxPriceCents = &n
+	}
+	if v := c.Query("available"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return f, errors.New("available must be a boolean")
+		}
+		f.Available = b
+	}
+	if v := c.Query("series_id"); v != "" {
+		if _, err := uuid.Parse(v); err != nil {
+			return f, errors.New("series_id must be a UUID")
+		}
+		f.SeriesID = v
+	}
+	return f, nil
+}
+
+// ListOwned godoc
+// @Summary List my events
+// @Description List events owned by the calling organizer. Accepts the same filters as GET /events.
+// @Tags organizer
+// @Produce json
+// @Param limit query int false "Max items to return (default 20, max 100)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Param q query string false "Search text matched against name and description"
+// @Param sort query string false "Sort key: starts_at, price, name, remaining, created (prefix with - for descending)"
+// @Success 200 {array} EventResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /organizer/events [get]
+func (h *Handler) ListOwned(c *gin.Context) {
+	f, err := parseListFilter(c)
+	if err == nil {
+		err = f.Validate()
+	}
 	if err != nil {
-		h.logger.Error("Failed to list events", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	f.OwnerID = c.GetString(auth.CtxUserID)
+	evts, err := h.svc.ListPage(c, f)
+	if err != nil {
+		h.logger.Error("Failed to list owned events", zap.String("user_id", f.OwnerID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
 		return
 	}
-	// map to response
 	out := make([]EventResponse, 0, len(evts))
 	for i := range evts {
 		out = append(out, eventToResponse(&evts[i]))
 	}
-	h.logger.Info("Events listed", zap.Int("count", len(evts)), zap.Int("limit", limit), zap.Int("offset", offset))
 	c.JSON(http.StatusOK, out)
 }
 
+// RequireOwner guards routes with an :id event parameter so callers holding
+// only events:own reach just the events they own (see CanManage).
+func (h *Handler) RequireOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		e, err := h.svc.Get(c, id)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+			return
+		}
+		uid := c.GetString(auth.CtxUserID)
+		if !CanManage(e, uid, c.GetStringSlice(auth.CtxPermissions)) {
+			h.logger.Warn("Event access denied", zap.String("event_id", id), zap.String("user_id", uid))
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: ErrNotOwner.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
 // Get godoc
 // @Summary Get event by ID
 // @Description Retrieve a single event by its ID
@@ -80,13 +220,16 @@ func (h *Handler) Get(c *gin.Context) {
 
 // Stats godoc
 // @Summary Event statistics
-// @Description Get total tickets sold and estimated revenue for an event
+// @Description Get total tickets sold and estimated revenue for an event; organizers only see their own events
 // @Tags events
 // @Produce json
 // @Param id path string true "Event ID"
 // @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} ErrorResponse "Event owned by another organizer"
 // @Failure 404 {object} ErrorResponse
-// @Router /events/{id}/stats [get]
+// @Security BearerAuth
+// @Router /admin/events/{id}/stats [get]
+// @Router /organizer/events/{id}/stats [get]
 func (h *Handler) Stats(c *gin.Context) {
 	id := c.Param("id")
 	if _, err := h.svc.Get(c, id); err != nil {
@@ -108,7 +251,7 @@ func (h *Handler) Stats(c *gin.Context) {
 
 // Create godoc
 // @Summary Create event
-// @Description Create a new event (Admin only)
+// @Description Create a new event (Admin or organizer). The caller becomes the event owner.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -117,6 +260,7 @@ func (h *Handler) Stats(c *gin.Context) {
 // @Failure 400 {object} ErrorResponse
 // @Security BearerAuth
 // @Router /admin/events [post]
+// @Router /organizer/events [post]
 func (h *Handler) Create(c *gin.Context) {
 	var req CreateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -132,6 +276,14 @@ func (h *Handler) Create(c *gin.Context) {
 		Capacity:         req.Capacity,
 		Remaining:        req.Capacity,
 		TicketPriceCents: req.TicketPriceCents,
+		ResaleCapPercent: req.ResaleCapPercent,
+		AttendeeFields:   req.AttendeeFields,
+		Questions:        req.Questions,
+		VenueID:          req.VenueID,
+		OrganizerID:      req.OrganizerID,
+	}
+	if uid := c.GetString(auth.CtxUserID); uid != "" {
+		e.OwnerID = &uid
 	}
 	if err := h.svc.Create(c, e); err != nil {
 		h.logger.Error("Failed to create event", zap.String("event_id", e.ID), zap.Error(err))
@@ -139,12 +291,14 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 	h.logger.Info("Event created", zap.String("event_id", e.ID))
-	c.JSON(http.StatusCreated, eventToResponse(e))
+	resp := eventToResponse(e)
+	h.record(c, audit.ActionEventCreate, e.ID, audit.Diff(nil, resp), nil)
+	c.JSON(http.StatusCreated, resp)
 }
 
 // Update godoc
 // @Summary Update event
-// @Description Update event details (Admin only)
+// @Description Update event details (Admin, or the organizer who owns the event). A capacity change adds or removes the same number of unsold seats.
 // @Tags events
 // @Accept json
 // @Produce json
@@ -152,9 +306,12 @@ func (h *Handler) Create(c *gin.Context) {
 // @Param input body UpdateEventRequest true "Updated event data"
 // @Success 200 {object} EventResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Event owned by another organizer"
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Capacity below tickets already sold"
 // @Security BearerAuth
 // @Router /admin/events/{id} [put]
+// @Router /organizer/events/{id} [put]
 func (h *Handler) Update(c *gin.Context) {
 	id := c.Param("id")
 	var req UpdateEventRequest
@@ -179,6 +336,15 @@ func (h *Handler) Update(c *gin.Context) {
 		Capacity:         existing.Capacity,
 		Remaining:        existing.Remaining,
 		TicketPriceCents: existing.TicketPriceCents,
+		ResaleCapPercent: existing.ResaleCapPercent,
+		AttendeeFields:   existing.AttendeeFields,
+		Questions:        existing.Questions,
+		VenueID:          existing.VenueID,
+		OrganizerID:      existing.OrganizerID,
+		OwnerID:          existing.OwnerID,
+		SeriesID:         existing.SeriesID,
+		// Editing a single occurrence detaches it from series-wide edits
+		SeriesOverride: existing.SeriesID != nil,
 	}
 	if req.Name != nil {
 		e.Name = *req.Name
@@ -193,59 +359,142 @@ func (h *Handler) Update(c *gin.Context) {
 		e.EndsAt = *req.EndsAt
 	}
 	if req.Capacity != nil {
-		// adjust remaining only if capacity increased and remaining less than new capacity
-		if *req.Capacity >= e.Remaining {
-			e.Remaining = minInt(e.Remaining, *req.Capacity)
-		}
+		// Remaining follows in the repository, against the stored count
 		e.Capacity = *req.Capacity
 	}
 	if req.TicketPriceCents != nil {
 		e.TicketPriceCents = *req.TicketPriceCents
 	}
+	if req.ResaleCapPercent != nil {
+		e.ResaleCapPercent = *req.ResaleCapPercent
+	}
+	if req.AttendeeFields != nil {
+		e.AttendeeFields = *req.AttendeeFields
+	}
+	if req.Questions != nil {
+		e.Questions = *req.Questions
+	}
+	if e.VenueID, err = optionalRef(e.VenueID, req.VenueID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid venue_id"})
+		return
+	}
+	if e.OrganizerID, err = optionalRef(e.OrganizerID, req.OrganizerID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid organizer_id"})
+		return
+	}
 	if err := h.svc.Update(c, e); err != nil {
+		if errors.Is(err, ErrUnknownVenue) || errors.Is(err, ErrUnknownOrganizer) || errors.Is(err, ErrDuplicateAttendeeField) ||
+			errors.Is(err, ErrInvalidQuestions) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		if errors.Is(err, ErrCapacityBelowSold) {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+			return
+		}
 		h.logger.Error("Failed to update event", zap.String("event_id", id), zap.Error(err))
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
 	h.logger.Info("Event updated", zap.String("event_id", id))
-	c.JSON(http.StatusOK, eventToResponse(e))
+	resp := eventToResponse(e)
+	h.record(c, audit.ActionEventUpdate, id, audit.Diff(eventToResponse(existing), resp), nil)
+	c.JSON(http.StatusOK, resp)
 }
 
 // Delete godoc
 // @Summary Delete event
-// @Description Delete an event (Admin only)
+// @Description Archive an event (soft delete, Admin only). Refused with 409 while CONFIRMED bookings exist unless force=true, which cancels them.
 // @Tags events
 // @Param id path string true "Event ID"
+// @Param force query bool false "Cancel all outstanding bookings and delete anyway"
 // @Success 204 "No Content"
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Event has confirmed bookings"
 // @Security BearerAuth
 // @Router /admin/events/{id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.svc.Delete(c, id); err != nil {
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err := h.svc.Delete(c, id, force); err != nil {
+		if errors.Is(err, ErrEventHasBookings) {
+			h.logger.Warn("Event delete refused", zap.String("event_id", id), zap.Error(err))
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+			return
+		}
 		h.logger.Error("Failed to delete event", zap.String("event_id", id), zap.Error(err))
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
 		return
 	}
-	h.logger.Info("Event deleted", zap.String("event_id", id))
+	h.logger.Info("Event deleted", zap.String("event_id", id), zap.Bool("force", force))
+	h.record(c, audit.ActionEventDelete, id, nil, map[string]any{"force": force})
 	c.Status(http.StatusNoContent)
 }
 
-func minInt(a, b int) int {
-	if a < b {
-		return a
+// Restore godoc
+// @Summary Restore event
+// @Description Restore a soft-deleted event (Admin only)
+// @Tags events
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} EventResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/events/{id}/restore [post]
+func (h *Handler) Restore(c *gin.Context) {
+	id := c.Param("id")
+	e, err := h.svc.Restore(c, id)
+	if err != nil {
+		h.logger.Error("Failed to restore event", zap.String("event_id", id), zap.Error(err))
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
 	}
-	return b
+	h.logger.Info("Event restored", zap.String("event_id", id))
+	h.record(c, audit.ActionEventRestore, id, nil, nil)
+	c.JSON(http.StatusOK, eventToResponse(e))
+}
+
+// record writes an event change to the audit log; a failure is logged there
+func (h *Handler) record(c *gin.Context, action, id string, changes audit.Changes, detail map[string]any) {
+	_ = h.audit.Record(c, &audit.Entry{Action: action, TargetType: "event", TargetID: id, Changes: changes, Detail: detail})
+}
+
+// optionalRef applies an update to a nullable reference: nil keeps the current
+// value, an empty string clears it, anything else must be a UUID.
+func optionalRef(current, update *string) (*string, error) {
+	if update == nil {
+		return current, nil
+	}
+	if *update == "" {
+		return nil, nil
+	}
+	if _, err := uuid.Parse(*update); err != nil {
+		return nil, err
+	}
+	return update, nil
 }
 
 func eventToResponse(e *Event) EventResponse {
-	return EventResponse{
-		ID:           e.ID,
-		Name:         e.Name,
-		Description:  e.Description,
-		DateTime:     e.StartsAt,
-		TotalTickets: e.Capacity,
-		TicketPrice:  float64(e.TicketPriceCents) / 100.0,
-		Remaining:    e.Remaining,
+	resp := EventResponse{
+		ID:             e.ID,
+		Name:           e.Name,
+		Description:    e.Description,
+		DateTime:       e.StartsAt,
+		TotalTickets:   e.Capacity,
+		TicketPrice:    float64(e.TicketPriceCents) / 100.0,
+		Remaining:      e.Remaining,
+		ResaleCap:      e.ResaleCapPercent,
+		AttendeeFields: e.AttendeeFields,
+		Questions:      e.Questions,
+		SeriesID:       e.SeriesID,
+	}
+	if e.Venue != nil {
+		v := venue.ToResponse(e.Venue)
+		resp.Venue = &v
+	}
+	if e.Organizer != nil {
+		o := organizer.ToResponse(e.Organizer)
+		resp.Organizer = &o
 	}
+	return resp
 }
@@ -2,19 +2,46 @@
 // CRUD operations, seat reservations, and statistics tracking.
 package event
 
-import "time"
+import (
+	"time"
+
+	"ticket-booking/internal/organizer"
+	"ticket-booking/internal/venue"
+
+	"gorm.io/gorm"
+)
 
 // Event represents a ticketed event with capacity management.
 // Tracks both total capacity and remaining available tickets for real-time availability.
 type Event struct {
-	ID               string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
-	Name             string    `gorm:"type:text;not null" json:"name"`                                         // Event title
-	Description      *string   `gorm:"type:text" json:"description,omitempty"`                                 // Optional event details
-	StartsAt         time.Time `json:"starts_at"`                                                              // Event start time (UTC)
-	EndsAt           time.Time `json:"ends_at"`                                                                // Event end time (UTC)
-	Capacity         int       `gorm:"not null" json:"capacity"`                                               // Total tickets available (immutable after creation)
-	Remaining        int       `gorm:"not null" json:"remaining"`                                              // Current available tickets (decreases with bookings)
-	TicketPriceCents int64     `gorm:"column:ticket_price_cents;not null;default:0" json:"ticket_price_cents"` // Price per ticket in cents for precision
-	CreatedAt        time.Time `json:"created_at"`                                                             // Event creation timestamp
-	UpdatedAt        time.Time `json:"updated_at"`                                                             // Last modification timestamp
+	ID               string          `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name             string          `gorm:"type:text;not null" json:"name"`                                         // Event title
+	Description      *string         `gorm:"type:text" json:"description,omitempty"`                                 // Optional event details
+	StartsAt         time.Time       `json:"starts_at"`                                                              // Event start time (UTC)
+	EndsAt           time.Time       `json:"ends_at"`                                                                // Event end time (UTC)
+	Capacity         int             `gorm:"not null" json:"capacity"`                                               // Total tickets available (immutable after creation)
+	Remaining        int             `gorm:"not null" json:"remaining"`                                              // Current available tickets (decreases with bookings)
+	TicketPriceCents int64           `gorm:"column:ticket_price_cents;not null;default:0" json:"ticket_price_cents"` // Price per ticket in cents for precision
+	ResaleCapPercent int             `gorm:"not null;default:0" json:"resale_cap_percent"`                           // Resale price cap, percent of the price paid; 0 disables resale
+	AttendeeFields   []AttendeeField `gorm:"type:jsonb;serializer:json" json:"attendee_fields,omitempty"`            // Custom details asked about each attendee
+	Questions        []Question      `gorm:"type:jsonb;serializer:json" json:"questions,omitempty"`                  // Registration form answered once per booking
+	VenueID          *string         `gorm:"type:uuid;index" json:"venue_id,omitempty"`                              // Where the event takes place
+	OrganizerID      *string         `gorm:"type:uuid;index" json:"organizer_id,omitempty"`                          // Who runs the event
+	OwnerID          *string         `gorm:"type:uuid;index" json:"owner_id,omitempty"`                              // User who created the event; organizers may only manage their own
+	SeriesID         *string         `gorm:"type:uuid;index" json:"series_id,omitempty"`                             // Recurring series this occurrence was generated from
+	SeriesOverride   bool            `gorm:"not null;default:false" json:"series_override,omitempty"`                // Edited individually; series-wide edits skip it
+	CreatedAt        time.Time       `json:"created_at"`                                                             // Event creation timestamp
+	UpdatedAt        time.Time       `json:"updated_at"`                                                             // Last modification timestamp
+	DeletedAt        gorm.DeletedAt  `gorm:"index" json:"deleted_at,omitempty" swaggertype:"string"`                 // Soft delete marker; deleted events are hidden by default scopes
+
+	Venue     *venue.Venue         `gorm:"foreignKey:VenueID" json:"venue,omitempty"`         // Preloaded on reads
+	Organizer *organizer.Organizer `gorm:"foreignKey:OrganizerID" json:"organizer,omitempty"` // Preloaded on reads
+}
+
+// AttendeeField is a custom detail the organiser asks about each attendee,
+// on top of their name and email
+type AttendeeField struct {
+	Key      string `json:"key" binding:"required,max=40" example:"company"`
+	Label    string `json:"label" binding:"required,max=100" example:"Company"`
+	Required bool   `json:"required,omitempty"`
 }
@@ -0,0 +1,176 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// QuestionType says how a registration question is answered
+type QuestionType string
+
+const (
+	// QuestionText questions take free text
+	QuestionText QuestionType = "text"
+	// QuestionChoice questions take one of their options
+	QuestionChoice QuestionType = "choice"
+	// QuestionCheckbox questions take true or false; a required one must be ticked
+	QuestionCheckbox QuestionType = "checkbox"
+)
+
+// DefaultAnswerLength caps text answers of questions without a max_length
+const DefaultAnswerLength = 500
+
+// Question is one entry of an event's registration form, answered once per
+// booking
+type Question struct {
+	Key       string       `json:"key" binding:"required,max=40" example:"tshirt_size"`
+	Label     string       `json:"label" binding:"required,max=200" example:"T-shirt size"`
+	Type      QuestionType `json:"type" binding:"required,oneof=text choice checkbox" example:"choice"`
+	Required  bool         `json:"required,omitempty"`
+	Options   []string     `json:"options,omitempty" binding:"max=50,dive,required,max=100"`    // Choice questions only
+	MaxLength int          `json:"max_length,omitempty" binding:"min=0,max=2000" example:"200"` // Text questions only; defaults to DefaultAnswerLength
+	Pattern   string       `json:"pattern,omitempty" binding:"max=200" example:"^[A-Z]{2}$"`    // Text questions only; non-empty answers must match
+
+	re *regexp.Regexp // Pattern compiled, once per form
+}
+
+var (
+	// ErrInvalidQuestions is returned for registration forms with repeated
+	// keys, choices without options or patterns that do not compile
+	ErrInvalidQuestions = errors.New("invalid registration questions")
+	// ErrInvalidAnswers is returned when answers do not fit the event's form
+	ErrInvalidAnswers = errors.New("invalid registration answers")
+)
+
+// checkQuestions rejects forms that could not be answered and compiles their
+// patterns
+func checkQuestions(qs []Question) error {
+	seen := make(map[string]bool, len(qs))
+	for i := range qs {
+		q := &qs[i]
+		if seen[q.Key] {
+			return fmt.Errorf("%w: key %q used twice", ErrInvalidQuestions, q.Key)
+		}
+		seen[q.Key] = true
+		switch q.Type {
+		case QuestionChoice:
+			if len(q.Options) == 0 {
+				return fmt.Errorf("%w: %s needs options", ErrInvalidQuestions, q.Key)
+			}
+		case QuestionText:
+			if _, err := q.pattern(); err != nil {
+				return err
+			}
+		}
+		if q.Type != QuestionChoice && len(q.Options) > 0 {
+			return fmt.Errorf("%w: only choice questions have options", ErrInvalidQuestions)
+		}
+	}
+	return nil
+}
+
+// CheckAnswers validates a booking's answers against the event's form and
+// returns them cleaned up: text is trimmed and blank optional answers are
+// dropped. Unknown keys, missing required answers, wrong types, unknown
+// options and text that is too long or misses the pattern fail with
+// ErrInvalidAnswers.
+func (e *Event) CheckAnswers(answers map[string]any) (map[string]any, error) {
+	known := make(map[string]bool, len(e.Questions))
+	for _, q := range e.Questions {
+		known[q.Key] = true
+	}
+	for k := range answers {
+		if !known[k] {
+			return nil, fmt.Errorf("%w: unknown question %q", ErrInvalidAnswers, k)
+		}
+	}
+
+	out := make(map[string]any, len(answers))
+	for i := range e.Questions {
+		q := &e.Questions[i]
+		v, given := answers[q.Key]
+		if v == nil {
+			given = false
+		}
+		switch q.Type {
+		case QuestionCheckbox:
+			ticked, ok := v.(bool)
+			if given && !ok {
+				return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidAnswers, q.Key)
+			}
+			if q.Required && !ticked {
+				return nil, fmt.Errorf("%w: %s must be ticked", ErrInvalidAnswers, q.Key)
+			}
+			if given {
+				out[q.Key] = ticked
+			}
+		default:
+			s, ok := v.(string)
+			if given && !ok {
+				return nil, fmt.Errorf("%w: %s must be text", ErrInvalidAnswers, q.Key)
+			}
+			if s = strings.TrimSpace(s); s == "" {
+				if q.Required {
+					return nil, fmt.Errorf("%w: %s is required", ErrInvalidAnswers, q.Key)
+				}
+				continue
+			}
+			if err := q.check(s); err != nil {
+				if errors.Is(err, ErrInvalidQuestions) {
+					return nil, err
+				}
+				return nil, fmt.Errorf("%w: %s %s", ErrInvalidAnswers, q.Key, err)
+			}
+			out[q.Key] = s
+		}
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// pattern returns q's compiled pattern, or nil when it has none. Forms
+// loaded from storage compile theirs on first use; a pattern that no longer
+// compiles fails with ErrInvalidQuestions.
+func (q *Question) pattern() (*regexp.Regexp, error) {
+	if q.Pattern == "" || q.re != nil {
+		return q.re, nil
+	}
+	re, err := regexp.Compile(q.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s pattern: %s", ErrInvalidQuestions, q.Key, err)
+	}
+	q.re = re
+	return re, nil
+}
+
+// check validates a non-blank text or choice answer
+func (q *Question) check(s string) error {
+	if q.Type == QuestionChoice {
+		for _, o := range q.Options {
+			if s == o {
+				return nil
+			}
+		}
+		return errors.New("is not one of the options")
+	}
+	limit := q.MaxLength
+	if limit == 0 {
+		limit = DefaultAnswerLength
+	}
+	if utf8.RuneCountInString(s) > limit {
+		return fmt.Errorf("is longer than %d characters", limit)
+	}
+	re, err := q.pattern()
+	if err != nil {
+		return err
+	}
+	if re != nil && !re.MatchString(s) {
+		return errors.New("does not match the expected format")
+	}
+	return nil
+}
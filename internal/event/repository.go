@@ -1,17 +1,25 @@
 package event
 
 import (
+	"ticket-booking/internal/organizer"
+	"ticket-booking/internal/venue"
+
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type EventRepository interface {
 	List() ([]Event, error)
-	ListPage(limit, offset int) ([]Event, error)
+	ListPage(f ListFilter) ([]Event, error)
 	Get(id string) (*Event, error)
 	Create(e *Event) error
 	Update(e *Event) error
-	Delete(id string) error
+	// Delete fails with ErrEventHasBookings while CONFIRMED bookings exist
+	// unless cancelConfirmed is set
+	Delete(id string, cancelConfirmed bool) (cancelled int64, err error)
+	Restore(id string) error
+	FindVenue(id string) (*venue.Venue, error)
+	FindOrganizer(id string) (*organizer.Organizer, error)
 	Reserve(tx *gorm.DB, eventID string, qty int) (bool, error)   // legacy atomic
 	ReserveTx(tx *gorm.DB, eventID string, qty int) (bool, error) // new explicit tx reservation
 }
@@ -20,40 +28,200 @@ type repo struct{ db *gorm.DB }
 
 func NewEventRepository(db *gorm.DB) EventRepository { return &repo{db} }
 
+// withRefs preloads the venue and organizer embedded in event responses
+func (r *repo) withRefs() *gorm.DB {
+	return r.db.Preload("Venue").Preload("Organizer")
+}
+
 func (r *repo) List() ([]Event, error) {
 	var out []Event
-	return out, r.db.Order("starts_at asc").Find(&out).Error
+	return out, r.withRefs().Order("starts_at asc").Find(&out).Error
 }
 
-func (r *repo) ListPage(limit, offset int) ([]Event, error) {
+func (r *repo) ListPage(f ListFilter) ([]Event, error) {
 	var out []Event
-	q := r.db.Order("starts_at asc")
-	if limit > 0 {
-		q = q.Limit(limit)
+	q := r.withRefs().Order(f.OrderBy())
+	if f.Query != "" {
+		p := likePattern(f.Query)
+		q = q.Where("(name ILIKE ? OR description ILIKE ?)", p, p)
+	}
+	if f.From != nil {
+		q = q.Where("starts_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("starts_at <= ?", *f.To)
+	}
+	if f.MinPriceCents != nil {
+		q = q.Where("ticket_price_cents >= ?", *f.MinPriceCents)
+	}
+	if f.MaxPriceCents != nil {
+		q = q.Where("ticket_price_cents <= ?", *f.MaxPriceCents)
+	}
+	if f.Available {
+		q = q.Where("remaining > 0")
+	}
+	if f.SeriesID != "" {
+		q = q.Where("series_id = ?", f.SeriesID)
 	}
-	if offset > 0 {
-		q = q.Offset(offset)
+	if f.OwnerID != "" {
+		q = q.Where("owner_id = ?", f.OwnerID)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.Offset > 0 {
+		q = q.Offset(f.Offset)
 	}
 	return out, q.Find(&out).Error
 }
 
 func (r *repo) Get(id string) (*Event, error) {
 	var e Event
-	if err := r.db.First(&e, "id = ?", id).Error; err != nil {
+	if err := r.withRefs().First(&e, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &e, nil
 }
 
-func (r *repo) Create(e *Event) error  { return r.db.Create(e).Error }
-func (r *repo) Update(e *Event) error  { return r.db.Save(e).Error }
-func (r *repo) Delete(id string) error { return r.db.Delete(&Event{}, "id = ?", id).Error }
+// Create and Update write only the event row; venues and organizers are
+// managed through their own packages and must never be upserted from here.
+func (r *repo) Create(e *Event) error { return r.db.Omit(clause.Associations).Create(e).Error }
+
+// Update never writes e.Remaining, which may predate reservations made since
+// e was read. A capacity change shifts the stored count by the same delta
+// under the row lock, and e gets the result; a count below zero fails with
+// ErrCapacityBelowSold.
+func (r *repo) Update(e *Event) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Raw("UPDATE events SET remaining = remaining + ? - capacity WHERE id = ? AND deleted_at IS NULL RETURNING remaining",
+			e.Capacity, e.ID).Scan(&e.Remaining)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if e.Remaining < 0 {
+			return ErrCapacityBelowSold
+		}
+		return tx.Omit(clause.Associations, "remaining").Save(e).Error
+	})
+}
+
+// Delete soft-deletes an event. Outstanding PENDING bookings are always cancelled
+// (they would otherwise confirm against an archived event); CONFIRMED bookings are
+// cancelled only when cancelConfirmed is set; otherwise their presence fails the
+// delete with ErrEventHasBookings, checked under the event's row lock. As when the booking service cancels,
+// their tickets are voided and taken off resale. Released seats are returned to
+// the event so a later restore starts from a consistent remaining count.
+func (r *repo) Delete(id string, cancelConfirmed bool) (int64, error) {
+	statuses := []string{"PENDING"}
+	if cancelConfirmed {
+		statuses = append(statuses, "CONFIRMED")
+	}
+
+	var cancelled int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var e Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&e, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if !cancelConfirmed {
+			var confirmed int64
+			if err := tx.Raw(
+				"SELECT COUNT(*) FROM bookings WHERE event_id = ? AND status = ?",
+				id, "CONFIRMED",
+			).Scan(&confirmed).Error; err != nil {
+				return err
+			}
+			if confirmed > 0 {
+				return ErrEventHasBookings
+			}
+		}
+
+		var seats int64
+		if err := tx.Raw(
+			"SELECT COALESCE(SUM(quantity),0) FROM bookings WHERE event_id = ? AND status IN ?",
+			id, statuses,
+		).Scan(&seats).Error; err != nil {
+			return err
+		}
+
+		// Tickets and listings first, while the bookings still match statuses
+		if err := tx.Exec(
+			`UPDATE resale_listings SET status = 'REMOVED', closed_at = now()
+			WHERE status IN ('ACTIVE','RESERVED') AND booking_id IN (SELECT id FROM bookings WHERE event_id = ? AND status IN ?)`,
+			id, statuses,
+		).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(
+			`UPDATE tickets SET status = 'VOID', voided_at = now()
+			WHERE status = 'VALID' AND booking_id IN (SELECT id FROM bookings WHERE event_id = ? AND status IN ?)`,
+			id, statuses,
+		).Error; err != nil {
+			return err
+		}
+
+		res := tx.Exec(
+			"UPDATE bookings SET status = ?, updated_at = now() WHERE event_id = ? AND status IN ?",
+			"CANCELLED", id, statuses,
+		)
+		if res.Error != nil {
+			return res.Error
+		}
+		cancelled = res.RowsAffected
+
+		if seats > 0 {
+			if err := tx.Exec(
+				"UPDATE events SET remaining = LEAST(remaining + ?, capacity) WHERE id = ?",
+				seats, id,
+			).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&Event{}, "id = ?", id).Error
+	})
+	return cancelled, err
+}
+
+// Restore clears the soft delete marker of an archived event.
+func (r *repo) Restore(id string) error {
+	res := r.db.Unscoped().Model(&Event{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repo) FindVenue(id string) (*venue.Venue, error) {
+	var v venue.Venue
+	if err := r.db.First(&v, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *repo) FindOrganizer(id string) (*organizer.Organizer, error) {
+	var o organizer.Organizer
+	if err := r.db.First(&o, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
 
 // atomic reservation (used in legacy code)
 func (r *repo) Reserve(tx *gorm.DB, eventID string, qty int) (bool, error) {
 	res := tx.Exec(`UPDATE events 
         SET remaining = remaining - ? 
-        WHERE id = ? AND remaining >= ?`, qty, eventID, qty)
+        WHERE id = ? AND remaining >= ? AND deleted_at IS NULL`, qty, eventID, qty)
 	if res.Error != nil {
 		return false, res.Error
 	}
@@ -5,12 +5,20 @@ import "github.com/gin-gonic/gin"
 func RegisterPublicRoutes(r *gin.RouterGroup, h *Handler) {
 	r.GET("/events", h.List)
 	r.GET("/events/:id", h.Get)
-	// stats per event
-	r.GET("/events/:id/stats", h.Stats)
 }
 
 func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
 	r.POST("/events", h.Create)
 	r.PUT("/events/:id", h.Update)
 	r.DELETE("/events/:id", h.Delete)
+	r.POST("/events/:id/restore", h.Restore)
+	r.GET("/events/:id/stats", h.Stats)
+}
+
+// RegisterOrganizerRoutes exposes event management scoped to the caller's own events
+func RegisterOrganizerRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/events", h.ListOwned)
+	r.POST("/events", h.Create)
+	r.PUT("/events/:id", h.RequireOwner(), h.Update)
+	r.GET("/events/:id/stats", h.RequireOwner(), h.Stats)
 }
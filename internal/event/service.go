@@ -3,9 +3,10 @@ package event
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"time"
 
+	"ticket-booking/internal/auth"
 	"ticket-booking/pkg/cache"
 
 	"go.uber.org/zap"
@@ -26,31 +27,91 @@ type ServiceInterface interface {
 	List(ctx context.Context) ([]Event, error)
 	// Reserve attempts fast Redis-based seat reservation (fast path)
 	Reserve(ctx context.Context, eventID string, qty int) (bool, error)
-	// ListPage retrieves paginated events with per-page caching
-	ListPage(ctx context.Context, limit, offset int) ([]Event, error)
+	// ListPage retrieves a filtered, paginated page of events with per-page caching
+	ListPage(ctx context.Context, f ListFilter) ([]Event, error)
 	// Create creates a new event and initializes cache
 	Create(ctx context.Context, e *Event) error
 	// Update modifies an event and invalidates relevant cache
 	Update(ctx context.Context, e *Event) error
-	// Delete removes an event and cleans up cache
-	Delete(ctx context.Context, id string) error
+	// Delete archives an event (soft delete) and cleans up cache.
+	// Refuses with ErrEventHasBookings while CONFIRMED bookings exist unless force is set,
+	// in which case those bookings are cancelled together with the event.
+	Delete(ctx context.Context, id string, force bool) error
+	// Restore brings a soft-deleted event back into the default scope
+	Restore(ctx context.Context, id string) (*Event, error)
 	// StatsDB calculates event statistics from database (CONFIRMED bookings only)
 	StatsDB(ctx context.Context, eventID string) (tickets int64, revenueCents int64, err error)
 }
 
+// Indexer keeps an external search index in sync with event writes.
+// Index failures are logged and never fail the write that triggered them.
+type Indexer interface {
+	// IndexEvent creates or replaces the event's search document
+	IndexEvent(ctx context.Context, e *Event) error
+	// DeleteEvent removes the event's search document
+	DeleteEvent(ctx context.Context, id string) error
+	// UpdateRemaining refreshes seat availability after reservations/releases
+	UpdateRemaining(ctx context.Context, id string, remaining int) error
+}
+
+type noopIndexer struct{}
+
+func (noopIndexer) IndexEvent(context.Context, *Event) error           { return nil }
+func (noopIndexer) DeleteEvent(context.Context, string) error          { return nil }
+func (noopIndexer) UpdateRemaining(context.Context, string, int) error { return nil }
+
+// ErrEventHasBookings is returned when deleting an event that still has CONFIRMED bookings
+var ErrEventHasBookings = errors.New("event has confirmed bookings")
+
+// ErrNotOwner is returned when an organizer touches an event they do not own
+var ErrNotOwner = errors.New("event is owned by another organizer")
+
+// CanManage reports whether the caller may manage e: events:write covers
+// every event, events:own only the events the caller owns.
+func CanManage(e *Event, userID string, perms []string) bool {
+	if auth.HasPermission(perms, auth.PermEventsWrite) {
+		return true
+	}
+	return auth.HasPermission(perms, auth.PermEventsOwn) &&
+		userID != "" && e.OwnerID != nil && *e.OwnerID == userID
+}
+
+var (
+	// ErrUnknownVenue is returned when an event references a venue that does not exist
+	ErrUnknownVenue = errors.New("venue not found")
+	// ErrUnknownOrganizer is returned when an event references an organizer that does not exist
+	ErrUnknownOrganizer = errors.New("organizer not found")
+	// ErrCapacityRequired is returned when neither the event nor its venue provides a capacity
+	ErrCapacityRequired = errors.New("capacity is required when the venue has no default capacity")
+	// ErrDuplicateAttendeeField is returned when two attendee fields share a key
+	ErrDuplicateAttendeeField = errors.New("attendee field keys must be unique")
+	// ErrCapacityBelowSold is returned when an update would leave fewer seats
+	// than are already taken
+	ErrCapacityBelowSold = errors.New("capacity is below tickets already sold")
+)
+
 // Service implements EventInterface with Redis caching for performance.
 // Uses dual-path strategy: Redis for speed, database transactions for consistency.
 type Service struct {
-	db     *gorm.DB        // Database connection for transactions
-	repo   EventRepository // Data access layer for events
-	cache  cache.Cache     // Redis cache for performance optimization
-	logger *zap.Logger     // Structured logger
+	db      *gorm.DB        // Database connection for transactions
+	repo    EventRepository // Data access layer for events
+	cache   cache.Cache     // Redis cache for performance optimization
+	indexer Indexer         // Search index sync (no-op unless configured)
+	logger  *zap.Logger     // Structured logger
 }
 
 // NewService creates a new event service with required dependencies.
 // All parameters are required for proper caching and transaction handling.
 func NewService(db *gorm.DB, r EventRepository, cache cache.Cache, logger *zap.Logger) *Service {
-	return &Service{db: db, repo: r, cache: cache, logger: logger}
+	return &Service{db: db, repo: r, cache: cache, indexer: noopIndexer{}, logger: logger}
+}
+
+// SetIndexer enables search index synchronisation for event writes and seat changes.
+func (s *Service) SetIndexer(ix Indexer) {
+	if ix == nil {
+		ix = noopIndexer{}
+	}
+	s.indexer = ix
 }
 
 // List retrieves all events with Redis caching for improved performance.
@@ -87,10 +148,10 @@ func (s *Service) List(ctx context.Context) ([]Event, error) {
 	return evts, nil
 }
 
-// ListPage returns paginated events with per-page Redis caching.
-// Each page is cached separately to optimize common pagination patterns.
-func (s *Service) ListPage(ctx context.Context, limit, offset int) ([]Event, error) {
-	cacheKey := fmt.Sprintf("events:list:%d:%d", limit, offset)
+// ListPage returns filtered, paginated events with per-page Redis caching.
+// Each page is cached separately, keyed by pagination and the canonical filter.
+func (s *Service) ListPage(ctx context.Context, f ListFilter) ([]Event, error) {
+	cacheKey := f.CacheKey()
 	if raw, err := s.cache.Get(ctx, cacheKey); err == nil && raw != "" {
 		var evts []Event
 		if err := json.Unmarshal([]byte(raw), &evts); err == nil {
@@ -100,7 +161,7 @@ func (s *Service) ListPage(ctx context.Context, limit, offset int) ([]Event, err
 		s.logger.Warn("Failed to unmarshal cached events page", zap.String("cache_key", cacheKey), zap.Error(err))
 	}
 
-	evts, err := s.repo.ListPage(limit, offset)
+	evts, err := s.repo.ListPage(f)
 	if err != nil {
 		s.logger.Error("Failed to list events page from database", zap.Error(err))
 		return nil, err
@@ -110,7 +171,7 @@ func (s *Service) ListPage(ctx context.Context, limit, offset int) ([]Event, err
 			s.logger.Warn("Failed to cache events page", zap.String("cache_key", cacheKey), zap.Error(err))
 		}
 	}
-	s.logger.Info("Events page retrieved from database", zap.Int("count", len(evts)), zap.Int("limit", limit), zap.Int("offset", offset))
+	s.logger.Info("Events page retrieved from database", zap.Int("count", len(evts)), zap.String("cache_key", cacheKey))
 	return evts, nil
 }
 
@@ -124,7 +185,25 @@ func (s *Service) Get(ctx context.Context, id string) (*Event, error) {
 	return event, nil
 }
 
+// Create persists a new event. When capacity is omitted it falls back to the
+// venue's default capacity, and remaining seats start at the final capacity.
 func (s *Service) Create(ctx context.Context, e *Event) error {
+	if err := checkAttendeeFields(e.AttendeeFields); err != nil {
+		return err
+	}
+	if err := checkQuestions(e.Questions); err != nil {
+		return err
+	}
+	if err := s.resolveRefs(e); err != nil {
+		return err
+	}
+	if e.Capacity == 0 && e.Venue != nil {
+		e.Capacity = e.Venue.DefaultCapacity
+		e.Remaining = e.Capacity
+	}
+	if e.Capacity <= 0 {
+		return ErrCapacityRequired
+	}
 	if err := s.repo.Create(e); err != nil {
 		s.logger.Error("Failed to create event", zap.String("event_id", e.ID), zap.Error(err))
 		return err
@@ -133,12 +212,22 @@ func (s *Service) Create(ctx context.Context, e *Event) error {
 	_ = s.cache.Set(ctx, "event:remaining:"+e.ID, e.Capacity, 0)
 	_ = s.cache.Set(ctx, "event:revenue:"+e.ID, 0, 0)
 	_ = s.cache.Del(ctx, "events:list")
+	s.index(ctx, e)
 
 	s.logger.Info("Event created", zap.String("event_id", e.ID))
 	return nil
 }
 
 func (s *Service) Update(ctx context.Context, e *Event) error {
+	if err := checkAttendeeFields(e.AttendeeFields); err != nil {
+		return err
+	}
+	if err := checkQuestions(e.Questions); err != nil {
+		return err
+	}
+	if err := s.resolveRefs(e); err != nil {
+		return err
+	}
 	if err := s.repo.Update(e); err != nil {
 		s.logger.Error("Failed to update event", zap.String("event_id", e.ID), zap.Error(err))
 		return err
@@ -146,13 +235,24 @@ func (s *Service) Update(ctx context.Context, e *Event) error {
 
 	_ = s.cache.Set(ctx, "event:remaining:"+e.ID, e.Remaining, 0)
 	_ = s.cache.Del(ctx, "events:list")
+	s.index(ctx, e)
 
 	s.logger.Info("Event updated", zap.String("event_id", e.ID))
 	return nil
 }
 
-func (s *Service) Delete(ctx context.Context, id string) error {
-	if err := s.repo.Delete(id); err != nil {
+func (s *Service) Delete(ctx context.Context, id string, force bool) error {
+	if _, err := s.repo.Get(id); err != nil {
+		s.logger.Error("Failed to load event for delete", zap.String("event_id", id), zap.Error(err))
+		return err
+	}
+
+	cancelled, err := s.repo.Delete(id, force)
+	if errors.Is(err, ErrEventHasBookings) {
+		s.logger.Warn("Refusing to delete event with confirmed bookings", zap.String("event_id", id))
+		return err
+	}
+	if err != nil {
 		s.logger.Error("Failed to delete event", zap.String("event_id", id), zap.Error(err))
 		return err
 	}
@@ -160,8 +260,75 @@ func (s *Service) Delete(ctx context.Context, id string) error {
 	_ = s.cache.Del(ctx, "event:remaining:"+id)
 	_ = s.cache.Del(ctx, "event:revenue:"+id)
 	_ = s.cache.Del(ctx, "events:list")
+	if err := s.indexer.DeleteEvent(ctx, id); err != nil {
+		s.logger.Warn("Failed to remove event from search index", zap.String("event_id", id), zap.Error(err))
+	}
+
+	s.logger.Info("Event deleted", zap.String("event_id", id), zap.Bool("force", force), zap.Int64("bookings_cancelled", cancelled))
+	return nil
+}
+
+// Restore un-archives a soft-deleted event and re-seeds its seat counter in cache.
+func (s *Service) Restore(ctx context.Context, id string) (*Event, error) {
+	if err := s.repo.Restore(id); err != nil {
+		s.logger.Error("Failed to restore event", zap.String("event_id", id), zap.Error(err))
+		return nil, err
+	}
+
+	ev, err := s.repo.Get(id)
+	if err != nil {
+		s.logger.Error("Failed to load restored event", zap.String("event_id", id), zap.Error(err))
+		return nil, err
+	}
+
+	_ = s.cache.Set(ctx, "event:remaining:"+id, ev.Remaining, 0)
+	_ = s.cache.Del(ctx, "events:list")
+	s.index(ctx, ev)
+
+	s.logger.Info("Event restored", zap.String("event_id", id))
+	return ev, nil
+}
+
+// checkAttendeeFields rejects field lists that reuse a key
+func checkAttendeeFields(fields []AttendeeField) error {
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if seen[f.Key] {
+			return ErrDuplicateAttendeeField
+		}
+		seen[f.Key] = true
+	}
+	return nil
+}
 
-	s.logger.Info("Event deleted", zap.String("event_id", id))
+// resolveRefs checks that the referenced venue and organizer exist and attaches
+// them to the event so responses can embed their details.
+func (s *Service) resolveRefs(e *Event) error {
+	e.Venue, e.Organizer = nil, nil
+	if e.VenueID != nil {
+		v, err := s.repo.FindVenue(*e.VenueID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.logger.Warn("Event references unknown venue", zap.String("venue_id", *e.VenueID))
+				return ErrUnknownVenue
+			}
+			s.logger.Error("Failed to load event venue", zap.String("venue_id", *e.VenueID), zap.Error(err))
+			return err
+		}
+		e.Venue = v
+	}
+	if e.OrganizerID != nil {
+		o, err := s.repo.FindOrganizer(*e.OrganizerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				s.logger.Warn("Event references unknown organizer", zap.String("organizer_id", *e.OrganizerID))
+				return ErrUnknownOrganizer
+			}
+			s.logger.Error("Failed to load event organizer", zap.String("organizer_id", *e.OrganizerID), zap.Error(err))
+			return err
+		}
+		e.Organizer = o
+	}
 	return nil
 }
 
@@ -198,9 +365,10 @@ func (s *Service) ReserveTx(tx *gorm.DB, eventID string, qty int) (bool, error)
 		return false, nil
 	}
 
-	// sync cache
+	// sync cache and search availability
 	if ev, err := s.repo.Get(eventID); err == nil {
 		_ = s.cache.Set(context.Background(), "event:remaining:"+eventID, ev.Remaining, 0)
+		s.syncRemaining(context.Background(), eventID, ev.Remaining)
 	}
 	return true, nil
 }
@@ -229,10 +397,25 @@ func (s *Service) Release(ctx context.Context, eventID string, qty int) error {
 	if err == nil {
 		_ = s.cache.Set(ctx, "event:remaining:"+eventID, ev.Remaining, 0)
 		_ = s.cache.Del(ctx, "events:list")
+		s.syncRemaining(ctx, eventID, ev.Remaining)
 	}
 	return nil
 }
 
+// index pushes the event document to the search index, logging failures
+func (s *Service) index(ctx context.Context, e *Event) {
+	if err := s.indexer.IndexEvent(ctx, e); err != nil {
+		s.logger.Warn("Failed to index event", zap.String("event_id", e.ID), zap.Error(err))
+	}
+}
+
+// syncRemaining pushes seat availability to the search index, logging failures
+func (s *Service) syncRemaining(ctx context.Context, eventID string, remaining int) {
+	if err := s.indexer.UpdateRemaining(ctx, eventID, remaining); err != nil {
+		s.logger.Warn("Failed to sync remaining seats to search index", zap.String("event_id", eventID), zap.Error(err))
+	}
+}
+
 // StatsDB computes tickets sold and revenue from DB (CONFIRMED only)
 func (s *Service) StatsDB(ctx context.Context, eventID string) (tickets int64, revenueCents int64, err error) {
 	if err = s.db.WithContext(ctx).Raw(
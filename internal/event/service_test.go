@@ -2,15 +2,22 @@ package event_test
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 
+	"ticket-booking/internal/auth"
 	"ticket-booking/internal/event"
 	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/venue"
 )
 
 func TestListEvents_FromCache(t *testing.T) {
@@ -66,6 +73,73 @@ func TestListEvents_CacheMiss_FallbackToRepo(t *testing.T) {
 	require.Equal(t, "Theater", evts[1].Name)
 }
 
+func TestListPage_FilterInCacheKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	minPrice := int64(1000)
+	f := event.ListFilter{Limit: 20, Offset: 0, Query: "Rock", MinPriceCents: &minPrice, Available: true, Sort: "-price"}
+	key := "events:list:20:0:available=1&min=1000&q=rock&sort=-price"
+
+	cache.EXPECT().Get(gomock.Any(), key).Return("", assert.AnError)
+	repo.EXPECT().ListPage(f).Return([]event.Event{{ID: "e1", Name: "Rock Night"}}, nil)
+	cache.EXPECT().Set(gomock.Any(), key, gomock.Any(), gomock.Any()).Return(nil)
+
+	evts, err := svc.ListPage(context.Background(), f)
+
+	require.NoError(t, err)
+	require.Len(t, evts, 1)
+}
+
+func TestListFilter_CacheKeyUnfiltered(t *testing.T) {
+	f := event.ListFilter{Limit: 20, Offset: 40}
+	require.Equal(t, "events:list:20:40", f.CacheKey())
+
+	// default sort spelled out explicitly shares the unfiltered key
+	f.Sort = event.DefaultSort
+	require.Equal(t, "events:list:20:40", f.CacheKey())
+}
+
+func TestListFilter_Validate(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	lo, hi := int64(500), int64(100)
+
+	tests := []struct {
+		name    string
+		filter  event.ListFilter
+		wantErr bool
+	}{
+		{"empty", event.ListFilter{}, false},
+		{"descending price", event.ListFilter{Sort: "-price"}, false},
+		{"unknown sort", event.ListFilter{Sort: "popularity"}, true},
+		{"inverted dates", event.ListFilter{From: &now, To: &earlier}, true},
+		{"inverted prices", event.ListFilter{MinPriceCents: &lo, MaxPriceCents: &hi}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, event.ErrInvalidFilter)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestListFilter_OrderBy(t *testing.T) {
+	require.Equal(t, "starts_at asc, id asc", event.ListFilter{}.OrderBy())
+	require.Equal(t, "ticket_price_cents desc, id asc", event.ListFilter{Sort: "-price"}.OrderBy())
+}
+
 func TestReserve_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -105,6 +179,177 @@ func TestReserve_NotEnoughSeats(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestDelete_RefusesWithConfirmedBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	repo.EXPECT().Get("e1").Return(&event.Event{ID: "e1"}, nil)
+	// The repo counts them under the event's row lock and refuses
+	repo.EXPECT().Delete("e1", false).Return(int64(0), event.ErrEventHasBookings)
+
+	err := svc.Delete(context.Background(), "e1", false)
+
+	require.ErrorIs(t, err, event.ErrEventHasBookings)
+}
+
+func TestDelete_NoConfirmedBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	repo.EXPECT().Get("e1").Return(&event.Event{ID: "e1"}, nil)
+	repo.EXPECT().Delete("e1", false).Return(int64(0), nil)
+	cache.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	err := svc.Delete(context.Background(), "e1", false)
+
+	require.NoError(t, err)
+}
+
+func TestDelete_ForceCancelsBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	// force skips the confirmed-bookings guard and cancels them in the repo
+	repo.EXPECT().Get("e1").Return(&event.Event{ID: "e1"}, nil)
+	repo.EXPECT().Delete("e1", true).Return(int64(3), nil)
+	cache.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	err := svc.Delete(context.Background(), "e1", true)
+
+	require.NoError(t, err)
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	repo.EXPECT().Get("missing").Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.Delete(context.Background(), "missing", true)
+
+	require.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestRestore_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	logger := zap.NewNop()
+
+	svc := event.NewService(nil, repo, cache, logger)
+
+	repo.EXPECT().Restore("e1").Return(nil)
+	repo.EXPECT().Get("e1").Return(&event.Event{ID: "e1", Remaining: 42}, nil)
+	cache.EXPECT().Set(gomock.Any(), "event:remaining:e1", 42, gomock.Any()).Return(nil)
+	cache.EXPECT().Del(gomock.Any(), "events:list").Return(nil)
+
+	e, err := svc.Restore(context.Background(), "e1")
+
+	require.NoError(t, err)
+	require.Equal(t, 42, e.Remaining)
+}
+
+func TestCreate_CapacityFromVenueDefault(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	svc := event.NewService(nil, repo, cache, zap.NewNop())
+
+	venueID := "v1"
+	repo.EXPECT().FindVenue(venueID).Return(&venue.Venue{ID: venueID, Name: "Hall", DefaultCapacity: 300}, nil)
+	repo.EXPECT().Create(gomock.Any()).Return(nil)
+	cache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	cache.EXPECT().Del(gomock.Any(), "events:list").Return(nil)
+
+	e := &event.Event{ID: "e1", Name: "Concert", VenueID: &venueID}
+	require.NoError(t, svc.Create(context.Background(), e))
+	require.Equal(t, 300, e.Capacity)
+	require.Equal(t, 300, e.Remaining)
+	require.NotNil(t, e.Venue)
+	require.Equal(t, "Hall", e.Venue.Name)
+}
+
+func TestCreate_CapacityRequiredWithoutVenue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	svc := event.NewService(nil, repo, mocks.NewMockCache(ctrl), zap.NewNop())
+
+	err := svc.Create(context.Background(), &event.Event{Name: "Concert"})
+	require.ErrorIs(t, err, event.ErrCapacityRequired)
+}
+
+func TestCreate_UnknownOrganizer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	svc := event.NewService(nil, repo, mocks.NewMockCache(ctrl), zap.NewNop())
+
+	orgID := "missing"
+	repo.EXPECT().FindOrganizer(orgID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.Create(context.Background(), &event.Event{Name: "Concert", Capacity: 10, OrganizerID: &orgID})
+	require.ErrorIs(t, err, event.ErrUnknownOrganizer)
+}
+
+func TestCreate_DuplicateAttendeeField(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	svc := event.NewService(nil, repo, mocks.NewMockCache(ctrl), zap.NewNop())
+
+	fields := []event.AttendeeField{{Key: "company", Label: "Company"}, {Key: "company", Label: "Employer"}}
+	err := svc.Create(context.Background(), &event.Event{Name: "Concert", Capacity: 10, AttendeeFields: fields})
+	require.ErrorIs(t, err, event.ErrDuplicateAttendeeField)
+}
+
+func TestCanManage(t *testing.T) {
+	owner := "u1"
+	owned := &event.Event{ID: "e1", OwnerID: &owner}
+	unowned := &event.Event{ID: "e2"}
+
+	admin := []string{auth.PermEventsWrite}
+	organizer := []string{auth.PermEventsOwn}
+
+	require.True(t, event.CanManage(owned, "admin", admin))
+	require.True(t, event.CanManage(unowned, "admin", admin))
+	require.True(t, event.CanManage(owned, "u1", organizer))
+	require.False(t, event.CanManage(owned, "u2", organizer))
+	require.False(t, event.CanManage(unowned, "u1", organizer))
+	require.False(t, event.CanManage(owned, "u1", nil))
+}
+
 // Test interface compliance
 func TestService_ImplementsInterface(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -145,3 +390,74 @@ func TestEvent_Model(t *testing.T) {
 	require.Equal(t, 100, e.Remaining)
 	require.Equal(t, int64(5000), e.TicketPriceCents)
 }
+
+func TestCreate_InvalidQuestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	svc := event.NewService(nil, repo, mocks.NewMockCache(ctrl), zap.NewNop())
+
+	for _, qs := range [][]event.Question{
+		{{Key: "size", Type: event.QuestionChoice}},
+		{{Key: "diet", Type: event.QuestionText}, {Key: "diet", Type: event.QuestionCheckbox}},
+		{{Key: "code", Type: event.QuestionText, Pattern: "("}},
+		{{Key: "terms", Type: event.QuestionCheckbox, Options: []string{"yes"}}},
+	} {
+		err := svc.Create(context.Background(), &event.Event{Name: "Concert", Capacity: 10, Questions: qs})
+		require.ErrorIs(t, err, event.ErrInvalidQuestions)
+	}
+}
+
+func TestCheckAnswers(t *testing.T) {
+	e := &event.Event{Questions: []event.Question{
+		{Key: "diet", Type: event.QuestionText, MaxLength: 10},
+		{Key: "size", Type: event.QuestionChoice, Required: true, Options: []string{"S", "M", "L"}},
+		{Key: "terms", Type: event.QuestionCheckbox, Required: true},
+		{Key: "newsletter", Type: event.QuestionCheckbox},
+		{Key: "country", Type: event.QuestionText, Pattern: "^[A-Z]{2}$"},
+	}}
+	valid := func() map[string]any {
+		return map[string]any{"diet": " vegan ", "size": "M", "terms": true, "newsletter": false, "country": ""}
+	}
+
+	got, err := e.CheckAnswers(valid())
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"diet": "vegan", "size": "M", "terms": true, "newsletter": false}, got)
+
+	cases := map[string]func(map[string]any){
+		"unknown key":       func(a map[string]any) { a["age"] = "30" },
+		"missing required":  func(a map[string]any) { delete(a, "size") },
+		"unknown option":    func(a map[string]any) { a["size"] = "XL" },
+		"unticked required": func(a map[string]any) { a["terms"] = false },
+		"checkbox as text":  func(a map[string]any) { a["newsletter"] = "yes" },
+		"text as number":    func(a map[string]any) { a["diet"] = 3.0 },
+		"too long":          func(a map[string]any) { a["diet"] = "no nuts, no dairy" },
+		"pattern mismatch":  func(a map[string]any) { a["country"] = "France" },
+	}
+	for name, mutate := range cases {
+		answers := valid()
+		mutate(answers)
+		_, err := e.CheckAnswers(answers)
+		require.ErrorIs(t, err, event.ErrInvalidAnswers, name)
+	}
+
+	got, err = (&event.Event{}).CheckAnswers(nil)
+	require.NoError(t, err)
+	require.Nil(t, got, "events without questions take no answers")
+
+	broken := &event.Event{Questions: []event.Question{{Key: "code", Label: "Code", Type: event.QuestionText, Pattern: "("}}}
+	_, err = broken.CheckAnswers(map[string]any{"code": "AB"})
+	require.ErrorIs(t, err, event.ErrInvalidQuestions, "a stored pattern that no longer compiles fails the form, not the process")
+}
+
+func TestStats_NotPublic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	event.RegisterPublicRoutes(r.Group("/api/v1"), event.NewHandler(nil, nil, zap.NewNop()))
+
+	// Sales and revenue are only served behind the organizer and admin groups
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/events/e1/stats", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -71,6 +71,51 @@ func (mr *MockBookingRepositoryMockRecorder) Get(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockBookingRepository)(nil).Get), id)
 }
 
+// GetForUpdate mocks base method.
+func (m *MockBookingRepository) GetForUpdate(tx *gorm.DB, id string) (*booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", tx, id)
+	ret0, _ := ret[0].(*booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockBookingRepositoryMockRecorder) GetForUpdate(tx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockBookingRepository)(nil).GetForUpdate), tx, id)
+}
+
+// ListByEvent mocks base method.
+func (m *MockBookingRepository) ListByEvent(ctx context.Context, eventID string, status booking.Status, limit, offset int) ([]*booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByEvent", ctx, eventID, status, limit, offset)
+	ret0, _ := ret[0].([]*booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByEvent indicates an expected call of ListByEvent.
+func (mr *MockBookingRepositoryMockRecorder) ListByEvent(ctx, eventID, status, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEvent", reflect.TypeOf((*MockBookingRepository)(nil).ListByEvent), ctx, eventID, status, limit, offset)
+}
+
+// ListByUser mocks base method.
+func (m *MockBookingRepository) ListByUser(ctx context.Context, userID string, limit, offset int) ([]*booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID, limit, offset)
+	ret0, _ := ret[0].([]*booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockBookingRepositoryMockRecorder) ListByUser(ctx, userID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockBookingRepository)(nil).ListByUser), ctx, userID, limit, offset)
+}
+
 // ListConfirmedByEvent mocks base method.
 func (m *MockBookingRepository) ListConfirmedByEvent(ctx context.Context, eventID string) ([]*booking.Booking, error) {
 	m.ctrl.T.Helper()
@@ -101,16 +146,113 @@ func (mr *MockBookingRepositoryMockRecorder) ListPendingOlderThan(ctx, cutoff an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingOlderThan", reflect.TypeOf((*MockBookingRepository)(nil).ListPendingOlderThan), ctx, cutoff)
 }
 
+// Search mocks base method.
+func (m *MockBookingRepository) Search(ctx context.Context, f booking.BookingFilter) ([]*booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, f)
+	ret0, _ := ret[0].([]*booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockBookingRepositoryMockRecorder) Search(ctx, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockBookingRepository)(nil).Search), ctx, f)
+}
+
+// UpdateEvent mocks base method.
+func (m *MockBookingRepository) UpdateEvent(tx *gorm.DB, b *booking.Booking, eventID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEvent", tx, b, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEvent indicates an expected call of UpdateEvent.
+func (mr *MockBookingRepositoryMockRecorder) UpdateEvent(tx, b, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEvent", reflect.TypeOf((*MockBookingRepository)(nil).UpdateEvent), tx, b, eventID)
+}
+
 // UpdateStatus mocks base method.
-func (m *MockBookingRepository) UpdateStatus(ctx context.Context, id string, status booking.Status) error {
+func (m *MockBookingRepository) UpdateStatus(ctx context.Context, b *booking.Booking, status booking.Status) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, b, status)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStatus indicates an expected call of UpdateStatus.
-func (mr *MockBookingRepositoryMockRecorder) UpdateStatus(ctx, id, status any) *gomock.Call {
+func (mr *MockBookingRepositoryMockRecorder) UpdateStatus(ctx, b, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockBookingRepository)(nil).UpdateStatus), ctx, b, status)
+}
+
+// MockAttendeeRepository is a mock of AttendeeRepository interface.
+type MockAttendeeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAttendeeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAttendeeRepositoryMockRecorder is the mock recorder for MockAttendeeRepository.
+type MockAttendeeRepositoryMockRecorder struct {
+	mock *MockAttendeeRepository
+}
+
+// NewMockAttendeeRepository creates a new mock instance.
+func NewMockAttendeeRepository(ctrl *gomock.Controller) *MockAttendeeRepository {
+	mock := &MockAttendeeRepository{ctrl: ctrl}
+	mock.recorder = &MockAttendeeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAttendeeRepository) EXPECT() *MockAttendeeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListByBooking mocks base method.
+func (m *MockAttendeeRepository) ListByBooking(ctx context.Context, bookingID string) ([]*booking.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByBooking", ctx, bookingID)
+	ret0, _ := ret[0].([]*booking.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByBooking indicates an expected call of ListByBooking.
+func (mr *MockAttendeeRepositoryMockRecorder) ListByBooking(ctx, bookingID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByBooking", reflect.TypeOf((*MockAttendeeRepository)(nil).ListByBooking), ctx, bookingID)
+}
+
+// ListByEvent mocks base method.
+func (m *MockAttendeeRepository) ListByEvent(ctx context.Context, eventID string) ([]*booking.Attendee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByEvent", ctx, eventID)
+	ret0, _ := ret[0].([]*booking.Attendee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByEvent indicates an expected call of ListByEvent.
+func (mr *MockAttendeeRepositoryMockRecorder) ListByEvent(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByEvent", reflect.TypeOf((*MockAttendeeRepository)(nil).ListByEvent), ctx, eventID)
+}
+
+// Save mocks base method.
+func (m *MockAttendeeRepository) Save(ctx context.Context, attendees []*booking.Attendee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, attendees)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockAttendeeRepositoryMockRecorder) Save(ctx, attendees any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockBookingRepository)(nil).UpdateStatus), ctx, id, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockAttendeeRepository)(nil).Save), ctx, attendees)
 }
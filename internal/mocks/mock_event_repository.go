@@ -12,6 +12,8 @@ package mocks
 import (
 	reflect "reflect"
 	event "ticket-booking/internal/event"
+	organizer "ticket-booking/internal/organizer"
+	venue "ticket-booking/internal/venue"
 
 	gomock "go.uber.org/mock/gomock"
 	gorm "gorm.io/gorm"
@@ -56,17 +58,48 @@ func (mr *MockEventRepositoryMockRecorder) Create(e any) *gomock.Call {
 }
 
 // Delete mocks base method.
-func (m *MockEventRepository) Delete(id string) error {
+func (m *MockEventRepository) Delete(id string, cancelConfirmed bool) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Delete", id, cancelConfirmed)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockEventRepositoryMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockEventRepositoryMockRecorder) Delete(id, cancelConfirmed any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventRepository)(nil).Delete), id, cancelConfirmed)
+}
+
+// FindOrganizer mocks base method.
+func (m *MockEventRepository) FindOrganizer(id string) (*organizer.Organizer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrganizer", id)
+	ret0, _ := ret[0].(*organizer.Organizer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrganizer indicates an expected call of FindOrganizer.
+func (mr *MockEventRepositoryMockRecorder) FindOrganizer(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrganizer", reflect.TypeOf((*MockEventRepository)(nil).FindOrganizer), id)
+}
+
+// FindVenue mocks base method.
+func (m *MockEventRepository) FindVenue(id string) (*venue.Venue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindVenue", id)
+	ret0, _ := ret[0].(*venue.Venue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindVenue indicates an expected call of FindVenue.
+func (mr *MockEventRepositoryMockRecorder) FindVenue(id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockEventRepository)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindVenue", reflect.TypeOf((*MockEventRepository)(nil).FindVenue), id)
 }
 
 // Get mocks base method.
@@ -100,18 +133,18 @@ func (mr *MockEventRepositoryMockRecorder) List() *gomock.Call {
 }
 
 // ListPage mocks base method.
-func (m *MockEventRepository) ListPage(limit, offset int) ([]event.Event, error) {
+func (m *MockEventRepository) ListPage(f event.ListFilter) ([]event.Event, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListPage", limit, offset)
+	ret := m.ctrl.Call(m, "ListPage", f)
 	ret0, _ := ret[0].([]event.Event)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListPage indicates an expected call of ListPage.
-func (mr *MockEventRepositoryMockRecorder) ListPage(limit, offset any) *gomock.Call {
+func (mr *MockEventRepositoryMockRecorder) ListPage(f any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockEventRepository)(nil).ListPage), limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*MockEventRepository)(nil).ListPage), f)
 }
 
 // Reserve mocks base method.
@@ -144,6 +177,20 @@ func (mr *MockEventRepositoryMockRecorder) ReserveTx(tx, eventID, qty any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveTx", reflect.TypeOf((*MockEventRepository)(nil).ReserveTx), tx, eventID, qty)
 }
 
+// Restore mocks base method.
+func (m *MockEventRepository) Restore(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockEventRepositoryMockRecorder) Restore(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockEventRepository)(nil).Restore), id)
+}
+
 // Update mocks base method.
 func (m *MockEventRepository) Update(e *event.Event) error {
 	m.ctrl.T.Helper()
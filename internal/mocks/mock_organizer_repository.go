@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/organizer/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/organizer/repository.go -destination=internal/mocks/mock_organizer_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	organizer "ticket-booking/internal/organizer"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOrganizerRepository is a mock of OrganizerRepository interface.
+type MockOrganizerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrganizerRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrganizerRepositoryMockRecorder is the mock recorder for MockOrganizerRepository.
+type MockOrganizerRepositoryMockRecorder struct {
+	mock *MockOrganizerRepository
+}
+
+// NewMockOrganizerRepository creates a new mock instance.
+func NewMockOrganizerRepository(ctrl *gomock.Controller) *MockOrganizerRepository {
+	mock := &MockOrganizerRepository{ctrl: ctrl}
+	mock.recorder = &MockOrganizerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrganizerRepository) EXPECT() *MockOrganizerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountEvents mocks base method.
+func (m *MockOrganizerRepository) CountEvents(id string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEvents", id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEvents indicates an expected call of CountEvents.
+func (mr *MockOrganizerRepositoryMockRecorder) CountEvents(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEvents", reflect.TypeOf((*MockOrganizerRepository)(nil).CountEvents), id)
+}
+
+// Create mocks base method.
+func (m *MockOrganizerRepository) Create(o *organizer.Organizer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", o)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockOrganizerRepositoryMockRecorder) Create(o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockOrganizerRepository)(nil).Create), o)
+}
+
+// Delete mocks base method.
+func (m *MockOrganizerRepository) Delete(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockOrganizerRepositoryMockRecorder) Delete(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOrganizerRepository)(nil).Delete), id)
+}
+
+// Get mocks base method.
+func (m *MockOrganizerRepository) Get(id string) (*organizer.Organizer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", id)
+	ret0, _ := ret[0].(*organizer.Organizer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockOrganizerRepositoryMockRecorder) Get(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockOrganizerRepository)(nil).Get), id)
+}
+
+// List mocks base method.
+func (m *MockOrganizerRepository) List(limit, offset int) ([]organizer.Organizer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", limit, offset)
+	ret0, _ := ret[0].([]organizer.Organizer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOrganizerRepositoryMockRecorder) List(limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrganizerRepository)(nil).List), limit, offset)
+}
+
+// Update mocks base method.
+func (m *MockOrganizerRepository) Update(o *organizer.Organizer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", o)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockOrganizerRepositoryMockRecorder) Update(o any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockOrganizerRepository)(nil).Update), o)
+}
@@ -55,6 +55,21 @@ func (mr *MockCacheMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockCache)(nil).Close))
 }
 
+// CompareAndSet mocks base method.
+func (m *MockCache) CompareAndSet(ctx context.Context, key, old string, value any, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareAndSet", ctx, key, old, value, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareAndSet indicates an expected call of CompareAndSet.
+func (mr *MockCacheMockRecorder) CompareAndSet(ctx, key, old, value, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareAndSet", reflect.TypeOf((*MockCache)(nil).CompareAndSet), ctx, key, old, value, ttl)
+}
+
 // DecrementSeats mocks base method.
 func (m *MockCache) DecrementSeats(ctx context.Context, eventID string, qty int) (int, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +99,20 @@ func (mr *MockCacheMockRecorder) Del(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Del", reflect.TypeOf((*MockCache)(nil).Del), ctx, key)
 }
 
+// Expire mocks base method.
+func (m *MockCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, key, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockCacheMockRecorder) Expire(ctx, key, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockCache)(nil).Expire), ctx, key, ttl)
+}
+
 // Get mocks base method.
 func (m *MockCache) Get(ctx context.Context, key string) (string, error) {
 	m.ctrl.T.Helper()
@@ -99,6 +128,21 @@ func (mr *MockCacheMockRecorder) Get(ctx, key any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCache)(nil).Get), ctx, key)
 }
 
+// GetDel mocks base method.
+func (m *MockCache) GetDel(ctx context.Context, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDel", ctx, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDel indicates an expected call of GetDel.
+func (mr *MockCacheMockRecorder) GetDel(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDel", reflect.TypeOf((*MockCache)(nil).GetDel), ctx, key)
+}
+
 // GetEventIDs mocks base method.
 func (m *MockCache) GetEventIDs(ctx context.Context) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -159,6 +203,22 @@ func (mr *MockCacheMockRecorder) IncrBy(ctx, key, n any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrBy", reflect.TypeOf((*MockCache)(nil).IncrBy), ctx, key, n)
 }
 
+// IncrUnlessLocked mocks base method.
+func (m *MockCache) IncrUnlessLocked(ctx context.Context, lockKey, countKey string, ttl time.Duration) (int, time.Duration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrUnlessLocked", ctx, lockKey, countKey, ttl)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Duration)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// IncrUnlessLocked indicates an expected call of IncrUnlessLocked.
+func (mr *MockCacheMockRecorder) IncrUnlessLocked(ctx, lockKey, countKey, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrUnlessLocked", reflect.TypeOf((*MockCache)(nil).IncrUnlessLocked), ctx, lockKey, countKey, ttl)
+}
+
 // Set mocks base method.
 func (m *MockCache) Set(ctx context.Context, key string, value any, ttl time.Duration) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/series/repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/series/repository.go -destination=internal/mocks/mock_series_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	event "ticket-booking/internal/event"
+	series "ticket-booking/internal/series"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSeriesRepository is a mock of SeriesRepository interface.
+type MockSeriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeriesRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSeriesRepositoryMockRecorder is the mock recorder for MockSeriesRepository.
+type MockSeriesRepositoryMockRecorder struct {
+	mock *MockSeriesRepository
+}
+
+// NewMockSeriesRepository creates a new mock instance.
+func NewMockSeriesRepository(ctrl *gomock.Controller) *MockSeriesRepository {
+	mock := &MockSeriesRepository{ctrl: ctrl}
+	mock.recorder = &MockSeriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeriesRepository) EXPECT() *MockSeriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountConfirmedBookings mocks base method.
+func (m *MockSeriesRepository) CountConfirmedBookings(seriesID string, from time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountConfirmedBookings", seriesID, from)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountConfirmedBookings indicates an expected call of CountConfirmedBookings.
+func (mr *MockSeriesRepositoryMockRecorder) CountConfirmedBookings(seriesID, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountConfirmedBookings", reflect.TypeOf((*MockSeriesRepository)(nil).CountConfirmedBookings), seriesID, from)
+}
+
+// Create mocks base method.
+func (m *MockSeriesRepository) Create(s *series.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSeriesRepositoryMockRecorder) Create(s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSeriesRepository)(nil).Create), s)
+}
+
+// Delete mocks base method.
+func (m *MockSeriesRepository) Delete(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSeriesRepositoryMockRecorder) Delete(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSeriesRepository)(nil).Delete), id)
+}
+
+// Get mocks base method.
+func (m *MockSeriesRepository) Get(id string) (*series.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", id)
+	ret0, _ := ret[0].(*series.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockSeriesRepositoryMockRecorder) Get(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSeriesRepository)(nil).Get), id)
+}
+
+// List mocks base method.
+func (m *MockSeriesRepository) List(limit, offset int) ([]series.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", limit, offset)
+	ret0, _ := ret[0].([]series.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockSeriesRepositoryMockRecorder) List(limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSeriesRepository)(nil).List), limit, offset)
+}
+
+// Occurrences mocks base method.
+func (m *MockSeriesRepository) Occurrences(seriesID string, from time.Time) ([]event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Occurrences", seriesID, from)
+	ret0, _ := ret[0].([]event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Occurrences indicates an expected call of Occurrences.
+func (mr *MockSeriesRepositoryMockRecorder) Occurrences(seriesID, from any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Occurrences", reflect.TypeOf((*MockSeriesRepository)(nil).Occurrences), seriesID, from)
+}
+
+// Update mocks base method.
+func (m *MockSeriesRepository) Update(s *series.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSeriesRepositoryMockRecorder) Update(s any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSeriesRepository)(nil).Update), s)
+}
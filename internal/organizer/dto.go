@@ -0,0 +1,46 @@
+package organizer
+
+// CreateOrganizerRequest input for creating an organizer
+type CreateOrganizerRequest struct {
+	Name        string  `json:"name" binding:"required,max=100" example:"Saigon Live Events"`
+	Email       string  `json:"email" binding:"required,email" example:"contact@saigonlive.vn"`
+	Phone       *string `json:"phone" binding:"omitempty,e164" example:"+84281234567"`
+	Website     *string `json:"website" binding:"omitempty,url" example:"https://saigonlive.vn"`
+	Description *string `json:"description" binding:"omitempty,max=2000" example:"Concerts and festivals since 2010"`
+}
+
+// UpdateOrganizerRequest input for updating an organizer; omitted fields are unchanged
+type UpdateOrganizerRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100" example:"Saigon Live Events"`
+	Email       *string `json:"email" binding:"omitempty,email" example:"contact@saigonlive.vn"`
+	Phone       *string `json:"phone" binding:"omitempty,e164" example:"+84281234567"`
+	Website     *string `json:"website" binding:"omitempty,url" example:"https://saigonlive.vn"`
+	Description *string `json:"description" binding:"omitempty,max=2000" example:"Concerts and festivals since 2010"`
+}
+
+// OrganizerResponse represents organizer output; also embedded in event responses
+type OrganizerResponse struct {
+	ID          string  `json:"id" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
+	Name        string  `json:"name" example:"Saigon Live Events"`
+	Email       string  `json:"email" example:"contact@saigonlive.vn"`
+	Phone       *string `json:"phone,omitempty" example:"+84281234567"`
+	Website     *string `json:"website,omitempty" example:"https://saigonlive.vn"`
+	Description *string `json:"description,omitempty" example:"Concerts and festivals since 2010"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+// ToResponse maps an organizer to its API representation
+func ToResponse(o *Organizer) OrganizerResponse {
+	return OrganizerResponse{
+		ID:          o.ID,
+		Name:        o.Name,
+		Email:       o.Email,
+		Phone:       o.Phone,
+		Website:     o.Website,
+		Description: o.Description,
+	}
+}
@@ -0,0 +1,176 @@
+package organizer
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// List godoc
+// @Summary List organizers
+// @Description List organizers ordered by name (Admin only)
+// @Tags organizers
+// @Produce json
+// @Param limit query int false "Max items to return (default 20, max 100)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} OrganizerResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/organizers [get]
+func (h *Handler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	orgs, err := h.svc.List(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]OrganizerResponse, 0, len(orgs))
+	for i := range orgs {
+		out = append(out, ToResponse(&orgs[i]))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get organizer
+// @Description Get an organizer by ID (Admin only)
+// @Tags organizers
+// @Produce json
+// @Param id path string true "Organizer ID"
+// @Success 200 {object} OrganizerResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/organizers/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	o, err := h.svc.Get(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, ToResponse(o))
+}
+
+// Create godoc
+// @Summary Create organizer
+// @Description Create an organizer (Admin only)
+// @Tags organizers
+// @Accept json
+// @Produce json
+// @Param input body CreateOrganizerRequest true "Organizer data"
+// @Success 201 {object} OrganizerResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/organizers [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateOrganizerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid organizer creation request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o := &Organizer{
+		Name:        req.Name,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Website:     req.Website,
+		Description: req.Description,
+	}
+	if err := h.svc.Create(c, o); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ToResponse(o))
+}
+
+// Update godoc
+// @Summary Update organizer
+// @Description Update organizer details (Admin only)
+// @Tags organizers
+// @Accept json
+// @Produce json
+// @Param id path string true "Organizer ID"
+// @Param input body UpdateOrganizerRequest true "Updated organizer data"
+// @Success 200 {object} OrganizerResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/organizers/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var req UpdateOrganizerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid organizer update request", zap.String("organizer_id", id), zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o, err := h.svc.Get(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	if req.Name != nil {
+		o.Name = *req.Name
+	}
+	if req.Email != nil {
+		o.Email = *req.Email
+	}
+	if req.Phone != nil {
+		o.Phone = req.Phone
+	}
+	if req.Website != nil {
+		o.Website = req.Website
+	}
+	if req.Description != nil {
+		o.Description = req.Description
+	}
+	if err := h.svc.Update(c, o); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ToResponse(o))
+}
+
+// Delete godoc
+// @Summary Delete organizer
+// @Description Delete an organizer that no event references (Admin only)
+// @Tags organizers
+// @Param id path string true "Organizer ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Organizer is referenced by events"
+// @Security BearerAuth
+// @Router /admin/organizers/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.svc.Delete(c, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, ErrOrganizerInUse):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
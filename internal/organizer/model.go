@@ -0,0 +1,16 @@
+// Package organizer manages the people or companies that run events.
+package organizer
+
+import "time"
+
+// Organizer represents the party responsible for an event.
+type Organizer struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"type:text;not null" json:"name"`         // Display name
+	Email       string    `gorm:"type:text;not null" json:"email"`        // Public contact email
+	Phone       *string   `gorm:"type:text" json:"phone,omitempty"`       // Optional contact phone
+	Website     *string   `gorm:"type:text" json:"website,omitempty"`     // Optional homepage URL
+	Description *string   `gorm:"type:text" json:"description,omitempty"` // Optional public bio
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
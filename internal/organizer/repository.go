@@ -0,0 +1,57 @@
+package organizer
+
+import "gorm.io/gorm"
+
+type OrganizerRepository interface {
+	List(limit, offset int) ([]Organizer, error)
+	Get(id string) (*Organizer, error)
+	Create(o *Organizer) error
+	Update(o *Organizer) error
+	Delete(id string) error
+	CountEvents(id string) (int64, error)
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewOrganizerRepository(db *gorm.DB) OrganizerRepository { return &repo{db} }
+
+func (r *repo) List(limit, offset int) ([]Organizer, error) {
+	var out []Organizer
+	q := r.db.Order("name asc")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	return out, q.Find(&out).Error
+}
+
+func (r *repo) Get(id string) (*Organizer, error) {
+	var o Organizer
+	if err := r.db.First(&o, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *repo) Create(o *Organizer) error { return r.db.Create(o).Error }
+func (r *repo) Update(o *Organizer) error { return r.db.Save(o).Error }
+
+func (r *repo) Delete(id string) error {
+	res := r.db.Delete(&Organizer{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountEvents counts events (including archived ones) run by the organizer
+func (r *repo) CountEvents(id string) (int64, error) {
+	var n int64
+	err := r.db.Raw("SELECT COUNT(*) FROM events WHERE organizer_id = ?", id).Scan(&n).Error
+	return n, err
+}
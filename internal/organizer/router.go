@@ -0,0 +1,11 @@
+package organizer
+
+import "github.com/gin-gonic/gin"
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/organizers", h.List)
+	r.GET("/organizers/:id", h.Get)
+	r.POST("/organizers", h.Create)
+	r.PUT("/organizers/:id", h.Update)
+	r.DELETE("/organizers/:id", h.Delete)
+}
@@ -0,0 +1,77 @@
+package organizer
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// ErrOrganizerInUse is returned when deleting an organizer that events still reference
+var ErrOrganizerInUse = errors.New("organizer is referenced by events")
+
+// Service implements organizer management.
+type Service struct {
+	repo   OrganizerRepository
+	logger *zap.Logger
+}
+
+// NewService creates a new organizer service
+func NewService(r OrganizerRepository, logger *zap.Logger) *Service {
+	return &Service{repo: r, logger: logger}
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]Organizer, error) {
+	out, err := s.repo.List(limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list organizers", zap.Error(err))
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Organizer, error) {
+	o, err := s.repo.Get(id)
+	if err != nil {
+		s.logger.Error("Failed to get organizer", zap.String("organizer_id", id), zap.Error(err))
+		return nil, err
+	}
+	return o, nil
+}
+
+func (s *Service) Create(ctx context.Context, o *Organizer) error {
+	if err := s.repo.Create(o); err != nil {
+		s.logger.Error("Failed to create organizer", zap.String("name", o.Name), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Organizer created", zap.String("organizer_id", o.ID))
+	return nil
+}
+
+func (s *Service) Update(ctx context.Context, o *Organizer) error {
+	if err := s.repo.Update(o); err != nil {
+		s.logger.Error("Failed to update organizer", zap.String("organizer_id", o.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Organizer updated", zap.String("organizer_id", o.ID))
+	return nil
+}
+
+// Delete removes an organizer that no event references
+func (s *Service) Delete(ctx context.Context, id string) error {
+	n, err := s.repo.CountEvents(id)
+	if err != nil {
+		s.logger.Error("Failed to count organizer events", zap.String("organizer_id", id), zap.Error(err))
+		return err
+	}
+	if n > 0 {
+		s.logger.Warn("Refusing to delete organizer in use", zap.String("organizer_id", id), zap.Int64("events", n))
+		return ErrOrganizerInUse
+	}
+	if err := s.repo.Delete(id); err != nil {
+		s.logger.Error("Failed to delete organizer", zap.String("organizer_id", id), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Organizer deleted", zap.String("organizer_id", id))
+	return nil
+}
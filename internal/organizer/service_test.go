@@ -0,0 +1,37 @@
+package organizer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/organizer"
+)
+
+func TestDeleteOrganizer_RefusedWhileReferenced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrganizerRepository(ctrl)
+	svc := organizer.NewService(repo, zap.NewNop())
+
+	repo.EXPECT().CountEvents("o1").Return(int64(1), nil)
+	require.ErrorIs(t, svc.Delete(context.Background(), "o1"), organizer.ErrOrganizerInUse)
+}
+
+func TestDeleteOrganizer_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockOrganizerRepository(ctrl)
+	svc := organizer.NewService(repo, zap.NewNop())
+
+	repo.EXPECT().CountEvents("missing").Return(int64(0), nil)
+	repo.EXPECT().Delete("missing").Return(gorm.ErrRecordNotFound)
+	require.ErrorIs(t, svc.Delete(context.Background(), "missing"), gorm.ErrRecordNotFound)
+}
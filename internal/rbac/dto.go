@@ -0,0 +1,41 @@
+package rbac
+
+// CreateRoleRequest input for creating a role
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required" example:"SUPPORT"`
+	Description *string  `json:"description" example:"Customer support staff"`
+	Permissions []string `json:"permissions" example:"bookings:read,bookings:refund"`
+}
+
+// UpdateRoleRequest input for updating a role; permissions replace the current set
+type UpdateRoleRequest struct {
+	Description *string   `json:"description" example:"Customer support staff"`
+	Permissions *[]string `json:"permissions" example:"bookings:read"`
+}
+
+// RoleResponse represents a role and its permissions
+type RoleResponse struct {
+	Name        string   `json:"name" example:"SUPPORT"`
+	Description *string  `json:"description,omitempty" example:"Customer support staff"`
+	BuiltIn     bool     `json:"built_in" example:"false"`
+	Permissions []string `json:"permissions" example:"bookings:read,bookings:refund"`
+}
+
+// PermissionResponse describes one grantable permission
+type PermissionResponse struct {
+	Name        string `json:"name" example:"bookings:refund"`
+	Description string `json:"description" example:"Refund bookings"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+func roleToResponse(r *Role) RoleResponse {
+	perms := r.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+	return RoleResponse{Name: r.Name, Description: r.Description, BuiltIn: r.BuiltIn, Permissions: perms}
+}
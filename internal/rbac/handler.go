@@ -0,0 +1,175 @@
+package rbac
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"ticket-booking/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// Permissions godoc
+// @Summary List permissions
+// @Description List every permission that can be granted to a role
+// @Tags roles
+// @Produce json
+// @Success 200 {array} PermissionResponse
+// @Security BearerAuth
+// @Router /admin/permissions [get]
+func (h *Handler) Permissions(c *gin.Context) {
+	out := make([]PermissionResponse, 0, len(auth.PermissionCatalog))
+	for name, desc := range auth.PermissionCatalog {
+		out = append(out, PermissionResponse{Name: name, Description: desc})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	c.JSON(http.StatusOK, out)
+}
+
+// List godoc
+// @Summary List roles
+// @Description List roles with their permissions
+// @Tags roles
+// @Produce json
+// @Success 200 {array} RoleResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles [get]
+func (h *Handler) List(c *gin.Context) {
+	roles, err := h.svc.List(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]RoleResponse, 0, len(roles))
+	for i := range roles {
+		out = append(out, roleToResponse(&roles[i]))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get role
+// @Description Get a role and its permissions
+// @Tags roles
+// @Produce json
+// @Param name path string true "Role name"
+// @Success 200 {object} RoleResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/roles/{name} [get]
+func (h *Handler) Get(c *gin.Context) {
+	r, err := h.svc.Get(c, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, roleToResponse(r))
+}
+
+// Create godoc
+// @Summary Create role
+// @Description Create a role, e.g. SUPPORT or FINANCE, with a set of permissions. Users pick up permission changes on their next login.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param input body CreateRoleRequest true "Role data"
+// @Success 201 {object} RoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Role already exists"
+// @Security BearerAuth
+// @Router /admin/roles [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	r := &Role{Name: req.Name, Description: req.Description, Permissions: req.Permissions}
+	if err := h.svc.Create(c, r); err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, roleToResponse(r))
+}
+
+// Update godoc
+// @Summary Update role
+// @Description Update a role's description and replace its permissions. ADMIN must keep roles:manage.
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param name path string true "Role name"
+// @Param input body UpdateRoleRequest true "Role changes"
+// @Success 200 {object} RoleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Protected role"
+// @Security BearerAuth
+// @Router /admin/roles/{name} [put]
+func (h *Handler) Update(c *gin.Context) {
+	var req UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	r, err := h.svc.Get(c, c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	if req.Description != nil {
+		r.Description = req.Description
+	}
+	if req.Permissions != nil {
+		r.Permissions = *req.Permissions
+	}
+	if err := h.svc.Update(c, r); err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, roleToResponse(r))
+}
+
+// Delete godoc
+// @Summary Delete role
+// @Description Delete a custom role that no user holds. Built-in roles cannot be deleted.
+// @Tags roles
+// @Param name path string true "Role name"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Protected or in use"
+// @Security BearerAuth
+// @Router /admin/roles/{name} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.svc.Delete(c, c.Param("name")); err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidRoleName), errors.Is(err, ErrUnknownPermission):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrRoleExists), errors.Is(err, ErrProtectedRole), errors.Is(err, ErrRoleInUse):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,21 @@
+// Package rbac stores roles and the permissions they grant. Users carry a
+// role name; its permissions are resolved when tokens are issued.
+package rbac
+
+import "time"
+
+// Role is a named set of permissions assignable to users.
+type Role struct {
+	Name        string    `gorm:"type:text;primaryKey" json:"name"`       // e.g. ADMIN, SUPPORT
+	Description *string   `gorm:"type:text" json:"description,omitempty"` // Human-readable purpose
+	BuiltIn     bool      `gorm:"not null;default:false" json:"built_in"` // USER, ORGANIZER and ADMIN cannot be deleted
+	Permissions []string  `gorm:"-" json:"permissions"`                   // Loaded from role_permissions
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RolePermission grants one permission to one role.
+type RolePermission struct {
+	Role       string `gorm:"type:text;primaryKey"`
+	Permission string `gorm:"type:text;primaryKey"`
+}
@@ -0,0 +1,113 @@
+package rbac
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	List() ([]Role, error)
+	Get(name string) (*Role, error)
+	Create(r *Role) error
+	// Update saves the role and replaces its permission set
+	Update(r *Role) error
+	Delete(name string) error
+	CountUsers(name string) (int64, error)
+	Permissions(role string) ([]string, error)
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewRoleRepository(db *gorm.DB) RoleRepository { return &repo{db} }
+
+func (r *repo) List() ([]Role, error) {
+	var roles []Role
+	if err := r.db.Order("name asc").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	var grants []RolePermission
+	if err := r.db.Order("permission asc").Find(&grants).Error; err != nil {
+		return nil, err
+	}
+	byRole := map[string][]string{}
+	for _, g := range grants {
+		byRole[g.Role] = append(byRole[g.Role], g.Permission)
+	}
+	for i := range roles {
+		roles[i].Permissions = byRole[roles[i].Name]
+	}
+	return roles, nil
+}
+
+func (r *repo) Get(name string) (*Role, error) {
+	var role Role
+	if err := r.db.First(&role, "name = ?", name).Error; err != nil {
+		return nil, err
+	}
+	perms, err := r.Permissions(name)
+	if err != nil {
+		return nil, err
+	}
+	role.Permissions = perms
+	return &role, nil
+}
+
+func (r *repo) Create(role *Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(role).Error; err != nil {
+			return err
+		}
+		return insertGrants(tx, role)
+	})
+}
+
+func (r *repo) Update(role *Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role = ?", role.Name).Delete(&RolePermission{}).Error; err != nil {
+			return err
+		}
+		return insertGrants(tx, role)
+	})
+}
+
+func insertGrants(tx *gorm.DB, role *Role) error {
+	if len(role.Permissions) == 0 {
+		return nil
+	}
+	grants := make([]RolePermission, 0, len(role.Permissions))
+	for _, p := range role.Permissions {
+		grants = append(grants, RolePermission{Role: role.Name, Permission: p})
+	}
+	return tx.Create(&grants).Error
+}
+
+func (r *repo) Delete(name string) error {
+	res := r.db.Delete(&Role{}, "name = ?", name)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repo) CountUsers(name string) (int64, error) {
+	var n int64
+	err := r.db.Raw("SELECT COUNT(*) FROM users WHERE role = ?", name).Scan(&n).Error
+	return n, err
+}
+
+// Permissions returns the role's permissions in sorted order; unknown roles have none
+func (r *repo) Permissions(role string) ([]string, error) {
+	var perms []string
+	if err := r.db.Model(&RolePermission{}).Where("role = ?", role).Pluck("permission", &perms).Error; err != nil {
+		return nil, err
+	}
+	sort.Strings(perms)
+	return perms, nil
+}
@@ -0,0 +1,12 @@
+package rbac
+
+import "github.com/gin-gonic/gin"
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/permissions", h.Permissions)
+	r.GET("/roles", h.List)
+	r.GET("/roles/:name", h.Get)
+	r.POST("/roles", h.Create)
+	r.PUT("/roles/:name", h.Update)
+	r.DELETE("/roles/:name", h.Delete)
+}
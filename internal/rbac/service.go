@@ -0,0 +1,145 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"ticket-booking/internal/auth"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidRoleName is returned for names outside ^[A-Z][A-Z0-9_]{1,31}$
+	ErrInvalidRoleName = errors.New("role name must be 2-32 uppercase letters, digits or underscores")
+	// ErrUnknownPermission is returned when granting a permission not in auth.PermissionCatalog
+	ErrUnknownPermission = errors.New("unknown permission")
+	// ErrRoleExists is returned when creating a role that already exists
+	ErrRoleExists = errors.New("role already exists")
+	// ErrProtectedRole is returned when deleting a built-in role or removing
+	// roles:manage from ADMIN, which would lock everyone out of this API
+	ErrProtectedRole = errors.New("role is protected")
+	// ErrRoleInUse is returned when deleting a role that users still hold
+	ErrRoleInUse = errors.New("role is assigned to users")
+)
+
+var roleName = regexp.MustCompile(`^[A-Z][A-Z0-9_]{1,31}$`)
+
+// Service manages roles and resolves permissions for token issuance.
+type Service struct {
+	repo   RoleRepository
+	logger *zap.Logger
+}
+
+// NewService creates a new RBAC service
+func NewService(r RoleRepository, logger *zap.Logger) *Service {
+	return &Service{repo: r, logger: logger}
+}
+
+// PermissionsFor resolves the permissions granted to role. It is called
+// whenever tokens are issued, so grant changes apply from the next login.
+func (s *Service) PermissionsFor(ctx context.Context, role string) ([]string, error) {
+	perms, err := s.repo.Permissions(role)
+	if err != nil {
+		s.logger.Error("Failed to resolve role permissions", zap.String("role", role), zap.Error(err))
+		return nil, err
+	}
+	return perms, nil
+}
+
+func (s *Service) List(ctx context.Context) ([]Role, error) {
+	roles, err := s.repo.List()
+	if err != nil {
+		s.logger.Error("Failed to list roles", zap.Error(err))
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *Service) Get(ctx context.Context, name string) (*Role, error) {
+	return s.repo.Get(name)
+}
+
+func (s *Service) Create(ctx context.Context, r *Role) error {
+	if !roleName.MatchString(r.Name) {
+		return ErrInvalidRoleName
+	}
+	perms, err := normalize(r.Permissions)
+	if err != nil {
+		return err
+	}
+	r.Permissions = perms
+	r.BuiltIn = false
+	if _, err := s.repo.Get(r.Name); err == nil {
+		return ErrRoleExists
+	}
+	if err := s.repo.Create(r); err != nil {
+		s.logger.Error("Failed to create role", zap.String("role", r.Name), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Role created", zap.String("role", r.Name), zap.Strings("permissions", r.Permissions))
+	return nil
+}
+
+// Update replaces the role's description and permission set
+func (s *Service) Update(ctx context.Context, r *Role) error {
+	perms, err := normalize(r.Permissions)
+	if err != nil {
+		return err
+	}
+	if r.Name == auth.RoleAdmin && !auth.HasPermission(perms, auth.PermRolesManage) {
+		return fmt.Errorf("%w: %s must keep %s", ErrProtectedRole, auth.RoleAdmin, auth.PermRolesManage)
+	}
+	r.Permissions = perms
+	if err := s.repo.Update(r); err != nil {
+		s.logger.Error("Failed to update role", zap.String("role", r.Name), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Role updated", zap.String("role", r.Name), zap.Strings("permissions", r.Permissions))
+	return nil
+}
+
+// Delete removes a custom role that no user holds
+func (s *Service) Delete(ctx context.Context, name string) error {
+	r, err := s.repo.Get(name)
+	if err != nil {
+		return err
+	}
+	if r.BuiltIn {
+		return ErrProtectedRole
+	}
+	n, err := s.repo.CountUsers(name)
+	if err != nil {
+		s.logger.Error("Failed to count role users", zap.String("role", name), zap.Error(err))
+		return err
+	}
+	if n > 0 {
+		return ErrRoleInUse
+	}
+	if err := s.repo.Delete(name); err != nil {
+		s.logger.Error("Failed to delete role", zap.String("role", name), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Role deleted", zap.String("role", name))
+	return nil
+}
+
+// normalize validates perms against the catalog and returns them sorted and de-duplicated
+func normalize(perms []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(perms))
+	for _, p := range perms {
+		if _, ok := auth.PermissionCatalog[p]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
@@ -0,0 +1,84 @@
+package rbac_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/rbac"
+)
+
+func TestCreateRole_NormalizesPermissions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoleRepository(ctrl)
+	svc := rbac.NewService(repo, zap.NewNop())
+
+	r := &rbac.Role{Name: "SUPPORT", BuiltIn: true, Permissions: []string{auth.PermBookingsRefund, auth.PermBookingsRead, auth.PermBookingsRead}}
+	repo.EXPECT().Get("SUPPORT").Return(nil, gorm.ErrRecordNotFound)
+	repo.EXPECT().Create(r).Return(nil)
+
+	require.NoError(t, svc.Create(context.Background(), r))
+	require.Equal(t, []string{auth.PermBookingsRead, auth.PermBookingsRefund}, r.Permissions)
+	require.False(t, r.BuiltIn)
+}
+
+func TestCreateRole_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoleRepository(ctrl)
+	svc := rbac.NewService(repo, zap.NewNop())
+
+	require.ErrorIs(t, svc.Create(context.Background(), &rbac.Role{Name: "support"}), rbac.ErrInvalidRoleName)
+	require.ErrorIs(t, svc.Create(context.Background(), &rbac.Role{Name: "FINANCE", Permissions: []string{"money:print"}}), rbac.ErrUnknownPermission)
+
+	repo.EXPECT().Get("FINANCE").Return(&rbac.Role{Name: "FINANCE"}, nil)
+	require.ErrorIs(t, svc.Create(context.Background(), &rbac.Role{Name: "FINANCE"}), rbac.ErrRoleExists)
+}
+
+func TestUpdateRole_AdminKeepsRolesManage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoleRepository(ctrl)
+	svc := rbac.NewService(repo, zap.NewNop())
+
+	err := svc.Update(context.Background(), &rbac.Role{Name: auth.RoleAdmin, Permissions: []string{auth.PermEventsWrite}})
+	require.ErrorIs(t, err, rbac.ErrProtectedRole)
+}
+
+func TestDeleteRole_Guards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoleRepository(ctrl)
+	svc := rbac.NewService(repo, zap.NewNop())
+
+	repo.EXPECT().Get("USER").Return(&rbac.Role{Name: "USER", BuiltIn: true}, nil)
+	require.ErrorIs(t, svc.Delete(context.Background(), "USER"), rbac.ErrProtectedRole)
+
+	repo.EXPECT().Get("SUPPORT").Return(&rbac.Role{Name: "SUPPORT"}, nil)
+	repo.EXPECT().CountUsers("SUPPORT").Return(int64(4), nil)
+	require.ErrorIs(t, svc.Delete(context.Background(), "SUPPORT"), rbac.ErrRoleInUse)
+}
+
+func TestPermissionsFor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockRoleRepository(ctrl)
+	svc := rbac.NewService(repo, zap.NewNop())
+
+	repo.EXPECT().Permissions("ORGANIZER").Return([]string{auth.PermEventsOwn}, nil)
+	perms, err := svc.PermissionsFor(context.Background(), "ORGANIZER")
+	require.NoError(t, err)
+	require.Equal(t, []string{auth.PermEventsOwn}, perms)
+}
@@ -0,0 +1,12 @@
+package resale
+
+// CreateListingRequest lists a ticket for resale
+type CreateListingRequest struct {
+	TicketID   string `json:"ticket_id" binding:"required,uuid"`
+	PriceCents int64  `json:"price_cents" binding:"required,min=1" example:"5500"` // At most the event's cap times the price paid
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
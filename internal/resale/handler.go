@@ -0,0 +1,200 @@
+package resale
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/auth"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// fail maps resale errors to responses
+func (h *Handler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrListingNotFound), errors.Is(err, ErrPurchaseNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+	case errors.Is(err, ErrPriceAboveCap), errors.Is(err, ErrOwnListing):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrNotListable), errors.Is(err, ErrAlreadyListed), errors.Is(err, ErrListingUnavailable),
+		errors.Is(err, ErrResaleDisabled), errors.Is(err, ErrResaleClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		h.logger.Error("Resale request failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
+
+// ===== Browse =====
+// @Summary List resale offers
+// @Description Tickets of an event offered for resale that can be bought now, cheapest first
+// @Tags resale
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param limit query int false "Max items to return (default 50, max 200)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} Listing
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /resale/events/{id}/listings [get]
+func (h *Handler) Browse(c *gin.Context) {
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid event id"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	listings, err := h.svc.Browse(c, c.Param("id"), limit, offset)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	if listings == nil {
+		listings = []*Listing{}
+	}
+	c.JSON(http.StatusOK, listings)
+}
+
+// ===== Create =====
+// @Summary List a ticket for resale
+// @Description Offer a valid ticket of a CONFIRMED booking for sale. The price may not exceed the event's resale cap, a percentage of the price paid. The ticket stays usable until it sells.
+// @Tags resale
+// @Accept json
+// @Produce json
+// @Param request body CreateListingRequest true "Ticket and price"
+// @Success 201 {object} Listing
+// @Failure 400 {object} ErrorResponse "Invalid request or price above the cap"
+// @Failure 409 {object} ErrorResponse "Ticket not listable, already listed, or resale disabled or closed"
+// @Security BearerAuth
+// @Router /resale/listings [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	l, err := h.svc.Create(c, c.GetString(auth.CtxUserID), req.TicketID, req.PriceCents)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, l)
+}
+
+// ===== Mine =====
+// @Summary List my resale listings
+// @Description Listings the caller created in any state, newest first
+// @Tags resale
+// @Produce json
+// @Success 200 {array} Listing
+// @Security BearerAuth
+// @Router /resale/listings/mine [get]
+func (h *Handler) Mine(c *gin.Context) {
+	listings, err := h.svc.Mine(c, c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	if listings == nil {
+		listings = []*Listing{}
+	}
+	c.JSON(http.StatusOK, listings)
+}
+
+// ===== Withdraw =====
+// @Summary Withdraw a resale listing
+// @Description Take a listing off the market, unless a buyer is paying for it (seller only)
+// @Tags resale
+// @Produce json
+// @Param id path string true "Listing ID"
+// @Success 200 {object} Listing
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Listing sold, reserved or closed"
+// @Security BearerAuth
+// @Router /resale/listings/{id} [delete]
+func (h *Handler) Withdraw(c *gin.Context) {
+	l, err := h.svc.Withdraw(c, c.GetString(auth.CtxUserID), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// ===== Buy =====
+// @Summary Buy a resale listing
+// @Description Reserve a listing for 15 minutes and charge the caller through the payment provider. The ticket moves, under a new code, once payment completes; poll the purchase for its status.
+// @Tags resale
+// @Produce json
+// @Param id path string true "Listing ID"
+// @Success 202 {object} Purchase
+// @Failure 400 {object} ErrorResponse "Own listing"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Listing no longer available, or resale closed"
+// @Security BearerAuth
+// @Router /resale/listings/{id}/buy [post]
+func (h *Handler) Buy(c *gin.Context) {
+	p, err := h.svc.Buy(c, c.GetString(auth.CtxUserID), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, p)
+}
+
+// ===== Purchase =====
+// @Summary Get a resale purchase
+// @Description One of the caller's purchases; once COMPLETED, ticket_id is their new ticket
+// @Tags resale
+// @Produce json
+// @Param id path string true "Purchase ID"
+// @Success 200 {object} Purchase
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /resale/purchases/{id} [get]
+func (h *Handler) Purchase(c *gin.Context) {
+	p, err := h.svc.Purchase(c, c.GetString(auth.CtxUserID), c.Param("id"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// ===== Payouts =====
+// @Summary List my resale payouts
+// @Description What the caller is owed or was paid for sold listings, newest first
+// @Tags resale
+// @Produce json
+// @Success 200 {array} Payout
+// @Security BearerAuth
+// @Router /resale/payouts [get]
+func (h *Handler) Payouts(c *gin.Context) {
+	payouts, err := h.svc.Payouts(c, c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	if payouts == nil {
+		payouts = []*Payout{}
+	}
+	c.JSON(http.StatusOK, payouts)
+}
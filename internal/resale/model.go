@@ -0,0 +1,95 @@
+// Package resale lets ticket holders resell tickets to other users, within a
+// price cap set by the event's organiser.
+package resale
+
+import "time"
+
+// ListingStatus represents the lifecycle of a resale listing
+type ListingStatus string
+
+const (
+	// ListingActive listings can be bought
+	ListingActive ListingStatus = "ACTIVE"
+	// ListingReserved listings wait for a buyer's payment; once the hold lapses
+	// they can be bought again
+	ListingReserved ListingStatus = "RESERVED"
+	// ListingSold listings moved their ticket to the buyer
+	ListingSold ListingStatus = "SOLD"
+	// ListingWithdrawn listings were taken down by the seller
+	ListingWithdrawn ListingStatus = "WITHDRAWN"
+	// ListingRemoved listings lost their ticket: the booking was refunded,
+	// cancelled or moved, or the ticket was used or transferred
+	ListingRemoved ListingStatus = "REMOVED"
+)
+
+// Listing offers one ticket for sale
+type Listing struct {
+	ID             string        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TicketID       string        `gorm:"type:uuid;not null" json:"ticket_id"`
+	BookingID      string        `gorm:"type:uuid;not null" json:"booking_id"`
+	EventID        string        `gorm:"type:uuid;not null" json:"event_id"`
+	SellerID       string        `gorm:"type:uuid;not null" json:"seller_id"`
+	PriceCents     int64         `gorm:"not null" json:"price_cents"`
+	FaceValueCents int64         `gorm:"not null" json:"face_value_cents"` // Unit price paid for the ticket
+	Status         ListingStatus `gorm:"type:text;not null" json:"status"`
+	PurchaseID     *string       `gorm:"type:uuid" json:"purchase_id,omitempty"` // Latest purchase holding or completing it
+	ReservedUntil  *time.Time    `json:"reserved_until,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	ClosedAt       *time.Time    `json:"closed_at,omitempty"` // Sold, withdrawn or removed
+}
+
+// TableName keeps resale tables together
+func (Listing) TableName() string { return "resale_listings" }
+
+// PurchaseStatus represents the lifecycle of a resale purchase
+type PurchaseStatus string
+
+const (
+	// PurchasePending purchases wait for the payment provider
+	PurchasePending PurchaseStatus = "PENDING"
+	// PurchaseCompleted purchases were paid and moved the ticket
+	PurchaseCompleted PurchaseStatus = "COMPLETED"
+	// PurchaseFailed purchases were not paid, or were paid for a listing that
+	// lost its ticket meanwhile and are paid back
+	PurchaseFailed PurchaseStatus = "FAILED"
+)
+
+// Purchase is a buyer's attempt to buy a listing
+type Purchase struct {
+	ID          string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ListingID   string         `gorm:"type:uuid;not null" json:"listing_id"`
+	BuyerID     string         `gorm:"type:uuid;not null" json:"buyer_id"`
+	AmountCents int64          `gorm:"not null" json:"amount_cents"`
+	Status      PurchaseStatus `gorm:"type:text;not null" json:"status"`
+	TicketID    *string        `gorm:"type:uuid" json:"ticket_id,omitempty"` // The buyer's new ticket, once completed
+	CreatedAt   time.Time      `json:"created_at"`
+	ResolvedAt  *time.Time     `json:"resolved_at,omitempty"`
+}
+
+// TableName keeps resale tables together
+func (Purchase) TableName() string { return "resale_purchases" }
+
+// PayoutStatus represents whether a seller was paid
+type PayoutStatus string
+
+const (
+	// PayoutPending payouts wait for the payment provider
+	PayoutPending PayoutStatus = "PENDING"
+	// PayoutPaid payouts reached the seller
+	PayoutPaid PayoutStatus = "PAID"
+)
+
+// Payout records what a seller is owed for a sold listing
+type Payout struct {
+	ID          string       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ListingID   string       `gorm:"type:uuid;not null" json:"listing_id"`
+	PurchaseID  string       `gorm:"type:uuid;not null;uniqueIndex" json:"purchase_id"`
+	SellerID    string       `gorm:"type:uuid;not null" json:"seller_id"`
+	AmountCents int64        `gorm:"not null" json:"amount_cents"`
+	Status      PayoutStatus `gorm:"type:text;not null" json:"status"`
+	CreatedAt   time.Time    `json:"created_at"`
+	PaidAt      *time.Time   `json:"paid_at,omitempty"`
+}
+
+// TableName keeps resale tables together
+func (Payout) TableName() string { return "resale_payouts" }
@@ -0,0 +1,219 @@
+package resale
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/ticket"
+)
+
+var (
+	// ErrListingUnavailable is returned when a listing is sold, reserved by
+	// another buyer, withdrawn, removed, or its ticket is no longer the seller's
+	ErrListingUnavailable = errors.New("listing is not available")
+	// ErrAlreadyListed is returned when the ticket has an open listing
+	ErrAlreadyListed = errors.New("ticket is already listed")
+)
+
+type ResaleRepository interface {
+	// Create stores l unless its ticket has an ACTIVE or RESERVED listing
+	Create(l *Listing) error
+	Get(id string) (*Listing, error)
+	// ListOpen returns the ACTIVE listings of an event, and RESERVED ones whose
+	// hold lapsed before at, cheapest first. Listings whose ticket stopped being
+	// VALID or changed hands are left out.
+	ListOpen(eventID string, at time.Time, limit, offset int) ([]*Listing, error)
+	// ListBySeller returns a seller's listings, newest first
+	ListBySeller(sellerID string) ([]*Listing, error)
+	// Close moves an ACTIVE listing, or a RESERVED one whose hold lapsed, to status
+	Close(id string, status ListingStatus, at time.Time) error
+	// RemoveByBooking marks the ACTIVE and RESERVED listings of a booking REMOVED
+	RemoveByBooking(bookingID string, at time.Time) (int64, error)
+	// CountSold returns how many tickets of a booking were resold
+	CountSold(bookingID string) (int64, error)
+
+	// Reserve holds l for p until the given time and stores p; it fails with
+	// ErrListingUnavailable unless l is ACTIVE or its previous hold lapsed
+	Reserve(l *Listing, p *Purchase, until time.Time) error
+	GetPurchase(id string) (*Purchase, error)
+	// Complete, in one transaction, marks p COMPLETED and its listing SOLD,
+	// voids the seller's ticket, stores fresh for the buyer and records payout.
+	// It fails with ErrListingUnavailable, changing nothing, unless p still
+	// holds the listing, the seller's ticket is VALID and the booking is still
+	// CONFIRMED; the booking row stays share-locked until the sale commits.
+	Complete(p *Purchase, l *Listing, fresh *ticket.Ticket, payout *Payout, at time.Time) error
+	// Fail marks a PENDING purchase FAILED and releases the listing it holds;
+	// a listing whose ticket stopped being VALID is REMOVED instead
+	Fail(p *Purchase, at time.Time) error
+
+	// ListPayouts returns a seller's payouts, newest first
+	ListPayouts(sellerID string) ([]*Payout, error)
+	// MarkPaid marks the payout of a purchase PAID; repeat calls are no-ops
+	MarkPaid(purchaseID string, at time.Time) error
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewResaleRepository(db *gorm.DB) ResaleRepository { return &repo{db} }
+
+// open matches listings that can be bought at the given time
+const open = "(status = 'ACTIVE' OR (status = 'RESERVED' AND reserved_until <= ?))"
+
+// ticketHeld matches listings whose ticket is still VALID and the seller's
+const ticketHeld = "EXISTS (SELECT 1 FROM tickets t WHERE t.id = resale_listings.ticket_id AND t.status = 'VALID' AND t.holder_id = resale_listings.seller_id)"
+
+func (r *repo) Create(l *Listing) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Model(&Listing{}).Where("ticket_id = ? AND status IN ?", l.TicketID,
+			[]ListingStatus{ListingActive, ListingReserved}).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrAlreadyListed
+		}
+		return tx.Create(l).Error
+	})
+}
+
+func (r *repo) Get(id string) (*Listing, error) {
+	var l Listing
+	if err := r.db.First(&l, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *repo) ListOpen(eventID string, at time.Time, limit, offset int) ([]*Listing, error) {
+	var out []*Listing
+	return out, r.db.Where("event_id = ?", eventID).Where(open, at).Where(ticketHeld).
+		Order("price_cents asc, created_at asc").Limit(limit).Offset(offset).Find(&out).Error
+}
+
+func (r *repo) ListBySeller(sellerID string) ([]*Listing, error) {
+	var out []*Listing
+	return out, r.db.Where("seller_id = ?", sellerID).Order("created_at desc").Find(&out).Error
+}
+
+func (r *repo) Close(id string, status ListingStatus, at time.Time) error {
+	res := r.db.Model(&Listing{}).Where("id = ?", id).Where(open, at).
+		Updates(map[string]any{"status": status, "closed_at": at})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected != 1 {
+		return ErrListingUnavailable
+	}
+	return nil
+}
+
+func (r *repo) RemoveByBooking(bookingID string, at time.Time) (int64, error) {
+	res := r.db.Model(&Listing{}).Where("booking_id = ? AND status IN ?", bookingID,
+		[]ListingStatus{ListingActive, ListingReserved}).
+		Updates(map[string]any{"status": ListingRemoved, "closed_at": at})
+	return res.RowsAffected, res.Error
+}
+
+func (r *repo) CountSold(bookingID string) (int64, error) {
+	var n int64
+	return n, r.db.Model(&Listing{}).Where("booking_id = ? AND status = ?", bookingID, ListingSold).Count(&n).Error
+}
+
+func (r *repo) Reserve(l *Listing, p *Purchase, until time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&Listing{}).Where("id = ?", l.ID).Where(open, p.CreatedAt).Where(ticketHeld).
+			Updates(map[string]any{"status": ListingReserved, "purchase_id": p.ID, "reserved_until": until})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrListingUnavailable
+		}
+		return nil
+	})
+}
+
+func (r *repo) GetPurchase(id string) (*Purchase, error) {
+	var p Purchase
+	if err := r.db.First(&p, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *repo) Complete(p *Purchase, l *Listing, fresh *ticket.Ticket, payout *Payout, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// Refunds, cancellations and moves change the booking's status or
+		// lock it first, so they either wait for the sale or make it fail
+		var held int64
+		if err := tx.Model(&booking.Booking{}).Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id = ? AND status = ?", l.BookingID, booking.StatusConfirmed).Count(&held).Error; err != nil {
+			return err
+		}
+		if held != 1 {
+			return ErrListingUnavailable
+		}
+		// A lapsed hold still completes as long as nobody else took the listing
+		res := tx.Model(&Listing{}).Where("id = ? AND status = ? AND purchase_id = ?", l.ID, ListingReserved, p.ID).
+			Updates(map[string]any{"status": ListingSold, "closed_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrListingUnavailable
+		}
+		res = tx.Model(&ticket.Ticket{}).Where("id = ? AND status = ? AND holder_id = ?", l.TicketID, ticket.StatusValid, l.SellerID).
+			Updates(map[string]any{"status": ticket.StatusVoid, "voided_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrListingUnavailable
+		}
+		if err := tx.Create(fresh).Error; err != nil {
+			return err
+		}
+		res = tx.Model(&Purchase{}).Where("id = ? AND status = ?", p.ID, PurchasePending).
+			Updates(map[string]any{"status": PurchaseCompleted, "ticket_id": fresh.ID, "resolved_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrListingUnavailable
+		}
+		return tx.Create(payout).Error
+	})
+}
+
+func (r *repo) Fail(p *Purchase, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Purchase{}).Where("id = ? AND status = ?", p.ID, PurchasePending).
+			Updates(map[string]any{"status": PurchaseFailed, "resolved_at": at}).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&Listing{}).Where("id = ? AND status = ? AND purchase_id = ?", p.ListingID, ListingReserved, p.ID).
+			Where(ticketHeld).Updates(map[string]any{"status": ListingActive, "reserved_until": nil})
+		if res.Error != nil || res.RowsAffected == 1 {
+			return res.Error
+		}
+		return tx.Model(&Listing{}).Where("id = ? AND status = ? AND purchase_id = ?", p.ListingID, ListingReserved, p.ID).
+			Updates(map[string]any{"status": ListingRemoved, "closed_at": at}).Error
+	})
+}
+
+func (r *repo) ListPayouts(sellerID string) ([]*Payout, error) {
+	var out []*Payout
+	return out, r.db.Where("seller_id = ?", sellerID).Order("created_at desc").Find(&out).Error
+}
+
+func (r *repo) MarkPaid(purchaseID string, at time.Time) error {
+	return r.db.Model(&Payout{}).Where("purchase_id = ? AND status = ?", purchaseID, PayoutPending).
+		Updates(map[string]any{"status": PayoutPaid, "paid_at": at}).Error
+}
@@ -0,0 +1,15 @@
+package resale
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes exposes the resale market; r must require authentication
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	g := r.Group("/resale")
+	g.GET("/events/:id/listings", h.Browse)
+	g.POST("/listings", h.Create)
+	g.GET("/listings/mine", h.Mine)
+	g.DELETE("/listings/:id", h.Withdraw)
+	g.POST("/listings/:id/buy", h.Buy)
+	g.GET("/purchases/:id", h.Purchase)
+	g.GET("/payouts", h.Payouts)
+}
@@ -0,0 +1,412 @@
+package resale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/pkg/config"
+)
+
+// PurchaseHold is how long a listing stays reserved for a buyer's payment
+const PurchaseHold = 15 * time.Minute
+
+var (
+	// ErrResaleDisabled is returned for events whose organiser set no resale cap
+	ErrResaleDisabled = errors.New("resale is not enabled for this event")
+	// ErrResaleClosed is returned once the event is within the transfer cutoff
+	ErrResaleClosed = errors.New("resale is closed for this event")
+	// ErrPriceAboveCap is returned for prices above the organiser's cap
+	ErrPriceAboveCap = errors.New("price exceeds the resale cap")
+	// ErrNotListable is returned for tickets the caller does not hold, that are
+	// not VALID, belong to a booking that is not CONFIRMED or are being transferred
+	ErrNotListable = errors.New("ticket cannot be listed")
+	// ErrOwnListing is returned when a seller tries to buy their own listing
+	ErrOwnListing = errors.New("cannot buy your own listing")
+	// ErrListingNotFound is returned for unknown listings, and for other
+	// sellers' listings on withdraw
+	ErrListingNotFound = errors.New("listing not found")
+	// ErrPurchaseNotFound is returned for unknown purchases and other buyers' purchases
+	ErrPurchaseNotFound = errors.New("purchase not found")
+)
+
+// Publisher sends resale messages to the payment side
+type Publisher interface {
+	Publish(topic string, v interface{}) error
+}
+
+// Tickets looks up listed tickets
+type Tickets interface {
+	ListByIDs(ids []string) ([]*ticket.Ticket, error)
+}
+
+// Transfers looks up ticket transfers
+type Transfers interface {
+	Get(id string) (*ticket.Transfer, error)
+}
+
+// Bookings looks up the booking a ticket was bought with
+type Bookings interface {
+	Get(ctx context.Context, id string) (*booking.Booking, error)
+}
+
+// Events looks up the event a ticket is for, with its resale cap
+type Events interface {
+	Get(ctx context.Context, id string) (*event.Event, error)
+}
+
+// ResalePurchasedMessage is published when a buyer reserves a listing, for
+// the payment side to charge AmountCents. It answers on resale.paid with
+// PurchaseID, or resale.payment_failed.
+type ResalePurchasedMessage struct {
+	PurchaseID  string `json:"purchase_id"`  // UUID of the purchase
+	ListingID   string `json:"listing_id"`   // UUID of the listing bought
+	BuyerID     string `json:"buyer_id"`     // UUID of the user to charge
+	EventID     string `json:"event_id"`     // UUID of the event
+	AmountCents int64  `json:"amount_cents"` // Listing price
+}
+
+// ResalePurchaseFailedMessage is published when a paid purchase cannot
+// complete because the listing lost its ticket, for the payment side to pay
+// AmountCents back. Consumers must be idempotent by PurchaseID.
+type ResalePurchaseFailedMessage struct {
+	PurchaseID  string `json:"purchase_id"`
+	BuyerID     string `json:"buyer_id"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// ResalePayoutMessage is published when a listing sells, for the payment side
+// to pay the seller. It answers on resale.payout_paid with PurchaseID.
+// Consumers must be idempotent by PurchaseID.
+type ResalePayoutMessage struct {
+	PayoutID    string `json:"payout_id"`
+	PurchaseID  string `json:"purchase_id"`
+	SellerID    string `json:"seller_id"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+// Service runs the resale market. It plugs into the booking service through
+// booking.Service.SetResale, so refunded bookings leave the market.
+type Service struct {
+	repo      ResaleRepository
+	tickets   Tickets
+	transfers Transfers
+	bookings  Bookings
+	events    Events
+	publisher Publisher
+	cutoff    time.Duration
+	logger    *zap.Logger
+}
+
+func NewService(r ResaleRepository, tickets Tickets, transfers Transfers, bookings Bookings, events Events,
+	pub Publisher, cfg config.Booking, logger *zap.Logger) *Service {
+	return &Service{
+		repo:      r,
+		tickets:   tickets,
+		transfers: transfers,
+		bookings:  bookings,
+		events:    events,
+		publisher: pub,
+		cutoff:    time.Duration(cfg.TransferCutoffHours) * time.Hour,
+		logger:    logger,
+	}
+}
+
+// Ensure *Service can be handed to booking.Service.SetResale
+var _ booking.Resale = (*Service)(nil)
+
+// MaxPrice is the highest resale price for a ticket bought at faceValue
+func MaxPrice(e *event.Event, faceValue int64) int64 {
+	return faceValue * int64(e.ResaleCapPercent) / 100
+}
+
+// open checks e takes resale listings and purchases at now
+func (s *Service) open(e *event.Event, now time.Time) error {
+	if e.ResaleCapPercent <= 0 {
+		return ErrResaleDisabled
+	}
+	if !now.Before(e.StartsAt.Add(-s.cutoff)) {
+		return ErrResaleClosed
+	}
+	return nil
+}
+
+// Create lists a ticket held by sellerID for priceCents
+func (s *Service) Create(ctx context.Context, sellerID, ticketID string, priceCents int64) (*Listing, error) {
+	found, err := s.tickets.ListByIDs([]string{ticketID})
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != 1 || found[0].HolderID != sellerID || found[0].Status != ticket.StatusValid {
+		return nil, ErrNotListable
+	}
+	t := found[0]
+	if t.TransferID != nil {
+		tr, err := s.transfers.Get(*t.TransferID)
+		if err != nil {
+			return nil, err
+		}
+		if tr.Status == ticket.TransferPending && time.Now().Before(tr.ExpiresAt) {
+			return nil, ErrNotListable
+		}
+	}
+	b, err := s.bookings.Get(ctx, t.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	if b.Status != booking.StatusConfirmed {
+		return nil, ErrNotListable
+	}
+	e, err := s.events.Get(ctx, t.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.open(e, time.Now()); err != nil {
+		return nil, err
+	}
+	if priceCents > MaxPrice(e, b.UnitPriceCents) {
+		return nil, ErrPriceAboveCap
+	}
+
+	l := &Listing{
+		TicketID:       t.ID,
+		BookingID:      t.BookingID,
+		EventID:        t.EventID,
+		SellerID:       sellerID,
+		PriceCents:     priceCents,
+		FaceValueCents: b.UnitPriceCents,
+		Status:         ListingActive,
+	}
+	if err := s.repo.Create(l); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Resale listing created", zap.String("listing_id", l.ID), zap.String("event_id", l.EventID), zap.Int64("price_cents", priceCents))
+	return l, nil
+}
+
+// Browse lists the listings of an event that can be bought, cheapest first
+func (s *Service) Browse(ctx context.Context, eventID string, limit, offset int) ([]*Listing, error) {
+	return s.repo.ListOpen(eventID, time.Now(), limit, offset)
+}
+
+// Mine lists the listings sellerID created, newest first
+func (s *Service) Mine(ctx context.Context, sellerID string) ([]*Listing, error) {
+	return s.repo.ListBySeller(sellerID)
+}
+
+// Payouts lists what sellerID is owed or was paid, newest first
+func (s *Service) Payouts(ctx context.Context, sellerID string) ([]*Payout, error) {
+	return s.repo.ListPayouts(sellerID)
+}
+
+// Withdraw takes a listing of sellerID off the market, unless a buyer is paying for it
+func (s *Service) Withdraw(ctx context.Context, sellerID, id string) (*Listing, error) {
+	l, err := s.repo.Get(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && l.SellerID != sellerID) {
+		return nil, ErrListingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.repo.Close(id, ListingWithdrawn, now); err != nil {
+		return nil, err
+	}
+	l.Status, l.ClosedAt = ListingWithdrawn, &now
+	s.logger.Info("Resale listing withdrawn", zap.String("listing_id", id))
+	return l, nil
+}
+
+// RemoveListings takes the tickets of a refunded, cancelled or moved booking off the market
+func (s *Service) RemoveListings(ctx context.Context, bookingID string) error {
+	n, err := s.repo.RemoveByBooking(bookingID, time.Now())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.Info("Resale listings removed", zap.String("booking_id", bookingID), zap.Int64("count", n))
+	}
+	return nil
+}
+
+// SoldTickets returns how many tickets of a booking were resold; their
+// buyers hold them, so the booking can no longer be refunded or moved
+func (s *Service) SoldTickets(ctx context.Context, bookingID string) (int64, error) {
+	return s.repo.CountSold(bookingID)
+}
+
+// Buy reserves a listing for buyerID and asks the payment side to charge
+// them. The ticket moves once payment completes the purchase.
+func (s *Service) Buy(ctx context.Context, buyerID, id string) (*Purchase, error) {
+	l, err := s.repo.Get(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrListingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if l.SellerID == buyerID {
+		return nil, ErrOwnListing
+	}
+	e, err := s.events.Get(ctx, l.EventID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.open(e, now); err != nil {
+		return nil, err
+	}
+	p := &Purchase{
+		ListingID:   l.ID,
+		BuyerID:     buyerID,
+		AmountCents: l.PriceCents,
+		Status:      PurchasePending,
+		CreatedAt:   now,
+	}
+	if err := s.repo.Reserve(l, p, now.Add(PurchaseHold)); err != nil {
+		return nil, err
+	}
+	msg := ResalePurchasedMessage{
+		PurchaseID:  p.ID,
+		ListingID:   l.ID,
+		BuyerID:     buyerID,
+		EventID:     l.EventID,
+		AmountCents: p.AmountCents,
+	}
+	if err := s.publisher.Publish("resale.purchased", msg); err != nil {
+		s.logger.Error("Failed to publish resale purchased message", zap.String("purchase_id", p.ID), zap.Error(err))
+		// Nobody will charge the buyer; free the listing for others
+		if ferr := s.repo.Fail(p, time.Now()); ferr != nil {
+			s.logger.Error("Failed to release resale listing", zap.String("purchase_id", p.ID), zap.Error(ferr))
+		}
+		return nil, err
+	}
+	s.logger.Info("Resale purchase started", zap.String("purchase_id", p.ID), zap.String("listing_id", l.ID))
+	return p, nil
+}
+
+// Purchase returns a purchase made by buyerID
+func (s *Service) Purchase(ctx context.Context, buyerID, id string) (*Purchase, error) {
+	p, err := s.repo.GetPurchase(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && p.BuyerID != buyerID) {
+		return nil, ErrPurchaseNotFound
+	}
+	return p, err
+}
+
+// Complete moves a paid purchase's ticket to the buyer under a new code and
+// records the seller's payout. A listing that lost its ticket meanwhile fails
+// the purchase, and the buyer is paid back. Repeat calls are no-ops.
+func (s *Service) Complete(ctx context.Context, purchaseID string) error {
+	p, err := s.repo.GetPurchase(purchaseID)
+	if err != nil {
+		return err
+	}
+	if p.Status != PurchasePending {
+		return nil
+	}
+	l, err := s.repo.Get(p.ListingID)
+	if err != nil {
+		return err
+	}
+	found, err := s.tickets.ListByIDs([]string{l.TicketID})
+	if err != nil {
+		return err
+	}
+	if len(found) != 1 {
+		return s.refund(p, time.Now())
+	}
+	fresh, err := ticket.Replacement(found[0], p.BuyerID)
+	if err != nil {
+		return err
+	}
+	payout := &Payout{
+		ListingID:   l.ID,
+		PurchaseID:  p.ID,
+		SellerID:    l.SellerID,
+		AmountCents: p.AmountCents,
+		Status:      PayoutPending,
+	}
+	now := time.Now()
+	err = s.repo.Complete(p, l, fresh, payout, now)
+	if errors.Is(err, ErrListingUnavailable) {
+		return s.refund(p, now)
+	}
+	if err != nil {
+		return err
+	}
+	s.logger.Info("Resale purchase completed", zap.String("purchase_id", p.ID), zap.String("ticket_id", fresh.ID))
+
+	// The payout record stands either way; a lost message is settled from it
+	if err := s.publisher.Publish("resale.payout", ResalePayoutMessage{
+		PayoutID:    payout.ID,
+		PurchaseID:  p.ID,
+		SellerID:    l.SellerID,
+		AmountCents: payout.AmountCents,
+	}); err != nil {
+		s.logger.Error("Failed to publish resale payout message", zap.String("payout_id", payout.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// refund fails a paid purchase and asks the payment side to pay the buyer back
+func (s *Service) refund(p *Purchase, at time.Time) error {
+	msg := ResalePurchaseFailedMessage{PurchaseID: p.ID, BuyerID: p.BuyerID, AmountCents: p.AmountCents}
+	if err := s.publisher.Publish("resale.purchase_failed", msg); err != nil {
+		s.logger.Error("Failed to publish resale purchase failed message", zap.String("purchase_id", p.ID), zap.Error(err))
+		return err
+	}
+	if err := s.repo.Fail(p, at); err != nil {
+		return err
+	}
+	s.logger.Warn("Resale purchase failed after payment", zap.String("purchase_id", p.ID), zap.String("listing_id", p.ListingID))
+	return nil
+}
+
+// paymentMessage is the payment side's answer about a purchase or payout
+type paymentMessage struct {
+	PurchaseID string `json:"purchase_id"`
+}
+
+// HandlePaid processes resale.paid messages from the payment side: the buyer
+// was charged, so the purchase completes
+func (s *Service) HandlePaid(ctx context.Context, body []byte) error {
+	var msg paymentMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+	return s.Complete(ctx, msg.PurchaseID)
+}
+
+// HandlePaymentFailed processes resale.payment_failed messages: the buyer was
+// not charged, so the listing goes back on the market
+func (s *Service) HandlePaymentFailed(ctx context.Context, body []byte) error {
+	var msg paymentMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+	p, err := s.repo.GetPurchase(msg.PurchaseID)
+	if err != nil {
+		return err
+	}
+	if p.Status != PurchasePending {
+		return nil
+	}
+	return s.repo.Fail(p, time.Now())
+}
+
+// HandlePayoutPaid processes resale.payout_paid messages: the seller was paid
+func (s *Service) HandlePayoutPaid(ctx context.Context, body []byte) error {
+	var msg paymentMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+	return s.repo.MarkPaid(msg.PurchaseID, time.Now())
+}
@@ -0,0 +1,416 @@
+package resale_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/resale"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/pkg/config"
+)
+
+const (
+	eventID = "6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"
+	ticket1 = "3d9a1c7e-2b4f-4e8a-9c6d-1f0e2a3b4c5d"
+	ticket2 = "8e2b0d4f-6a1c-4b3d-9e5f-7a8b9c0d1e2f"
+)
+
+// memTickets holds tickets by ID
+type memTickets struct{ tickets []*ticket.Ticket }
+
+func (m *memTickets) ListByIDs(ids []string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for _, id := range ids {
+		for _, t := range m.tickets {
+			if t.ID == id {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memTickets) held(l *resale.Listing) bool {
+	found, _ := m.ListByIDs([]string{l.TicketID})
+	return len(found) == 1 && found[0].Status == ticket.StatusValid && found[0].HolderID == l.SellerID
+}
+
+// memResale is an in-memory ResaleRepository over the tickets of a memTickets
+type memResale struct {
+	tickets   *memTickets
+	listings  []*resale.Listing
+	purchases []*resale.Purchase
+	payouts   []*resale.Payout
+}
+
+func open(l *resale.Listing, at time.Time) bool {
+	return l.Status == resale.ListingActive || (l.Status == resale.ListingReserved && !l.ReservedUntil.After(at))
+}
+
+func (m *memResale) Create(l *resale.Listing) error {
+	for _, o := range m.listings {
+		if o.TicketID == l.TicketID && (o.Status == resale.ListingActive || o.Status == resale.ListingReserved) {
+			return resale.ErrAlreadyListed
+		}
+	}
+	l.ID, l.CreatedAt = "l"+string(rune('0'+len(m.listings)+1)), time.Now()
+	m.listings = append(m.listings, l)
+	return nil
+}
+
+func (m *memResale) Get(id string) (*resale.Listing, error) {
+	for _, l := range m.listings {
+		if l.ID == id {
+			return l, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memResale) ListOpen(eventID string, at time.Time, limit, offset int) ([]*resale.Listing, error) {
+	var out []*resale.Listing
+	for _, l := range m.listings {
+		if l.EventID == eventID && open(l, at) && m.tickets.held(l) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (m *memResale) ListBySeller(sellerID string) ([]*resale.Listing, error) {
+	var out []*resale.Listing
+	for _, l := range m.listings {
+		if l.SellerID == sellerID {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (m *memResale) Close(id string, status resale.ListingStatus, at time.Time) error {
+	l, err := m.Get(id)
+	if err != nil || !open(l, at) {
+		return resale.ErrListingUnavailable
+	}
+	l.Status, l.ClosedAt = status, &at
+	return nil
+}
+
+func (m *memResale) RemoveByBooking(bookingID string, at time.Time) (int64, error) {
+	var n int64
+	for _, l := range m.listings {
+		if l.BookingID == bookingID && (l.Status == resale.ListingActive || l.Status == resale.ListingReserved) {
+			l.Status, l.ClosedAt = resale.ListingRemoved, &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memResale) CountSold(bookingID string) (int64, error) {
+	var n int64
+	for _, l := range m.listings {
+		if l.BookingID == bookingID && l.Status == resale.ListingSold {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memResale) Reserve(l *resale.Listing, p *resale.Purchase, until time.Time) error {
+	if !open(l, p.CreatedAt) || !m.tickets.held(l) {
+		return resale.ErrListingUnavailable
+	}
+	p.ID = "p" + string(rune('0'+len(m.purchases)+1))
+	m.purchases = append(m.purchases, p)
+	l.Status, l.PurchaseID, l.ReservedUntil = resale.ListingReserved, &p.ID, &until
+	return nil
+}
+
+func (m *memResale) GetPurchase(id string) (*resale.Purchase, error) {
+	for _, p := range m.purchases {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memResale) Complete(p *resale.Purchase, l *resale.Listing, fresh *ticket.Ticket, payout *resale.Payout, at time.Time) error {
+	if l.Status != resale.ListingReserved || *l.PurchaseID != p.ID || !m.tickets.held(l) || p.Status != resale.PurchasePending {
+		return resale.ErrListingUnavailable
+	}
+	old, _ := m.tickets.ListByIDs([]string{l.TicketID})
+	old[0].Status, old[0].VoidedAt = ticket.StatusVoid, &at
+	fresh.ID = "fresh-" + p.ID
+	m.tickets.tickets = append(m.tickets.tickets, fresh)
+	l.Status, l.ClosedAt = resale.ListingSold, &at
+	p.Status, p.TicketID, p.ResolvedAt = resale.PurchaseCompleted, &fresh.ID, &at
+	payout.ID = "po-" + p.ID
+	m.payouts = append(m.payouts, payout)
+	return nil
+}
+
+func (m *memResale) Fail(p *resale.Purchase, at time.Time) error {
+	if p.Status == resale.PurchasePending {
+		p.Status, p.ResolvedAt = resale.PurchaseFailed, &at
+	}
+	l, _ := m.Get(p.ListingID)
+	if l.Status != resale.ListingReserved || *l.PurchaseID != p.ID {
+		return nil
+	}
+	if m.tickets.held(l) {
+		l.Status, l.ReservedUntil = resale.ListingActive, nil
+	} else {
+		l.Status, l.ClosedAt = resale.ListingRemoved, &at
+	}
+	return nil
+}
+
+func (m *memResale) ListPayouts(sellerID string) ([]*resale.Payout, error) {
+	var out []*resale.Payout
+	for _, p := range m.payouts {
+		if p.SellerID == sellerID {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func (m *memResale) MarkPaid(purchaseID string, at time.Time) error {
+	for _, p := range m.payouts {
+		if p.PurchaseID == purchaseID && p.Status == resale.PayoutPending {
+			p.Status, p.PaidAt = resale.PayoutPaid, &at
+		}
+	}
+	return nil
+}
+
+type noTransfers struct{}
+
+func (noTransfers) Get(string) (*ticket.Transfer, error) { return nil, gorm.ErrRecordNotFound }
+
+type bookingMap map[string]*booking.Booking
+
+func (m bookingMap) Get(_ context.Context, id string) (*booking.Booking, error) {
+	if b, ok := m[id]; ok {
+		return b, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type eventMap map[string]*event.Event
+
+func (m eventMap) Get(_ context.Context, id string) (*event.Event, error) {
+	if e, ok := m[id]; ok {
+		return e, nil
+	}
+	return nil, errors.New("not found")
+}
+
+// published records messages by topic
+type published map[string][]any
+
+func (p published) Publish(topic string, v interface{}) error {
+	p[topic] = append(p[topic], v)
+	return nil
+}
+
+type fixture struct {
+	svc     *resale.Service
+	repo    *memResale
+	tickets *memTickets
+	event   *event.Event
+	pub     published
+}
+
+// setup gives u1 two tickets bought at 50.00 for an event in ten days whose
+// organiser caps resale at 110%
+func setup() *fixture {
+	tickets := &memTickets{tickets: []*ticket.Ticket{
+		{ID: ticket1, BookingID: "b1", EventID: eventID, HolderID: "u1", Number: 1, Code: "7K3QX9M2TD", Status: ticket.StatusValid},
+		{ID: ticket2, BookingID: "b1", EventID: eventID, HolderID: "u1", Number: 2, Code: "Q4W8E2R6T0", Status: ticket.StatusValid},
+	}}
+	e := &event.Event{ID: eventID, StartsAt: time.Now().Add(240 * time.Hour), ResaleCapPercent: 110}
+	bookings := bookingMap{"b1": {ID: "b1", UserID: "u1", EventID: eventID, Quantity: 2, UnitPriceCents: 5000, Status: booking.StatusConfirmed}}
+	f := &fixture{repo: &memResale{tickets: tickets}, tickets: tickets, event: e, pub: published{}}
+	f.svc = resale.NewService(f.repo, tickets, noTransfers{}, bookings, eventMap{eventID: e}, f.pub,
+		config.Booking{TransferCutoffHours: 24}, zap.NewNop())
+	return f
+}
+
+func TestResale_PaidPurchaseMovesTicketAndRecordsPayout(t *testing.T) {
+	f := setup()
+	ctx := context.Background()
+
+	_, err := f.svc.Create(ctx, "u1", ticket1, 5501)
+	require.ErrorIs(t, err, resale.ErrPriceAboveCap)
+	_, err = f.svc.Create(ctx, "u2", ticket1, 5000)
+	require.ErrorIs(t, err, resale.ErrNotListable)
+	l, err := f.svc.Create(ctx, "u1", ticket1, 5500)
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), l.FaceValueCents)
+	_, err = f.svc.Create(ctx, "u1", ticket1, 5000)
+	require.ErrorIs(t, err, resale.ErrAlreadyListed)
+
+	_, err = f.svc.Buy(ctx, "u1", l.ID)
+	require.ErrorIs(t, err, resale.ErrOwnListing)
+	p, err := f.svc.Buy(ctx, "u2", l.ID)
+	require.NoError(t, err)
+	require.Equal(t, resale.PurchasePending, p.Status)
+	require.Len(t, f.pub["resale.purchased"], 1)
+	require.Equal(t, int64(5500), f.pub["resale.purchased"][0].(resale.ResalePurchasedMessage).AmountCents)
+
+	// Reserved while the buyer pays
+	listed, err := f.svc.Browse(ctx, eventID, 50, 0)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+	_, err = f.svc.Buy(ctx, "u3", l.ID)
+	require.ErrorIs(t, err, resale.ErrListingUnavailable)
+	_, err = f.svc.Withdraw(ctx, "u1", l.ID)
+	require.ErrorIs(t, err, resale.ErrListingUnavailable)
+
+	paid, _ := json.Marshal(map[string]string{"purchase_id": p.ID})
+	require.NoError(t, f.svc.HandlePaid(ctx, paid))
+	require.NoError(t, f.svc.HandlePaid(ctx, paid), "repeat deliveries are no-ops")
+
+	require.Equal(t, resale.PurchaseCompleted, p.Status)
+	require.Equal(t, resale.ListingSold, l.Status)
+	require.Equal(t, ticket.StatusVoid, f.tickets.tickets[0].Status)
+	fresh := f.tickets.tickets[2]
+	require.Equal(t, *p.TicketID, fresh.ID)
+	require.Equal(t, "u2", fresh.HolderID)
+	require.Equal(t, 1, fresh.Number)
+	require.Equal(t, "b1", fresh.BookingID)
+	require.NotEqual(t, f.tickets.tickets[0].Code, fresh.Code)
+
+	payouts, err := f.svc.Payouts(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, payouts, 1)
+	require.Equal(t, int64(5500), payouts[0].AmountCents)
+	require.Equal(t, resale.PayoutPending, payouts[0].Status)
+	require.Len(t, f.pub["resale.payout"], 1)
+	require.NoError(t, f.svc.HandlePayoutPaid(ctx, paid))
+	require.Equal(t, resale.PayoutPaid, payouts[0].Status)
+}
+
+func TestResale_RefundedBookingLeavesMarket(t *testing.T) {
+	f := setup()
+	ctx := context.Background()
+
+	l1, err := f.svc.Create(ctx, "u1", ticket1, 4000)
+	require.NoError(t, err)
+	l2, err := f.svc.Create(ctx, "u1", ticket2, 4500)
+	require.NoError(t, err)
+	p, err := f.svc.Buy(ctx, "u2", l2.ID)
+	require.NoError(t, err)
+
+	// Refunded while the buyer pays: both listings go, and the paid purchase is paid back
+	require.NoError(t, f.svc.RemoveListings(ctx, "b1"))
+	require.Equal(t, resale.ListingRemoved, l1.Status)
+	require.Equal(t, resale.ListingRemoved, l2.Status)
+	_, err = f.svc.Buy(ctx, "u3", l1.ID)
+	require.ErrorIs(t, err, resale.ErrListingUnavailable)
+
+	paid, _ := json.Marshal(map[string]string{"purchase_id": p.ID})
+	require.NoError(t, f.svc.HandlePaid(ctx, paid))
+	require.Equal(t, resale.PurchaseFailed, p.Status)
+	require.Len(t, f.pub["resale.purchase_failed"], 1)
+	require.Empty(t, f.pub["resale.payout"])
+	require.Equal(t, "u1", f.tickets.tickets[1].HolderID)
+}
+
+func TestResale_FailedPaymentAndCaps(t *testing.T) {
+	f := setup()
+	ctx := context.Background()
+
+	l, err := f.svc.Create(ctx, "u1", ticket1, 5000)
+	require.NoError(t, err)
+	p, err := f.svc.Buy(ctx, "u2", l.ID)
+	require.NoError(t, err)
+	failed, _ := json.Marshal(map[string]string{"purchase_id": p.ID})
+	require.NoError(t, f.svc.HandlePaymentFailed(ctx, failed))
+	require.Equal(t, resale.PurchaseFailed, p.Status)
+	require.Equal(t, resale.ListingActive, l.Status, "back on the market")
+
+	// The seller used the ticket at the door: the listing disappears
+	f.tickets.tickets[0].Status = ticket.StatusUsed
+	listed, err := f.svc.Browse(ctx, eventID, 50, 0)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+	_, err = f.svc.Buy(ctx, "u2", l.ID)
+	require.ErrorIs(t, err, resale.ErrListingUnavailable)
+
+	withdrawn, err := f.svc.Create(ctx, "u1", ticket2, 5000)
+	require.NoError(t, err)
+	_, err = f.svc.Withdraw(ctx, "u2", withdrawn.ID)
+	require.ErrorIs(t, err, resale.ErrListingNotFound)
+	_, err = f.svc.Withdraw(ctx, "u1", withdrawn.ID)
+	require.NoError(t, err)
+
+	f.event.ResaleCapPercent = 0
+	_, err = f.svc.Create(ctx, "u1", ticket2, 100)
+	require.ErrorIs(t, err, resale.ErrResaleDisabled)
+	f.event.ResaleCapPercent = 100
+	f.event.StartsAt = time.Now().Add(12 * time.Hour)
+	_, err = f.svc.Create(ctx, "u1", ticket2, 100)
+	require.ErrorIs(t, err, resale.ErrResaleClosed)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := setup()
+	h := resale.NewHandler(f.svc, zap.NewNop())
+
+	do := func(method, path, userID string, body any) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set(auth.CtxUserID, userID) })
+		resale.RegisterRoutes(r.Group(""), h)
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/resale/listings", "u1", map[string]any{"ticket_id": ticket1, "price_cents": 9000}).Code)
+	w := do(http.MethodPost, "/resale/listings", "u1", map[string]any{"ticket_id": ticket1, "price_cents": 5000})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var l resale.Listing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &l))
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/resale/listings", "u1", map[string]any{"ticket_id": ticket1, "price_cents": 5000}).Code)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/resale/events/e1/listings", "u2", nil).Code)
+	w = do(http.MethodGet, "/resale/events/"+eventID+"/listings", "u2", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var listed []resale.Listing
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+
+	w = do(http.MethodPost, "/resale/listings/"+l.ID+"/buy", "u2", nil)
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var p resale.Purchase
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/resale/purchases/"+p.ID, "u2", nil).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/resale/purchases/"+p.ID, "u1", nil).Code)
+	require.Equal(t, http.StatusConflict, do(http.MethodDelete, "/resale/listings/"+l.ID, "u1", nil).Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodPost, "/resale/listings/missing/buy", "u2", nil).Code)
+
+	w = do(http.MethodGet, "/resale/listings/mine", "u1", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"RESERVED"`)
+	require.JSONEq(t, `[]`, do(http.MethodGet, "/resale/payouts", "u1", nil).Body.String())
+}
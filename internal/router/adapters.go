@@ -0,0 +1,27 @@
+package router
+
+import (
+	"context"
+
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/user"
+)
+
+// UserBookings lists a user's bookings for the admin user API, so user does
+// not import booking
+type UserBookings struct {
+	Bookings *booking.Service
+}
+
+// ListByUser implements user.UserBookings
+func (u UserBookings) ListByUser(ctx context.Context, userID string, limit, offset int) ([]user.AccountBooking, error) {
+	bookings, err := u.Bookings.ListByUser(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]user.AccountBooking, 0, len(bookings))
+	for _, b := range bookings {
+		out = append(out, user.AccountBooking{ID: b.ID, EventID: b.EventID, Quantity: b.Quantity, Status: string(b.Status)})
+	}
+	return out, nil
+}
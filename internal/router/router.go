@@ -5,10 +5,19 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"ticket-booking/internal/audit"
 	"ticket-booking/internal/auth"
 	"ticket-booking/internal/booking"
+	"ticket-booking/internal/document"
 	"ticket-booking/internal/event"
+	"ticket-booking/internal/organizer"
+	"ticket-booking/internal/rbac"
+	"ticket-booking/internal/resale"
+	"ticket-booking/internal/search"
+	"ticket-booking/internal/series"
+	"ticket-booking/internal/ticket"
 	"ticket-booking/internal/user"
+	"ticket-booking/internal/venue"
 	"ticket-booking/pkg/config"
 
 	_ "ticket-booking/docs" // swagger docs
@@ -16,11 +25,26 @@ import (
 
 // Deps aggregates all handlers and cross-cutting dependencies
 type Deps struct {
-	UserH    *user.Handler
-	EventH   *event.Handler
-	BookingH *booking.Handler
-	Cfg      *config.Security
-	AuthM    *auth.Middleware
+	UserH         *user.Handler
+	UserAdminH    *user.AdminHandler
+	OIDCH         *user.OIDCHandler // optional; nil when no OIDC providers are configured
+	EventH        *event.Handler
+	BookingH      *booking.Handler
+	BookingAdminH *booking.AdminHandler
+	AttendeeH     *booking.AttendeeHandler
+	TicketH       *ticket.Handler
+	TransferH     *ticket.TransferHandler
+	DocumentH     *document.Handler
+	ResaleH       *resale.Handler
+	VenueH        *venue.Handler
+	OrganizerH    *organizer.Handler
+	SeriesH       *series.Handler
+	RBACH         *rbac.Handler
+	AuditH        *audit.Handler
+	SearchH       *search.Handler // optional; nil when Elasticsearch is not configured
+	Cfg           *config.Security
+	Keys          *auth.KeySet // optional; nil when access tokens use the HS256 secret
+	AuthM         *auth.Middleware
 }
 
 // New creates a new Gin router with middleware, rate limiting, and route registration.
@@ -36,12 +60,20 @@ func New(d Deps) *gin.Engine {
 	// API documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Public keys for verifying access tokens
+	if d.Keys != nil {
+		r.GET("/.well-known/jwks.json", d.Keys.JWKSHandler())
+	}
+
 	// API v1 root group
 	api := r.Group("/api/v1")
 
 	// Public routes (no authentication required)
-	user.RegisterRoutes(api, d.UserH)
 	event.RegisterPublicRoutes(api, d.EventH)
+	series.RegisterPublicRoutes(api, d.SeriesH)
+	if d.SearchH != nil {
+		search.RegisterPublicRoutes(api, d.SearchH)
+	}
 
 	// Rate limiting for all subsequent routes
 	api.Use(d.AuthM.RateLimit(auth.RatePlan{
@@ -51,17 +83,54 @@ func New(d Deps) *gin.Engine {
 		UserBurst: 20, // Authenticated: burst of 20 requests
 	}))
 
+	// Login, registration and refresh are rate limited per IP on top of the
+	// per-account lockout in user.LoginGuard
+	user.RegisterRoutes(api, d.UserH)
+	if d.OIDCH != nil {
+		user.RegisterOIDCRoutes(api, d.OIDCH)
+	}
+
 	// Protected routes (JWT authentication required)
 	protected := api.Group("")
 	protected.Use(d.AuthM.Authn())
 
 	booking.RegisterRoutes(protected, d.BookingH)
+	booking.RegisterAttendeeRoutes(protected, d.AttendeeH)
+	ticket.RegisterRoutes(protected, d.TicketH)
+	ticket.RegisterTransferRoutes(protected, d.TransferH)
+	document.RegisterRoutes(protected, d.DocumentH)
+	resale.RegisterRoutes(protected, d.ResaleH)
 	user.RegisterProtectedRoutes(protected, d.UserH)
 
-	// Admin-only routes (authentication + admin role required)
+	// Door check-in for event staff
+	checkin := api.Group("/checkin")
+	checkin.Use(d.AuthM.Authn(), d.AuthM.Require(auth.PermTicketsScan))
+	ticket.RegisterScannerRoutes(checkin, d.TicketH)
+
+	// Organizer routes: own events only (events:write passes every ownership check)
+	org := api.Group("/organizer")
+	org.Use(d.AuthM.Authn(), d.AuthM.RequireMFA(), d.AuthM.Require(auth.PermEventsOwn, auth.PermEventsWrite))
+	event.RegisterOrganizerRoutes(org, d.EventH)
+	booking.RegisterOrganizerRoutes(org, d.BookingH, d.EventH.RequireOwner())
+	booking.RegisterAttendeeExportRoutes(org, d.AttendeeH, d.EventH.RequireOwner())
+
+	// Admin routes (authentication + a permission per area). Roles listed in
+	// security.mfa_required_roles must have logged in with a TOTP code.
 	admin := api.Group("/admin")
-	admin.Use(d.AuthM.Authn(), d.AuthM.Authorize(auth.RoleAdmin))
-	event.RegisterAdminRoutes(admin, d.EventH)
+	admin.Use(d.AuthM.Authn(), d.AuthM.RequireMFA())
+	event.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermEventsWrite)), d.EventH)
+	series.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermEventsWrite)), d.SeriesH)
+	venue.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermVenuesWrite)), d.VenueH)
+	organizer.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermVenuesWrite)), d.OrganizerH)
+	rbac.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermRolesManage)), d.RBACH)
+	user.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermUsersManage)), d.UserH)
+	user.RegisterAdminUserRoutes(admin.Group("", d.AuthM.Require(auth.PermUsersManage)), d.UserAdminH)
+	booking.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermBookingsRead)), d.BookingAdminH,
+		d.AuthM.Require(auth.PermBookingsManage), d.AuthM.Require(auth.PermBookingsRefund))
+	audit.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermReportsRead)), d.AuditH)
+	if d.SearchH != nil {
+		search.RegisterAdminRoutes(admin.Group("", d.AuthM.Require(auth.PermSearchReindex)), d.SearchH)
+	}
 
 	return r
 }
@@ -0,0 +1,50 @@
+package search
+
+import "time"
+
+// SearchRequest holds GET /events/search query parameters
+type SearchRequest struct {
+	Q             string     `form:"q" binding:"max=100" example:"jaz festval"`
+	From          *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-09-01T00:00:00Z"`
+	To            *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2025-12-31T23:59:59Z"`
+	MinPriceCents *int64     `form:"min_price_cents" binding:"omitempty,min=0" example:"1000"`
+	MaxPriceCents *int64     `form:"max_price_cents" binding:"omitempty,min=0" example:"10000"`
+	Available     bool       `form:"available" example:"true"`
+	Limit         int        `form:"limit" binding:"omitempty,min=1,max=100" example:"20"`
+	Offset        int        `form:"offset" binding:"omitempty,min=0,max=10000" example:"0"`
+}
+
+// Hit is a single ranked search result
+type Hit struct {
+	ID          string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name        string    `json:"name" example:"Jazz Festival"`
+	Description string    `json:"description,omitempty" example:"Open air jazz"`
+	DateTime    time.Time `json:"date_time" example:"2025-09-02T09:00:00Z"`
+	TicketPrice float64   `json:"ticket_price" example:"50.00"`
+	Remaining   int       `json:"remaining" example:"95"`
+	Score       float64   `json:"score" example:"7.42"`
+}
+
+// FacetBucket is one value of a facet with its document count
+type FacetBucket struct {
+	Key   string `json:"key" example:"20_50"`
+	Count int64  `json:"count" example:"12"`
+}
+
+// SearchResponse is the GET /events/search output
+type SearchResponse struct {
+	Total  int64                    `json:"total" example:"42"`
+	Hits   []Hit                    `json:"hits"`
+	Facets map[string][]FacetBucket `json:"facets"`
+}
+
+// ReindexResponse reports the outcome of a full index rebuild
+type ReindexResponse struct {
+	Index     string `json:"index" example:"events_1726000000000000000"`
+	Documents int    `json:"documents" example:"17"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Handler serves search and index maintenance endpoints
+type Handler struct {
+	svc     *Service
+	indexer *Indexer
+	source  EventSource
+	logger  *zap.Logger
+}
+
+// NewHandler creates a new Handler. source feeds full index rebuilds.
+func NewHandler(s *Service, ix *Indexer, source EventSource, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, indexer: ix, source: source, logger: logger}
+}
+
+// Search godoc
+// @Summary Search events
+// @Description Full-text event search with relevance ranking, typo tolerance and facets (price, availability, month)
+// @Tags events
+// @Produce json
+// @Param q query string false "Search text (typos tolerated)"
+// @Param from query string false "Only events starting at or after (RFC3339)"
+// @Param to query string false "Only events starting at or before (RFC3339)"
+// @Param min_price_cents query int false "Minimum ticket price in cents"
+// @Param max_price_cents query int false "Maximum ticket price in cents"
+// @Param available query bool false "Only events with remaining tickets"
+// @Param limit query int false "Max items to return (default 20, max 100)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse "Search backend unavailable"
+// @Router /events/search [get]
+func (h *Handler) Search(c *gin.Context) {
+	var req SearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		h.logger.Warn("Invalid search request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if req.From != nil && req.To != nil && req.From.After(*req.To) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "from must be before to"})
+		return
+	}
+
+	res, err := h.svc.Search(c, req)
+	if err != nil {
+		h.logger.Error("Failed to search events", zap.String("q", req.Q), zap.Error(err))
+		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "search unavailable"})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Reindex godoc
+// @Summary Rebuild search index
+// @Description Rebuild the events search index from the database and swap it in atomically (Admin only)
+// @Tags events
+// @Produce json
+// @Success 200 {object} ReindexResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/search/reindex [post]
+func (h *Handler) Reindex(c *gin.Context) {
+	index, n, err := h.indexer.Rebuild(c, h.source)
+	if err != nil {
+		h.logger.Error("Failed to rebuild search index", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "reindex failed"})
+		return
+	}
+	h.logger.Info("Search index rebuilt", zap.String("index", index), zap.Int("documents", n))
+	c.JSON(http.StatusOK, ReindexResponse{Index: index, Documents: n})
+}
@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ticket-booking/internal/event"
+	"ticket-booking/pkg/search"
+
+	"go.uber.org/zap"
+)
+
+// EventSource lists the events that make up a full index rebuild
+type EventSource interface {
+	List() ([]event.Event, error)
+}
+
+// Indexer keeps the events index in sync with event.Service writes.
+// Reads and writes go through an alias so Rebuild can swap in a fresh
+// physical index without downtime.
+type Indexer struct {
+	client search.Client
+	alias  string
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// Ensure *Indexer implements event.Indexer
+var _ event.Indexer = (*Indexer)(nil)
+
+// NewIndexer creates an indexer writing to the given alias (e.g. "events").
+func NewIndexer(client search.Client, alias string, logger *zap.Logger) *Indexer {
+	return &Indexer{client: client, alias: alias, logger: logger, now: time.Now}
+}
+
+// IndexEvent creates or replaces the event's document
+func (ix *Indexer) IndexEvent(ctx context.Context, e *event.Event) error {
+	return ix.client.Index(ctx, ix.alias, e.ID, DocumentFromEvent(e))
+}
+
+// DeleteEvent removes the event's document
+func (ix *Indexer) DeleteEvent(ctx context.Context, id string) error {
+	return ix.client.Delete(ctx, ix.alias, id)
+}
+
+// UpdateRemaining patches seat availability only
+func (ix *Indexer) UpdateRemaining(ctx context.Context, id string, remaining int) error {
+	return ix.client.Update(ctx, ix.alias, id, map[string]int{"remaining": remaining})
+}
+
+// Rebuild loads every live event into a new physical index, then atomically
+// repoints the alias and drops the previous indexes. Events written while it
+// ran, by this process or another, went to the previous index; once the
+// alias points at the new one they are read again and indexed there.
+// Returns the new index name and number of documents indexed.
+func (ix *Indexer) Rebuild(ctx context.Context, src EventSource) (string, int, error) {
+	evts, err := src.List()
+	if err != nil {
+		return "", 0, err
+	}
+
+	index := fmt.Sprintf("%s_%d", ix.alias, ix.now().UnixNano())
+	if err := ix.client.CreateIndex(ctx, index, indexDefinition); err != nil {
+		return "", 0, err
+	}
+
+	docs := documents(evts)
+	if err := ix.client.Bulk(ctx, index, docs); err != nil {
+		_ = ix.client.DeleteIndex(ctx, index)
+		return "", 0, err
+	}
+
+	old, err := ix.client.AliasTargets(ctx, ix.alias)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(old) == 0 {
+		// First rebuild: incremental writes may have auto-created a concrete
+		// index under the alias name, which would block creating the alias.
+		// Writes it took after the listing are indexed again by catchUp.
+		if err := ix.client.DeleteIndex(ctx, ix.alias); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := ix.client.SwapAlias(ctx, ix.alias, index, old); err != nil {
+		return "", 0, err
+	}
+	// Writes from here on reach the new index through the alias
+	n, err := ix.catchUp(ctx, src, index, docs)
+	if err != nil {
+		ix.logger.Error("Failed to index events changed during rebuild", zap.String("index", index), zap.Error(err))
+		n = len(docs)
+	}
+	for _, o := range old {
+		if o == index {
+			continue
+		}
+		if err := ix.client.DeleteIndex(ctx, o); err != nil {
+			ix.logger.Warn("Failed to drop previous search index", zap.String("index", o), zap.Error(err))
+		}
+	}
+
+	ix.logger.Info("Search index rebuilt", zap.String("alias", ix.alias), zap.String("index", index), zap.Int("documents", n))
+	return index, n, nil
+}
+
+// catchUp lists the events again and brings index up to date with those
+// that changed since loaded was built. Returns the number of live events.
+func (ix *Indexer) catchUp(ctx context.Context, src EventSource, index string, loaded map[string]interface{}) (int, error) {
+	evts, err := src.List()
+	if err != nil {
+		return 0, err
+	}
+	current := documents(evts)
+	changed := make(map[string]interface{})
+	for id, d := range current {
+		if prev, ok := loaded[id]; !ok || prev != d {
+			changed[id] = d
+		}
+	}
+	if err := ix.client.Bulk(ctx, index, changed); err != nil {
+		return 0, err
+	}
+	for id := range loaded {
+		if _, ok := current[id]; ok {
+			continue
+		}
+		if err := ix.client.Delete(ctx, index, id); err != nil {
+			return 0, err
+		}
+	}
+	return len(current), nil
+}
+
+// documents maps events to their search documents by ID
+func documents(evts []event.Event) map[string]interface{} {
+	docs := make(map[string]interface{}, len(evts))
+	for i := range evts {
+		docs[evts[i].ID] = DocumentFromEvent(&evts[i])
+	}
+	return docs
+}
@@ -0,0 +1,64 @@
+// Package search maintains the Elasticsearch events index and serves
+// relevance-ranked, typo-tolerant event search with facets.
+package search
+
+import (
+	"time"
+
+	"ticket-booking/internal/event"
+)
+
+// Document is the indexed representation of an event.
+type Document struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description,omitempty"`
+	StartsAt         time.Time `json:"starts_at"`
+	EndsAt           time.Time `json:"ends_at"`
+	Capacity         int       `json:"capacity"`
+	Remaining        int       `json:"remaining"`
+	TicketPriceCents int64     `json:"ticket_price_cents"`
+}
+
+// DocumentFromEvent maps an event row to its search document
+func DocumentFromEvent(e *event.Event) Document {
+	d := Document{
+		ID:               e.ID,
+		Name:             e.Name,
+		StartsAt:         e.StartsAt,
+		EndsAt:           e.EndsAt,
+		Capacity:         e.Capacity,
+		Remaining:        e.Remaining,
+		TicketPriceCents: e.TicketPriceCents,
+	}
+	if e.Description != nil {
+		d.Description = *e.Description
+	}
+	return d
+}
+
+// indexDefinition holds settings and mappings for the events index.
+// name/description use the english analyzer for stemming; name also keeps a
+// keyword sub-field for exact sorting.
+var indexDefinition = map[string]interface{}{
+	"settings": map[string]interface{}{
+		"number_of_shards":   1,
+		"number_of_replicas": 0,
+	},
+	"mappings": map[string]interface{}{
+		"properties": map[string]interface{}{
+			"id": map[string]string{"type": "keyword"},
+			"name": map[string]interface{}{
+				"type":     "text",
+				"analyzer": "english",
+				"fields":   map[string]interface{}{"raw": map[string]string{"type": "keyword"}},
+			},
+			"description":        map[string]string{"type": "text", "analyzer": "english"},
+			"starts_at":          map[string]string{"type": "date"},
+			"ends_at":            map[string]string{"type": "date"},
+			"capacity":           map[string]string{"type": "integer"},
+			"remaining":          map[string]string{"type": "integer"},
+			"ticket_price_cents": map[string]string{"type": "long"},
+		},
+	},
+}
@@ -0,0 +1,11 @@
+package search
+
+import "github.com/gin-gonic/gin"
+
+func RegisterPublicRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/events/search", h.Search)
+}
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/search/reindex", h.Reindex)
+}
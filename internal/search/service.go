@@ -0,0 +1,205 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"ticket-booking/pkg/search"
+
+	"go.uber.org/zap"
+)
+
+// Service runs event searches against the events alias.
+type Service struct {
+	client search.Client
+	alias  string
+	logger *zap.Logger
+}
+
+// NewService creates a search service reading from the given alias.
+func NewService(client search.Client, alias string, logger *zap.Logger) *Service {
+	return &Service{client: client, alias: alias, logger: logger}
+}
+
+// priceRanges are the price facet buckets, in cents
+var priceRanges = []map[string]interface{}{
+	{"key": "under_20", "to": 2000},
+	{"key": "20_50", "from": 2000, "to": 5000},
+	{"key": "50_100", "from": 5000, "to": 10000},
+	{"key": "over_100", "from": 10000},
+}
+
+// BuildQuery translates a request into Elasticsearch query DSL.
+// Free text uses a fuzzy multi_match (typo tolerance, name boosted over
+// description) and results are ranked by relevance; without text, events are
+// ordered by start time. Filters do not affect scoring.
+func BuildQuery(req SearchRequest) map[string]interface{} {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	filters := []interface{}{}
+	if req.From != nil || req.To != nil {
+		r := map[string]interface{}{}
+		if req.From != nil {
+			r["gte"] = req.From.UTC().Format(time.RFC3339)
+		}
+		if req.To != nil {
+			r["lte"] = req.To.UTC().Format(time.RFC3339)
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"starts_at": r}})
+	}
+	if req.MinPriceCents != nil || req.MaxPriceCents != nil {
+		r := map[string]interface{}{}
+		if req.MinPriceCents != nil {
+			r["gte"] = *req.MinPriceCents
+		}
+		if req.MaxPriceCents != nil {
+			r["lte"] = *req.MaxPriceCents
+		}
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"ticket_price_cents": r}})
+	}
+	if req.Available {
+		filters = append(filters, map[string]interface{}{"range": map[string]interface{}{"remaining": map[string]int{"gt": 0}}})
+	}
+
+	boolQ := map[string]interface{}{"filter": filters}
+	sort := []interface{}{map[string]string{"starts_at": "asc"}}
+	if req.Q != "" {
+		boolQ["must"] = []interface{}{
+			map[string]interface{}{
+				"multi_match": map[string]interface{}{
+					"query":         req.Q,
+					"fields":        []string{"name^3", "description"},
+					"fuzziness":     "AUTO",
+					"prefix_length": 1,
+					"operator":      "and",
+				},
+			},
+		}
+		// exact phrase matches in the name float to the top
+		boolQ["should"] = []interface{}{
+			map[string]interface{}{"match_phrase": map[string]interface{}{"name": map[string]interface{}{"query": req.Q, "boost": 2}}},
+		}
+		sort = []interface{}{"_score", map[string]string{"starts_at": "asc"}}
+	}
+
+	return map[string]interface{}{
+		"from":             req.Offset,
+		"size":             limit,
+		"track_total_hits": true,
+		"query":            map[string]interface{}{"bool": boolQ},
+		"sort":             sort,
+		"aggs": map[string]interface{}{
+			"price": map[string]interface{}{
+				"range": map[string]interface{}{"field": "ticket_price_cents", "ranges": priceRanges},
+			},
+			"availability": map[string]interface{}{
+				"filters": map[string]interface{}{"filters": map[string]interface{}{
+					"available": map[string]interface{}{"range": map[string]interface{}{"remaining": map[string]int{"gt": 0}}},
+					"sold_out":  map[string]interface{}{"term": map[string]int{"remaining": 0}},
+				}},
+			},
+			"month": map[string]interface{}{
+				"date_histogram": map[string]interface{}{
+					"field":             "starts_at",
+					"calendar_interval": "month",
+					"format":            "yyyy-MM",
+					"min_doc_count":     1,
+				},
+			},
+		},
+	}
+}
+
+// esResponse is the subset of the _search response we consume
+type esResponse struct {
+	Hits struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			Score  *float64 `json:"_score"`
+			Source Document `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations struct {
+		Price struct {
+			Buckets []struct {
+				Key      string `json:"key"`
+				DocCount int64  `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"price"`
+		Availability struct {
+			Buckets map[string]struct {
+				DocCount int64 `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"availability"`
+		Month struct {
+			Buckets []struct {
+				KeyAsString string `json:"key_as_string"`
+				DocCount    int64  `json:"doc_count"`
+			} `json:"buckets"`
+		} `json:"month"`
+	} `json:"aggregations"`
+}
+
+// Search executes the query and maps hits and aggregations to the API shape.
+func (s *Service) Search(ctx context.Context, req SearchRequest) (*SearchResponse, error) {
+	raw, err := s.client.Search(ctx, s.alias, BuildQuery(req))
+	if err != nil {
+		s.logger.Error("Search request failed", zap.String("q", req.Q), zap.Error(err))
+		return nil, err
+	}
+
+	var es esResponse
+	if err := json.Unmarshal(raw, &es); err != nil {
+		s.logger.Error("Failed to decode search response", zap.Error(err))
+		return nil, err
+	}
+
+	out := &SearchResponse{
+		Total:  es.Hits.Total.Value,
+		Hits:   make([]Hit, 0, len(es.Hits.Hits)),
+		Facets: map[string][]FacetBucket{},
+	}
+	for _, h := range es.Hits.Hits {
+		hit := Hit{
+			ID:          h.Source.ID,
+			Name:        h.Source.Name,
+			Description: h.Source.Description,
+			DateTime:    h.Source.StartsAt,
+			TicketPrice: float64(h.Source.TicketPriceCents) / 100.0,
+			Remaining:   h.Source.Remaining,
+		}
+		if h.Score != nil {
+			hit.Score = *h.Score
+		}
+		out.Hits = append(out.Hits, hit)
+	}
+
+	price := make([]FacetBucket, 0, len(es.Aggregations.Price.Buckets))
+	for _, b := range es.Aggregations.Price.Buckets {
+		price = append(price, FacetBucket{Key: b.Key, Count: b.DocCount})
+	}
+	out.Facets["price"] = price
+
+	avail := make([]FacetBucket, 0, 2)
+	for _, k := range []string{"available", "sold_out"} {
+		if b, ok := es.Aggregations.Availability.Buckets[k]; ok {
+			avail = append(avail, FacetBucket{Key: k, Count: b.DocCount})
+		}
+	}
+	out.Facets["availability"] = avail
+
+	month := make([]FacetBucket, 0, len(es.Aggregations.Month.Buckets))
+	for _, b := range es.Aggregations.Month.Buckets {
+		month = append(month, FacetBucket{Key: b.KeyAsString, Count: b.DocCount})
+	}
+	out.Facets["month"] = month
+
+	s.logger.Info("Events searched", zap.String("q", req.Q), zap.Int64("total", out.Total))
+	return out, nil
+}
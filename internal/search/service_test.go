@@ -0,0 +1,282 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/search"
+)
+
+// fakeClient is an in-process stand-in for Elasticsearch. Documents are kept
+// per index, aliases resolve to their target, and searches return a canned
+// response while recording the query that was sent.
+type fakeClient struct {
+	mu        sync.Mutex
+	indexes   map[string]map[string]json.RawMessage
+	aliases   map[string][]string
+	lastQuery map[string]interface{}
+	response  string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{indexes: map[string]map[string]json.RawMessage{}, aliases: map[string][]string{}}
+}
+
+func (f *fakeClient) resolve(name string) string {
+	if t := f.aliases[name]; len(t) == 1 {
+		return t[0]
+	}
+	return name
+}
+
+func (f *fakeClient) docs(name string) map[string]json.RawMessage {
+	name = f.resolve(name)
+	if f.indexes[name] == nil {
+		f.indexes[name] = map[string]json.RawMessage{}
+	}
+	return f.indexes[name]
+}
+
+func (f *fakeClient) CreateIndex(_ context.Context, index string, _ interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.docs(index)
+	return nil
+}
+
+func (f *fakeClient) DeleteIndex(_ context.Context, index string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.indexes, index)
+	return nil
+}
+
+func (f *fakeClient) Index(_ context.Context, index, id string, doc interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw, _ := json.Marshal(doc)
+	f.docs(index)[id] = raw
+	return nil
+}
+
+func (f *fakeClient) Update(_ context.Context, index, id string, partial interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var merged map[string]interface{}
+	_ = json.Unmarshal(f.docs(index)[id], &merged)
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+	raw, _ := json.Marshal(partial)
+	_ = json.Unmarshal(raw, &merged)
+	f.docs(index)[id], _ = json.Marshal(merged)
+	return nil
+}
+
+func (f *fakeClient) Delete(_ context.Context, index, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.docs(index), id)
+	return nil
+}
+
+func (f *fakeClient) Bulk(ctx context.Context, index string, docs map[string]interface{}) error {
+	for id, d := range docs {
+		if err := f.Index(ctx, index, id, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *fakeClient) Search(_ context.Context, _ string, body interface{}) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw, _ := json.Marshal(body)
+	f.lastQuery = nil
+	_ = json.Unmarshal(raw, &f.lastQuery)
+	return []byte(f.response), nil
+}
+
+func (f *fakeClient) AliasTargets(_ context.Context, alias string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.aliases[alias]...), nil
+}
+
+func (f *fakeClient) SwapAlias(_ context.Context, alias, index string, _ []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.aliases[alias] = []string{index}
+	return nil
+}
+
+type staticSource []event.Event
+
+func (s staticSource) List() ([]event.Event, error) { return s, nil }
+
+func doc(t *testing.T, f *fakeClient, index, id string) map[string]interface{} {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw, ok := f.docs(index)[id]
+	if !ok {
+		return nil
+	}
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(raw, &out))
+	return out
+}
+
+func TestIndexer_SyncsEventWrites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockEventRepository(ctrl)
+	cache := mocks.NewMockCache(ctrl)
+	fake := newFakeClient()
+
+	svc := event.NewService(nil, repo, cache, zap.NewNop())
+	svc.SetIndexer(search.NewIndexer(fake, "events", zap.NewNop()))
+
+	cache.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	cache.EXPECT().Del(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	// Create
+	e := &event.Event{ID: "e1", Name: "Jazz Night", Capacity: 10, Remaining: 10, TicketPriceCents: 2500}
+	repo.EXPECT().Create(e).Return(nil)
+	require.NoError(t, svc.Create(context.Background(), e))
+	require.Equal(t, "Jazz Night", doc(t, fake, "events", "e1")["name"])
+
+	// Update
+	e.Name = "Late Jazz Night"
+	repo.EXPECT().Update(e).Return(nil)
+	require.NoError(t, svc.Update(context.Background(), e))
+	require.Equal(t, "Late Jazz Night", doc(t, fake, "events", "e1")["name"])
+
+	// Seat change through the transactional reservation path
+	repo.EXPECT().Reserve(gomock.Any(), "e1", 3).Return(true, nil)
+	repo.EXPECT().Get("e1").Return(&event.Event{ID: "e1", Remaining: 7}, nil)
+	ok, err := svc.ReserveTx(nil, "e1", 3)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, 7, doc(t, fake, "events", "e1")["remaining"])
+	require.Equal(t, "Late Jazz Night", doc(t, fake, "events", "e1")["name"])
+
+	// Delete
+	repo.EXPECT().Get("e1").Return(e, nil)
+	repo.EXPECT().Delete("e1", false).Return(int64(0), nil)
+	require.NoError(t, svc.Delete(context.Background(), "e1", false))
+	require.Nil(t, doc(t, fake, "events", "e1"))
+}
+
+func TestIndexer_RebuildSwapsAlias(t *testing.T) {
+	fake := newFakeClient()
+	fake.indexes["events_old"] = map[string]json.RawMessage{"stale": json.RawMessage(`{"id":"stale"}`)}
+	fake.aliases["events"] = []string{"events_old"}
+
+	ix := search.NewIndexer(fake, "events", zap.NewNop())
+	index, n, err := ix.Rebuild(context.Background(), staticSource{
+		{ID: "e1", Name: "Concert"},
+		{ID: "e2", Name: "Theater"},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []string{index}, fake.aliases["events"])
+	require.NotContains(t, fake.indexes, "events_old")
+	require.NotNil(t, doc(t, fake, "events", "e2"))
+	require.Nil(t, doc(t, fake, "events", "stale"))
+}
+
+// changingSource returns before on the first listing and after from then
+// on; write runs once the first listing is taken, as a concurrent event
+// change would
+type changingSource struct {
+	before, after []event.Event
+	write         func()
+	listed        bool
+}
+
+func (s *changingSource) List() ([]event.Event, error) {
+	if s.listed {
+		return s.after, nil
+	}
+	s.listed = true
+	defer s.write()
+	return s.before, nil
+}
+
+func TestIndexer_RebuildKeepsWritesMadeWhileLoading(t *testing.T) {
+	// First rebuild: incremental writes so far went to a concrete "events" index
+	fake := newFakeClient()
+	ix := search.NewIndexer(fake, "events", zap.NewNop())
+	ctx := context.Background()
+	renamed := event.Event{ID: "e1", Name: "Late Concert"}
+	src := &changingSource{
+		before: []event.Event{{ID: "e1", Name: "Concert"}, {ID: "e2", Name: "Theater"}},
+		after:  []event.Event{renamed, {ID: "e3", Name: "Opera"}},
+		write: func() {
+			require.NoError(t, ix.IndexEvent(ctx, &renamed))
+			require.NoError(t, ix.DeleteEvent(ctx, "e2"))
+			require.NoError(t, ix.IndexEvent(ctx, &event.Event{ID: "e3", Name: "Opera"}))
+		},
+	}
+
+	index, n, err := ix.Rebuild(ctx, src)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, []string{index}, fake.aliases["events"])
+	require.Equal(t, "Late Concert", doc(t, fake, "events", "e1")["name"])
+	require.Nil(t, doc(t, fake, "events", "e2"))
+	require.Equal(t, "Opera", doc(t, fake, "events", "e3")["name"])
+}
+
+func TestSearch_MapsHitsAndFacets(t *testing.T) {
+	fake := newFakeClient()
+	fake.response = `{
+		"hits": {"total": {"value": 1}, "hits": [
+			{"_score": 3.5, "_source": {"id": "e1", "name": "Jazz Festival", "remaining": 4, "ticket_price_cents": 4500}}
+		]},
+		"aggregations": {
+			"price": {"buckets": [{"key": "20_50", "doc_count": 1}]},
+			"availability": {"buckets": {"available": {"doc_count": 1}, "sold_out": {"doc_count": 0}}},
+			"month": {"buckets": [{"key_as_string": "2025-09", "doc_count": 1}]}
+		}
+	}`
+
+	svc := search.NewService(fake, "events", zap.NewNop())
+	res, err := svc.Search(context.Background(), search.SearchRequest{Q: "jaz festval", Available: true})
+
+	require.NoError(t, err)
+	require.EqualValues(t, 1, res.Total)
+	require.Len(t, res.Hits, 1)
+	require.Equal(t, "Jazz Festival", res.Hits[0].Name)
+	require.Equal(t, 45.0, res.Hits[0].TicketPrice)
+	require.Equal(t, 3.5, res.Hits[0].Score)
+	require.Equal(t, []search.FacetBucket{{Key: "20_50", Count: 1}}, res.Facets["price"])
+	require.Equal(t, []search.FacetBucket{{Key: "available", Count: 1}, {Key: "sold_out", Count: 0}}, res.Facets["availability"])
+	require.Equal(t, []search.FacetBucket{{Key: "2025-09", Count: 1}}, res.Facets["month"])
+
+	// typo tolerance and relevance ranking are requested from the backend
+	q := fake.lastQuery["query"].(map[string]interface{})["bool"].(map[string]interface{})
+	mm := q["must"].([]interface{})[0].(map[string]interface{})["multi_match"].(map[string]interface{})
+	require.Equal(t, "AUTO", mm["fuzziness"])
+	require.Equal(t, "_score", fake.lastQuery["sort"].([]interface{})[0])
+}
+
+func TestBuildQuery_NoTextSortsByStart(t *testing.T) {
+	q := search.BuildQuery(search.SearchRequest{})
+
+	require.Equal(t, 20, q["size"])
+	require.Equal(t, []interface{}{map[string]string{"starts_at": "asc"}}, q["sort"])
+	require.NotContains(t, q["query"].(map[string]interface{})["bool"], "must")
+}
@@ -0,0 +1,109 @@
+package series
+
+import (
+	"time"
+
+	"ticket-booking/internal/event"
+)
+
+// CreateSeriesRequest input for creating a recurring series
+type CreateSeriesRequest struct {
+	Name             string    `json:"name" binding:"required" example:"Friday Night Jazz"`
+	Description      *string   `json:"description" example:"Live jazz every Friday"`
+	RRule            string    `json:"rrule" binding:"required" example:"FREQ=WEEKLY;BYDAY=FR;COUNT=12"`
+	Timezone         string    `json:"timezone" example:"Asia/Ho_Chi_Minh"` // Defaults to UTC
+	StartsAt         time.Time `json:"starts_at" binding:"required" example:"2025-09-05T20:00:00+07:00"`
+	DurationMinutes  int       `json:"duration_minutes" binding:"required,min=1" example:"120"`
+	Capacity         int       `json:"capacity" binding:"omitempty,min=1" example:"200"` // Defaults to the venue's default capacity
+	TicketPriceCents int64     `json:"ticket_price_cents" binding:"min=0" example:"2500"`
+	VenueID          *string   `json:"venue_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	OrganizerID      *string   `json:"organizer_id" binding:"omitempty,uuid" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
+}
+
+// UpdateSeriesRequest input for a series-wide edit; omitted fields are unchanged
+type UpdateSeriesRequest struct {
+	Name             *string    `json:"name" example:"Friday Night Jazz"`
+	Description      *string    `json:"description" example:"Live jazz every Friday"`
+	RRule            *string    `json:"rrule" example:"FREQ=WEEKLY;BYDAY=FR,SA;COUNT=20"`
+	Timezone         *string    `json:"timezone" example:"Asia/Ho_Chi_Minh"`
+	StartsAt         *time.Time `json:"starts_at" example:"2025-09-05T20:00:00+07:00"`
+	DurationMinutes  *int       `json:"duration_minutes" binding:"omitempty,min=1" example:"150"`
+	Capacity         *int       `json:"capacity" binding:"omitempty,min=1" example:"250"`
+	TicketPriceCents *int64     `json:"ticket_price_cents" binding:"omitempty,min=0" example:"3000"`
+	VenueID          *string    `json:"venue_id" binding:"omitempty,uuid" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	OrganizerID      *string    `json:"organizer_id" binding:"omitempty,uuid" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
+}
+
+// SeriesResponse represents series output
+type SeriesResponse struct {
+	ID              string               `json:"id" example:"3f2b8c1a-5d6e-4f70-8a9b-0c1d2e3f4a5b"`
+	Name            string               `json:"name" example:"Friday Night Jazz"`
+	Description     *string              `json:"description,omitempty" example:"Live jazz every Friday"`
+	RRule           string               `json:"rrule" example:"FREQ=WEEKLY;BYDAY=FR;COUNT=12"`
+	Timezone        string               `json:"timezone" example:"Asia/Ho_Chi_Minh"`
+	StartsAt        time.Time            `json:"starts_at" example:"2025-09-05T20:00:00+07:00"`
+	DurationMinutes int                  `json:"duration_minutes" example:"120"`
+	Capacity        int                  `json:"capacity" example:"200"`
+	TicketPrice     float64              `json:"ticket_price" example:"25.00"`
+	VenueID         *string              `json:"venue_id,omitempty" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	OrganizerID     *string              `json:"organizer_id,omitempty" example:"9b2f6c1e-3d4a-4f5b-8c7d-1e2f3a4b5c6d"`
+	Upcoming        []OccurrenceResponse `json:"upcoming,omitempty"`
+}
+
+// OccurrenceResponse is a generated event as listed under its series
+type OccurrenceResponse struct {
+	EventID     string    `json:"event_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StartsAt    time.Time `json:"starts_at" example:"2025-09-05T13:00:00Z"`
+	EndsAt      time.Time `json:"ends_at" example:"2025-09-05T15:00:00Z"`
+	Remaining   int       `json:"remaining" example:"180"`
+	TicketPrice float64   `json:"ticket_price" example:"25.00"`
+	Override    bool      `json:"override" example:"false"` // Edited individually
+}
+
+// CreateSeriesResponse is returned after generating a series
+type CreateSeriesResponse struct {
+	Series      SeriesResponse `json:"series"`
+	Occurrences int            `json:"occurrences" example:"12"`
+}
+
+// UpdateSeriesResponse is returned after a series-wide edit
+type UpdateSeriesResponse struct {
+	Series SeriesResponse `json:"series"`
+	Sync   SyncResult     `json:"sync"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+func seriesToResponse(s *Series, upcoming []event.Event) SeriesResponse {
+	resp := SeriesResponse{
+		ID:              s.ID,
+		Name:            s.Name,
+		Description:     s.Description,
+		RRule:           s.RRule,
+		Timezone:        s.Timezone,
+		StartsAt:        s.StartsAt,
+		DurationMinutes: s.DurationMinutes,
+		Capacity:        s.Capacity,
+		TicketPrice:     float64(s.TicketPriceCents) / 100.0,
+		VenueID:         s.VenueID,
+		OrganizerID:     s.OrganizerID,
+	}
+	if loc, err := time.LoadLocation(s.Timezone); err == nil {
+		resp.StartsAt = s.StartsAt.In(loc)
+	}
+	for i := range upcoming {
+		e := &upcoming[i]
+		resp.Upcoming = append(resp.Upcoming, OccurrenceResponse{
+			EventID:     e.ID,
+			StartsAt:    e.StartsAt,
+			EndsAt:      e.EndsAt,
+			Remaining:   e.Remaining,
+			TicketPrice: float64(e.TicketPriceCents) / 100.0,
+			Override:    e.SeriesOverride,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,218 @@
+package series
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"ticket-booking/internal/event"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// List godoc
+// @Summary List event series
+// @Description List recurring event series
+// @Tags series
+// @Produce json
+// @Param limit query int false "Max items to return (default 20, max 100)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} SeriesResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /series [get]
+func (h *Handler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	list, err := h.svc.List(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]SeriesResponse, 0, len(list))
+	for i := range list {
+		out = append(out, seriesToResponse(&list[i], nil))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get event series
+// @Description Get a series with its upcoming occurrences. Use GET /events?series_id= for full event details.
+// @Tags series
+// @Produce json
+// @Param id path string true "Series ID"
+// @Success 200 {object} SeriesResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /series/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	id := c.Param("id")
+	sr, err := h.svc.Get(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	upcoming, err := h.svc.Upcoming(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, seriesToResponse(sr, upcoming))
+}
+
+// Create godoc
+// @Summary Create event series
+// @Description Create a recurring series and generate its events (Admin only). Supported RRULE parts: FREQ (DAILY, WEEKLY, MONTHLY), INTERVAL, BYDAY, and exactly one of COUNT or UNTIL.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param input body CreateSeriesRequest true "Series data"
+// @Success 201 {object} CreateSeriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/series [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid series creation request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	sr := &Series{
+		Name:             req.Name,
+		Description:      req.Description,
+		RRule:            req.RRule,
+		Timezone:         req.Timezone,
+		StartsAt:         req.StartsAt,
+		DurationMinutes:  req.DurationMinutes,
+		Capacity:         req.Capacity,
+		TicketPriceCents: req.TicketPriceCents,
+		VenueID:          req.VenueID,
+		OrganizerID:      req.OrganizerID,
+	}
+	if sr.Timezone == "" {
+		sr.Timezone = "UTC"
+	}
+	n, err := h.svc.Create(c, sr)
+	if err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, CreateSeriesResponse{Series: seriesToResponse(sr, nil), Occurrences: n})
+}
+
+// Update godoc
+// @Summary Update event series
+// @Description Edit the whole series (Admin only). Upcoming occurrences take the new template; occurrences edited individually via PUT /admin/events/{id} are left untouched. Dates dropped from the rule are archived unless they hold confirmed bookings.
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param id path string true "Series ID"
+// @Param input body UpdateSeriesRequest true "Updated series data"
+// @Success 200 {object} UpdateSeriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Capacity below tickets already sold"
+// @Security BearerAuth
+// @Router /admin/series/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid series update request", zap.String("series_id", id), zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	sr, err := h.svc.Get(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	if req.Name != nil {
+		sr.Name = *req.Name
+	}
+	if req.Description != nil {
+		sr.Description = req.Description
+	}
+	if req.RRule != nil {
+		sr.RRule = *req.RRule
+	}
+	if req.Timezone != nil {
+		sr.Timezone = *req.Timezone
+	}
+	if req.StartsAt != nil {
+		sr.StartsAt = *req.StartsAt
+	}
+	if req.DurationMinutes != nil {
+		sr.DurationMinutes = *req.DurationMinutes
+	}
+	if req.Capacity != nil {
+		sr.Capacity = *req.Capacity
+	}
+	if req.TicketPriceCents != nil {
+		sr.TicketPriceCents = *req.TicketPriceCents
+	}
+	if req.VenueID != nil {
+		sr.VenueID = req.VenueID
+	}
+	if req.OrganizerID != nil {
+		sr.OrganizerID = req.OrganizerID
+	}
+	res, err := h.svc.Update(c, sr)
+	if err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, UpdateSeriesResponse{Series: seriesToResponse(sr, nil), Sync: res})
+}
+
+// Delete godoc
+// @Summary Delete event series
+// @Description Archive upcoming occurrences and delete the series (Admin only). Past occurrences remain as standalone events. Refused with 409 while upcoming occurrences have confirmed bookings unless force=true.
+// @Tags series
+// @Param id path string true "Series ID"
+// @Param force query bool false "Cancel bookings on upcoming occurrences and delete anyway"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Series has confirmed bookings"
+// @Security BearerAuth
+// @Router /admin/series/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	force, _ := strconv.ParseBool(c.DefaultQuery("force", "false"))
+	if err := h.svc.Delete(c, c.Param("id"), force); err != nil {
+		c.JSON(statusFor(err), ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrCapacityBelowSold), errors.Is(err, ErrSeriesHasBookings), errors.Is(err, event.ErrEventHasBookings):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidRule), errors.Is(err, ErrTooManyOccurrences), errors.Is(err, ErrNoOccurrences),
+		errors.Is(err, ErrInvalidTimezone), errors.Is(err, event.ErrUnknownVenue),
+		errors.Is(err, event.ErrUnknownOrganizer), errors.Is(err, event.ErrCapacityRequired):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,77 @@
+// Package series manages recurring events. A series holds a recurrence rule
+// plus the template every generated occurrence (a regular event.Event) copies.
+package series
+
+import (
+	"time"
+
+	"ticket-booking/internal/event"
+)
+
+// Series is the template and recurrence definition for a run of events.
+type Series struct {
+	ID               string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name             string    `gorm:"type:text;not null" json:"name"`                                         // Copied to every occurrence
+	Description      *string   `gorm:"type:text" json:"description,omitempty"`                                 // Copied to every occurrence
+	RRule            string    `gorm:"column:rrule;type:text;not null" json:"rrule"`                           // e.g. FREQ=WEEKLY;BYDAY=FR;COUNT=12
+	Timezone         string    `gorm:"type:text;not null" json:"timezone"`                                     // IANA zone the wall-clock start time is kept in
+	StartsAt         time.Time `gorm:"not null" json:"starts_at"`                                              // First occurrence (DTSTART)
+	DurationMinutes  int       `gorm:"not null" json:"duration_minutes"`                                       // Occurrence length
+	Capacity         int       `gorm:"not null;default:0" json:"capacity"`                                     // 0 = venue default capacity
+	TicketPriceCents int64     `gorm:"column:ticket_price_cents;not null;default:0" json:"ticket_price_cents"` // Price per ticket in cents
+	VenueID          *string   `gorm:"type:uuid" json:"venue_id,omitempty"`
+	OrganizerID      *string   `gorm:"type:uuid" json:"organizer_id,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (Series) TableName() string { return "event_series" }
+
+// Schedule expands the series' recurrence rule into occurrence start times
+func (s *Series) Schedule() ([]time.Time, error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil || s.Timezone == "" || s.Timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	rule, err := ParseRule(s.RRule)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Occurrences(s.StartsAt.In(loc))
+}
+
+// occurrence builds the event generated for a given start time
+func (s *Series) occurrence(start time.Time) *event.Event {
+	id := s.ID
+	return &event.Event{
+		Name:             s.Name,
+		Description:      s.Description,
+		StartsAt:         start.UTC(),
+		EndsAt:           start.Add(s.duration()).UTC(),
+		Capacity:         s.Capacity,
+		Remaining:        s.Capacity,
+		TicketPriceCents: s.TicketPriceCents,
+		VenueID:          s.VenueID,
+		OrganizerID:      s.OrganizerID,
+		SeriesID:         &id,
+	}
+}
+
+// applyTo copies the series template onto an existing occurrence. The event
+// repository shifts remaining by the capacity delta when it stores e, so
+// sold seats are preserved.
+func (s *Series) applyTo(e *event.Event) {
+	e.Name = s.Name
+	e.Description = s.Description
+	e.EndsAt = e.StartsAt.Add(s.duration())
+	e.TicketPriceCents = s.TicketPriceCents
+	e.VenueID = s.VenueID
+	e.OrganizerID = s.OrganizerID
+	if s.Capacity > 0 {
+		e.Capacity = s.Capacity
+	}
+}
+
+func (s *Series) duration() time.Duration {
+	return time.Duration(s.DurationMinutes) * time.Minute
+}
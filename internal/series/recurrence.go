@@ -0,0 +1,227 @@
+package series
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is the RRULE FREQ part
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// MaxOccurrences caps how many events a single series may generate
+const MaxOccurrences = 366
+
+var (
+	// ErrInvalidRule is returned for malformed or unsupported recurrence rules
+	ErrInvalidRule = errors.New("invalid recurrence rule")
+	// ErrTooManyOccurrences is returned when a rule expands past MaxOccurrences
+	ErrTooManyOccurrences = fmt.Errorf("recurrence expands to more than %d occurrences", MaxOccurrences)
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule is the subset of RFC 5545 RRULE we support:
+// FREQ=DAILY|WEEKLY|MONTHLY, INTERVAL, BYDAY (plain weekdays), COUNT and UNTIL.
+// Exactly one of COUNT or UNTIL is required so every series is finite.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Count    int
+	Until    *time.Time
+}
+
+// ParseRule parses e.g. "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// A leading "RRULE:" prefix is accepted.
+func ParseRule(s string) (Rule, error) {
+	r := Rule{Interval: 1}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+	for _, part := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok || v == "" {
+			return r, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(v)); f {
+			case Daily, Weekly, Monthly:
+				r.Freq = f
+			default:
+				return r, fmt.Errorf("%w: unsupported FREQ %q", ErrInvalidRule, v)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := parseUntil(v)
+			if err != nil {
+				return r, fmt.Errorf("%w: UNTIL must be YYYYMMDD, YYYYMMDDTHHMMSSZ or RFC3339", ErrInvalidRule)
+			}
+			r.Until = &t
+		case "BYDAY":
+			seen := map[time.Weekday]bool{}
+			for _, d := range strings.Split(strings.ToUpper(v), ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return r, fmt.Errorf("%w: unsupported BYDAY %q", ErrInvalidRule, d)
+				}
+				if !seen[wd] {
+					seen[wd] = true
+					r.ByDay = append(r.ByDay, wd)
+				}
+			}
+		default:
+			return r, fmt.Errorf("%w: unsupported part %q", ErrInvalidRule, k)
+		}
+	}
+	if r.Freq == "" {
+		return r, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	}
+	if (r.Count == 0) == (r.Until == nil) {
+		return r, fmt.Errorf("%w: exactly one of COUNT or UNTIL is required", ErrInvalidRule)
+	}
+	if r.Freq == Monthly && len(r.ByDay) > 0 {
+		return r, fmt.Errorf("%w: BYDAY is not supported with FREQ=MONTHLY", ErrInvalidRule)
+	}
+	if r.Count > MaxOccurrences {
+		return r, ErrTooManyOccurrences
+	}
+	// Monday-first order keeps weekly expansion chronological within a week
+	sort.Slice(r.ByDay, func(i, j int) bool { return mondayIndex(r.ByDay[i]) < mondayIndex(r.ByDay[j]) })
+	return r, nil
+}
+
+func parseUntil(v string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	// A bare date includes the whole day
+	t, err := time.Parse("20060102", v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(24*time.Hour - time.Second), nil
+}
+
+// String renders the rule in canonical RRULE form
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			days = append(days, strings.ToUpper(wd.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences expands the rule from dtstart. Wall-clock time is kept in
+// dtstart's location, so a 19:00 weekly show stays at 19:00 across DST.
+// dtstart itself is the first occurrence only if it matches BYDAY.
+func (r Rule) Occurrences(dtstart time.Time) ([]time.Time, error) {
+	var out []time.Time
+	emit := func(t time.Time) (done bool, err error) {
+		if t.Before(dtstart) {
+			return false, nil
+		}
+		if r.Until != nil && t.After(*r.Until) {
+			return true, nil
+		}
+		if len(out) == MaxOccurrences {
+			return true, ErrTooManyOccurrences
+		}
+		out = append(out, t)
+		return r.Count > 0 && len(out) == r.Count, nil
+	}
+
+	y, m, d := dtstart.Date()
+	hh, mm, ss := dtstart.Clock()
+	loc := dtstart.Location()
+	at := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, hh, mm, ss, 0, loc) }
+
+	// Each period yields at least one candidate or is skipped (short months),
+	// so this bound is only a guard against rules that never terminate.
+	for period := 0; period < MaxOccurrences*31; period++ {
+		var candidates []time.Time
+		switch r.Freq {
+		case Daily:
+			t := at(y, m, d+period*r.Interval)
+			if len(r.ByDay) == 0 || containsDay(r.ByDay, t.Weekday()) {
+				candidates = append(candidates, t)
+			}
+		case Weekly:
+			weekStart := d - mondayIndex(dtstart.Weekday()) + period*7*r.Interval
+			days := r.ByDay
+			if len(days) == 0 {
+				days = []time.Weekday{dtstart.Weekday()}
+			}
+			for _, wd := range days {
+				candidates = append(candidates, at(y, m, weekStart+mondayIndex(wd)))
+			}
+		case Monthly:
+			first := at(y, m+time.Month(period*r.Interval), 1)
+			// Months without this day (e.g. the 31st) are skipped, as in RFC 5545
+			if t := at(first.Year(), first.Month(), d); t.Month() == first.Month() {
+				candidates = append(candidates, t)
+			}
+		}
+		for _, t := range candidates {
+			done, err := emit(t)
+			if err != nil {
+				return nil, err
+			}
+			if done {
+				return out, nil
+			}
+		}
+	}
+	return out, nil
+}
+
+func mondayIndex(wd time.Weekday) int { return (int(wd) + 6) % 7 }
+
+func containsDay(days []time.Weekday, wd time.Weekday) bool {
+	for _, d := range days {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package series
+
+import (
+	"time"
+
+	"ticket-booking/internal/event"
+
+	"gorm.io/gorm"
+)
+
+type SeriesRepository interface {
+	List(limit, offset int) ([]Series, error)
+	Get(id string) (*Series, error)
+	Create(s *Series) error
+	Update(s *Series) error
+	Delete(id string) error
+	// Occurrences lists the series' events starting at or after from
+	Occurrences(seriesID string, from time.Time) ([]event.Event, error)
+	// CountConfirmedBookings counts CONFIRMED bookings on occurrences starting at or after from
+	CountConfirmedBookings(seriesID string, from time.Time) (int64, error)
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository { return &repo{db} }
+
+func (r *repo) List(limit, offset int) ([]Series, error) {
+	var out []Series
+	q := r.db.Order("starts_at asc, id asc")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	return out, q.Find(&out).Error
+}
+
+func (r *repo) Get(id string) (*Series, error) {
+	var s Series
+	if err := r.db.First(&s, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *repo) Create(s *Series) error { return r.db.Create(s).Error }
+func (r *repo) Update(s *Series) error { return r.db.Save(s).Error }
+
+func (r *repo) Delete(id string) error {
+	res := r.db.Delete(&Series{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repo) Occurrences(seriesID string, from time.Time) ([]event.Event, error) {
+	var out []event.Event
+	return out, r.db.
+		Where("series_id = ? AND starts_at >= ?", seriesID, from).
+		Order("starts_at asc").
+		Find(&out).Error
+}
+
+func (r *repo) CountConfirmedBookings(seriesID string, from time.Time) (int64, error) {
+	var n int64
+	err := r.db.Raw(`SELECT COUNT(*) FROM bookings b
+		JOIN events e ON e.id = b.event_id
+		WHERE e.series_id = ? AND e.starts_at >= ? AND e.deleted_at IS NULL AND b.status = ?`,
+		seriesID, from, "CONFIRMED",
+	).Scan(&n).Error
+	return n, err
+}
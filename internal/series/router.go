@@ -0,0 +1,14 @@
+package series
+
+import "github.com/gin-gonic/gin"
+
+func RegisterPublicRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/series", h.List)
+	r.GET("/series/:id", h.Get)
+}
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/series", h.Create)
+	r.PUT("/series/:id", h.Update)
+	r.DELETE("/series/:id", h.Delete)
+}
@@ -0,0 +1,241 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"ticket-booking/internal/event"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidTimezone is returned when the series timezone is not a known IANA zone
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrNoOccurrences is returned when a rule produces no events
+	ErrNoOccurrences = errors.New("recurrence produces no occurrences")
+	// ErrCapacityBelowSold is returned when a series-wide capacity is lower than
+	// the seats already taken on one of its upcoming occurrences
+	ErrCapacityBelowSold = errors.New("capacity is below tickets already sold for an occurrence")
+	// ErrSeriesHasBookings is returned when deleting a series whose upcoming
+	// occurrences still have CONFIRMED bookings
+	ErrSeriesHasBookings = errors.New("series has confirmed bookings")
+)
+
+// SyncResult reports what a series-wide edit did to upcoming occurrences
+type SyncResult struct {
+	Created int `json:"created"` // New dates added by the recurrence rule
+	Updated int `json:"updated"` // Occurrences that took the new template
+	Removed int `json:"removed"` // Dates no longer in the rule (archived)
+	Skipped int `json:"skipped"` // Individually edited, or dropped dates that still hold bookings
+}
+
+// Service generates and maintains the events of recurring series.
+// Occurrences are written through the event service so caching, search
+// indexing and venue/organizer validation behave exactly as for single events.
+type Service struct {
+	repo   SeriesRepository
+	events event.ServiceInterface
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewService creates a new series service
+func NewService(r SeriesRepository, events event.ServiceInterface, logger *zap.Logger) *Service {
+	return &Service{repo: r, events: events, logger: logger, now: time.Now}
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]Series, error) {
+	out, err := s.repo.List(limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list series", zap.Error(err))
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Series, error) {
+	sr, err := s.repo.Get(id)
+	if err != nil {
+		s.logger.Error("Failed to get series", zap.String("series_id", id), zap.Error(err))
+		return nil, err
+	}
+	return sr, nil
+}
+
+// Upcoming lists the occurrences that have not started yet
+func (s *Service) Upcoming(ctx context.Context, id string) ([]event.Event, error) {
+	out, err := s.repo.Occurrences(id, s.now())
+	if err != nil {
+		s.logger.Error("Failed to list series occurrences", zap.String("series_id", id), zap.Error(err))
+		return nil, err
+	}
+	return out, nil
+}
+
+// Create stores the series and generates one event per occurrence. If any
+// occurrence fails, the ones already generated and the series are removed.
+func (s *Service) Create(ctx context.Context, sr *Series) (int, error) {
+	schedule, err := sr.Schedule()
+	if err != nil {
+		return 0, err
+	}
+	if len(schedule) == 0 {
+		return 0, ErrNoOccurrences
+	}
+	if err := s.repo.Create(sr); err != nil {
+		s.logger.Error("Failed to create series", zap.String("name", sr.Name), zap.Error(err))
+		return 0, err
+	}
+
+	created := make([]string, 0, len(schedule))
+	for _, start := range schedule {
+		e := sr.occurrence(start)
+		if err := s.events.Create(ctx, e); err != nil {
+			s.logger.Error("Failed to generate series occurrence",
+				zap.String("series_id", sr.ID), zap.Time("starts_at", start), zap.Error(err))
+			s.rollback(ctx, sr.ID, created)
+			return 0, err
+		}
+		created = append(created, e.ID)
+	}
+
+	s.logger.Info("Series created", zap.String("series_id", sr.ID), zap.Int("occurrences", len(created)))
+	return len(created), nil
+}
+
+func (s *Service) rollback(ctx context.Context, seriesID string, eventIDs []string) {
+	for _, id := range eventIDs {
+		if err := s.events.Delete(ctx, id, true); err != nil {
+			s.logger.Warn("Failed to roll back series occurrence", zap.String("event_id", id), zap.Error(err))
+		}
+	}
+	if err := s.repo.Delete(seriesID); err != nil {
+		s.logger.Warn("Failed to roll back series", zap.String("series_id", seriesID), zap.Error(err))
+	}
+}
+
+// Update saves the series and re-syncs its upcoming occurrences: dates still in
+// the rule take the new template, new dates are generated, and dates dropped
+// from the rule are archived unless they hold CONFIRMED bookings. Occurrences
+// edited individually (SeriesOverride) and past occurrences are left alone.
+func (s *Service) Update(ctx context.Context, sr *Series) (SyncResult, error) {
+	var res SyncResult
+	schedule, err := sr.Schedule()
+	if err != nil {
+		return res, err
+	}
+	now := s.now()
+	existing, err := s.repo.Occurrences(sr.ID, now)
+	if err != nil {
+		s.logger.Error("Failed to list series occurrences", zap.String("series_id", sr.ID), zap.Error(err))
+		return res, err
+	}
+
+	wanted := make(map[int64]time.Time, len(schedule))
+	for _, t := range schedule {
+		if !t.Before(now) {
+			wanted[t.Unix()] = t
+		}
+	}
+	// Check capacity up front so a rejected edit changes nothing
+	if sr.Capacity > 0 {
+		for i := range existing {
+			e := &existing[i]
+			if _, keep := wanted[e.StartsAt.Unix()]; keep && !e.SeriesOverride && sr.Capacity < e.Capacity-e.Remaining {
+				return res, ErrCapacityBelowSold
+			}
+		}
+	}
+
+	if err := s.repo.Update(sr); err != nil {
+		s.logger.Error("Failed to update series", zap.String("series_id", sr.ID), zap.Error(err))
+		return res, err
+	}
+
+	for i := range existing {
+		e := &existing[i]
+		key := e.StartsAt.Unix()
+		_, keep := wanted[key]
+		delete(wanted, key) // the slot is taken either way
+		switch {
+		case e.SeriesOverride:
+			res.Skipped++
+		case keep:
+			sr.applyTo(e)
+			if err := s.events.Update(ctx, e); err != nil {
+				if errors.Is(err, event.ErrCapacityBelowSold) {
+					return res, ErrCapacityBelowSold
+				}
+				return res, err
+			}
+			res.Updated++
+		default:
+			if err := s.events.Delete(ctx, e.ID, false); err != nil {
+				if !errors.Is(err, event.ErrEventHasBookings) {
+					return res, err
+				}
+				s.logger.Warn("Keeping dropped occurrence with bookings",
+					zap.String("series_id", sr.ID), zap.String("event_id", e.ID))
+				res.Skipped++
+				continue
+			}
+			res.Removed++
+		}
+	}
+
+	for _, start := range schedule {
+		if _, ok := wanted[start.Unix()]; !ok {
+			continue
+		}
+		if err := s.events.Create(ctx, sr.occurrence(start)); err != nil {
+			return res, err
+		}
+		res.Created++
+	}
+
+	s.logger.Info("Series updated", zap.String("series_id", sr.ID),
+		zap.Int("created", res.Created), zap.Int("updated", res.Updated),
+		zap.Int("removed", res.Removed), zap.Int("skipped", res.Skipped))
+	return res, nil
+}
+
+// Delete archives the upcoming occurrences and removes the series. Past
+// occurrences stay as standalone events. Refuses with ErrSeriesHasBookings
+// while upcoming occurrences have CONFIRMED bookings unless force is set.
+func (s *Service) Delete(ctx context.Context, id string, force bool) error {
+	if _, err := s.repo.Get(id); err != nil {
+		s.logger.Error("Failed to get series for delete", zap.String("series_id", id), zap.Error(err))
+		return err
+	}
+	now := s.now()
+	if !force {
+		n, err := s.repo.CountConfirmedBookings(id, now)
+		if err != nil {
+			s.logger.Error("Failed to count series bookings", zap.String("series_id", id), zap.Error(err))
+			return err
+		}
+		if n > 0 {
+			s.logger.Warn("Series delete refused: confirmed bookings exist", zap.String("series_id", id), zap.Int64("confirmed", n))
+			return ErrSeriesHasBookings
+		}
+	}
+
+	upcoming, err := s.repo.Occurrences(id, now)
+	if err != nil {
+		s.logger.Error("Failed to list series occurrences", zap.String("series_id", id), zap.Error(err))
+		return err
+	}
+	for i := range upcoming {
+		if err := s.events.Delete(ctx, upcoming[i].ID, force); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.Delete(id); err != nil {
+		s.logger.Error("Failed to delete series", zap.String("series_id", id), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Series deleted", zap.String("series_id", id), zap.Int("occurrences_archived", len(upcoming)))
+	return nil
+}
@@ -0,0 +1,245 @@
+package series_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/series"
+)
+
+func TestParseRule_Errors(t *testing.T) {
+	for _, rule := range []string{
+		"",
+		"FREQ=YEARLY;COUNT=2",
+		"FREQ=WEEKLY",                        // unbounded
+		"FREQ=WEEKLY;COUNT=2;UNTIL=20990101", // both bounds
+		"FREQ=WEEKLY;BYDAY=XX;COUNT=2",       // unknown day
+		"FREQ=MONTHLY;BYDAY=MO;COUNT=2",      // unsupported combination
+		"FREQ=DAILY;INTERVAL=0;COUNT=2",      // bad interval
+		"FREQ=DAILY;BYSETPOS=1;COUNT=2",      // unsupported part
+	} {
+		_, err := series.ParseRule(rule)
+		require.ErrorIs(t, err, series.ErrInvalidRule, rule)
+	}
+	_, err := series.ParseRule("FREQ=DAILY;COUNT=1000")
+	require.ErrorIs(t, err, series.ErrTooManyOccurrences)
+}
+
+func TestParseRule_RoundTrip(t *testing.T) {
+	r, err := series.ParseRule("RRULE:freq=weekly;byday=we,mo;interval=2;count=4")
+	require.NoError(t, err)
+	require.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4", r.String())
+}
+
+func TestOccurrences_WeeklyByDay(t *testing.T) {
+	r, err := series.ParseRule("FREQ=WEEKLY;BYDAY=MO,WE;COUNT=4")
+	require.NoError(t, err)
+
+	// Tuesday start: the Monday of the first week is before DTSTART and skipped
+	start := time.Date(2099, 6, 2, 19, 0, 0, 0, time.UTC)
+	got, err := r.Occurrences(start)
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{
+		time.Date(2099, 6, 3, 19, 0, 0, 0, time.UTC),
+		time.Date(2099, 6, 8, 19, 0, 0, 0, time.UTC),
+		time.Date(2099, 6, 10, 19, 0, 0, 0, time.UTC),
+		time.Date(2099, 6, 15, 19, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func TestOccurrences_KeepsWallClockAcrossDST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	r, err := series.ParseRule("FREQ=WEEKLY;COUNT=3")
+	require.NoError(t, err)
+
+	// US DST ends on 2025-11-02
+	got, err := r.Occurrences(time.Date(2025, 10, 24, 19, 0, 0, 0, ny))
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for _, t0 := range got {
+		assert.Equal(t, 19, t0.Hour())
+	}
+	assert.Equal(t, 7*24*time.Hour+time.Hour, got[2].Sub(got[1]))
+}
+
+func TestOccurrences_MonthlySkipsShortMonthsAndHonoursUntil(t *testing.T) {
+	r, err := series.ParseRule("FREQ=MONTHLY;UNTIL=20990531")
+	require.NoError(t, err)
+
+	got, err := r.Occurrences(time.Date(2099, 1, 31, 20, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []time.Time{
+		time.Date(2099, 1, 31, 20, 0, 0, 0, time.UTC),
+		time.Date(2099, 3, 31, 20, 0, 0, 0, time.UTC),
+		time.Date(2099, 5, 31, 20, 0, 0, 0, time.UTC),
+	}, got)
+}
+
+func newSeries() *series.Series {
+	return &series.Series{
+		ID:               "s1",
+		Name:             "Friday Jazz",
+		RRule:            "FREQ=WEEKLY;COUNT=3",
+		Timezone:         "Asia/Ho_Chi_Minh",
+		StartsAt:         time.Date(2099, 1, 2, 20, 0, 0, 0, time.FixedZone("ICT", 7*3600)),
+		DurationMinutes:  120,
+		Capacity:         100,
+		TicketPriceCents: 2500,
+	}
+}
+
+func TestCreate_GeneratesOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	events := mocks.NewMockEventService(ctrl)
+	svc := series.NewService(repo, events, zap.NewNop())
+
+	sr := newSeries()
+	repo.EXPECT().Create(sr).Return(nil)
+	var generated []*event.Event
+	events.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		generated = append(generated, e)
+		return nil
+	}).Times(3)
+
+	n, err := svc.Create(context.Background(), sr)
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Equal(t, "s1", *generated[0].SeriesID)
+	require.Equal(t, 100, generated[0].Remaining)
+	require.Equal(t, time.Date(2099, 1, 2, 13, 0, 0, 0, time.UTC), generated[0].StartsAt)
+	require.Equal(t, 2*time.Hour, generated[0].EndsAt.Sub(generated[0].StartsAt))
+	require.Equal(t, 7*24*time.Hour, generated[1].StartsAt.Sub(generated[0].StartsAt))
+}
+
+func TestCreate_RollsBackOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	events := mocks.NewMockEventService(ctrl)
+	svc := series.NewService(repo, events, zap.NewNop())
+
+	sr := newSeries()
+	repo.EXPECT().Create(sr).Return(nil)
+	gomock.InOrder(
+		events.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+			e.ID = "e1"
+			return nil
+		}),
+		events.EXPECT().Create(gomock.Any(), gomock.Any()).Return(event.ErrUnknownVenue),
+	)
+	events.EXPECT().Delete(gomock.Any(), "e1", true).Return(nil)
+	repo.EXPECT().Delete("s1").Return(nil)
+
+	_, err := svc.Create(context.Background(), sr)
+	require.ErrorIs(t, err, event.ErrUnknownVenue)
+}
+
+func TestUpdate_SyncsUpcomingOccurrences(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	events := mocks.NewMockEventService(ctrl)
+	svc := series.NewService(repo, events, zap.NewNop())
+
+	sr := newSeries()
+	schedule, err := sr.Schedule()
+	require.NoError(t, err)
+	sid := sr.ID
+
+	// Rule shrinks to two dates and moves price; the third date is dropped
+	sr.RRule = "FREQ=WEEKLY;COUNT=2"
+	sr.TicketPriceCents = 3000
+	existing := []event.Event{
+		{ID: "e1", StartsAt: schedule[0], Capacity: 100, Remaining: 90, SeriesID: &sid},
+		{ID: "e2", StartsAt: schedule[1], Capacity: 100, Remaining: 100, SeriesID: &sid, SeriesOverride: true},
+		{ID: "e3", StartsAt: schedule[2], Capacity: 100, Remaining: 100, SeriesID: &sid},
+	}
+	repo.EXPECT().Occurrences("s1", gomock.Any()).Return(existing, nil)
+	repo.EXPECT().Update(sr).Return(nil)
+	events.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		require.Equal(t, "e1", e.ID)
+		require.Equal(t, int64(3000), e.TicketPriceCents)
+		require.Equal(t, 90, e.Remaining)
+		return nil
+	})
+	events.EXPECT().Delete(gomock.Any(), "e3", false).Return(nil)
+
+	res, err := svc.Update(context.Background(), sr)
+	require.NoError(t, err)
+	require.Equal(t, series.SyncResult{Updated: 1, Removed: 1, Skipped: 1}, res)
+}
+
+func TestUpdate_CapacityBelowSold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	svc := series.NewService(repo, mocks.NewMockEventService(ctrl), zap.NewNop())
+
+	sr := newSeries()
+	schedule, err := sr.Schedule()
+	require.NoError(t, err)
+	sr.Capacity = 5
+	repo.EXPECT().Occurrences("s1", gomock.Any()).Return([]event.Event{
+		{ID: "e1", StartsAt: schedule[0], Capacity: 100, Remaining: 90},
+	}, nil)
+
+	_, err = svc.Update(context.Background(), sr)
+	require.ErrorIs(t, err, series.ErrCapacityBelowSold)
+}
+
+func TestUpdate_CapacityLeavesRemainingToTheEventRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	events := mocks.NewMockEventService(ctrl)
+	svc := series.NewService(repo, events, zap.NewNop())
+
+	sr := newSeries()
+	schedule, err := sr.Schedule()
+	require.NoError(t, err)
+	sid := sr.ID
+	sr.RRule = "FREQ=WEEKLY;COUNT=1"
+	sr.Capacity = 20
+	repo.EXPECT().Occurrences("s1", gomock.Any()).Return([]event.Event{
+		{ID: "e1", StartsAt: schedule[0], Capacity: 100, Remaining: 90, SeriesID: &sid},
+	}, nil)
+	repo.EXPECT().Update(sr).Return(nil)
+	// Seats sold after the occurrence was read leave too few for the new capacity
+	events.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *event.Event) error {
+		require.Equal(t, 20, e.Capacity)
+		require.Equal(t, 90, e.Remaining, "the stored count is shifted in SQL, not from this copy")
+		return event.ErrCapacityBelowSold
+	})
+
+	_, err = svc.Update(context.Background(), sr)
+	require.ErrorIs(t, err, series.ErrCapacityBelowSold)
+}
+
+func TestDelete_RefusesWithConfirmedBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockSeriesRepository(ctrl)
+	svc := series.NewService(repo, mocks.NewMockEventService(ctrl), zap.NewNop())
+
+	repo.EXPECT().Get("s1").Return(newSeries(), nil)
+	repo.EXPECT().CountConfirmedBookings("s1", gomock.Any()).Return(int64(3), nil)
+
+	require.ErrorIs(t, svc.Delete(context.Background(), "s1", false), series.ErrSeriesHasBookings)
+}
@@ -0,0 +1,330 @@
+package ticket
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+)
+
+// ScanResult says what a scan did to a ticket
+type ScanResult string
+
+const (
+	// ScanAdmitted means the ticket was VALID and is now USED
+	ScanAdmitted ScanResult = "ADMITTED"
+	// ScanAlreadyUsed means the ticket had been checked in before
+	ScanAlreadyUsed ScanResult = "ALREADY_USED"
+	// ScanVoid means the ticket's booking was cancelled, refunded or moved
+	ScanVoid ScanResult = "VOID"
+	// ScanWrongEvent means the ticket is for another event than the gate's
+	ScanWrongEvent ScanResult = "WRONG_EVENT"
+	// ScanInvalid means the payload is malformed or its signature is wrong
+	ScanInvalid ScanResult = "INVALID"
+	// ScanUnknown means the payload is signed but no such ticket exists
+	ScanUnknown ScanResult = "UNKNOWN"
+)
+
+// Scan is one ticket presented at a gate
+type Scan struct {
+	Payload string    // QR payload as read by the scanner
+	EventID string    // Event the gate admits to; empty accepts any event
+	Gate    string    // Free-form gate or device label
+	At      time.Time // When the ticket was presented
+	By      string    // Scanning staff member
+}
+
+// ScanOutcome is the result of a scan and the ticket as it stands afterwards;
+// Ticket is nil for INVALID and UNKNOWN
+type ScanOutcome struct {
+	Result ScanResult
+	Ticket *Ticket
+}
+
+// CheckIn verifies a scanned payload and marks its ticket USED. Concurrent
+// scans of one ticket admit it once. The first check-in stands: any later
+// scan, including one dated earlier and uploaded late by an offline scanner,
+// gets ALREADY_USED. Every scan goes to the scan log.
+func (s *Service) CheckIn(ctx context.Context, scan Scan) (*ScanOutcome, error) {
+	eventID, code, err := s.signer.Verify(scan.Payload)
+	if err != nil {
+		return s.logScan(scan, "", "", &ScanOutcome{Result: ScanInvalid}), nil
+	}
+	if scan.EventID != "" && eventID != scan.EventID {
+		return s.logScan(scan, code, eventID, &ScanOutcome{Result: ScanWrongEvent}), nil
+	}
+	ok, err := s.repo.CheckIn(code, eventID, scan.At, scan.Gate, scan.By)
+	if err != nil {
+		return nil, err
+	}
+	t, err := s.repo.GetByCode(code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return s.logScan(scan, code, eventID, &ScanOutcome{Result: ScanUnknown}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := &ScanOutcome{Ticket: t}
+	switch {
+	case ok:
+		out.Result = ScanAdmitted
+	case t.Status == StatusUsed:
+		out.Result = ScanAlreadyUsed
+	case t.Status == StatusVoid:
+		out.Result = ScanVoid
+	default:
+		out.Result = ScanWrongEvent
+	}
+	return s.logScan(scan, code, eventID, out), nil
+}
+
+// logScan records scan and its outcome in the scan log and returns the
+// outcome. A failed write is logged; the scan's result stands regardless.
+func (s *Service) logScan(scan Scan, code, eventID string, out *ScanOutcome) *ScanOutcome {
+	rec := &ScanRecord{Code: code, EventID: eventID, Result: out.Result, Gate: scan.Gate, ScannedAt: scan.At}
+	if scan.By != "" {
+		rec.ScannedBy = &scan.By
+	}
+	if err := s.repo.LogScan(rec); err != nil {
+		s.logger.Error("Failed to log scan", zap.String("code", code), zap.String("result", string(out.Result)), zap.Error(err))
+	}
+	return out
+}
+
+// Sync applies scans an offline scanner recorded for eventID, oldest first,
+// and returns their outcomes in the order given
+func (s *Service) Sync(ctx context.Context, eventID, by string, scans []Scan) ([]*ScanOutcome, error) {
+	order := make([]int, len(scans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return scans[order[a]].At.Before(scans[order[b]].At) })
+
+	out := make([]*ScanOutcome, len(scans))
+	for _, i := range order {
+		scan := scans[i]
+		scan.EventID, scan.By = eventID, by
+		res, err := s.CheckIn(ctx, scan)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = res
+	}
+	return out, nil
+}
+
+// Manifest lists every ticket of an event for offline scanners
+type Manifest struct {
+	EventID     string          `json:"event_id"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Tickets     []ManifestEntry `json:"tickets"`
+}
+
+// ManifestEntry is a ticket as an offline scanner sees it. Void tickets are
+// listed so they can be told apart from unknown codes.
+type ManifestEntry struct {
+	Code        string     `json:"code"`
+	BookingID   string     `json:"booking_id"`
+	Number      int        `json:"number"`
+	Status      Status     `json:"status"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	Gate        string     `json:"gate,omitempty"`
+}
+
+// Manifest returns the JSON manifest of an event and its signature
+func (s *Service) Manifest(ctx context.Context, eventID string) (body []byte, signature string, err error) {
+	tickets, err := s.repo.ListByEvent(eventID)
+	if err != nil {
+		return nil, "", err
+	}
+	m := Manifest{EventID: eventID, GeneratedAt: time.Now().UTC(), Tickets: make([]ManifestEntry, 0, len(tickets))}
+	for _, t := range tickets {
+		m.Tickets = append(m.Tickets, ManifestEntry{
+			Code: t.Code, BookingID: t.BookingID, Number: t.Number,
+			Status: t.Status, CheckedInAt: t.CheckedInAt, Gate: t.Gate,
+		})
+	}
+	body, err = json.Marshal(m)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, s.signer.SignManifest(body), nil
+}
+
+// --- HTTP ---
+
+// maxSyncScans caps one offline upload
+const maxSyncScans = 1000
+
+// clockSkew is how far ahead of the server an offline scanner's clock may run
+const clockSkew = time.Minute
+
+// scanStatus maps each result to the status code scanners branch on
+var scanStatus = map[ScanResult]int{
+	ScanAdmitted:    http.StatusOK,
+	ScanAlreadyUsed: http.StatusConflict,
+	ScanVoid:        http.StatusConflict,
+	ScanWrongEvent:  http.StatusConflict,
+	ScanInvalid:     http.StatusUnprocessableEntity,
+	ScanUnknown:     http.StatusUnprocessableEntity,
+}
+
+// validEvent rejects an :id that is not a UUID
+func validEvent(c *gin.Context) bool {
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid event id"})
+		return false
+	}
+	return true
+}
+
+func scanResponse(o *ScanOutcome) ScanResponse {
+	resp := ScanResponse{Result: o.Result}
+	if t := o.Ticket; t != nil {
+		resp.TicketID, resp.BookingID, resp.EventID = t.ID, t.BookingID, t.EventID
+		resp.Code, resp.Number = t.Code, t.Number
+		resp.CheckedInAt, resp.Gate = t.CheckedInAt, t.Gate
+	}
+	return resp
+}
+
+// Scan godoc
+// @Summary Check a ticket in
+// @Description Verify a scanned QR payload and mark its ticket used. 200 admits; 409 means already used, void or for another event (the body says which and, for a used ticket, when and where it was checked in); 422 means the code is forged or unknown (requires tickets:scan)
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Param request body ScanRequest true "Scanned payload"
+// @Success 200 {object} ScanResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ScanResponse
+// @Failure 422 {object} ScanResponse
+// @Security BearerAuth
+// @Router /checkin/scan [post]
+func (h *Handler) Scan(c *gin.Context) {
+	var req ScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	out, err := h.svc.CheckIn(c, Scan{
+		Payload: req.Payload, EventID: req.EventID, Gate: req.Gate,
+		At: time.Now(), By: c.GetString(auth.CtxUserID),
+	})
+	if err != nil {
+		h.logger.Error("Check-in failed", zap.String("gate", req.Gate), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	if out.Result != ScanAdmitted {
+		h.logger.Warn("Ticket rejected at gate", zap.String("result", string(out.Result)), zap.String("gate", req.Gate))
+	}
+	c.JSON(scanStatus[out.Result], scanResponse(out))
+}
+
+// Manifest godoc
+// @Summary Download scanner manifest
+// @Description Every ticket of an event with its status, for scanners working offline. The X-Manifest-Signature header holds the base64url Ed25519 signature of the exact response body; verify it with the key from /checkin/manifest-key (requires tickets:scan)
+// @Tags checkin
+// @Produce json
+// @Param id path string true "Event ID"
+// @Success 200 {object} Manifest
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /checkin/events/{id}/manifest [get]
+func (h *Handler) Manifest(c *gin.Context) {
+	if !validEvent(c) {
+		return
+	}
+	body, sig, err := h.svc.Manifest(c, c.Param("id"))
+	if err != nil {
+		h.logger.Error("Failed to build manifest", zap.String("event_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.Header("X-Manifest-Signature", sig)
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/json", body)
+}
+
+// ManifestKey godoc
+// @Summary Manifest signing key
+// @Description The Ed25519 public key manifests and QR payloads are signed with, base64url encoded, for scanners to pin (requires tickets:scan)
+// @Tags checkin
+// @Produce json
+// @Success 200 {object} ManifestKeyResponse
+// @Security BearerAuth
+// @Router /checkin/manifest-key [get]
+func (h *Handler) ManifestKey(c *gin.Context) {
+	c.JSON(http.StatusOK, ManifestKeyResponse{
+		Algorithm: "Ed25519",
+		PublicKey: base64.RawURLEncoding.EncodeToString(h.svc.signer.ManifestKey()),
+	})
+}
+
+// Sync godoc
+// @Summary Upload offline scans
+// @Description Apply scans an offline scanner recorded for an event. Scans are applied oldest first. A ticket's first check-in stands, online or offline: any other scan of it, even one dated earlier, comes back ALREADY_USED with that check-in. Every scan is kept in the scan log. Results follow the order of the request (requires tickets:scan)
+// @Tags checkin
+// @Accept json
+// @Produce json
+// @Param id path string true "Event ID"
+// @Param request body SyncRequest true "Recorded scans"
+// @Success 200 {object} SyncResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /checkin/events/{id}/sync [post]
+func (h *Handler) Sync(c *gin.Context) {
+	if !validEvent(c) {
+		return
+	}
+	var req SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if len(req.Scans) > maxSyncScans {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "too many scans, upload at most 1000 at a time"})
+		return
+	}
+	latest := time.Now().Add(clockSkew)
+	scans := make([]Scan, len(req.Scans))
+	for i, s := range req.Scans {
+		if s.ScannedAt.After(latest) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "scanned_at is in the future"})
+			return
+		}
+		// A scanner clock slightly ahead must not outrank scans made since
+		at := s.ScannedAt
+		if at.After(time.Now()) {
+			at = time.Now()
+		}
+		scans[i] = Scan{Payload: s.Payload, Gate: s.Gate, At: at}
+	}
+	eventID := c.Param("id")
+	outcomes, err := h.svc.Sync(c, eventID, c.GetString(auth.CtxUserID), scans)
+	if err != nil {
+		h.logger.Error("Offline sync failed", zap.String("event_id", eventID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	resp := SyncResponse{Results: make([]ScanResponse, len(outcomes))}
+	for i, o := range outcomes {
+		resp.Results[i] = scanResponse(o)
+		if o.Result == ScanAdmitted {
+			resp.Admitted++
+		}
+	}
+	h.logger.Info("Offline scans synced", zap.String("event_id", eventID), zap.Int("scans", len(scans)), zap.Int("admitted", resp.Admitted))
+	c.JSON(http.StatusOK, resp)
+}
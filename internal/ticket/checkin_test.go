@@ -0,0 +1,156 @@
+package ticket_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/ticket"
+)
+
+const (
+	eventA = "6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"
+	eventB = "0a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+)
+
+// issued returns a service holding two tickets for eventA and one void ticket
+func issued(t *testing.T) (*ticket.Service, *memTickets, *ticket.Signer) {
+	t.Helper()
+	repo := &memTickets{}
+	signer := ticket.NewSigner("0123456789abcdef")
+	svc := ticket.NewService(repo, signer, zap.NewNop())
+	require.NoError(t, svc.Issue(context.Background(), &booking.Booking{ID: "b1", UserID: "u1", EventID: eventA, Quantity: 2}))
+	require.NoError(t, svc.Issue(context.Background(), &booking.Booking{ID: "b2", UserID: "u2", EventID: eventA, Quantity: 1}))
+	require.NoError(t, svc.Void(context.Background(), "b2"))
+	return svc, repo, signer
+}
+
+func TestCheckIn(t *testing.T) {
+	svc, repo, signer := issued(t)
+	ctx := context.Background()
+	now := time.Now()
+	scan := func(tk *ticket.Ticket, eventID string) ticket.ScanResult {
+		out, err := svc.CheckIn(ctx, ticket.Scan{Payload: signer.Sign(tk), EventID: eventID, Gate: "north-1", At: now, By: "s1"})
+		require.NoError(t, err)
+		return out.Result
+	}
+
+	require.Equal(t, ticket.ScanAdmitted, scan(repo.tickets[0], eventA))
+	require.Equal(t, ticket.StatusUsed, repo.tickets[0].Status)
+	require.Equal(t, "north-1", repo.tickets[0].Gate)
+	require.Equal(t, ticket.ScanAlreadyUsed, scan(repo.tickets[0], eventA), "no double entry")
+	require.Equal(t, ticket.ScanVoid, scan(repo.tickets[2], ""))
+	require.Equal(t, ticket.ScanWrongEvent, scan(repo.tickets[1], eventB))
+	require.Equal(t, ticket.ScanUnknown, scan(&ticket.Ticket{EventID: eventA, Code: "0000000000"}, eventA))
+
+	out, err := svc.CheckIn(ctx, ticket.Scan{Payload: ticket.NewSigner("forged-secret-123").Sign(repo.tickets[1]), At: now})
+	require.NoError(t, err)
+	require.Equal(t, ticket.ScanInvalid, out.Result)
+	require.Equal(t, ticket.StatusValid, repo.tickets[1].Status)
+}
+
+func TestSync_FirstCheckInStands(t *testing.T) {
+	svc, repo, signer := issued(t)
+	ctx := context.Background()
+	t0 := time.Now().Add(-time.Hour)
+
+	// Scanned online at the north gate...
+	_, err := svc.CheckIn(ctx, ticket.Scan{Payload: signer.Sign(repo.tickets[0]), Gate: "north", At: t0.Add(30 * time.Minute)})
+	require.NoError(t, err)
+
+	// ...then offline scanners at the south gate upload scans dated earlier,
+	// as a copied code would be
+	out, err := svc.Sync(ctx, eventA, "s2", []ticket.Scan{
+		{Payload: signer.Sign(repo.tickets[0]), Gate: "south-2", At: t0.Add(20 * time.Minute)},
+		{Payload: signer.Sign(repo.tickets[0]), Gate: "south-1", At: t0.Add(10 * time.Minute)},
+		{Payload: signer.Sign(repo.tickets[1]), Gate: "south-1", At: t0},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ticket.ScanAlreadyUsed, out[0].Result)
+	require.Equal(t, ticket.ScanAlreadyUsed, out[1].Result)
+	require.Equal(t, "north", out[1].Ticket.Gate, "results show the check-in that stands")
+	require.Equal(t, ticket.ScanAdmitted, out[2].Result)
+	require.True(t, repo.tickets[0].CheckedInAt.Equal(t0.Add(30*time.Minute)))
+
+	// Every scan is logged, oldest first as applied
+	require.Len(t, repo.scans, 4)
+	require.Equal(t, ticket.ScanAdmitted, repo.scans[0].Result)
+	require.Equal(t, []string{"south-1", "south-1", "south-2"}, []string{repo.scans[1].Gate, repo.scans[2].Gate, repo.scans[3].Gate})
+	require.Equal(t, ticket.ScanAlreadyUsed, repo.scans[2].Result)
+	require.Equal(t, "s2", *repo.scans[3].ScannedBy)
+}
+
+func TestScannerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo, signer := issued(t)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set(auth.CtxUserID, "s1") })
+	ticket.RegisterScannerRoutes(r.Group("/checkin"), ticket.NewHandler(svc, bookingMap{}, zap.NewNop()))
+	send := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, &buf))
+		return w
+	}
+
+	scan := ticket.ScanRequest{Payload: signer.Sign(repo.tickets[0]), EventID: eventA, Gate: "north-1"}
+	w := send(http.MethodPost, "/checkin/scan", scan)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = send(http.MethodPost, "/checkin/scan", scan)
+	require.Equal(t, http.StatusConflict, w.Code)
+	var resp ticket.ScanResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, ticket.ScanAlreadyUsed, resp.Result)
+	require.Equal(t, "north-1", resp.Gate)
+	require.Equal(t, http.StatusUnprocessableEntity, send(http.MethodPost, "/checkin/scan", ticket.ScanRequest{Payload: "TB1.x.y.z"}).Code)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodPost, "/checkin/scan", ticket.ScanRequest{}).Code)
+
+	// The manifest verifies against the published key
+	w = send(http.MethodGet, "/checkin/manifest-key", nil)
+	var key ticket.ManifestKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
+	pub, err := base64.RawURLEncoding.DecodeString(key.PublicKey)
+	require.NoError(t, err)
+
+	w = send(http.MethodGet, "/checkin/events/"+eventA+"/manifest", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	sig, err := base64.RawURLEncoding.DecodeString(w.Header().Get("X-Manifest-Signature"))
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(pub, w.Body.Bytes(), sig))
+	var m ticket.Manifest
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &m))
+	require.Len(t, m.Tickets, 3)
+	require.Equal(t, ticket.StatusUsed, m.Tickets[0].Status)
+	require.Equal(t, ticket.StatusVoid, m.Tickets[2].Status)
+	require.Equal(t, http.StatusBadRequest, send(http.MethodGet, "/checkin/events/nope/manifest", nil).Code)
+
+	w = send(http.MethodPost, "/checkin/events/"+eventA+"/sync", ticket.SyncRequest{Scans: []ticket.OfflineScan{
+		{Payload: signer.Sign(repo.tickets[1]), Gate: "south-1", ScannedAt: time.Now().Add(-time.Minute)},
+		{Payload: signer.Sign(repo.tickets[2]), Gate: "south-1", ScannedAt: time.Now().Add(-time.Minute)},
+	}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var synced ticket.SyncResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &synced))
+	require.Equal(t, 1, synced.Admitted)
+	require.Equal(t, ticket.ScanVoid, synced.Results[1].Result)
+
+	w = send(http.MethodPost, "/checkin/events/"+eventA+"/sync", ticket.SyncRequest{Scans: []ticket.OfflineScan{
+		{Payload: signer.Sign(repo.tickets[1]), ScannedAt: time.Now().Add(time.Hour)},
+	}})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
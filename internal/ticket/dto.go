@@ -0,0 +1,84 @@
+package ticket
+
+import "time"
+
+// TicketResponse is a ticket as shown to its holder. Only VALID tickets carry a
+// QR code, and only for the holder or staff; others do not see the code either.
+type TicketResponse struct {
+	ID        string     `json:"id"`
+	BookingID string     `json:"booking_id"`
+	EventID   string     `json:"event_id"`
+	HolderID  string     `json:"holder_id"`
+	Number    int        `json:"number" example:"1"`
+	Code      string     `json:"code" example:"7K3QX9M2TD"`
+	Status    Status     `json:"status" example:"VALID"`
+	Payload   string     `json:"payload,omitempty" example:"TB2.<event_id>.7K3QX9M2TD.<signature>"`
+	QRCode    string     `json:"qr_code,omitempty" example:"data:image/png;base64,iVBORw0KGgo..."`
+	CreatedAt time.Time  `json:"created_at"`
+	VoidedAt  *time.Time `json:"voided_at,omitempty"`
+
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	Gate        string     `json:"gate,omitempty"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+// ScanRequest is a ticket scanned at a gate
+type ScanRequest struct {
+	Payload string `json:"payload" binding:"required" example:"TB2.<event_id>.7K3QX9M2TD.<signature>"`
+	EventID string `json:"event_id,omitempty" binding:"omitempty,uuid"` // Reject tickets for other events
+	Gate    string `json:"gate,omitempty" binding:"max=64" example:"north-1"`
+}
+
+// ScanResponse says whether to admit; for a used ticket it shows the check-in that stands
+type ScanResponse struct {
+	Result      ScanResult `json:"result" example:"ADMITTED"`
+	TicketID    string     `json:"ticket_id,omitempty"`
+	BookingID   string     `json:"booking_id,omitempty"`
+	EventID     string     `json:"event_id,omitempty"`
+	Code        string     `json:"code,omitempty" example:"7K3QX9M2TD"`
+	Number      int        `json:"number,omitempty" example:"1"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	Gate        string     `json:"gate,omitempty" example:"north-1"`
+}
+
+// OfflineScan is a scan recorded while the scanner was offline
+type OfflineScan struct {
+	Payload   string    `json:"payload" binding:"required"`
+	Gate      string    `json:"gate,omitempty" binding:"max=64" example:"north-1"`
+	ScannedAt time.Time `json:"scanned_at" binding:"required"`
+}
+
+// SyncRequest uploads offline scans for one event
+type SyncRequest struct {
+	Scans []OfflineScan `json:"scans" binding:"required,dive"`
+}
+
+// SyncResponse has one result per uploaded scan, in upload order
+type SyncResponse struct {
+	Admitted int            `json:"admitted" example:"42"`
+	Results  []ScanResponse `json:"results"`
+}
+
+// ManifestKeyResponse is the public key manifests are signed with
+type ManifestKeyResponse struct {
+	Algorithm string `json:"alg" example:"Ed25519"`
+	PublicKey string `json:"public_key"` // base64url, 32 bytes
+}
+
+// TransferRequest names what to transfer: every ticket the caller holds for a
+// CONFIRMED booking of theirs, or individual tickets
+type TransferRequest struct {
+	BookingID string   `json:"booking_id,omitempty" binding:"omitempty,uuid"`
+	TicketIDs []string `json:"ticket_ids,omitempty" binding:"omitempty,max=50,dive,uuid"`
+	Email     string   `json:"email" binding:"required,email" example:"friend@example.com"`
+}
+
+// TransferListResponse splits a user's transfers by direction, newest first
+type TransferListResponse struct {
+	Incoming []*Transfer `json:"incoming"`
+	Outgoing []*Transfer `json:"outgoing"`
+}
@@ -0,0 +1,214 @@
+package ticket
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+)
+
+// Bookings looks up the booking tickets belong to
+type Bookings interface {
+	Get(ctx context.Context, id string) (*booking.Booking, error)
+}
+
+type Handler struct {
+	svc      *Service
+	bookings Bookings
+	logger   *zap.Logger
+}
+
+func NewHandler(s *Service, b Bookings, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, bookings: b, logger: logger}
+}
+
+// booking loads the booking in :id, answering 404 when it does not exist or
+// belongs to someone else and the caller lacks bookings:read
+func (h *Handler) booking(c *gin.Context) (*booking.Booking, bool) {
+	userID := c.GetString(auth.CtxUserID)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return nil, false
+	}
+	b, err := h.bookings.Get(c, c.Param("id"))
+	if err != nil || !booking.CanView(b, userID, c.GetStringSlice(auth.CtxPermissions)) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return nil, false
+	}
+	return b, true
+}
+
+// format reads the QR image format, png unless asked otherwise
+func format(c *gin.Context) (string, bool) {
+	f := c.DefaultQuery("format", FormatPNG)
+	if f != FormatPNG && f != FormatSVG {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "format must be png or svg"})
+		return "", false
+	}
+	return f, true
+}
+
+// List godoc
+// @Summary List booking tickets
+// @Description One ticket per seat of the booking, each valid ticket with its signed QR payload and code as a data URI (booking owner, or bookings:read). Seats transferred to someone else show no code.
+// @Tags bookings
+// @Produce json
+// @Param id path string true "Booking ID"
+// @Param format query string false "QR image format: png (default) or svg"
+// @Success 200 {array} TicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /bookings/{id}/tickets [get]
+func (h *Handler) List(c *gin.Context) {
+	f, ok := format(c)
+	if !ok {
+		return
+	}
+	b, ok := h.booking(c)
+	if !ok {
+		return
+	}
+	tickets, err := h.svc.ForBooking(c, b)
+	if err != nil {
+		h.logger.Error("Failed to list tickets", zap.String("booking_id", b.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	userID := c.GetString(auth.CtxUserID)
+	staff := auth.HasPermission(c.GetStringSlice(auth.CtxPermissions), auth.PermBookingsRead)
+	out := make([]TicketResponse, 0, len(tickets))
+	for _, t := range tickets {
+		// Seats transferred to someone else are listed without their QR code
+		resp, err := h.response(t, f, staff || t.HolderID == userID)
+		if err != nil {
+			h.logger.Error("Failed to render QR code", zap.String("ticket_id", t.ID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+			return
+		}
+		out = append(out, resp)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// response renders t, with its payload and QR code when it is VALID and withQR is set
+func (h *Handler) response(t *Ticket, f string, withQR bool) (TicketResponse, error) {
+	resp := TicketResponse{
+		ID: t.ID, BookingID: t.BookingID, EventID: t.EventID, Number: t.Number, Code: t.Code,
+		Status: t.Status, CreatedAt: t.CreatedAt, VoidedAt: t.VoidedAt,
+		CheckedInAt: t.CheckedInAt, Gate: t.Gate, HolderID: t.HolderID,
+	}
+	if !withQR {
+		resp.Code = ""
+		return resp, nil
+	}
+	if t.Status == StatusValid {
+		resp.Payload = h.svc.Payload(t)
+		img, contentType, err := RenderQR(resp.Payload, f)
+		if err != nil {
+			return resp, err
+		}
+		resp.QRCode = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(img)
+	}
+	return resp, nil
+}
+
+// Held godoc
+// @Summary List my tickets
+// @Description Valid and used tickets the caller holds, bought or received by transfer, newest first, each valid ticket with its QR code
+// @Tags tickets
+// @Produce json
+// @Param format query string false "QR image format: png (default) or svg"
+// @Success 200 {array} TicketResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /tickets [get]
+func (h *Handler) Held(c *gin.Context) {
+	f, ok := format(c)
+	if !ok {
+		return
+	}
+	userID := c.GetString(auth.CtxUserID)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+		return
+	}
+	tickets, err := h.svc.Held(c, userID)
+	if err != nil {
+		h.logger.Error("Failed to list held tickets", zap.String("user_id", userID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]TicketResponse, 0, len(tickets))
+	for _, t := range tickets {
+		resp, err := h.response(t, f, true)
+		if err != nil {
+			h.logger.Error("Failed to render QR code", zap.String("ticket_id", t.ID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+			return
+		}
+		out = append(out, resp)
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// QR godoc
+// @Summary Ticket QR code
+// @Description The QR code of a valid ticket as an image (booking owner, or bookings:read)
+// @Tags bookings
+// @Produce image/png
+// @Produce image/svg+xml
+// @Param id path string true "Booking ID"
+// @Param ticket_id path string true "Ticket ID"
+// @Param format query string false "png (default) or svg"
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 410 {object} ErrorResponse "Ticket used or voided"
+// @Security BearerAuth
+// @Router /bookings/{id}/tickets/{ticket_id}/qr [get]
+func (h *Handler) QR(c *gin.Context) {
+	f, ok := format(c)
+	if !ok {
+		return
+	}
+	b, ok := h.booking(c)
+	if !ok {
+		return
+	}
+	tickets, err := h.svc.ForBooking(c, b)
+	if err != nil {
+		h.logger.Error("Failed to list tickets", zap.String("booking_id", b.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	for _, t := range tickets {
+		if t.ID != c.Param("ticket_id") {
+			continue
+		}
+		// Only the holder, or staff, gets the code of a transferred seat
+		if t.HolderID != c.GetString(auth.CtxUserID) && !auth.HasPermission(c.GetStringSlice(auth.CtxPermissions), auth.PermBookingsRead) {
+			break
+		}
+		if t.Status != StatusValid {
+			c.JSON(http.StatusGone, ErrorResponse{Error: "ticket " + strings.ToLower(string(t.Status))})
+			return
+		}
+		img, contentType, err := RenderQR(h.svc.Payload(t), f)
+		if err != nil {
+			h.logger.Error("Failed to render QR code", zap.String("ticket_id", t.ID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Data(http.StatusOK, contentType, img)
+		return
+	}
+	c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+}
@@ -0,0 +1,90 @@
+// Package ticket issues the per-seat tickets of confirmed bookings, each with
+// a unique code and a signed QR payload to present at the door.
+package ticket
+
+import "time"
+
+// Status represents the lifecycle of a ticket
+type Status string
+
+const (
+	// StatusValid tickets admit their holder
+	StatusValid Status = "VALID"
+	// StatusUsed tickets have been checked in at the door
+	StatusUsed Status = "USED"
+	// StatusVoid tickets belong to a cancelled, refunded or moved booking, or
+	// were replaced by new codes when transferred
+	StatusVoid Status = "VOID"
+)
+
+// Ticket is one seat of a confirmed booking
+type Ticket struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	BookingID string     `gorm:"type:uuid;not null" json:"booking_id"`
+	EventID   string     `gorm:"type:uuid;not null" json:"event_id"`
+	HolderID  string     `gorm:"type:uuid;not null" json:"holder_id"`   // User the ticket belongs to
+	Number    int        `gorm:"not null" json:"number"`                // 1..quantity within the booking
+	Code      string     `gorm:"type:text;not null;unique" json:"code"` // Short unique code, also printed under the QR
+	Status    Status     `gorm:"type:text;not null" json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	VoidedAt  *time.Time `json:"voided_at,omitempty"`
+
+	// Check-in, set once the ticket is USED
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+	Gate        string     `gorm:"type:text" json:"gate,omitempty"`
+	CheckedInBy *string    `gorm:"type:uuid" json:"checked_in_by,omitempty"` // Scanning staff member
+
+	// Transfer offering this ticket while PENDING; kept once ACCEPTED as the
+	// transfer that replaced it
+	TransferID *string `gorm:"type:uuid" json:"transfer_id,omitempty"`
+}
+
+// ScanRecord logs one scan at a gate, whatever its result
+type ScanRecord struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Code      string     `gorm:"type:text;not null" json:"code"`     // Empty when the payload did not verify
+	EventID   string     `gorm:"type:text;not null" json:"event_id"` // Event the payload names
+	Result    ScanResult `gorm:"type:text;not null" json:"result"`
+	Gate      string     `gorm:"type:text;not null" json:"gate"`
+	ScannedAt time.Time  `gorm:"not null" json:"scanned_at"` // When the ticket was presented
+	ScannedBy *string    `gorm:"type:uuid" json:"scanned_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"` // When the scan reached the server
+}
+
+// TableName keeps the scan log next to tickets
+func (ScanRecord) TableName() string { return "ticket_scans" }
+
+// TransferStatus represents the lifecycle of a ticket transfer
+type TransferStatus string
+
+const (
+	// TransferPending transfers wait for the recipient
+	TransferPending TransferStatus = "PENDING"
+	// TransferAccepted transfers moved the tickets to the recipient
+	TransferAccepted TransferStatus = "ACCEPTED"
+	// TransferDeclined transfers were turned down by the recipient
+	TransferDeclined TransferStatus = "DECLINED"
+	// TransferCancelled transfers were withdrawn by the sender, or their
+	// tickets were voided before acceptance
+	TransferCancelled TransferStatus = "CANCELLED"
+	// TransferExpired transfers were not accepted in time
+	TransferExpired TransferStatus = "EXPIRED"
+)
+
+// Transfer offers some of a holder's tickets to another person by email
+type Transfer struct {
+	ID         string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	EventID    string         `gorm:"type:uuid;not null" json:"event_id"`
+	FromUserID string         `gorm:"type:uuid;not null" json:"from_user_id"`
+	FromEmail  string         `gorm:"type:text;not null" json:"from_email"` // Sender's address when the offer was made
+	ToEmail    string         `gorm:"type:text;not null" json:"to_email"`   // Lower-cased
+	ToUserID   *string        `gorm:"type:uuid" json:"to_user_id,omitempty"`
+	Count      int            `gorm:"not null" json:"count"` // Tickets offered
+	Status     TransferStatus `gorm:"type:text;not null" json:"status"`
+	ExpiresAt  time.Time      `json:"expires_at"`
+	CreatedAt  time.Time      `json:"created_at"`
+	ResolvedAt *time.Time     `json:"resolved_at,omitempty"`
+}
+
+// TableName keeps transfers next to tickets
+func (Transfer) TableName() string { return "ticket_transfers" }
@@ -0,0 +1,82 @@
+package ticket
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Image formats a QR code can be rendered in
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// qrSize is the edge of PNG QR codes in pixels
+const qrSize = 256
+
+// quietZone is the white margin around SVG QR codes, in modules
+const quietZone = 4
+
+// RenderQR draws payload as a QR code and returns the image and its content type
+func RenderQR(payload, format string) ([]byte, string, error) {
+	code, err := qr.Encode(payload, qr.M, qr.Auto)
+	if err != nil {
+		return nil, "", err
+	}
+	switch format {
+	case FormatSVG:
+		return svg(code), "image/svg+xml", nil
+	case FormatPNG:
+		scaled, err := barcode.Scale(code, qrSize, qrSize)
+		if err != nil {
+			return nil, "", err
+		}
+		// 8-bit gray keeps the file small and readable by PDF writers
+		gray := image.NewGray(scaled.Bounds())
+		draw.Draw(gray, gray.Bounds(), scaled, scaled.Bounds().Min, draw.Src)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, gray); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/png", nil
+	default:
+		return nil, "", fmt.Errorf("unknown QR format %q", format)
+	}
+}
+
+// svg draws each horizontal run of dark modules as one rectangle, so the
+// image scales without blurring
+func svg(code barcode.Barcode) []byte {
+	n := code.Bounds().Dx()
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, n+2*quietZone, n+2*quietZone)
+	b.WriteString(`<rect width="100%" height="100%" fill="#fff"/><path fill="#000" d="`)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; {
+			run := 0
+			for x+run < n && dark(code, x+run, y) {
+				run++
+			}
+			if run > 0 {
+				fmt.Fprintf(&b, "M%d %dh%dv1h-%dz", x+quietZone, y+quietZone, run, run)
+				x += run
+			} else {
+				x++
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return []byte(b.String())
+}
+
+func dark(code barcode.Barcode, x, y int) bool {
+	r, _, _, _ := code.At(x, y).RGBA()
+	return r == 0
+}
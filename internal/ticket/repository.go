@@ -0,0 +1,216 @@
+package ticket
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketRepository interface {
+	// CreateBatch stores tickets, skipping seats that already have a VALID or
+	// USED ticket for the same event, and returns how many it stored
+	CreateBatch(tickets []*Ticket) (int64, error)
+	ListByBooking(bookingID string) ([]*Ticket, error)
+	ListByEvent(eventID string) ([]*Ticket, error)
+	// ListByHolder returns the VALID and USED tickets a user holds, newest first
+	ListByHolder(userID string) ([]*Ticket, error)
+	ListByIDs(ids []string) ([]*Ticket, error)
+	GetByCode(code string) (*Ticket, error)
+	// VoidByBooking voids a booking's valid tickets and returns how many it voided
+	VoidByBooking(bookingID string, at time.Time) (int64, error)
+	// CheckIn marks the VALID ticket with code for eventID USED at time at.
+	// A USED ticket keeps its first check-in. It reports whether the ticket
+	// was updated.
+	CheckIn(code, eventID string, at time.Time, gate, by string) (bool, error)
+	// LogScan appends a scan to the scan log
+	LogScan(s *ScanRecord) error
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewTicketRepository(db *gorm.DB) TicketRepository { return &repo{db} }
+
+// seatTaken is the unique partial index on live tickets from 016_tickets.sql
+var seatTaken = clause.OnConflict{
+	Columns:     []clause.Column{{Name: "booking_id"}, {Name: "event_id"}, {Name: "number"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status <> 'VOID'"}}},
+	DoNothing:   true,
+}
+
+func (r *repo) CreateBatch(tickets []*Ticket) (int64, error) {
+	res := r.db.Clauses(seatTaken).Create(tickets)
+	return res.RowsAffected, res.Error
+}
+
+func (r *repo) ListByBooking(bookingID string) ([]*Ticket, error) {
+	var out []*Ticket
+	return out, r.db.Where("booking_id = ?", bookingID).Order("created_at asc, number asc").Find(&out).Error
+}
+
+func (r *repo) VoidByBooking(bookingID string, at time.Time) (int64, error) {
+	res := r.db.Model(&Ticket{}).
+		Where("booking_id = ? AND status = ?", bookingID, StatusValid).
+		Updates(map[string]any{"status": StatusVoid, "voided_at": at})
+	return res.RowsAffected, res.Error
+}
+
+func (r *repo) ListByEvent(eventID string) ([]*Ticket, error) {
+	var out []*Ticket
+	return out, r.db.Where("event_id = ?", eventID).Order("booking_id asc, number asc").Find(&out).Error
+}
+
+func (r *repo) GetByCode(code string) (*Ticket, error) {
+	var t Ticket
+	if err := r.db.Where("code = ?", code).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *repo) CheckIn(code, eventID string, at time.Time, gate, by string) (bool, error) {
+	res := r.db.Model(&Ticket{}).
+		Where("code = ? AND event_id = ?", code, eventID).
+		Where("status = ?", StatusValid).
+		Updates(map[string]any{"status": StatusUsed, "checked_in_at": at, "gate": gate, "checked_in_by": by})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *repo) LogScan(s *ScanRecord) error { return r.db.Create(s).Error }
+
+func (r *repo) ListByHolder(userID string) ([]*Ticket, error) {
+	var out []*Ticket
+	return out, r.db.Where("holder_id = ? AND status <> ?", userID, StatusVoid).Order("created_at desc, number asc").Find(&out).Error
+}
+
+func (r *repo) ListByIDs(ids []string) ([]*Ticket, error) {
+	var out []*Ticket
+	return out, r.db.Where("id IN ?", ids).Order("number asc").Find(&out).Error
+}
+
+var (
+	// ErrTicketsUnavailable is returned when offered tickets are no longer VALID,
+	// no longer held by the sender or already offered elsewhere
+	ErrTicketsUnavailable = errors.New("tickets are not available for transfer")
+	// ErrTransferNotPending is returned when a transfer was already accepted,
+	// declined, cancelled or has expired
+	ErrTransferNotPending = errors.New("transfer is no longer pending")
+)
+
+type TransferRepository interface {
+	// Create stores t and marks its tickets, held by t.FromUserID, as offered
+	// by it. It fails with ErrTicketsUnavailable, storing nothing, unless
+	// every ticket is VALID and free of other pending transfers.
+	Create(t *Transfer, ticketIDs []string) error
+	Get(id string) (*Transfer, error)
+	// ListForUser returns transfers sent by userID or addressed to email, newest first
+	ListForUser(userID, email string) ([]*Transfer, error)
+	// Tickets returns the tickets a transfer offers or replaced
+	Tickets(transferID string) ([]*Ticket, error)
+	// Resolve moves a PENDING transfer to status and frees its tickets
+	Resolve(id string, status TransferStatus, at time.Time) error
+	// Accept voids the tickets a PENDING transfer offers and stores fresh,
+	// replacing them, in one transaction. It fails with ErrTicketsUnavailable
+	// when any offered ticket stopped being VALID.
+	Accept(t *Transfer, toUserID string, fresh []*Ticket, at time.Time) error
+	// Expire marks PENDING transfers past their expiry EXPIRED and frees their tickets
+	Expire(at time.Time) error
+}
+
+type transferRepo struct{ db *gorm.DB }
+
+func NewTransferRepository(db *gorm.DB) TransferRepository { return &transferRepo{db} }
+
+// offeredElsewhere matches tickets held by a live pending transfer
+const offeredElsewhere = "transfer_id IN (SELECT id FROM ticket_transfers WHERE status = ? AND expires_at > ?)"
+
+func (r *transferRepo) Create(t *Transfer, ticketIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(t).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&Ticket{}).
+			Where("id IN ? AND holder_id = ? AND status = ?", ticketIDs, t.FromUserID, StatusValid).
+			Where("transfer_id IS NULL OR NOT "+offeredElsewhere, TransferPending, time.Now()).
+			Update("transfer_id", t.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(len(ticketIDs)) {
+			return ErrTicketsUnavailable
+		}
+		return nil
+	})
+}
+
+func (r *transferRepo) Get(id string) (*Transfer, error) {
+	var t Transfer
+	if err := r.db.First(&t, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *transferRepo) ListForUser(userID, email string) ([]*Transfer, error) {
+	var out []*Transfer
+	return out, r.db.Where("from_user_id = ? OR to_email = ?", userID, email).Order("created_at desc").Find(&out).Error
+}
+
+func (r *transferRepo) Tickets(transferID string) ([]*Ticket, error) {
+	var out []*Ticket
+	return out, r.db.Where("transfer_id = ?", transferID).Order("number asc").Find(&out).Error
+}
+
+func (r *transferRepo) Resolve(id string, status TransferStatus, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Transfer{}).Where("id = ? AND status = ?", id, TransferPending).
+			Updates(map[string]any{"status": status, "resolved_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrTransferNotPending
+		}
+		return tx.Model(&Ticket{}).Where("transfer_id = ?", id).Update("transfer_id", nil).Error
+	})
+}
+
+func (r *transferRepo) Accept(t *Transfer, toUserID string, fresh []*Ticket, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Transfer{}).Where("id = ? AND status = ? AND expires_at > ?", t.ID, TransferPending, at).
+			Updates(map[string]any{"status": TransferAccepted, "to_user_id": toUserID, "resolved_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != 1 {
+			return ErrTransferNotPending
+		}
+		res = tx.Model(&Ticket{}).Where("transfer_id = ? AND status = ?", t.ID, StatusValid).
+			Updates(map[string]any{"status": StatusVoid, "voided_at": at})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected != int64(t.Count) || len(fresh) != t.Count {
+			return ErrTicketsUnavailable
+		}
+		return tx.Create(fresh).Error
+	})
+}
+
+func (r *transferRepo) Expire(at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		if err := tx.Model(&Transfer{}).Where("status = ? AND expires_at <= ?", TransferPending, at).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		if err := tx.Model(&Transfer{}).Where("id IN ? AND status = ?", ids, TransferPending).
+			Updates(map[string]any{"status": TransferExpired, "resolved_at": at}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Ticket{}).Where("transfer_id IN ? AND status = ?", ids, StatusValid).Update("transfer_id", nil).Error
+	})
+}
@@ -0,0 +1,28 @@
+package ticket
+
+import "github.com/gin-gonic/gin"
+
+// RegisterRoutes exposes a booking's tickets to its owner and held tickets to
+// their holder; r must require authentication
+func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/bookings/:id/tickets", h.List)
+	r.GET("/bookings/:id/tickets/:ticket_id/qr", h.QR)
+	r.GET("/tickets", h.Held)
+}
+
+// RegisterTransferRoutes exposes ticket transfers; r must require authentication
+func RegisterTransferRoutes(r *gin.RouterGroup, h *TransferHandler) {
+	r.POST("/transfers", h.Create)
+	r.GET("/transfers", h.List)
+	r.POST("/transfers/:id/accept", h.Accept)
+	r.POST("/transfers/:id/decline", h.Decline)
+	r.POST("/transfers/:id/cancel", h.Cancel)
+}
+
+// RegisterScannerRoutes exposes door check-in; r must require tickets:scan
+func RegisterScannerRoutes(r *gin.RouterGroup, h *Handler) {
+	r.POST("/scan", h.Scan)
+	r.GET("/manifest-key", h.ManifestKey)
+	r.GET("/events/:id/manifest", h.Manifest)
+	r.POST("/events/:id/sync", h.Sync)
+}
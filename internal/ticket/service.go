@@ -0,0 +1,118 @@
+package ticket
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/booking"
+)
+
+// Service issues, lists and voids tickets. It plugs into the booking service
+// through booking.Service.SetTickets.
+type Service struct {
+	repo   TicketRepository
+	signer *Signer
+	logger *zap.Logger
+}
+
+func NewService(r TicketRepository, signer *Signer, logger *zap.Logger) *Service {
+	return &Service{repo: r, signer: signer, logger: logger}
+}
+
+// Ensure *Service can be handed to booking.Service.SetTickets
+var _ booking.TicketIssuer = (*Service)(nil)
+
+// Issue creates one VALID ticket per seat of b. A booking that already holds
+// valid or used tickets for its event keeps them, so repeat calls are no-ops;
+// concurrent calls race on a unique index and the loser stores nothing.
+// Seats transferred before a move stay with their latest holder.
+func (s *Service) Issue(ctx context.Context, b *booking.Booking) error {
+	existing, err := s.repo.ListByBooking(b.ID)
+	if err != nil {
+		return err
+	}
+	holders := map[int]string{}
+	for _, t := range existing {
+		if t.Status != StatusVoid && t.EventID == b.EventID {
+			return nil
+		}
+		holders[t.Number] = t.HolderID // oldest first, so the latest holder wins
+	}
+	tickets := make([]*Ticket, 0, b.Quantity)
+	for i := 1; i <= b.Quantity; i++ {
+		code, err := newCode()
+		if err != nil {
+			return err
+		}
+		holder, ok := holders[i]
+		if !ok {
+			holder = b.UserID
+		}
+		tickets = append(tickets, &Ticket{
+			BookingID: b.ID,
+			EventID:   b.EventID,
+			HolderID:  holder,
+			Number:    i,
+			Code:      code,
+			Status:    StatusValid,
+		})
+	}
+	n, err := s.repo.CreateBatch(tickets)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.Info("Tickets issued", zap.String("booking_id", b.ID), zap.Int64("count", n))
+	}
+	return nil
+}
+
+// Replacement returns a new VALID ticket for old's seat, held by holderID under
+// a fresh code. Storing it is up to the caller, alongside voiding old.
+func Replacement(old *Ticket, holderID string) (*Ticket, error) {
+	code, err := newCode()
+	if err != nil {
+		return nil, err
+	}
+	return &Ticket{
+		BookingID: old.BookingID,
+		EventID:   old.EventID,
+		HolderID:  holderID,
+		Number:    old.Number,
+		Code:      code,
+		Status:    StatusValid,
+	}, nil
+}
+
+// Void invalidates every valid ticket of a booking
+func (s *Service) Void(ctx context.Context, bookingID string) error {
+	n, err := s.repo.VoidByBooking(bookingID, time.Now())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.Info("Tickets voided", zap.String("booking_id", bookingID), zap.Int64("count", n))
+	}
+	return nil
+}
+
+// ForBooking lists b's tickets. A CONFIRMED booking without tickets, because
+// issuing failed at confirmation, gets them issued first.
+func (s *Service) ForBooking(ctx context.Context, b *booking.Booking) ([]*Ticket, error) {
+	if b.Status == booking.StatusConfirmed {
+		if err := s.Issue(ctx, b); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListByBooking(b.ID)
+}
+
+// Held lists the tickets userID holds, whether bought or received by transfer
+func (s *Service) Held(ctx context.Context, userID string) ([]*Ticket, error) {
+	return s.repo.ListByHolder(userID)
+}
+
+// Payload returns the signed QR payload of t
+func (s *Service) Payload(t *Ticket) string { return s.signer.Sign(t) }
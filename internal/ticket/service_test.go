@@ -0,0 +1,301 @@
+package ticket_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/pkg/config"
+)
+
+// memTickets is an in-memory TicketRepository
+type memTickets struct {
+	tickets []*ticket.Ticket
+	scans   []*ticket.ScanRecord
+}
+
+func (m *memTickets) CreateBatch(tickets []*ticket.Ticket) (int64, error) {
+	var n int64
+	for _, t := range tickets {
+		if m.taken(t) {
+			continue
+		}
+		t.ID = "t" + string(rune('0'+len(m.tickets)+1))
+		t.CreatedAt = time.Now()
+		m.tickets = append(m.tickets, t)
+		n++
+	}
+	return n, nil
+}
+
+// taken mirrors the unique index on the live tickets of a seat
+func (m *memTickets) taken(t *ticket.Ticket) bool {
+	for _, o := range m.tickets {
+		if o.BookingID == t.BookingID && o.EventID == t.EventID && o.Number == t.Number && o.Status != ticket.StatusVoid {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memTickets) ListByBooking(bookingID string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for _, t := range m.tickets {
+		if t.BookingID == bookingID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (m *memTickets) VoidByBooking(bookingID string, at time.Time) (int64, error) {
+	var n int64
+	for _, t := range m.tickets {
+		if t.BookingID == bookingID && t.Status == ticket.StatusValid {
+			t.Status, t.VoidedAt = ticket.StatusVoid, &at
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memTickets) ListByEvent(eventID string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for _, t := range m.tickets {
+		if t.EventID == eventID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (m *memTickets) ListByHolder(userID string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for i := len(m.tickets) - 1; i >= 0; i-- {
+		if t := m.tickets[i]; t.HolderID == userID && t.Status != ticket.StatusVoid {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (m *memTickets) ListByIDs(ids []string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for _, id := range ids {
+		for _, t := range m.tickets {
+			if t.ID == id {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *memTickets) GetByCode(code string) (*ticket.Ticket, error) {
+	for _, t := range m.tickets {
+		if t.Code == code {
+			return t, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memTickets) CheckIn(code, eventID string, at time.Time, gate, by string) (bool, error) {
+	t, err := m.GetByCode(code)
+	if err != nil || t.EventID != eventID {
+		return false, nil
+	}
+	if t.Status == ticket.StatusValid {
+		t.Status, t.CheckedInAt, t.Gate, t.CheckedInBy = ticket.StatusUsed, &at, gate, &by
+		return true, nil
+	}
+	return false, nil
+}
+
+func (m *memTickets) LogScan(s *ticket.ScanRecord) error {
+	m.scans = append(m.scans, s)
+	return nil
+}
+
+// bookingMap is a Bookings lookup over fixed bookings
+type bookingMap map[string]*booking.Booking
+
+func (m bookingMap) Get(_ context.Context, id string) (*booking.Booking, error) {
+	if b, ok := m[id]; ok {
+		return b, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestSigner(t *testing.T) {
+	s := ticket.NewSigner("0123456789abcdef")
+	tk := &ticket.Ticket{EventID: "e1", Code: "7K3QX9M2TD"}
+	payload := s.Sign(tk)
+	require.True(t, strings.HasPrefix(payload, "TB2.e1.7K3QX9M2TD."))
+
+	eventID, code, err := s.Verify(payload)
+	require.NoError(t, err)
+	require.Equal(t, "e1", eventID)
+	require.Equal(t, "7K3QX9M2TD", code)
+
+	// Offline scanners check the signature with the pinned public key alone
+	cut := strings.LastIndex(payload, ".")
+	sig, err := base64.RawURLEncoding.DecodeString(payload[cut+1:])
+	require.NoError(t, err)
+	require.True(t, ed25519.Verify(s.ManifestKey(), []byte(payload[:cut]), sig))
+
+	// Payloads issued before TB2 still pass online
+	qrKey := hmac.New(sha256.New, []byte("0123456789abcdef"))
+	qrKey.Write([]byte("ticket-qr"))
+	mac := hmac.New(sha256.New, qrKey.Sum(nil))
+	mac.Write([]byte("TB1.e1.7K3QX9M2TD"))
+	legacy := "TB1.e1.7K3QX9M2TD." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	_, code, err = s.Verify(legacy)
+	require.NoError(t, err)
+	require.Equal(t, "7K3QX9M2TD", code)
+
+	// Without a ticket secret the refresh secret signs, under its own label
+	require.Equal(t, payload, ticket.NewSignerFromConfig(&config.Security{JWTRefreshSecret: "0123456789abcdef"}).Sign(tk))
+	require.NotEqual(t, payload, ticket.NewSignerFromConfig(&config.Security{JWTRefreshSecret: "0123456789abcdef", TicketSecret: "fedcba9876543210"}).Sign(tk))
+
+	for _, bad := range []string{
+		strings.Replace(payload, "7K3QX9M2TD", "7K3QX9M2TE", 1), // another code
+		strings.Replace(payload, "TB2.e1", "TB2.e2", 1),         // another event
+		ticket.NewSigner("another-secret-16").Sign(tk),          // another key
+		strings.Replace(legacy, "TB1.", "TB2.", 1),              // an HMAC passed off as TB2
+		"TB2.e1.7K3QX9M2TD",
+		"",
+	} {
+		_, _, err := s.Verify(bad)
+		require.ErrorIs(t, err, ticket.ErrInvalidPayload, bad)
+	}
+}
+
+func TestIssueAndVoid(t *testing.T) {
+	repo := &memTickets{}
+	svc := ticket.NewService(repo, ticket.NewSigner("0123456789abcdef"), zap.NewNop())
+	b := &booking.Booking{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 3, Status: booking.StatusConfirmed}
+
+	require.NoError(t, svc.Issue(context.Background(), b))
+	require.NoError(t, svc.Issue(context.Background(), b), "repeat calls are no-ops")
+	require.Len(t, repo.tickets, 3)
+	codes := map[string]bool{}
+	for i, tk := range repo.tickets {
+		require.Equal(t, i+1, tk.Number)
+		require.Equal(t, "u1", tk.HolderID)
+		require.Equal(t, ticket.StatusValid, tk.Status)
+		require.Len(t, tk.Code, 10)
+		codes[tk.Code] = true
+	}
+	require.Len(t, codes, 3)
+
+	// Moved to another event: the old tickets are voided and new ones issued
+	require.NoError(t, svc.Void(context.Background(), "b1"))
+	b.EventID = "e2"
+	require.NoError(t, svc.Issue(context.Background(), b))
+	require.Len(t, repo.tickets, 6)
+	require.Equal(t, ticket.StatusVoid, repo.tickets[0].Status)
+	require.NotNil(t, repo.tickets[0].VoidedAt)
+	require.Equal(t, "e2", repo.tickets[5].EventID)
+}
+
+// staleTickets lists no tickets, like a concurrent Issue that read before
+// the other one stored its tickets
+type staleTickets struct{ *memTickets }
+
+func (staleTickets) ListByBooking(string) ([]*ticket.Ticket, error) { return nil, nil }
+
+func TestIssue_ConcurrentCallsIssueOnce(t *testing.T) {
+	repo := &memTickets{}
+	b := &booking.Booking{ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed}
+	require.NoError(t, ticket.NewService(repo, ticket.NewSigner("0123456789abcdef"), zap.NewNop()).Issue(context.Background(), b))
+
+	stale := ticket.NewService(staleTickets{repo}, ticket.NewSigner("0123456789abcdef"), zap.NewNop())
+	require.NoError(t, stale.Issue(context.Background(), b), "a taken seat counts as already issued")
+	require.Len(t, repo.tickets, 2)
+}
+
+func TestHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &memTickets{}
+	svc := ticket.NewService(repo, ticket.NewSigner("0123456789abcdef"), zap.NewNop())
+	bookings := bookingMap{
+		"b1": {ID: "b1", UserID: "u1", EventID: "e1", Quantity: 2, Status: booking.StatusConfirmed},
+		"b2": {ID: "b2", UserID: "u1", EventID: "e1", Quantity: 1, Status: booking.StatusPending},
+	}
+	h := ticket.NewHandler(svc, bookings, zap.NewNop())
+
+	get := func(path, userID string, perms ...string) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(auth.CtxUserID, userID)
+			c.Set(auth.CtxPermissions, perms)
+		})
+		ticket.RegisterRoutes(r.Group(""), h)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Tickets missing from a confirmed booking are issued on first read
+	w := get("/bookings/b1/tickets", "u1")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var list []ticket.TicketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list, 2)
+	require.True(t, strings.HasPrefix(list[0].QRCode, "data:image/png;base64,"))
+	img, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(list[0].QRCode, "data:image/png;base64,"))
+	require.NoError(t, err)
+	_, err = png.Decode(bytes.NewReader(img))
+	require.NoError(t, err)
+
+	w = get("/bookings/b1/tickets?format=svg", "u1")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.True(t, strings.HasPrefix(list[0].QRCode, "data:image/svg+xml;base64,"))
+	require.Equal(t, http.StatusBadRequest, get("/bookings/b1/tickets?format=gif", "u1").Code)
+
+	w = get("/bookings/b1/tickets/"+list[1].ID+"/qr?format=svg", "u1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	require.True(t, strings.HasPrefix(w.Body.String(), "<svg"))
+
+	// Other users' bookings are hidden unless the caller may read any booking
+	require.Equal(t, http.StatusNotFound, get("/bookings/b1/tickets", "u2").Code)
+	require.Equal(t, http.StatusOK, get("/bookings/b1/tickets", "u2", auth.PermBookingsRead).Code)
+	require.Equal(t, http.StatusNotFound, get("/bookings/missing/tickets", "u1").Code)
+
+	// Pending bookings have no tickets yet
+	w = get("/bookings/b2/tickets", "u1")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `[]`, w.Body.String())
+
+	// Void tickets keep their record but lose their QR code
+	require.NoError(t, svc.Void(context.Background(), "b1"))
+	bookings["b1"].Status = booking.StatusCancelled
+	w = get("/bookings/b1/tickets", "u1")
+	list = nil
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Equal(t, ticket.StatusVoid, list[0].Status)
+	require.Empty(t, list[0].QRCode)
+	require.Empty(t, list[0].Payload)
+	require.Equal(t, http.StatusGone, get("/bookings/b1/tickets/"+list[0].ID+"/qr", "u1").Code)
+}
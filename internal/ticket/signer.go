@@ -0,0 +1,118 @@
+package ticket
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"ticket-booking/pkg/config"
+)
+
+// payloadVersion prefixes every QR payload: TB2.<event_id>.<code>.<signature>,
+// where signature is the base64url Ed25519 signature of everything before
+// it, so offline scanners can check it with the pinned public key
+const payloadVersion = "TB2"
+
+// legacyPayloadVersion marks payloads issued before TB2, whose signature is
+// an HMAC-SHA256 only the server can check
+const legacyPayloadVersion = "TB1"
+
+// ErrInvalidPayload is returned for a QR payload that is malformed or not signed by us
+var ErrInvalidPayload = errors.New("invalid ticket payload")
+
+// Signer signs and checks ticket QR payloads, and signs the scanner manifests
+type Signer struct {
+	key     []byte // Checks legacy TB1 payloads
+	scanner ed25519.PrivateKey
+}
+
+// NewSigner derives the signing keys from secret and a label each. QR
+// payloads and manifests use Ed25519, letting scanners check both without
+// the secret; the version prefix keeps a payload from passing for a manifest.
+func NewSigner(secret string) *Signer {
+	derive := func(label string) []byte {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(label))
+		return mac.Sum(nil)
+	}
+	return &Signer{key: derive("ticket-qr"), scanner: ed25519.NewKeyFromSeed(derive("ticket-manifest"))}
+}
+
+// NewSignerFromConfig signs with security.ticket_secret, falling back to the
+// refresh token secret when none is set
+func NewSignerFromConfig(cfg *config.Security) *Signer {
+	if cfg.TicketSecret != "" {
+		return NewSigner(cfg.TicketSecret)
+	}
+	return NewSigner(cfg.JWTRefreshSecret)
+}
+
+func (s *Signer) mac(msg string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(msg))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns the QR payload of t
+func (s *Signer) Sign(t *Ticket) string {
+	msg := payloadVersion + "." + t.EventID + "." + t.Code
+	return msg + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(s.scanner, []byte(msg)))
+}
+
+// Verify checks payload's signature and returns the event and ticket code it
+// names. Legacy TB1 payloads are still accepted here, though offline
+// scanners cannot check them.
+func (s *Signer) Verify(payload string) (eventID, code string, err error) {
+	parts := strings.Split(payload, ".")
+	if len(parts) != 4 {
+		return "", "", ErrInvalidPayload
+	}
+	msg := strings.Join(parts[:3], ".")
+	switch parts[0] {
+	case payloadVersion:
+		sig, err := base64.RawURLEncoding.DecodeString(parts[3])
+		if err != nil || !ed25519.Verify(s.ManifestKey(), []byte(msg), sig) {
+			return "", "", ErrInvalidPayload
+		}
+	case legacyPayloadVersion:
+		if !hmac.Equal([]byte(parts[3]), []byte(s.mac(msg))) {
+			return "", "", ErrInvalidPayload
+		}
+	default:
+		return "", "", ErrInvalidPayload
+	}
+	return parts[1], parts[2], nil
+}
+
+// SignManifest returns the base64url Ed25519 signature of a manifest body
+func (s *Signer) SignManifest(body []byte) string {
+	return base64.RawURLEncoding.EncodeToString(ed25519.Sign(s.scanner, body))
+}
+
+// ManifestKey returns the public key scanners verify manifests and QR
+// payloads with
+func (s *Signer) ManifestKey() ed25519.PublicKey {
+	return s.scanner.Public().(ed25519.PublicKey)
+}
+
+// codeAlphabet is Crockford's base32: no I, L, O or U to misread at the door
+const codeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// codeLength gives 50 random bits per code
+const codeLength = 10
+
+// newCode returns a random ticket code
+func newCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,491 @@
+package ticket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+// TransferTTL is how long a recipient has to accept, unless the transfer
+// cutoff comes first
+const TransferTTL = 72 * time.Hour
+
+var (
+	// ErrTransferClosed is returned once the event is within the transfer cutoff
+	ErrTransferClosed = errors.New("transfers are closed for this event")
+	// ErrTransferTarget is returned unless exactly one of booking_id and ticket_ids is given
+	ErrTransferTarget = errors.New("give either booking_id or ticket_ids")
+	// ErrSelfTransfer is returned when the recipient is the sender
+	ErrSelfTransfer = errors.New("cannot transfer tickets to yourself")
+	// ErrNothingToTransfer is returned when the request names no valid tickets of the caller
+	ErrNothingToTransfer = errors.New("no valid tickets to transfer")
+	// ErrMixedEvents is returned when the named tickets are for different events
+	ErrMixedEvents = errors.New("tickets must be for the same event")
+	// ErrTransferNotFound is returned for unknown transfers and for transfers
+	// the caller is neither sender nor recipient of
+	ErrTransferNotFound = errors.New("transfer not found")
+	// ErrTransferStale is returned on accept when an offered ticket was used
+	// or voided meanwhile; the transfer is cancelled
+	ErrTransferStale = errors.New("offered tickets are no longer valid")
+)
+
+// Events looks up the event tickets are for
+type Events interface {
+	Get(ctx context.Context, id string) (*event.Event, error)
+}
+
+// Users looks up senders and recipients
+type Users interface {
+	Get(ctx context.Context, id string) (*user.User, error)
+}
+
+// TransferService moves tickets between users. The recipient gets new codes,
+// so a screenshot of the sender's QR stops working at the door.
+type TransferService struct {
+	repo     TransferRepository
+	tickets  TicketRepository
+	bookings Bookings
+	events   Events
+	users    Users
+	mailer   mail.Mailer
+	tmpl     *mail.Templates
+	linkBase string
+	cutoff   time.Duration
+	logger   *zap.Logger
+}
+
+func NewTransferService(r TransferRepository, tickets TicketRepository, bookings Bookings, events Events, users Users,
+	mailer mail.Mailer, cfg config.Booking, email config.Email, logger *zap.Logger) *TransferService {
+	return &TransferService{
+		repo:     r,
+		tickets:  tickets,
+		bookings: bookings,
+		events:   events,
+		users:    users,
+		mailer:   mailer,
+		tmpl:     mail.NewTemplates(email.TemplateDir),
+		linkBase: strings.TrimRight(email.LinkBaseURL, "/"),
+		cutoff:   time.Duration(cfg.TransferCutoffHours) * time.Hour,
+		logger:   logger,
+	}
+}
+
+// closesAt is when transfers for e stop
+func (s *TransferService) closesAt(e *event.Event) time.Time {
+	return e.StartsAt.Add(-s.cutoff)
+}
+
+// offered resolves req to the caller's VALID tickets
+func (s *TransferService) offered(ctx context.Context, userID string, req TransferRequest) ([]*Ticket, error) {
+	var tickets []*Ticket
+	switch {
+	case (req.BookingID == "") == (len(req.TicketIDs) == 0):
+		return nil, ErrTransferTarget
+	case req.BookingID != "":
+		b, err := s.bookings.Get(ctx, req.BookingID)
+		if err != nil || b.UserID != userID {
+			return nil, ErrNothingToTransfer
+		}
+		if b.Status != booking.StatusConfirmed {
+			return nil, ErrNothingToTransfer
+		}
+		all, err := s.tickets.ListByBooking(b.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range all {
+			if t.EventID == b.EventID {
+				tickets = append(tickets, t)
+			}
+		}
+	case len(req.TicketIDs) > 0:
+		var err error
+		if tickets, err = s.tickets.ListByIDs(req.TicketIDs); err != nil {
+			return nil, err
+		}
+		if len(tickets) != len(req.TicketIDs) {
+			return nil, ErrNothingToTransfer
+		}
+	}
+
+	var out []*Ticket
+	for _, t := range tickets {
+		if t.HolderID != userID || t.Status != StatusValid {
+			// Named tickets must all be transferable; a booking offers what is left of it
+			if req.BookingID == "" {
+				return nil, ErrNothingToTransfer
+			}
+			continue
+		}
+		if len(out) > 0 && t.EventID != out[0].EventID {
+			return nil, ErrMixedEvents
+		}
+		out = append(out, t)
+	}
+	if len(out) == 0 {
+		return nil, ErrNothingToTransfer
+	}
+	return out, nil
+}
+
+// Create offers tickets of userID to req.Email and mails the recipient. The
+// tickets stay usable by the sender until the recipient accepts.
+func (s *TransferService) Create(ctx context.Context, userID string, req TransferRequest) (*Transfer, error) {
+	from, err := s.users.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	to := strings.ToLower(strings.TrimSpace(req.Email))
+	if to == strings.ToLower(from.Email) {
+		return nil, ErrSelfTransfer
+	}
+	tickets, err := s.offered(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.events.Get(ctx, tickets[0].EventID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	closes := s.closesAt(e)
+	if !now.Before(closes) {
+		return nil, ErrTransferClosed
+	}
+	expires := now.Add(TransferTTL)
+	if closes.Before(expires) {
+		expires = closes
+	}
+
+	t := &Transfer{
+		EventID:    e.ID,
+		FromUserID: userID,
+		FromEmail:  from.Email,
+		ToEmail:    to,
+		Count:      len(tickets),
+		Status:     TransferPending,
+		ExpiresAt:  expires,
+	}
+	ids := make([]string, len(tickets))
+	for i, tk := range tickets {
+		ids[i] = tk.ID
+	}
+	if err := s.repo.Create(t, ids); err != nil {
+		return nil, err
+	}
+	s.logger.Info("Ticket transfer offered", zap.String("transfer_id", t.ID), zap.String("event_id", e.ID), zap.Int("count", t.Count))
+
+	// The transfer stands without the email; the recipient also sees it under GET /transfers
+	msg, err := s.tmpl.Render("ticket_transfer", to, map[string]any{
+		"FromEmail": from.Email,
+		"Count":     t.Count,
+		"EventName": e.Name,
+		"ExpiresAt": expires.UTC().Format("Mon 2 Jan 2006 15:04 MST"),
+		"Link":      s.linkBase + "/transfers/" + t.ID,
+	})
+	if err == nil {
+		err = s.mailer.Send(ctx, msg)
+	}
+	if err != nil {
+		s.logger.Error("Failed to mail ticket transfer", zap.String("transfer_id", t.ID), zap.Error(err))
+	}
+	return t, nil
+}
+
+// get loads transfer id, which userID must have sent or, by email, received
+func (s *TransferService) get(ctx context.Context, id, userID string) (*Transfer, *user.User, error) {
+	u, err := s.users.Get(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := s.repo.Get(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrTransferNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.FromUserID != userID && t.ToEmail != strings.ToLower(u.Email) {
+		return nil, nil, ErrTransferNotFound
+	}
+	return t, u, nil
+}
+
+// List returns the transfers userID sent and those addressed to their email.
+// Pending offers past their expiry are shown EXPIRED; WatchExpiry catches the
+// stored rows up.
+func (s *TransferService) List(ctx context.Context, userID string) (incoming, outgoing []*Transfer, err error) {
+	u, err := s.users.Get(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	all, err := s.repo.ListForUser(userID, strings.ToLower(u.Email))
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	incoming, outgoing = []*Transfer{}, []*Transfer{}
+	for _, t := range all {
+		if t.Status == TransferPending && !now.Before(t.ExpiresAt) {
+			t.Status = TransferExpired
+		}
+		if t.FromUserID == userID {
+			outgoing = append(outgoing, t)
+		} else {
+			incoming = append(incoming, t)
+		}
+	}
+	return incoming, outgoing, nil
+}
+
+// ExpireDue marks PENDING transfers past their expiry EXPIRED and frees their
+// tickets
+func (s *TransferService) ExpireDue(ctx context.Context) error {
+	if err := s.repo.Expire(time.Now()); err != nil {
+		s.logger.Error("Failed to expire ticket transfers", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// WatchExpiry runs ExpireDue every interval until ctx is done
+func (s *TransferService) WatchExpiry(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			_ = s.ExpireDue(ctx)
+		}
+	}
+}
+
+// Accept moves the offered tickets to userID, the recipient: the sender's
+// codes are voided and new ones issued for the same seats in one transaction
+func (s *TransferService) Accept(ctx context.Context, id, userID string) (*Transfer, []*Ticket, error) {
+	t, u, err := s.get(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t.ToEmail != strings.ToLower(u.Email) {
+		return nil, nil, ErrTransferNotFound
+	}
+	if t.Status != TransferPending {
+		return nil, nil, ErrTransferNotPending
+	}
+	now := time.Now()
+	if !now.Before(t.ExpiresAt) {
+		if err := s.repo.Resolve(t.ID, TransferExpired, now); err != nil && !errors.Is(err, ErrTransferNotPending) {
+			return nil, nil, err
+		}
+		return nil, nil, ErrTransferNotPending
+	}
+
+	offered, err := s.repo.Tickets(t.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	fresh := make([]*Ticket, 0, len(offered))
+	for _, old := range offered {
+		if old.Status != StatusValid {
+			continue
+		}
+		tk, err := Replacement(old, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		tk.TransferID = &t.ID
+		fresh = append(fresh, tk)
+	}
+	err = s.repo.Accept(t, userID, fresh, now)
+	if errors.Is(err, ErrTicketsUnavailable) {
+		// Used at the door or voided since the offer; it can never go through
+		if err := s.repo.Resolve(t.ID, TransferCancelled, now); err != nil && !errors.Is(err, ErrTransferNotPending) {
+			return nil, nil, err
+		}
+		return nil, nil, ErrTransferStale
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	t.Status, t.ToUserID, t.ResolvedAt = TransferAccepted, &userID, &now
+	s.logger.Info("Ticket transfer accepted", zap.String("transfer_id", t.ID), zap.Int("count", len(fresh)))
+	return t, fresh, nil
+}
+
+// Decline turns a transfer down; only its recipient may
+func (s *TransferService) Decline(ctx context.Context, id, userID string) (*Transfer, error) {
+	t, u, err := s.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.ToEmail != strings.ToLower(u.Email) {
+		return nil, ErrTransferNotFound
+	}
+	return s.resolve(t, TransferDeclined)
+}
+
+// Cancel withdraws a transfer; only its sender may
+func (s *TransferService) Cancel(ctx context.Context, id, userID string) (*Transfer, error) {
+	t, _, err := s.get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.FromUserID != userID {
+		return nil, ErrTransferNotFound
+	}
+	return s.resolve(t, TransferCancelled)
+}
+
+func (s *TransferService) resolve(t *Transfer, status TransferStatus) (*Transfer, error) {
+	now := time.Now()
+	if err := s.repo.Resolve(t.ID, status, now); err != nil {
+		return nil, err
+	}
+	t.Status, t.ResolvedAt = status, &now
+	s.logger.Info("Ticket transfer resolved", zap.String("transfer_id", t.ID), zap.String("status", string(status)))
+	return t, nil
+}
+
+type TransferHandler struct {
+	svc    *TransferService
+	logger *zap.Logger
+}
+
+func NewTransferHandler(s *TransferService, logger *zap.Logger) *TransferHandler {
+	return &TransferHandler{svc: s, logger: logger}
+}
+
+// fail maps transfer errors to responses
+func (h *TransferHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrTransferTarget), errors.Is(err, ErrSelfTransfer), errors.Is(err, ErrMixedEvents):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrTransferNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+	case errors.Is(err, ErrNothingToTransfer), errors.Is(err, ErrTicketsUnavailable),
+		errors.Is(err, ErrTransferNotPending), errors.Is(err, ErrTransferStale), errors.Is(err, ErrTransferClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		h.logger.Error("Ticket transfer failed", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
+
+// Create godoc
+// @Summary Transfer tickets
+// @Description Offer every valid ticket of a CONFIRMED booking, or individual tickets, to another person by email. The recipient has 72 hours to accept, and transfers close a configured number of hours before the event starts.
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Param request body TransferRequest true "Tickets and recipient"
+// @Success 201 {object} Transfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Tickets not transferable, already offered, or transfers closed"
+// @Security BearerAuth
+// @Router /transfers [post]
+func (h *TransferHandler) Create(c *gin.Context) {
+	var req TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	t, err := h.svc.Create(c, c.GetString(auth.CtxUserID), req)
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// List godoc
+// @Summary List my transfers
+// @Description Transfers addressed to the caller's email and those they sent, newest first
+// @Tags tickets
+// @Produce json
+// @Success 200 {object} TransferListResponse
+// @Security BearerAuth
+// @Router /transfers [get]
+func (h *TransferHandler) List(c *gin.Context) {
+	incoming, outgoing, err := h.svc.List(c, c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, TransferListResponse{Incoming: incoming, Outgoing: outgoing})
+}
+
+// Accept godoc
+// @Summary Accept a transfer
+// @Description Take over the offered tickets; the sender's codes stop working and new ones are issued under GET /tickets (recipient only)
+// @Tags tickets
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} Transfer
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Transfer no longer pending, or its tickets were used or voided"
+// @Security BearerAuth
+// @Router /transfers/{id}/accept [post]
+func (h *TransferHandler) Accept(c *gin.Context) {
+	t, _, err := h.svc.Accept(c, c.Param("id"), c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Decline godoc
+// @Summary Decline a transfer
+// @Description Turn down a pending transfer; the sender keeps the tickets (recipient only)
+// @Tags tickets
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} Transfer
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/{id}/decline [post]
+func (h *TransferHandler) Decline(c *gin.Context) {
+	t, err := h.svc.Decline(c, c.Param("id"), c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// Cancel godoc
+// @Summary Cancel a transfer
+// @Description Withdraw a pending transfer (sender only)
+// @Tags tickets
+// @Produce json
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} Transfer
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /transfers/{id}/cancel [post]
+func (h *TransferHandler) Cancel(c *gin.Context) {
+	t, err := h.svc.Cancel(c, c.Param("id"), c.GetString(auth.CtxUserID))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
@@ -0,0 +1,366 @@
+package ticket_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/booking"
+	"ticket-booking/internal/event"
+	"ticket-booking/internal/ticket"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+// memTransfers is an in-memory TransferRepository over the tickets of a memTickets
+type memTransfers struct {
+	tickets   *memTickets
+	transfers []*ticket.Transfer
+}
+
+func (m *memTransfers) live(id *string, at time.Time) bool {
+	if id == nil {
+		return false
+	}
+	t, err := m.Get(*id)
+	return err == nil && t.Status == ticket.TransferPending && t.ExpiresAt.After(at)
+}
+
+func (m *memTransfers) Create(t *ticket.Transfer, ticketIDs []string) error {
+	offered, _ := m.tickets.ListByIDs(ticketIDs)
+	if len(offered) != len(ticketIDs) {
+		return ticket.ErrTicketsUnavailable
+	}
+	for _, tk := range offered {
+		if tk.HolderID != t.FromUserID || tk.Status != ticket.StatusValid || m.live(tk.TransferID, time.Now()) {
+			return ticket.ErrTicketsUnavailable
+		}
+	}
+	t.ID = "x" + string(rune('0'+len(m.transfers)+1))
+	t.CreatedAt = time.Now()
+	m.transfers = append(m.transfers, t)
+	for _, tk := range offered {
+		tk.TransferID = &t.ID
+	}
+	return nil
+}
+
+func (m *memTransfers) Get(id string) (*ticket.Transfer, error) {
+	for _, t := range m.transfers {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memTransfers) ListForUser(userID, email string) ([]*ticket.Transfer, error) {
+	var out []*ticket.Transfer
+	for i := len(m.transfers) - 1; i >= 0; i-- {
+		if t := m.transfers[i]; t.FromUserID == userID || t.ToEmail == email {
+			cp := *t
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (m *memTransfers) Tickets(transferID string) ([]*ticket.Ticket, error) {
+	var out []*ticket.Ticket
+	for _, tk := range m.tickets.tickets {
+		if tk.TransferID != nil && *tk.TransferID == transferID {
+			out = append(out, tk)
+		}
+	}
+	return out, nil
+}
+
+func (m *memTransfers) Resolve(id string, status ticket.TransferStatus, at time.Time) error {
+	t, err := m.Get(id)
+	if err != nil || t.Status != ticket.TransferPending {
+		return ticket.ErrTransferNotPending
+	}
+	t.Status, t.ResolvedAt = status, &at
+	offered, _ := m.Tickets(id)
+	for _, tk := range offered {
+		tk.TransferID = nil
+	}
+	return nil
+}
+
+func (m *memTransfers) Accept(t *ticket.Transfer, toUserID string, fresh []*ticket.Ticket, at time.Time) error {
+	if !m.live(&t.ID, at) {
+		return ticket.ErrTransferNotPending
+	}
+	offered, _ := m.Tickets(t.ID)
+	var valid []*ticket.Ticket
+	for _, tk := range offered {
+		if tk.Status == ticket.StatusValid {
+			valid = append(valid, tk)
+		}
+	}
+	if len(valid) != t.Count || len(fresh) != t.Count {
+		return ticket.ErrTicketsUnavailable
+	}
+	t.Status, t.ToUserID, t.ResolvedAt = ticket.TransferAccepted, &toUserID, &at
+	for _, tk := range valid {
+		tk.Status, tk.VoidedAt = ticket.StatusVoid, &at
+	}
+	_, err := m.tickets.CreateBatch(fresh)
+	return err
+}
+
+func (m *memTransfers) Expire(at time.Time) error {
+	for _, t := range m.transfers {
+		if t.Status == ticket.TransferPending && !t.ExpiresAt.After(at) {
+			_ = m.Resolve(t.ID, ticket.TransferExpired, at)
+		}
+	}
+	return nil
+}
+
+type eventMap map[string]*event.Event
+
+func (m eventMap) Get(_ context.Context, id string) (*event.Event, error) {
+	if e, ok := m[id]; ok {
+		return e, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type userMap map[string]*user.User
+
+func (m userMap) Get(_ context.Context, id string) (*user.User, error) {
+	if u, ok := m[id]; ok {
+		return u, nil
+	}
+	return nil, errors.New("not found")
+}
+
+type transferFixture struct {
+	tickets   *ticket.Service
+	transfers *ticket.TransferService
+	repo      *memTickets
+	xfers     *memTransfers
+	signer    *ticket.Signer
+	bookings  bookingMap
+	events    eventMap
+	outbox    *mail.MemoryOutbox
+}
+
+// transferSetup issues u1 two tickets for eventA, which starts in ten days
+func transferSetup(t *testing.T) *transferFixture {
+	t.Helper()
+	f := &transferFixture{
+		repo:   &memTickets{},
+		signer: ticket.NewSigner("0123456789abcdef"),
+		bookings: bookingMap{
+			"b1": {ID: "b1", UserID: "u1", EventID: eventA, Quantity: 2, Status: booking.StatusConfirmed},
+		},
+		events: eventMap{
+			eventA: {ID: eventA, Name: "Jazz Night", StartsAt: time.Now().Add(240 * time.Hour)},
+			eventB: {ID: eventB, Name: "Late Show", StartsAt: time.Now().Add(12 * time.Hour)},
+		},
+		outbox: mail.NewMemoryOutbox(),
+	}
+	f.xfers = &memTransfers{tickets: f.repo}
+	users := userMap{
+		"u1": {ID: "u1", Email: "an@example.com"},
+		"u2": {ID: "u2", Email: "binh@example.com"},
+		"u3": {ID: "u3", Email: "chi@example.com"},
+	}
+	f.tickets = ticket.NewService(f.repo, f.signer, zap.NewNop())
+	f.transfers = ticket.NewTransferService(f.xfers, f.repo, f.bookings, f.events, users, f.outbox,
+		config.Booking{TransferCutoffHours: 24}, config.Email{LinkBaseURL: "https://tickets.example.com/"}, zap.NewNop())
+	require.NoError(t, f.tickets.Issue(context.Background(), f.bookings["b1"]))
+	return f
+}
+
+func TestTransfer_AcceptReissuesCodes(t *testing.T) {
+	f := transferSetup(t)
+	ctx := context.Background()
+	seat2 := f.repo.tickets[1]
+	oldPayload := f.signer.Sign(seat2)
+
+	_, err := f.transfers.Create(ctx, "u1", ticket.TransferRequest{TicketIDs: []string{seat2.ID}, Email: "AN@example.com"})
+	require.ErrorIs(t, err, ticket.ErrSelfTransfer)
+	_, err = f.transfers.Create(ctx, "u1", ticket.TransferRequest{Email: "binh@example.com"})
+	require.ErrorIs(t, err, ticket.ErrTransferTarget)
+
+	tr, err := f.transfers.Create(ctx, "u1", ticket.TransferRequest{TicketIDs: []string{seat2.ID}, Email: " Binh@Example.com"})
+	require.NoError(t, err)
+	require.Equal(t, "binh@example.com", tr.ToEmail)
+	require.Equal(t, 1, tr.Count)
+	require.WithinDuration(t, time.Now().Add(ticket.TransferTTL), tr.ExpiresAt, time.Minute)
+	msgs := f.outbox.Messages()
+	require.Len(t, msgs, 1)
+	require.Equal(t, "binh@example.com", msgs[0].To)
+	require.Contains(t, msgs[0].Body, "https://tickets.example.com/transfers/"+tr.ID)
+
+	// A ticket is offered to one person at a time
+	_, err = f.transfers.Create(ctx, "u1", ticket.TransferRequest{BookingID: "b1", Email: "chi@example.com"})
+	require.ErrorIs(t, err, ticket.ErrTicketsUnavailable)
+
+	// Only the recipient can accept
+	_, _, err = f.transfers.Accept(ctx, tr.ID, "u1")
+	require.ErrorIs(t, err, ticket.ErrTransferNotFound)
+	_, _, err = f.transfers.Accept(ctx, tr.ID, "u3")
+	require.ErrorIs(t, err, ticket.ErrTransferNotFound)
+
+	_, fresh, err := f.transfers.Accept(ctx, tr.ID, "u2")
+	require.NoError(t, err)
+	require.Len(t, fresh, 1)
+	require.Equal(t, "u2", fresh[0].HolderID)
+	require.Equal(t, 2, fresh[0].Number)
+	require.NotEqual(t, seat2.Code, fresh[0].Code)
+	require.Equal(t, ticket.StatusVoid, seat2.Status)
+	_, _, err = f.transfers.Accept(ctx, tr.ID, "u2")
+	require.ErrorIs(t, err, ticket.ErrTransferNotPending)
+
+	// The sender's copy is dead at the door; the recipient's works
+	out, err := f.tickets.CheckIn(ctx, ticket.Scan{Payload: oldPayload, EventID: eventA, At: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, ticket.ScanVoid, out.Result)
+	held, err := f.tickets.Held(ctx, "u2")
+	require.NoError(t, err)
+	require.Len(t, held, 1)
+	out, err = f.tickets.CheckIn(ctx, ticket.Scan{Payload: f.tickets.Payload(held[0]), EventID: eventA, At: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, ticket.ScanAdmitted, out.Result)
+
+	// Moving the booking reissues each seat to its current holder
+	require.NoError(t, f.tickets.Void(ctx, "b1"))
+	b := *f.bookings["b1"]
+	b.EventID = eventB
+	require.NoError(t, f.tickets.Issue(ctx, &b))
+	moved := f.repo.tickets[len(f.repo.tickets)-2:]
+	require.Equal(t, "u1", moved[0].HolderID)
+	require.Equal(t, "u2", moved[1].HolderID)
+}
+
+func TestTransfer_CutoffAndStaleOffers(t *testing.T) {
+	f := transferSetup(t)
+	ctx := context.Background()
+
+	// Closer to the start than the cutoff: closed
+	f.repo.tickets = append(f.repo.tickets, &ticket.Ticket{ID: "late", EventID: eventB, HolderID: "u1", Number: 1, Code: "LATE000001", Status: ticket.StatusValid})
+	_, err := f.transfers.Create(ctx, "u1", ticket.TransferRequest{TicketIDs: []string{"late"}, Email: "binh@example.com"})
+	require.ErrorIs(t, err, ticket.ErrTransferClosed)
+
+	// An offer expires at the cutoff when that comes before its 72 hours
+	f.events[eventA].StartsAt = time.Now().Add(30 * time.Hour)
+	tr, err := f.transfers.Create(ctx, "u1", ticket.TransferRequest{BookingID: "b1", Email: "binh@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, 2, tr.Count)
+	require.WithinDuration(t, f.events[eventA].StartsAt.Add(-24*time.Hour), tr.ExpiresAt, time.Second)
+
+	// The sender used a ticket at the door before the recipient accepted
+	_, err = f.tickets.CheckIn(ctx, ticket.Scan{Payload: f.signer.Sign(f.repo.tickets[0]), At: time.Now()})
+	require.NoError(t, err)
+	_, _, err = f.transfers.Accept(ctx, tr.ID, "u2")
+	require.ErrorIs(t, err, ticket.ErrTransferStale)
+	require.Equal(t, ticket.TransferCancelled, tr.Status)
+	require.Nil(t, f.repo.tickets[1].TransferID, "the unused ticket is free to offer again")
+	require.Equal(t, ticket.StatusValid, f.repo.tickets[1].Status)
+
+	// Declined and cancelled offers give the tickets back too
+	tr, err = f.transfers.Create(ctx, "u1", ticket.TransferRequest{BookingID: "b1", Email: "binh@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, 1, tr.Count, "used tickets are not offered")
+	_, err = f.transfers.Decline(ctx, tr.ID, "u1")
+	require.ErrorIs(t, err, ticket.ErrTransferNotFound)
+	_, err = f.transfers.Decline(ctx, tr.ID, "u2")
+	require.NoError(t, err)
+	tr, err = f.transfers.Create(ctx, "u1", ticket.TransferRequest{BookingID: "b1", Email: "chi@example.com"})
+	require.NoError(t, err)
+	_, err = f.transfers.Cancel(ctx, tr.ID, "u3")
+	require.ErrorIs(t, err, ticket.ErrTransferNotFound)
+	_, err = f.transfers.Cancel(ctx, tr.ID, "u1")
+	require.NoError(t, err)
+	_, err = f.transfers.Cancel(ctx, tr.ID, "u1")
+	require.ErrorIs(t, err, ticket.ErrTransferNotPending)
+
+	// Past their expiry offers are listed as EXPIRED
+	tr, err = f.transfers.Create(ctx, "u1", ticket.TransferRequest{BookingID: "b1", Email: "binh@example.com"})
+	require.NoError(t, err)
+	tr.ExpiresAt = time.Now().Add(-time.Minute)
+	incoming, outgoing, err := f.transfers.List(ctx, "u2")
+	require.NoError(t, err)
+	require.Empty(t, outgoing)
+	require.Len(t, incoming, 3)
+	require.Equal(t, ticket.TransferExpired, incoming[0].Status)
+	require.Equal(t, ticket.TransferPending, tr.Status, "listing writes nothing")
+	require.NoError(t, f.transfers.ExpireDue(ctx))
+	require.Equal(t, ticket.TransferExpired, tr.Status)
+	_, _, err = f.transfers.Accept(ctx, tr.ID, "u2")
+	require.ErrorIs(t, err, ticket.ErrTransferNotPending)
+}
+
+func TestTransferHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := transferSetup(t)
+	th := ticket.NewTransferHandler(f.transfers, zap.NewNop())
+	h := ticket.NewHandler(f.tickets, f.bookings, zap.NewNop())
+
+	do := func(method, path, userID string, body any) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(func(c *gin.Context) { c.Set(auth.CtxUserID, userID) })
+		ticket.RegisterRoutes(r.Group(""), h)
+		ticket.RegisterTransferRoutes(r.Group(""), th)
+		data, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewReader(data)))
+		return w
+	}
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/transfers", "u1", map[string]any{"booking_id": "b1"}).Code)
+	w := do(http.MethodPost, "/transfers", "u1", map[string]any{"ticket_ids": []string{f.repo.tickets[0].ID}, "email": "binh@example.com"})
+	require.Equal(t, http.StatusBadRequest, w.Code, "ticket ids are UUIDs")
+
+	f.repo.tickets[0].ID = "3d9a1c7e-2b4f-4e8a-9c6d-1f0e2a3b4c5d"
+	w = do(http.MethodPost, "/transfers", "u1", map[string]any{"ticket_ids": []string{f.repo.tickets[0].ID}, "email": "binh@example.com"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var tr ticket.Transfer
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tr))
+	require.Equal(t, http.StatusConflict,
+		do(http.MethodPost, "/transfers", "u1", map[string]any{"ticket_ids": []string{f.repo.tickets[0].ID}, "email": "chi@example.com"}).Code)
+
+	w = do(http.MethodGet, "/transfers", "u2", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var list ticket.TransferListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	require.Len(t, list.Incoming, 1)
+	require.Empty(t, list.Outgoing)
+
+	require.Equal(t, http.StatusNotFound, do(http.MethodPost, "/transfers/"+tr.ID+"/accept", "u3", nil).Code)
+	require.Equal(t, http.StatusOK, do(http.MethodPost, "/transfers/"+tr.ID+"/accept", "u2", nil).Code)
+	require.Equal(t, http.StatusConflict, do(http.MethodPost, "/transfers/"+tr.ID+"/decline", "u2", nil).Code)
+
+	// The recipient finds the ticket under /tickets; the booking owner no longer sees its code
+	w = do(http.MethodGet, "/tickets", "u2", nil)
+	var held []ticket.TicketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &held))
+	require.Len(t, held, 1)
+	require.NotEmpty(t, held[0].QRCode)
+
+	w = do(http.MethodGet, "/bookings/b1/tickets", "u1", nil)
+	var tickets []ticket.TicketResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &tickets))
+	require.Len(t, tickets, 3)
+	require.Equal(t, "u2", tickets[2].HolderID)
+	require.Empty(t, tickets[2].Code)
+	require.Empty(t, tickets[2].QRCode)
+	require.Equal(t, http.StatusNotFound, do(http.MethodGet, "/bookings/b1/tickets/"+tickets[2].ID+"/qr", "u1", nil).Code)
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	purposeVerifyEmail   = "verify_email"
+	purposePasswordReset = "password_reset"
+	purposeMFAChallenge  = "mfa_challenge"
+	purposeChangeEmail   = "change_email"
+)
+
+// actionClaims back single-use tokens: links in account emails and the
+// challenge between the two login steps. Stamp fingerprints the state the
+// token changes (the email for verification, the password hash for reset,
+// the second factor for a challenge, both addresses for an email change),
+// so it stops working once used.
+type actionClaims struct {
+	Purpose     string `json:"pur"`
+	Stamp       string `json:"stm"`
+	FirstFactor string `json:"ff,omitempty"` // amr of the step before an MFA challenge
+	jwt.RegisteredClaims
+}
+
+// actionTokens signs action tokens with a key derived from a secret and a
+// label, so they can never be confused with tokens signed by the secret
+// itself or with another kind of action token
+type actionTokens struct {
+	key []byte
+}
+
+func newActionTokens(secret, label string) *actionTokens {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(label))
+	return &actionTokens{key: mac.Sum(nil)}
+}
+
+func stampFor(purpose string, u *User) string {
+	var src string
+	switch purpose {
+	case purposeVerifyEmail:
+		src = strings.ToLower(u.Email)
+	case purposePasswordReset:
+		src = u.PasswordHash
+	case purposeChangeEmail:
+		src = strings.ToLower(u.Email)
+		if u.PendingEmail != nil {
+			src += ">" + strings.ToLower(*u.PendingEmail)
+		}
+	case purposeMFAChallenge:
+		src = u.PasswordHash + ":" + u.TOTPSecret + ":" + strconv.FormatInt(u.TOTPLastStep, 10) + ":" + strings.Join(u.RecoveryCodes, ",")
+	}
+	sum := sha256.Sum256([]byte(purpose + ":" + src))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (a *actionTokens) issue(purpose string, u *User, ttl time.Duration) (string, error) {
+	return a.sign(a.claims(purpose, u, ttl))
+}
+
+// issueChallenge issues the token between a first login factor and the TOTP step
+func (a *actionTokens) issueChallenge(u *User, ttl time.Duration, firstFactor string) (string, error) {
+	claims := a.claims(purposeMFAChallenge, u, ttl)
+	claims.FirstFactor = firstFactor
+	return a.sign(claims)
+}
+
+func (a *actionTokens) claims(purpose string, u *User, ttl time.Duration) actionClaims {
+	now := time.Now()
+	return actionClaims{
+		Purpose: purpose,
+		Stamp:   stampFor(purpose, u),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   u.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+}
+
+func (a *actionTokens) sign(claims actionClaims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(a.key)
+}
+
+// parse returns the claims of a token issued for purpose
+func (a *actionTokens) parse(purpose, token string) (*actionClaims, error) {
+	claims := &actionClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return a.key, nil },
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Purpose != purpose || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// valid reports whether the token still matches the user's current state
+func (c *actionClaims) valid(u *User) bool {
+	return hmac.Equal([]byte(c.Stamp), []byte(stampFor(c.Purpose, u)))
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAccountDeleted = Err("account deleted")
+	ErrUnknownRole    = Err("unknown role")
+	ErrOwnAccount     = Err("admins cannot change the role of or suspend their own account")
+	// ErrSessionsNotRevoked reports a role change whose old sessions still run
+	ErrSessionsNotRevoked = Err("role changed, but the user's sessions could not be ended")
+)
+
+// ListUsers returns accounts matching f, newest first
+func (s *Service) ListUsers(ctx context.Context, f UserFilter) ([]*User, error) {
+	users, err := s.repo.List(f)
+	if err != nil {
+		s.logger.Error("Failed to list users", zap.Error(err))
+		return nil, err
+	}
+	return users, nil
+}
+
+// SetRole gives a user another role and returns the previous one. The caller
+// checks role exists; actorID may not change their own role.
+func (s *Service) SetRole(ctx context.Context, actorID, id, role string) (string, error) {
+	if actorID == id {
+		return "", ErrOwnAccount
+	}
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		return "", err
+	}
+	if u.AnonymizedAt != nil {
+		return "", ErrAccountDeleted
+	}
+	from := u.Role
+	u.Role = role
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to change role", zap.String("user_id", id), zap.Error(err))
+		return "", err
+	}
+	s.logger.Info("User role changed", zap.String("user_id", id), zap.String("from", from), zap.String("to", role), zap.String("by", actorID))
+	return from, nil
+}
+
+// Suspend disables an account; login and refresh are refused until Reactivate
+func (s *Service) Suspend(ctx context.Context, actorID, id string) (*User, error) {
+	if actorID == id {
+		return nil, ErrOwnAccount
+	}
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if u.AnonymizedAt != nil {
+		return nil, ErrAccountDeleted
+	}
+	if u.DisabledAt == nil {
+		now := time.Now()
+		u.DisabledAt = &now
+		if err := s.repo.Update(u); err != nil {
+			s.logger.Error("Failed to suspend user", zap.String("user_id", id), zap.Error(err))
+			return nil, err
+		}
+	}
+	s.logger.Info("User suspended", zap.String("user_id", id), zap.String("by", actorID))
+	return u, nil
+}
+
+// Reactivate lifts a suspension. Deleted accounts cannot come back.
+func (s *Service) Reactivate(ctx context.Context, id string) (*User, error) {
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if u.AnonymizedAt != nil {
+		return nil, ErrAccountDeleted
+	}
+	if u.DisabledAt != nil {
+		u.DisabledAt = nil
+		if err := s.repo.Update(u); err != nil {
+			s.logger.Error("Failed to reactivate user", zap.String("user_id", id), zap.Error(err))
+			return nil, err
+		}
+	}
+	s.logger.Info("User reactivated", zap.String("user_id", id))
+	return u, nil
+}
+
+// ForcePasswordReset replaces the password with one nobody knows and mails
+// the user a reset link, so the old password stops working at once. A failed
+// email is logged and does not undo the reset
+func (s *Service) ForcePasswordReset(ctx context.Context, id string) error {
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		return err
+	}
+	if u.AnonymizedAt != nil {
+		return ErrAccountDeleted
+	}
+	if u.DisabledAt != nil {
+		return ErrAccountDisabled
+	}
+	hash, err := unusablePassword()
+	if err != nil {
+		return err
+	}
+	u.PasswordHash = hash
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to clear password", zap.String("user_id", id), zap.Error(err))
+		return err
+	}
+	// The password is gone either way; the user can still ask for a link
+	// through the forgotten password flow
+	if err := s.emails.sendPasswordReset(ctx, u); err != nil {
+		s.logger.Error("Failed to send password reset email", zap.String("user_id", id), zap.Error(err))
+	}
+	s.logger.Info("Password reset forced", zap.String("user_id", id))
+	return nil
+}
@@ -0,0 +1,321 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/rbac"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// RoleLookup finds a role by name; *rbac.Service satisfies it
+type RoleLookup interface {
+	Get(ctx context.Context, name string) (*rbac.Role, error)
+}
+
+// AccountBooking is one of a user's bookings as the admin user API lists it
+type AccountBooking struct {
+	ID       string
+	EventID  string
+	Quantity int
+	Status   string
+}
+
+// UserBookings lists a user's bookings, newest first. The booking service
+// backs it through an adapter in the router package, so user does not
+// depend on booking.
+type UserBookings interface {
+	ListByUser(ctx context.Context, userID string, limit, offset int) ([]AccountBooking, error)
+}
+
+// AdminHandler serves /admin/users. Every change is written to the audit log.
+type AdminHandler struct {
+	svc      *Service
+	sessions *auth.Sessions
+	roles    RoleLookup
+	bookings UserBookings
+	audit    *audit.Service
+	logger   *zap.Logger
+}
+
+// NewAdminHandler serves admin user management with h's service and sessions
+func NewAdminHandler(h *Handler, roles RoleLookup, bookings UserBookings, a *audit.Service) *AdminHandler {
+	return &AdminHandler{svc: h.svc, sessions: h.sessions, roles: roles, bookings: bookings, audit: a, logger: h.logger}
+}
+
+func adminUserResponse(u *User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:               u.ID,
+		Email:            u.Email,
+		FullName:         u.FullName,
+		Phone:            u.Phone,
+		Role:             u.Role,
+		Status:           u.Status(),
+		EmailVerified:    u.EmailVerifiedAt != nil,
+		TwoFactorEnabled: u.TOTPEnabledAt != nil,
+		DisabledAt:       u.DisabledAt,
+		CreatedAt:        u.CreatedAt,
+		UpdatedAt:        u.UpdatedAt,
+	}
+}
+
+// pagination reads limit and offset, defaulting to 50 and capping at 200
+func pagination(c *gin.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// ===== List =====
+// @Summary List users
+// @Description Search accounts by email or name, newest first (requires users:manage)
+// @Tags admin-users
+// @Produce json
+// @Param q query string false "Case-insensitive match on email or full name"
+// @Param role query string false "Only this role"
+// @Param status query string false "active, unverified, suspended or deleted"
+// @Param limit query int false "Max items to return (default 50, max 200)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users [get]
+func (a *AdminHandler) List(c *gin.Context) {
+	f := UserFilter{Query: strings.TrimSpace(c.Query("q")), Role: c.Query("role"), Status: c.Query("status")}
+	switch f.Status {
+	case "", StatusActive, StatusUnverified, StatusSuspended, StatusDeleted:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid status"})
+		return
+	}
+	f.Limit, f.Offset = pagination(c)
+
+	users, err := a.svc.ListUsers(c, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]AdminUserResponse, 0, len(users))
+	for _, u := range users {
+		out = append(out, adminUserResponse(u))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ===== Get =====
+// @Summary Get user
+// @Description Account details with the user's bookings, newest first (requires users:manage)
+// @Tags admin-users
+// @Produce json
+// @Param id path string true "User ID"
+// @Param limit query int false "Max bookings to return (default 50, max 200)"
+// @Param offset query int false "Offset into the bookings (default 0)"
+// @Success 200 {object} AdminUserDetailResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id} [get]
+func (a *AdminHandler) Get(c *gin.Context) {
+	u, err := a.svc.Get(c, c.Param("id"))
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	limit, offset := pagination(c)
+	bookings, err := a.bookings.ListByUser(c, u.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := AdminUserDetailResponse{AdminUserResponse: adminUserResponse(u), Bookings: make([]AdminBookingResponse, 0, len(bookings))}
+	for _, b := range bookings {
+		out.Bookings = append(out.Bookings, AdminBookingResponse{ID: b.ID, EventID: b.EventID, UserID: u.ID, Quantity: b.Quantity, Status: b.Status})
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// ===== SetRole =====
+// @Summary Change user role
+// @Description Give a user another role from the roles table. The user's sessions end so the new permissions apply from their next login (requires users:manage).
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param input body SetRoleRequest true "New role"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse "Unknown role"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Own or deleted account"
+// @Failure 500 {object} ErrorResponse "Role changed, but the user's sessions could not be ended"
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [put]
+func (a *AdminHandler) SetRole(c *gin.Context) {
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	if _, err := a.roles.Get(c, req.Role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: ErrUnknownRole.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+
+	id := c.Param("id")
+	from, err := a.svc.SetRole(c, c.GetString(auth.CtxUserID), id, req.Role)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	var revokeErr error
+	if from != req.Role {
+		if revokeErr = a.sessions.RevokeAll(c, id); revokeErr != nil {
+			a.logger.Error("Failed to revoke sessions after role change", zap.String("user_id", id), zap.Error(revokeErr))
+		}
+	}
+	a.record(c, &audit.Entry{
+		Action: audit.ActionUserRole, TargetType: "user", TargetID: id,
+		Changes: audit.Changes{"role": {From: from, To: req.Role}},
+	})
+	if revokeErr != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: ErrSessionsNotRevoked.Error()})
+		return
+	}
+	a.respond(c, id)
+}
+
+// ===== Suspend =====
+// @Summary Suspend user
+// @Description Block the account: every token is rejected at once and login and refresh are refused until reactivation (requires users:manage)
+// @Tags admin-users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param input body SuspendRequest false "Reason, kept in the audit log"
+// @Success 200 {object} AdminUserResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Own or deleted account"
+// @Security BearerAuth
+// @Router /admin/users/{id}/suspend [post]
+func (a *AdminHandler) Suspend(c *gin.Context) {
+	var req SuspendRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+	id := c.Param("id")
+	u, err := a.svc.Suspend(c, c.GetString(auth.CtxUserID), id)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	if err := a.sessions.Suspend(c, id); err != nil {
+		a.logger.Error("Failed to end sessions of suspended user", zap.String("user_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	detail := map[string]any{}
+	if req.Reason != "" {
+		detail["reason"] = req.Reason
+	}
+	a.record(c, &audit.Entry{Action: audit.ActionUserSuspend, TargetType: "user", TargetID: id, Detail: detail})
+	c.JSON(http.StatusOK, adminUserResponse(u))
+}
+
+// ===== Reactivate =====
+// @Summary Reactivate user
+// @Description Lift a suspension; the user logs in again (requires users:manage)
+// @Tags admin-users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} AdminUserResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Deleted account"
+// @Security BearerAuth
+// @Router /admin/users/{id}/reactivate [post]
+func (a *AdminHandler) Reactivate(c *gin.Context) {
+	id := c.Param("id")
+	u, err := a.svc.Reactivate(c, id)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	if err := a.sessions.Reactivate(c, id); err != nil {
+		a.logger.Error("Failed to lift session suspension", zap.String("user_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	a.record(c, &audit.Entry{Action: audit.ActionUserReactivate, TargetType: "user", TargetID: id})
+	c.JSON(http.StatusOK, adminUserResponse(u))
+}
+
+// ===== ForcePasswordReset =====
+// @Summary Force password reset
+// @Description Invalidate the password, end every session and email the user a reset link; a failed email is logged and the user can still use the forgotten password flow (requires users:manage)
+// @Tags admin-users
+// @Param id path string true "User ID"
+// @Success 202 "Accepted"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Suspended or deleted account"
+// @Security BearerAuth
+// @Router /admin/users/{id}/password-reset [post]
+func (a *AdminHandler) ForcePasswordReset(c *gin.Context) {
+	id := c.Param("id")
+	if err := a.svc.ForcePasswordReset(c, id); err != nil {
+		a.fail(c, err)
+		return
+	}
+	if err := a.sessions.RevokeAll(c, id); err != nil {
+		a.logger.Error("Failed to revoke sessions after forced reset", zap.String("user_id", id), zap.Error(err))
+	}
+	a.record(c, &audit.Entry{Action: audit.ActionUserPasswordReset, TargetType: "user", TargetID: id})
+	c.Status(http.StatusAccepted)
+}
+
+// record writes e to the audit log. The change it describes is already
+// made, so a lost entry is logged with the admin who made it rather than
+// failing the request.
+func (a *AdminHandler) record(c *gin.Context, e *audit.Entry) {
+	if err := a.audit.Record(c, e); err != nil {
+		a.logger.Error("Admin user change not audited", zap.String("action", e.Action),
+			zap.String("user_id", e.TargetID), zap.String("by", c.GetString(auth.CtxUserID)), zap.Error(err))
+	}
+}
+
+func (a *AdminHandler) respond(c *gin.Context, id string) {
+	u, err := a.svc.Get(c, id)
+	if err != nil {
+		a.fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, adminUserResponse(u))
+}
+
+func (a *AdminHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+	case errors.Is(err, ErrOwnAccount), errors.Is(err, ErrAccountDeleted), errors.Is(err, ErrAccountDisabled):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
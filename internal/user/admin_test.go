@@ -0,0 +1,180 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/rbac"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/mail"
+)
+
+type knownRoles []string
+
+func (k knownRoles) Get(_ context.Context, name string) (*rbac.Role, error) {
+	for _, r := range k {
+		if r == name {
+			return &rbac.Role{Name: r}, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fixedBookings holds each user's bookings
+type fixedBookings map[string][]user.AccountBooking
+
+func (f fixedBookings) ListByUser(_ context.Context, userID string, _, _ int) ([]user.AccountBooking, error) {
+	return f[userID], nil
+}
+
+// adminUsers serves /admin/users with every role and u1's bookings
+var adminUsers = withAdminUsers(knownRoles{"USER", "ORGANIZER", "ADMIN"},
+	fixedBookings{"u1": {{ID: "b1", EventID: "e1", Quantity: 2, Status: "CONFIRMED"}}})
+
+func TestAdminUsers_ListAndGet(t *testing.T) {
+	repo := newMemRepo()
+	r, cfg, _ := newTestRouter(t, repo, adminUsers)
+	token := seedAdmin(t, repo, cfg)
+	seedUser(t, r, repo, "u1", "jane@example.com", "password123")
+	repo.users["u2"] = &user.User{ID: "u2", Email: "pending@example.com", Role: "USER"}
+
+	w := sendJSON(r, http.MethodGet, "/admin/users?status=unverified", token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	list := decode[[]user.AdminUserResponse](t, w)
+	require.Len(t, list, 1)
+	require.Equal(t, "u2", list[0].ID)
+
+	w = sendJSON(r, http.MethodGet, "/admin/users?q=JANE", token, nil)
+	require.Len(t, decode[[]user.AdminUserResponse](t, w), 1)
+	require.Equal(t, http.StatusBadRequest, sendJSON(r, http.MethodGet, "/admin/users?status=nope", token, nil).Code)
+
+	w = sendJSON(r, http.MethodGet, "/admin/users/u1", token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	detail := decode[user.AdminUserDetailResponse](t, w)
+	require.Equal(t, "jane@example.com", detail.Email)
+	require.Equal(t, user.StatusActive, detail.Status)
+	require.Len(t, detail.Bookings, 1)
+	require.Equal(t, "b1", detail.Bookings[0].ID)
+
+	require.Equal(t, http.StatusNotFound, sendJSON(r, http.MethodGet, "/admin/users/missing", token, nil).Code)
+
+	// Regular users are kept out
+	mine := seedUser(t, r, repo, "u3", "user@example.com", "password123")
+	require.Equal(t, http.StatusForbidden, sendJSON(r, http.MethodGet, "/admin/users", mine.AccessToken, nil).Code)
+}
+
+func TestAdminUsers_SetRole(t *testing.T) {
+	repo := newMemRepo()
+	var log []audit.Entry
+	r, cfg, _ := newTestRouter(t, repo, adminUsers, withAudit(&log))
+	token := seedAdmin(t, repo, cfg)
+	tokens := seedUser(t, r, repo, "u1", "jane@example.com", "password123")
+	// Revocation has one-second granularity; tokens from the same second survive it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	w := sendJSON(r, http.MethodPut, "/admin/users/u1/role", token, user.SetRoleRequest{Role: "WIZARD"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	w = sendJSON(r, http.MethodPut, "/admin/users/admin/role", token, user.SetRoleRequest{Role: "USER"})
+	require.Equal(t, http.StatusConflict, w.Code, "admins cannot demote themselves")
+
+	w = sendJSON(r, http.MethodPut, "/admin/users/u1/role", token, user.SetRoleRequest{Role: "ORGANIZER"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "ORGANIZER", decode[user.AdminUserResponse](t, w).Role)
+	require.Equal(t, "ORGANIZER", repo.users["u1"].Role)
+
+	// Old tokens carry the old role, so they end
+	require.Equal(t, http.StatusUnauthorized, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil).Code)
+
+	require.Len(t, log, 1)
+	require.Equal(t, audit.ActionUserRole, log[0].Action)
+	require.Equal(t, "u1", log[0].TargetID)
+	require.Equal(t, "admin", *log[0].ActorID)
+	require.Equal(t, audit.Changes{"role": {From: "USER", To: "ORGANIZER"}}, log[0].Changes)
+}
+
+func TestAdminUsers_SuspendAndReactivate(t *testing.T) {
+	repo := newMemRepo()
+	var log []audit.Entry
+	r, cfg, _ := newTestRouter(t, repo, adminUsers, withAudit(&log))
+	token := seedAdmin(t, repo, cfg)
+	tokens := seedUser(t, r, repo, "u1", "jane@example.com", "password123")
+
+	w := postJSONAuth(r, "/admin/users/u1/suspend", token, user.SuspendRequest{Reason: "fraud"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, user.StatusSuspended, decode[user.AdminUserResponse](t, w).Status)
+
+	// Tokens are refused at once, even one issued in the same second
+	w = sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "account suspended")
+	require.Equal(t, http.StatusUnauthorized, postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken}).Code)
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "jane@example.com", Password: "password123"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postJSONAuth(r, "/admin/users/u1/reactivate", token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, user.StatusActive, decode[user.AdminUserResponse](t, w).Status)
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "jane@example.com", Password: "password123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	fresh := decode[user.LoginResponse](t, w)
+	require.Equal(t, http.StatusOK, sendJSON(r, http.MethodGet, "/users/me", fresh.AccessToken, nil).Code)
+
+	require.Equal(t, http.StatusConflict, postJSONAuth(r, "/admin/users/admin/suspend", token, nil).Code)
+
+	require.Len(t, log, 2)
+	require.Equal(t, audit.ActionUserSuspend, log[0].Action)
+	require.Equal(t, "fraud", log[0].Detail["reason"])
+	require.Equal(t, audit.ActionUserReactivate, log[1].Action)
+}
+
+func TestAdminUsers_ForcePasswordReset(t *testing.T) {
+	repo := newMemRepo()
+	var log []audit.Entry
+	r, cfg, outbox := newTestRouter(t, repo, adminUsers, withAudit(&log))
+	token := seedAdmin(t, repo, cfg)
+	seedUser(t, r, repo, "u1", "jane@example.com", "password123")
+
+	w := postJSONAuth(r, "/admin/users/u1/password-reset", token, nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "jane@example.com", Password: "password123"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	reset := lastToken(t, outbox, "jane@example.com")
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: reset, Password: "n3w-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "jane@example.com", Password: "n3w-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.Len(t, log, 1)
+	require.Equal(t, audit.ActionUserPasswordReset, log[0].Action)
+}
+
+// downMailer fails every send, like an unreachable SMTP server
+type downMailer struct{}
+
+func (downMailer) Send(context.Context, mail.Message) error { return errors.New("smtp down") }
+
+func TestAdminUsers_ForcePasswordResetWithoutMail(t *testing.T) {
+	repo := newMemRepo()
+	var log []audit.Entry
+	r, cfg, _ := newTestRouter(t, repo, adminUsers, withAudit(&log), withMailer(downMailer{}))
+	token := seedAdmin(t, repo, cfg)
+	tokens := seedUser(t, r, repo, "u1", "jane@example.com", "password123")
+	// Revocation has one-second granularity; tokens from the same second survive it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	// The password is gone, so the sessions end and the change is audited all the same
+	w := postJSONAuth(r, "/admin/users/u1/password-reset", token, nil)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	require.Equal(t, http.StatusUnauthorized, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil).Code)
+	require.Len(t, log, 1)
+	require.Equal(t, audit.ActionUserPasswordReset, log[0].Action)
+}
@@ -1,5 +1,7 @@
 package user
 
+import "time"
+
 // RegisterRequest represents input for user registration
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
@@ -17,15 +19,75 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required,min=8,max=64" example:"secret123"`
 }
 
-// LoginResponse represents login success with token
+// LoginResponse represents login success with token. With two-factor on, the
+// password step returns only MFARequired and ChallengeToken.
 type LoginResponse struct {
-	AccessToken  string `json:"accessToken" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	RefreshToken string `json:"refreshToken" example:"dGhpc19pc19hX3NhbXBsZV9yZWZyZXNoX3Rva2Vu"`
+	AccessToken           string `json:"accessToken,omitempty" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken          string `json:"refreshToken,omitempty" example:"dGhpc19pc19hX3NhbXBsZV9yZWZyZXNoX3Rva2Vu"`
+	MFARequired           bool   `json:"mfaRequired,omitempty" example:"false"`
+	ChallengeToken        string `json:"challengeToken,omitempty"`                        // For /users/login/2fa
+	MFAEnrollmentRequired bool   `json:"mfaEnrollmentRequired,omitempty" example:"false"` // Role needs two-factor for admin routes
+}
+
+// TwoFactorLoginRequest completes a login that returned mfaRequired
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required" example:"123456"` // TOTP or recovery code
+}
+
+// TOTPCodeRequest carries a code from the authenticator app, or a recovery code where accepted
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required" example:"123456"`
+}
+
+// TOTPEnrollmentResponse is what an authenticator app needs to add the account
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURL string `json:"otpauthUrl" example:"otpauth://totp/Ticket%20Booking:john@example.com?issuer=Ticket%20Booking&secret=JBSWY3DPEHPK3PXP"`
+	QRCode     string `json:"qrCode" example:"data:image/png;base64,iVBORw0KGgo..."` // PNG data URI of otpauthUrl
+}
+
+// RecoveryCodesResponse lists single-use recovery codes
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes" example:"k3jd9-x8a2p"`
 }
 
-// UpdateProfileRequest represents input for updating profile
+// UpdateProfileRequest represents input for updating profile. Omitted fields are left as they are.
 type UpdateProfileRequest struct {
-	FullName *string `json:"full_name" binding:"omitempty,min=2,max=100" example:"John Doe"`
+	FullName      *string                  `json:"full_name" binding:"omitempty,min=2,max=100" example:"John Doe"`
+	Phone         *string                  `json:"phone" example:"+44 20 7946 0958"` // Empty string removes the number
+	Notifications *NotificationPreferences `json:"notifications"`
+}
+
+// ProfileResponse is the caller's own account
+type ProfileResponse struct {
+	ID               string                  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email            string                  `json:"email" example:"john@example.com"`
+	PendingEmail     *string                 `json:"pending_email,omitempty" example:"john.doe@example.com"` // Awaiting confirmation
+	FullName         *string                 `json:"full_name,omitempty" example:"John Doe"`
+	Phone            *string                 `json:"phone,omitempty" example:"+442079460958"`
+	Role             string                  `json:"role" example:"USER"`
+	EmailVerified    bool                    `json:"email_verified" example:"true"`
+	TwoFactorEnabled bool                    `json:"two_factor_enabled" example:"false"`
+	Notifications    NotificationPreferences `json:"notifications"`
+	CreatedAt        time.Time               `json:"created_at"`
+}
+
+// ChangeEmailRequest asks to move the account to a new address
+type ChangeEmailRequest struct {
+	Email    string `json:"email" binding:"required,email" example:"john.doe@example.com"`
+	Password string `json:"password" binding:"required" example:"secret123"` // Current password
+}
+
+// ChangePasswordRequest replaces the password of a logged-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"secret123"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=64" example:"n3w-secret"`
+}
+
+// DeleteAccountRequest confirms account deletion with the current password
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required" example:"secret123"`
 }
 
 // UpdateProfileResponse represents success response for profile update
@@ -42,3 +104,69 @@ type ErrorResponse struct {
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" binding:"required" example:"dGhpc19pc19hX3NhbXBsZV9yZWZyZXNoX3Rva2Vu"`
 }
+
+// LogoutRequest optionally names the refresh token to revoke with the access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken" example:"dGhpc19pc19hX3NhbXBsZV9yZWZyZXNoX3Rva2Vu"`
+}
+
+// VerifyEmailRequest carries the token from a verification link
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EmailRequest names the account for resend-verification and forgot-password
+type EmailRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// ResetPasswordRequest carries the token from a reset link and the new password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8,max=64" example:"n3w-secret"`
+}
+
+// OKResponse acknowledges an action
+type OKResponse struct {
+	OK bool `json:"ok" example:"true"`
+}
+
+// AdminUserResponse is an account as seen in the admin user API
+type AdminUserResponse struct {
+	ID               string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email            string     `json:"email" example:"john@example.com"`
+	FullName         *string    `json:"full_name,omitempty" example:"John Doe"`
+	Phone            *string    `json:"phone,omitempty" example:"+442079460958"`
+	Role             string     `json:"role" example:"USER"`
+	Status           string     `json:"status" example:"active"` // active, unverified, suspended or deleted
+	EmailVerified    bool       `json:"email_verified" example:"true"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled" example:"false"`
+	DisabledAt       *time.Time `json:"disabled_at,omitempty"` // When suspended or deleted
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// AdminBookingResponse is one of the bookings shown with an account
+type AdminBookingResponse struct {
+	ID       string `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	EventID  string `json:"event_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID   string `json:"user_id" example:"42e1d21e-1111-2222-3333-444455556666"`
+	Quantity int    `json:"quantity" example:"2"`
+	Status   string `json:"status" example:"CONFIRMED"`
+}
+
+// AdminUserDetailResponse is an account with its bookings, newest first
+type AdminUserDetailResponse struct {
+	AdminUserResponse
+	Bookings []AdminBookingResponse `json:"bookings"`
+}
+
+// SetRoleRequest names the new role of a user
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required" example:"ORGANIZER"`
+}
+
+// SuspendRequest optionally explains a suspension
+type SuspendRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"Chargeback fraud"`
+}
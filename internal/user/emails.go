@@ -0,0 +1,87 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+// Lifetimes of the links sent by email
+const (
+	VerifyEmailTTL   = 48 * time.Hour
+	PasswordResetTTL = time.Hour
+	ChangeEmailTTL   = 24 * time.Hour
+)
+
+// AccountEmails issues signed, expiring single-use links and mails them.
+type AccountEmails struct {
+	tokens   *actionTokens
+	mailer   mail.Mailer
+	tmpl     *mail.Templates
+	linkBase string
+}
+
+// NewAccountEmails signs links with a key derived from secret
+func NewAccountEmails(secret string, mailer mail.Mailer, cfg config.Email) *AccountEmails {
+	return &AccountEmails{
+		tokens:   newActionTokens(secret, "user-action-tokens"),
+		mailer:   mailer,
+		tmpl:     mail.NewTemplates(cfg.TemplateDir),
+		linkBase: strings.TrimRight(cfg.LinkBaseURL, "/"),
+	}
+}
+
+func (a *AccountEmails) send(ctx context.Context, purpose, path, to string, u *User, ttl time.Duration) error {
+	token, err := a.tokens.issue(purpose, u, ttl)
+	if err != nil {
+		return err
+	}
+	msg, err := a.tmpl.Render(purpose, to, map[string]any{
+		"Link":      a.linkBase + path + "?token=" + url.QueryEscape(token),
+		"ExpiresIn": humanDuration(ttl),
+	})
+	if err != nil {
+		return err
+	}
+	return a.mailer.Send(ctx, msg)
+}
+
+func (a *AccountEmails) sendVerification(ctx context.Context, u *User) error {
+	return a.send(ctx, purposeVerifyEmail, "/verify-email", u.Email, u, VerifyEmailTTL)
+}
+
+func (a *AccountEmails) sendPasswordReset(ctx context.Context, u *User) error {
+	return a.send(ctx, purposePasswordReset, "/reset-password", u.Email, u, PasswordResetTTL)
+}
+
+// sendEmailChange mails the confirmation link to the pending address and a
+// notice to the current one, so a hijacked session cannot move the account
+// away unnoticed
+func (a *AccountEmails) sendEmailChange(ctx context.Context, u *User) error {
+	if err := a.send(ctx, purposeChangeEmail, "/confirm-email", *u.PendingEmail, u, ChangeEmailTTL); err != nil {
+		return err
+	}
+	msg, err := a.tmpl.Render("email_change_notice", u.Email, map[string]any{"NewEmail": *u.PendingEmail})
+	if err != nil {
+		return err
+	}
+	return a.mailer.Send(ctx, msg)
+}
+
+func humanDuration(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	case d == time.Hour:
+		return "1 hour"
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%d hours", d/time.Hour)
+	default:
+		return d.String()
+	}
+}
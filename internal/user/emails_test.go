@@ -0,0 +1,127 @@
+package user_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"ticket-booking/internal/user"
+)
+
+func TestEmailVerification_Flow(t *testing.T) {
+	repo := newMemRepo()
+	r, _, outbox := newTestRouter(t, repo)
+	creds := user.LoginRequest{Email: "new@example.com", Password: "password123"}
+
+	w := postJSON(r, "/users/register", user.RegisterRequest{Email: creds.Email, Password: creds.Password})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Len(t, outbox.Messages(), 1)
+	require.Equal(t, "Confirm your email address", outbox.Messages()[0].Subject)
+
+	// Login is refused until the link is followed
+	w = postJSON(r, "/users/login", creds)
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postJSON(r, "/users/verify/resend", user.EmailRequest{Email: creds.Email})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, outbox.Messages(), 2)
+
+	token := lastToken(t, outbox, creds.Email)
+	w = postJSON(r, "/users/verify", user.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = postJSON(r, "/users/login", creds)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// A used link does not work twice
+	w = postJSON(r, "/users/verify", user.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Nothing is sent once verified, and nothing for unknown addresses
+	require.Equal(t, http.StatusAccepted, postJSON(r, "/users/verify/resend", user.EmailRequest{Email: creds.Email}).Code)
+	require.Equal(t, http.StatusAccepted, postJSON(r, "/users/verify/resend", user.EmailRequest{Email: "nobody@example.com"}).Code)
+	require.Len(t, outbox.Messages(), 2)
+}
+
+func TestPasswordReset_Flow(t *testing.T) {
+	repo := newMemRepo()
+	r, _, outbox := newTestRouter(t, repo)
+	email := "reset@example.com"
+
+	w := postJSON(r, "/users/register", user.RegisterRequest{Email: email, Password: "old-password"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	// Unknown addresses look the same to the caller but get no email
+	w = postJSON(r, "/users/password/forgot", user.EmailRequest{Email: "nobody@example.com"})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, outbox.Messages(), 1)
+
+	w = postJSON(r, "/users/password/forgot", user.EmailRequest{Email: email})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	require.Len(t, outbox.Messages(), 2)
+	token := lastToken(t, outbox, email)
+
+	// A verification token is not a reset token
+	verifyLink := linkRe.FindString(outbox.Messages()[0].Body)
+	verifyURL, _ := url.Parse(verifyLink)
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: verifyURL.Query().Get("token"), Password: "new-password"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: token, Password: "new-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	// The link dies with the old password
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: token, Password: "another-password"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Resetting proves the address, so the new password logs in straight away
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: email, Password: "old-password"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: email, Password: "new-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+// staleRepo answers ByID from a snapshot, as a reset that read the user
+// before a concurrent one wrote would see it
+type staleRepo struct {
+	*memRepo
+	snapshot map[string]*user.User
+}
+
+func (s *staleRepo) ByID(id string) (*user.User, error) {
+	u, ok := s.snapshot[id]
+	if !ok {
+		return s.memRepo.ByID(id)
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func TestPasswordReset_ConcurrentUseOfOneLink(t *testing.T) {
+	repo := newMemRepo()
+	stale := &staleRepo{memRepo: repo}
+	r, _, outbox := newTestRouter(t, stale)
+	email := "race@example.com"
+
+	w := postJSON(r, "/users/register", user.RegisterRequest{Email: email, Password: "old-password"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = postJSON(r, "/users/password/forgot", user.EmailRequest{Email: email})
+	require.Equal(t, http.StatusAccepted, w.Code)
+	token := lastToken(t, outbox, email)
+
+	stale.snapshot = map[string]*user.User{}
+	for id, u := range repo.users {
+		cp := *u
+		stale.snapshot[id] = &cp
+	}
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: token, Password: "first-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	// The second reset still reads the old hash, so its token looks valid
+	w = postJSON(r, "/users/password/reset", user.ResetPasswordRequest{Token: token, Password: "second-password"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: email, Password: "first-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -1,30 +1,50 @@
 package user
 
 import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"ticket-booking/internal/auth"
 	"ticket-booking/pkg/config"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// PermissionResolver maps a role to the permissions embedded in access tokens
+type PermissionResolver interface {
+	PermissionsFor(ctx context.Context, role string) ([]string, error)
+}
+
 // Handler handles user-related HTTP requests
 type Handler struct {
-	svc    *Service
-	cfg    *config.Security
-	logger *zap.Logger
+	svc      *Service
+	cfg      *config.Security
+	keys     *auth.KeySet // nil signs access tokens with the HS256 secret
+	perms    PermissionResolver
+	sessions *auth.Sessions
+	guard    *LoginGuard
+	mfa      *actionTokens // Challenges between the password and code login steps
+	logger   *zap.Logger
 }
 
 // NewHandler creates a new Handler
-func NewHandler(s *Service, cfg *config.Security, logger *zap.Logger) *Handler {
-	return &Handler{svc: s, cfg: cfg, logger: logger}
+func NewHandler(s *Service, cfg *config.Security, keys *auth.KeySet, perms PermissionResolver, sessions *auth.Sessions, guard *LoginGuard, logger *zap.Logger) *Handler {
+	return &Handler{
+		svc: s, cfg: cfg, keys: keys, perms: perms, sessions: sessions, guard: guard,
+		mfa:    newActionTokens(cfg.JWTRefreshSecret, "user-mfa-challenge"),
+		logger: logger,
+	}
 }
 
 // ===== Register =====
 // @Summary Register new user
-// @Description Create a new user account
+// @Description Create a new user account and email a verification link. Login is refused until the email is verified.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -54,7 +74,7 @@ func (h *Handler) Register(c *gin.Context) {
 
 // ===== Login =====
 // @Summary User login
-// @Description Authenticate user and return JWT access & refresh tokens
+// @Description Authenticate user and return JWT access & refresh tokens. With two-factor authentication on, returns mfaRequired and a challengeToken for /users/login/2fa instead. Repeated failures lock the account or client IP for a growing period.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -62,6 +82,8 @@ func (h *Handler) Register(c *gin.Context) {
 // @Success 200 {object} LoginResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Account disabled or email not verified"
+// @Failure 429 {object} ErrorResponse "Locked out; see Retry-After"
 // @Router /users/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -71,30 +93,170 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	attempt, ok := h.attempt(c, req.Email)
+	if !ok {
+		return
+	}
+
 	u, err := h.svc.VerifyLogin(c, req.Email, req.Password)
+	if errors.Is(err, ErrAccountDisabled) || errors.Is(err, ErrEmailNotVerified) {
+		h.guard.Release(c, attempt)
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Warn("Login attempt failed", zap.String("email", req.Email), zap.Error(err))
+		if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, gorm.ErrRecordNotFound) {
+			h.guard.Fail(c, attempt)
+		} else {
+			h.guard.Release(c, attempt)
+		}
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid credentials"})
 		return
 	}
+	h.guard.Succeed(c, attempt)
+	h.completeLogin(c, u, auth.AMRPassword)
+}
+
+// completeLogin issues tokens after a first factor, or a challenge for
+// /users/login/2fa when the user has two-factor on
+func (h *Handler) completeLogin(c *gin.Context, u *User, firstFactor string) {
+	if u.TOTPEnabledAt == nil {
+		h.startSession(c, u, []string{firstFactor})
+		return
+	}
+	challenge, err := h.mfa.issueChallenge(u, config.DefaultMFAChallengeTTL, firstFactor)
+	if err != nil {
+		h.logger.Error("Failed to issue MFA challenge", zap.String("user_id", u.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
+		return
+	}
+	h.logger.Info("First factor accepted, awaiting second factor", zap.String("user_id", u.ID), zap.String("first_factor", firstFactor))
+	c.JSON(http.StatusOK, LoginResponse{MFARequired: true, ChallengeToken: challenge})
+}
+
+// ===== LoginTwoFactor =====
+// @Summary Complete two-factor login
+// @Description Exchange the challenge token from /users/login (or an OIDC callback) and a TOTP or recovery code for JWT access & refresh tokens. Failed codes count towards the login lockout.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body TwoFactorLoginRequest true "Challenge and code"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "Invalid challenge or code"
+// @Failure 403 {object} ErrorResponse "Account disabled"
+// @Failure 429 {object} ErrorResponse "Locked out; see Retry-After"
+// @Router /users/login/2fa [post]
+func (h *Handler) LoginTwoFactor(c *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
 
-	tokens, err := auth.GenerateTokens(h.cfg, u.ID, u.Role)
+	claims, err := h.mfa.parse(purposeMFAChallenge, req.ChallengeToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid or expired challenge"})
+		return
+	}
+	u, err := h.svc.Reload(c, claims.Subject)
+	if errors.Is(err, ErrAccountDisabled) {
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid or expired challenge"})
+		return
+	}
+
+	// The stamp covers the last used code, so a completed challenge is dead
+	if !claims.valid(u) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid or expired challenge"})
+		return
+	}
+	attempt, ok := h.attempt(c, u.Email)
+	if !ok {
+		return
+	}
+	if err := h.svc.VerifySecondFactor(c, u, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidOTP) {
+			h.guard.Fail(c, attempt)
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: err.Error()})
+			return
+		}
+		h.guard.Release(c, attempt)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	h.guard.Succeed(c, attempt)
+	first := claims.FirstFactor
+	if first == "" {
+		first = auth.AMRPassword
+	}
+	h.startSession(c, u, []string{first, auth.AMROTP, auth.AMRMFA})
+}
+
+// attempt claims a login attempt for email from the client IP. While the
+// account or IP is locked out it answers 429 and returns false.
+func (h *Handler) attempt(c *gin.Context, email string) (*LoginAttempt, bool) {
+	ip := c.ClientIP()
+	a, left, err := h.guard.Attempt(c, email, ip)
+	if err == nil {
+		return a, true
+	}
+	if errors.Is(err, ErrLockedOut) {
+		h.logger.Warn("Login refused while locked out", zap.String("email", email), zap.String("client_ip", ip))
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(left.Seconds()))))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	h.logger.Error("Failed to check login lockout", zap.String("email", email), zap.Error(err))
+	c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	return nil, false
+}
+
+// startSession issues the token pair for a completed login
+func (h *Handler) startSession(c *gin.Context, u *User, amr []string) {
+	perms, err := h.perms.PermissionsFor(c, u.Role)
+	if err != nil {
+		h.logger.Error("Failed to resolve permissions", zap.String("user_id", u.ID), zap.String("role", u.Role), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
+		return
+	}
+
+	tokens, err := auth.GenerateTokens(h.cfg, h.keys, u.ID, u.Role, perms, amr, "")
 	if err != nil {
 		h.logger.Error("Failed to generate tokens", zap.String("user_id", u.ID), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
 		return
 	}
+	if err := h.sessions.Start(c, tokens); err != nil {
+		h.logger.Error("Failed to start session", zap.String("user_id", u.ID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
+		return
+	}
 
-	h.logger.Info("User login successful", zap.String("user_id", u.ID), zap.String("email", req.Email))
+	h.logger.Info("User login successful", zap.String("user_id", u.ID), zap.String("email", u.Email), zap.Strings("amr", amr))
 	c.JSON(http.StatusOK, LoginResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
+		AccessToken:           tokens.AccessToken,
+		RefreshToken:          tokens.RefreshToken,
+		MFAEnrollmentRequired: u.TOTPEnabledAt == nil && h.mfaRequired(u.Role),
 	})
 }
 
+func (h *Handler) mfaRequired(role string) bool {
+	for _, r := range h.cfg.MFARequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 // ===== RefreshToken =====
 // @Summary Refresh access token
-// @Description Use refresh token to get a new access token
+// @Description Exchange a refresh token for a new token pair carrying the user's current role and permissions. Each refresh token works once; reusing a rotated one revokes every token of that login. Disabled accounts are refused.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -118,12 +280,45 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokens, err := auth.GenerateTokens(h.cfg, refreshClaims.UserID, "")
+	// Claims come from the user as they are now, not as they were at login
+	u, err := h.svc.Reload(c, refreshClaims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrAccountDisabled) {
+			if err := h.sessions.Revoke(c, refreshClaims.Family); err != nil {
+				h.logger.Warn("Failed to revoke session of disabled account", zap.String("user_id", refreshClaims.UserID), zap.Error(err))
+			}
+		}
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+		return
+	}
+	perms, err := h.perms.PermissionsFor(c, u.Role)
+	if err != nil {
+		h.logger.Error("Failed to resolve permissions", zap.String("user_id", u.ID), zap.String("role", u.Role), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
+		return
+	}
+
+	tokens, err := auth.GenerateTokens(h.cfg, h.keys, u.ID, u.Role, perms, refreshClaims.AMR, refreshClaims.Family)
 	if err != nil {
 		h.logger.Error("Failed to generate new tokens", zap.String("user_id", refreshClaims.UserID), zap.Error(err))
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
 		return
 	}
+	if err := h.sessions.Rotate(c, refreshClaims, tokens); err != nil {
+		switch {
+		case errors.Is(err, auth.ErrRefreshReused):
+			h.logger.Warn("Refresh token reuse detected, family revoked",
+				zap.String("user_id", refreshClaims.UserID), zap.String("family", refreshClaims.Family))
+		case errors.Is(err, auth.ErrSessionRevoked):
+			h.logger.Warn("Refresh token of revoked session", zap.String("user_id", refreshClaims.UserID))
+		default:
+			h.logger.Error("Failed to rotate refresh token", zap.String("user_id", refreshClaims.UserID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "failed to generate tokens"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+		return
+	}
 
 	h.logger.Info("Token refresh successful", zap.String("user_id", refreshClaims.UserID))
 	c.JSON(http.StatusOK, LoginResponse{
@@ -132,9 +327,283 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 	})
 }
 
+// ===== VerifyEmail =====
+// @Summary Verify email
+// @Description Confirm the email address with the token from the verification link
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} OKResponse
+// @Failure 400 {object} ErrorResponse "Invalid, expired or already used token"
+// @Router /users/verify [post]
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	if err := h.svc.VerifyEmail(c, req.Token); err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	c.JSON(http.StatusOK, OKResponse{OK: true})
+}
+
+// ===== ResendVerification =====
+// @Summary Resend verification email
+// @Description Email a new verification link. Always accepted, whether or not the address is registered.
+// @Tags users
+// @Accept json
+// @Param input body EmailRequest true "Account email"
+// @Success 202 "Accepted"
+// @Failure 400 {object} ErrorResponse
+// @Router /users/verify/resend [post]
+func (h *Handler) ResendVerification(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	// Errors are logged by the service; the answer never reveals the account
+	_ = h.svc.ResendVerification(c, req.Email)
+	c.Status(http.StatusAccepted)
+}
+
+// ===== ForgotPassword =====
+// @Summary Request password reset
+// @Description Email a single-use password reset link. Always accepted, whether or not the address is registered.
+// @Tags users
+// @Accept json
+// @Param input body EmailRequest true "Account email"
+// @Success 202 "Accepted"
+// @Failure 400 {object} ErrorResponse
+// @Router /users/password/forgot [post]
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var req EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	_ = h.svc.ForgotPassword(c, req.Email)
+	c.Status(http.StatusAccepted)
+}
+
+// ===== ResetPassword =====
+// @Summary Reset password
+// @Description Set a new password with the token from a reset link. Every existing session of the account is logged out.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} OKResponse
+// @Failure 400 {object} ErrorResponse "Invalid, expired or already used token"
+// @Router /users/password/reset [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	userID, err := h.svc.ResetPassword(c, req.Token, req.Password)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	if err := h.sessions.RevokeAll(c, userID); err != nil {
+		h.logger.Error("Failed to revoke sessions after password reset", zap.String("user_id", userID), zap.Error(err))
+	}
+	c.JSON(http.StatusOK, OKResponse{OK: true})
+}
+
+// ===== EnrollTOTP =====
+// @Summary Start two-factor enrolment
+// @Description Generate a TOTP secret for an authenticator app. Login keeps working with the password alone until the enrolment is confirmed. Calling again replaces an unconfirmed secret.
+// @Tags users
+// @Produce json
+// @Success 200 {object} TOTPEnrollmentResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Already enabled"
+// @Security BearerAuth
+// @Router /users/2fa/enroll [post]
+func (h *Handler) EnrollTOTP(c *gin.Context) {
+	userID := c.GetString(auth.CtxUserID)
+	e, err := h.svc.EnrollTOTP(c, userID)
+	if err != nil {
+		h.totpError(c, userID, err)
+		return
+	}
+	c.JSON(http.StatusOK, TOTPEnrollmentResponse{
+		Secret:     e.Secret,
+		OTPAuthURL: e.URL,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(e.QRCode),
+	})
+}
+
+// ===== ConfirmTOTP =====
+// @Summary Confirm two-factor enrolment
+// @Description Turn on two-factor login with a code from the authenticator app. Returns single-use recovery codes, shown only this once.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body TOTPCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse "Invalid code or no enrolment"
+// @Failure 409 {object} ErrorResponse "Already enabled"
+// @Security BearerAuth
+// @Router /users/2fa/confirm [post]
+func (h *Handler) ConfirmTOTP(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	userID := c.GetString(auth.CtxUserID)
+	codes, err := h.svc.ConfirmTOTP(c, userID, req.Code)
+	if err != nil {
+		h.totpError(c, userID, err)
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ===== RegenerateRecoveryCodes =====
+// @Summary Replace recovery codes
+// @Description Invalidate every recovery code and return a new set
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body TOTPCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse "Invalid code or two-factor not enabled"
+// @Security BearerAuth
+// @Router /users/2fa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	userID := c.GetString(auth.CtxUserID)
+	codes, err := h.svc.RegenerateRecoveryCodes(c, userID, req.Code)
+	if err != nil {
+		h.totpError(c, userID, err)
+		return
+	}
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ===== DisableTOTP =====
+// @Summary Turn off two-factor authentication
+// @Description Turn off two-factor login with a current TOTP or recovery code. Roles that require two-factor lose admin access until it is set up again.
+// @Tags users
+// @Accept json
+// @Param input body TOTPCodeRequest true "TOTP or recovery code"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Invalid code or two-factor not enabled"
+// @Security BearerAuth
+// @Router /users/2fa/disable [post]
+func (h *Handler) DisableTOTP(c *gin.Context) {
+	var req TOTPCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	userID := c.GetString(auth.CtxUserID)
+	if err := h.svc.DisableTOTP(c, userID, req.Code); err != nil {
+		h.totpError(c, userID, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) totpError(c *gin.Context, userID string, err error) {
+	switch {
+	case errors.Is(err, ErrTOTPAlreadyEnabled):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrInvalidOTP), errors.Is(err, ErrTOTPNotEnabled), errors.Is(err, ErrTOTPNotEnrolled):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrAccountDisabled), errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "unauthorized"})
+	default:
+		h.logger.Error("Two-factor operation failed", zap.String("user_id", userID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
+
+// ===== Logout =====
+// @Summary Log out
+// @Description Revoke the current access token and, when given, the refresh token of this login
+// @Tags users
+// @Accept json
+// @Param input body LogoutRequest false "Refresh token of this login"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /users/logout [post]
+func (h *Handler) Logout(c *gin.Context) {
+	claims := c.MustGet(auth.CtxClaims).(*auth.AccessClaims)
+
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+			return
+		}
+	}
+	if req.RefreshToken != "" {
+		refreshClaims, err := auth.ValidateRefreshToken(h.cfg, req.RefreshToken)
+		if err != nil || refreshClaims.UserID != claims.UserID {
+			h.logger.Warn("Invalid refresh token on logout", zap.String("user_id", claims.UserID), zap.Error(err))
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "invalid refresh token"})
+			return
+		}
+		if err := h.sessions.Revoke(c, refreshClaims.Family); err != nil {
+			h.logger.Error("Failed to revoke session", zap.String("user_id", claims.UserID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+			return
+		}
+	}
+	if err := h.sessions.Deny(c, claims); err != nil {
+		h.logger.Error("Failed to revoke access token", zap.String("user_id", claims.UserID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+
+	h.logger.Info("User logged out", zap.String("user_id", claims.UserID))
+	c.Status(http.StatusNoContent)
+}
+
+// ===== LogoutAll =====
+// @Summary Log out everywhere
+// @Description Revoke every access and refresh token issued to the caller so far
+// @Tags users
+// @Success 204 "No Content"
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /users/logout-all [post]
+func (h *Handler) LogoutAll(c *gin.Context) {
+	claims := c.MustGet(auth.CtxClaims).(*auth.AccessClaims)
+	if !h.endSessions(c, claims) {
+		return
+	}
+
+	h.logger.Info("User logged out of all sessions", zap.String("user_id", claims.UserID))
+	c.Status(http.StatusNoContent)
+}
+
 // ===== UpdateProfile =====
 // @Summary Update user profile
-// @Description Update own user info (only the authenticated user can update self)
+// @Description Update own name, phone number and notification preferences (only the authenticated user can update self). Omitted fields are unchanged.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -162,7 +631,8 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.UpdateProfile(c, callerID, targetID, req.FullName); err != nil {
+	update := ProfileUpdate{FullName: req.FullName, Phone: req.Phone, Notifications: req.Notifications}
+	if err := h.svc.UpdateProfile(c, callerID, targetID, update); err != nil {
 		h.logger.Error("Failed to update profile", zap.String("user_id", targetID), zap.Error(err))
 		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
 		return
@@ -171,3 +641,225 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 	h.logger.Info("Profile updated successfully", zap.String("user_id", targetID))
 	c.JSON(http.StatusOK, UpdateProfileResponse{OK: true})
 }
+
+// ===== GetMe =====
+// @Summary Get own profile
+// @Description Return the authenticated user's account and preferences
+// @Tags users
+// @Produce json
+// @Success 200 {object} ProfileResponse
+// @Failure 401 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /users/me [get]
+func (h *Handler) GetMe(c *gin.Context) {
+	u, err := h.svc.Get(c, c.GetString(auth.CtxUserID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, ProfileResponse{
+		ID:               u.ID,
+		Email:            u.Email,
+		PendingEmail:     u.PendingEmail,
+		FullName:         u.FullName,
+		Phone:            u.Phone,
+		Role:             u.Role,
+		EmailVerified:    u.EmailVerifiedAt != nil,
+		TwoFactorEnabled: u.TOTPEnabledAt != nil,
+		Notifications:    u.Notifications,
+		CreatedAt:        u.CreatedAt,
+	})
+}
+
+// reauthenticate loads the caller and checks their current password before
+// a sensitive change. Wrong passwords count towards the login lockout. On
+// failure the response is written and ok is false.
+func (h *Handler) reauthenticate(c *gin.Context, password string) (u *User, ok bool) {
+	userID := c.GetString(auth.CtxUserID)
+	u, err := h.svc.Get(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return nil, false
+	}
+	attempt, ok := h.attempt(c, u.Email)
+	if !ok {
+		return nil, false
+	}
+	if err := h.svc.CheckPassword(c, u, password); err != nil {
+		h.guard.Fail(c, attempt)
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "current password is incorrect"})
+		return nil, false
+	}
+	h.guard.Succeed(c, attempt)
+	return u, true
+}
+
+// ===== ChangeEmail =====
+// @Summary Change email
+// @Description Email a confirmation link to the new address and a notice to the current one. The current address stays in use until the link is followed.
+// @Tags users
+// @Accept json
+// @Param input body ChangeEmailRequest true "New address and current password"
+// @Success 202 "Accepted"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Wrong current password"
+// @Failure 409 {object} ErrorResponse "Address already in use"
+// @Failure 429 {object} ErrorResponse "Locked out; see Retry-After"
+// @Security BearerAuth
+// @Router /users/me/email [post]
+func (h *Handler) ChangeEmail(c *gin.Context) {
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	u, ok := h.reauthenticate(c, req.Password)
+	if !ok {
+		return
+	}
+	err := h.svc.RequestEmailChange(c, u, req.Email)
+	switch {
+	case err == nil:
+		c.Status(http.StatusAccepted)
+	case errors.Is(err, ErrSameEmail):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrEmailTaken):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
+
+// ===== ConfirmEmailChange =====
+// @Summary Confirm email change
+// @Description Switch the account to the new address with the token from the confirmation link
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body VerifyEmailRequest true "Confirmation token"
+// @Success 200 {object} OKResponse
+// @Failure 400 {object} ErrorResponse "Invalid, expired or already used token"
+// @Failure 409 {object} ErrorResponse "Address taken since the link was sent"
+// @Router /users/email/confirm [post]
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	err := h.svc.ConfirmEmailChange(c, req.Token)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusOK, OKResponse{OK: true})
+	case errors.Is(err, ErrInvalidToken):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	case errors.Is(err, ErrEmailTaken):
+		c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+	}
+}
+
+// ===== ChangePassword =====
+// @Summary Change password
+// @Description Replace the password after checking the current one. Every session is logged out; the response carries a fresh token pair for this client.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param input body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Wrong current password"
+// @Failure 429 {object} ErrorResponse "Locked out; see Retry-After"
+// @Security BearerAuth
+// @Router /users/me/password [put]
+func (h *Handler) ChangePassword(c *gin.Context) {
+	claims := c.MustGet(auth.CtxClaims).(*auth.AccessClaims)
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	u, ok := h.reauthenticate(c, req.CurrentPassword)
+	if !ok {
+		return
+	}
+	if err := h.svc.ChangePassword(c, u, req.NewPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	if !h.endSessions(c, claims) {
+		return
+	}
+	h.startSession(c, u, claims.AMR)
+}
+
+// ===== DeleteAccount =====
+// @Summary Delete own account
+// @Description Anonymise the account after checking the password: email, name, phone, two-factor and linked logins are removed and every session ends. Bookings are kept for accounting.
+// @Tags users
+// @Accept json
+// @Param input body DeleteAccountRequest true "Current password"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Wrong current password"
+// @Failure 429 {object} ErrorResponse "Locked out; see Retry-After"
+// @Security BearerAuth
+// @Router /users/me [delete]
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	claims := c.MustGet(auth.CtxClaims).(*auth.AccessClaims)
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid request body"})
+		return
+	}
+	u, ok := h.reauthenticate(c, req.Password)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteAccount(c, u); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	if !h.endSessions(c, claims) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// endSessions revokes every token of the caller, the current one included
+func (h *Handler) endSessions(c *gin.Context, claims *auth.AccessClaims) bool {
+	if err := h.sessions.RevokeAll(c, claims.UserID); err != nil {
+		h.logger.Error("Failed to revoke sessions", zap.String("user_id", claims.UserID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return false
+	}
+	return true
+}
+
+// ===== Unlock =====
+// @Summary Unlock account
+// @Description Lift a failed-login lockout on an account and reset its backoff (requires users:manage)
+// @Tags users
+// @Param id path string true "User ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/unlock [post]
+func (h *Handler) Unlock(c *gin.Context) {
+	id := c.Param("id")
+	u, err := h.svc.Get(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	if err := h.guard.Unlock(c, u.Email); err != nil {
+		h.logger.Error("Failed to unlock account", zap.String("user_id", id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	h.logger.Info("Account unlocked", zap.String("user_id", id), zap.String("by", c.GetString(auth.CtxUserID)))
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,100 @@
+package user_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/user"
+)
+
+func TestRefresh_UsesCurrentRole(t *testing.T) {
+	repo := new(MockRepository)
+	r, cfg, _ := newTestRouter(t, repo)
+
+	tokens := login(t, r, repo)
+	claims, err := auth.ValidateAccessToken(cfg, nil, tokens.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "USER", claims.Role)
+	require.Empty(t, claims.Permissions)
+
+	// Promoted between login and refresh
+	repo.On("ByID", "u1").Return(&user.User{ID: "u1", Role: "ADMIN"}, nil).Once()
+	w := postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var refreshed user.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	claims, err = auth.ValidateAccessToken(cfg, nil, refreshed.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "ADMIN", claims.Role)
+	require.Equal(t, []string{auth.PermEventsWrite, auth.PermRolesManage}, claims.Permissions)
+
+	// Demoted again: the next refresh drops the admin permissions
+	repo.On("ByID", "u1").Return(&user.User{ID: "u1", Role: "USER"}, nil).Once()
+	w = postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	claims, err = auth.ValidateAccessToken(cfg, nil, refreshed.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "USER", claims.Role)
+	require.Empty(t, claims.Permissions)
+	repo.AssertExpectations(t)
+}
+
+func TestRefresh_RejectsDisabledAccount(t *testing.T) {
+	repo := new(MockRepository)
+	r, _, _ := newTestRouter(t, repo)
+
+	tokens := login(t, r, repo)
+	disabled := time.Now()
+	repo.On("ByID", "u1").Return(&user.User{ID: "u1", Role: "USER", DisabledAt: &disabled}, nil).Once()
+	w := postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// The family is revoked, so re-enabling does not revive the old token
+	repo.On("ByID", "u1").Return(&user.User{ID: "u1", Role: "USER"}, nil).Once()
+	w = postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestRefresh_ReusedTokenRevokesLogin(t *testing.T) {
+	repo := new(MockRepository)
+	r, _, _ := newTestRouter(t, repo)
+
+	tokens := login(t, r, repo)
+	repo.On("ByID", "u1").Return(&user.User{ID: "u1", Role: "USER"}, nil)
+	w := postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code)
+	var rotated user.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rotated))
+
+	w = postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: tokens.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: rotated.RefreshToken})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogin_LocksOutAfterRepeatedFailures(t *testing.T) {
+	repo := new(MockRepository)
+	r, _, _ := newTestRouter(t, repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	verified := time.Now()
+	repo.On("ByEmail", "a@example.com").Return(&user.User{ID: "u1", Role: "USER", PasswordHash: string(hashed), EmailVerifiedAt: &verified}, nil)
+
+	for i := 0; i < testCfg.LoginMaxAttempts; i++ {
+		w := postJSON(r, "/users/login", user.LoginRequest{Email: "a@example.com", Password: "wrong-password"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// Even the right password is refused until the lockout ends
+	w := postJSON(r, "/users/login", user.LoginRequest{Email: "A@example.com", Password: "password123"})
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "900", w.Header().Get("Retry-After"))
+}
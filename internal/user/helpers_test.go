@@ -0,0 +1,419 @@
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/config"
+	"ticket-booking/pkg/mail"
+)
+
+var testCfg = &config.Security{
+	JWTAccessSecret: "a", JWTRefreshSecret: "r", AccessTTLMinute: 15, RefreshTTLMinute: 60,
+	LoginMaxAttempts: 3, LoginMaxIPAttempts: 10, LoginLockoutMinutes: 15,
+	MFARequiredRoles: []string{"ADMIN"},
+}
+
+// testRouterOpts holds the optional parts of the test router
+type testRouterOpts struct {
+	providers *user.OIDCProviders
+	roles     user.RoleLookup
+	bookings  user.UserBookings
+	audit     *[]audit.Entry
+	mailer    mail.Mailer
+}
+
+// testRouterOption adds an optional part to the test router
+type testRouterOption func(*testRouterOpts)
+
+// withOIDC serves /users/oidc from providers
+func withOIDC(providers *user.OIDCProviders) testRouterOption {
+	return func(o *testRouterOpts) { o.providers = providers }
+}
+
+// withAdminUsers serves /admin/users, looking up roles and bookings in the fakes
+func withAdminUsers(roles user.RoleLookup, bookings user.UserBookings) testRouterOption {
+	return func(o *testRouterOpts) { o.roles, o.bookings = roles, bookings }
+}
+
+// withMailer sends account emails through m instead of the returned outbox
+func withMailer(m mail.Mailer) testRouterOption {
+	return func(o *testRouterOpts) { o.mailer = m }
+}
+
+// withAudit collects the audit records into log
+func withAudit(log *[]audit.Entry) testRouterOption {
+	return func(o *testRouterOpts) { o.audit = log }
+}
+
+// newTestRouter serves the user routes over repo, a map-backed cache and an
+// in-memory outbox. Options add the OIDC and admin user routes or replace
+// the outbox.
+func newTestRouter(t *testing.T, repo user.Repository, opts ...testRouterOption) (*gin.Engine, *config.Security, *mail.MemoryOutbox) {
+	gin.SetMode(gin.TestMode)
+	var o testRouterOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctrl := gomock.NewController(t)
+	cfg := testCfg
+	perms := rolePerms{"ADMIN": {auth.PermEventsWrite, auth.PermRolesManage}}
+	c, _ := memCache(ctrl)
+	auditSvc := testAudit(ctrl, o.audit)
+	guard := user.NewLoginGuard(c, cfg, auditSvc, zap.NewNop())
+	outbox := mail.NewMemoryOutbox()
+	var mailer mail.Mailer = outbox
+	if o.mailer != nil {
+		mailer = o.mailer
+	}
+	sessions := auth.NewSessions(c, cfg)
+	h := user.NewHandler(user.NewService(repo, testEmails(mailer), zap.NewNop()), cfg, nil, perms, sessions, guard, zap.NewNop())
+	m := auth.NewMiddleware(zap.NewNop(), zap.NewNop(), cfg, nil, sessions)
+
+	r := gin.New()
+	r.Use(m.RequestID())
+	user.RegisterRoutes(r.Group(""), h)
+	if o.providers != nil {
+		user.RegisterOIDCRoutes(r.Group(""), user.NewOIDCHandler(h, o.providers, c))
+	}
+	user.RegisterProtectedRoutes(r.Group("", m.Authn()), h)
+	admin := r.Group("/admin", m.Authn(), m.RequireMFA(), m.Require(auth.PermRolesManage))
+	user.RegisterAdminRoutes(admin, h)
+	if o.roles != nil {
+		user.RegisterAdminUserRoutes(admin, user.NewAdminHandler(h, o.roles, o.bookings, auditSvc))
+	}
+	return r, cfg, outbox
+}
+
+// newTestGuard returns a login guard over a map-backed cache, with the map,
+// collecting its audit records into log when set
+func newTestGuard(t *testing.T, log *[]audit.Entry) (*user.LoginGuard, map[string]string) {
+	ctrl := gomock.NewController(t)
+	c, store := memCache(ctrl)
+	return user.NewLoginGuard(c, testCfg, testAudit(ctrl, log), zap.NewNop()), store
+}
+
+// testAudit records into log when set and drops the records otherwise
+func testAudit(ctrl *gomock.Controller, log *[]audit.Entry) *audit.Service {
+	auditRepo := mocks.NewMockAuditRepository(ctrl)
+	auditRepo.EXPECT().Create(gomock.Any()).AnyTimes().DoAndReturn(func(e *audit.Entry) error {
+		if log != nil {
+			*log = append(*log, *e)
+		}
+		return nil
+	})
+	return audit.NewService(auditRepo, nil, zap.NewNop())
+}
+
+// testEmails sends account emails with links to http://app into outbox
+func testEmails(outbox mail.Mailer) *user.AccountEmails {
+	return user.NewAccountEmails("test-link-secret", outbox, config.Email{LinkBaseURL: "http://app"})
+}
+
+type rolePerms map[string][]string
+
+func (r rolePerms) PermissionsFor(_ context.Context, role string) ([]string, error) {
+	return r[role], nil
+}
+
+// memCache backs a MockCache with a map so sessions and lockouts behave as
+// against Redis. TTLs are ignored.
+func memCache(ctrl *gomock.Controller) (*mocks.MockCache, map[string]string) {
+	store := map[string]string{}
+	c := mocks.NewMockCache(ctrl)
+	c.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k string, v interface{}, _ time.Duration) error {
+			store[k] = fmt.Sprint(v)
+			return nil
+		})
+	c.EXPECT().Get(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k string) (string, error) {
+			v, ok := store[k]
+			if !ok {
+				return "", redis.Nil
+			}
+			return v, nil
+		})
+	c.EXPECT().Del(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k string) error {
+			delete(store, k)
+			return nil
+		})
+	c.EXPECT().GetDel(gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k string) (string, error) {
+			v, ok := store[k]
+			if !ok {
+				return "", redis.Nil
+			}
+			delete(store, k)
+			return v, nil
+		})
+	c.EXPECT().CompareAndSet(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k, old string, v interface{}, _ time.Duration) (bool, error) {
+			current, ok := store[k]
+			if !ok {
+				return false, redis.Nil
+			}
+			if current != old {
+				return false, nil
+			}
+			store[k] = fmt.Sprint(v)
+			return true, nil
+		})
+	c.EXPECT().IncrBy(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, k string, n int) (int, error) {
+			v, _ := strconv.Atoi(store[k])
+			store[k] = strconv.Itoa(v + n)
+			return v + n, nil
+		})
+	c.EXPECT().IncrUnlessLocked(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().
+		DoAndReturn(func(_ context.Context, lock, k string, ttl time.Duration) (int, time.Duration, error) {
+			if until, ok := store[lock]; ok {
+				ts, _ := strconv.ParseInt(until, 10, 64)
+				return 0, time.Until(time.Unix(ts, 0)), nil
+			}
+			v, _ := strconv.Atoi(store[k])
+			store[k] = strconv.Itoa(v + 1)
+			return v + 1, ttl, nil
+		})
+	c.EXPECT().Expire(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	return c, store
+}
+
+// memRepo is a Repository over a map, for flows that read back what they
+// wrote. Its methods are safe for concurrent use.
+type memRepo struct {
+	mu         sync.Mutex
+	users      map[string]*user.User
+	identities []user.Identity
+}
+
+func newMemRepo() *memRepo { return &memRepo{users: map[string]*user.User{}} }
+
+func (m *memRepo) Create(u *user.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u.ID = uuid.NewString()
+	u.Role = "USER"
+	cp := *u
+	m.users[u.ID] = &cp
+	return nil
+}
+
+func (m *memRepo) ByEmail(email string) (*user.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range m.users {
+		if strings.EqualFold(u.Email, email) {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memRepo) ByID(id string) (*user.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	u, ok := m.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	cp := *u
+	return &cp, nil
+}
+
+func (m *memRepo) Update(u *user.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cp := *u
+	m.users[u.ID] = &cp
+	return nil
+}
+
+func (m *memRepo) UpdatePassword(u *user.User, oldHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.users[u.ID]
+	if !ok || stored.PasswordHash != oldHash {
+		return false, nil
+	}
+	stored.PasswordHash, stored.EmailVerifiedAt = u.PasswordHash, u.EmailVerifiedAt
+	return true, nil
+}
+
+func (m *memRepo) UpdateSecondFactor(u *user.User, oldStep int64, oldCodes []string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.users[u.ID]
+	if !ok || stored.TOTPLastStep != oldStep || !slices.Equal(stored.RecoveryCodes, oldCodes) {
+		return false, nil
+	}
+	stored.TOTPLastStep, stored.RecoveryCodes = u.TOTPLastStep, u.RecoveryCodes
+	return true, nil
+}
+
+func (m *memRepo) IdentityBySubject(provider, subject string) (*user.Identity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, i := range m.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return &i, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memRepo) CreateIdentity(i *user.Identity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i.ID = uuid.NewString()
+	m.identities = append(m.identities, *i)
+	return nil
+}
+
+func (m *memRepo) DeleteIdentities(userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := m.identities[:0]
+	for _, i := range m.identities {
+		if i.UserID != userID {
+			kept = append(kept, i)
+		}
+	}
+	m.identities = kept
+	return nil
+}
+
+// List filters like the SQL query, in no particular order
+func (m *memRepo) List(f user.UserFilter) ([]*user.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []*user.User
+	for _, u := range m.users {
+		if (f.Role == "" || u.Role == f.Role) && (f.Status == "" || u.Status() == f.Status) &&
+			(f.Query == "" || strings.Contains(strings.ToLower(u.Email), strings.ToLower(f.Query))) {
+			cp := *u
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+// seedUser stores a verified account and logs it in
+func seedUser(t *testing.T, r http.Handler, repo *memRepo, id, email, password string) user.LoginResponse {
+	t.Helper()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	now := time.Now()
+	repo.users[id] = &user.User{ID: id, Email: email, Role: "USER", PasswordHash: string(hashed), EmailVerifiedAt: &now,
+		Notifications: user.DefaultNotificationPreferences()}
+
+	w := postJSON(r, "/users/login", user.LoginRequest{Email: email, Password: password})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	return decode[user.LoginResponse](t, w)
+}
+
+// seedAdmin stores the verified admin "admin" and returns an access token
+// for it
+func seedAdmin(t *testing.T, repo *memRepo, cfg *config.Security) string {
+	t.Helper()
+	now := time.Now()
+	repo.users["admin"] = &user.User{ID: "admin", Email: "root@example.com", Role: "ADMIN", EmailVerifiedAt: &now}
+	return adminToken(t, cfg, "admin")
+}
+
+// adminToken issues an access token for an admin who logged in with a TOTP code
+func adminToken(t *testing.T, cfg *config.Security, id string) string {
+	t.Helper()
+	tokens, err := auth.GenerateTokens(cfg, nil, id, "ADMIN", []string{auth.PermRolesManage},
+		[]string{auth.AMRPassword, auth.AMROTP, auth.AMRMFA}, "")
+	require.NoError(t, err)
+	return tokens.AccessToken
+}
+
+// login signs in a@example.com as u1 through a MockRepository
+func login(t *testing.T, r http.Handler, repo *MockRepository) user.LoginResponse {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	verified := time.Now()
+	repo.On("ByEmail", "a@example.com").Return(&user.User{ID: "u1", Role: "USER", PasswordHash: string(hashed), EmailVerifiedAt: &verified}, nil).Once()
+
+	w := postJSON(r, "/users/login", user.LoginRequest{Email: "a@example.com", Password: "password123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var out user.LoginResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+	return out
+}
+
+func postJSON(r http.Handler, path string, body any) *httptest.ResponseRecorder {
+	return postJSONAuth(r, path, "", body)
+}
+
+// postJSONAuth posts body with accessToken as the bearer token
+func postJSONAuth(r http.Handler, path, accessToken string, body any) *httptest.ResponseRecorder {
+	return sendJSON(r, http.MethodPost, path, accessToken, body)
+}
+
+// sendJSON sends body as JSON, with accessToken as the bearer token unless empty
+func sendJSON(r http.Handler, method, path, accessToken string, body any) *httptest.ResponseRecorder {
+	raw, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var out T
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out), w.Body.String())
+	return out
+}
+
+var linkRe = regexp.MustCompile(`http://app/[a-z-]+\?token=\S+`)
+
+// lastToken pulls the token out of the link in the newest email to "to"
+func lastToken(t *testing.T, outbox *mail.MemoryOutbox, to string) string {
+	t.Helper()
+	msgs := outbox.Messages()
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].To != to {
+			continue
+		}
+		link, err := url.Parse(linkRe.FindString(msgs[i].Body))
+		require.NoError(t, err)
+		token := link.Query().Get("token")
+		require.NotEmpty(t, token, msgs[i].Body)
+		return token
+	}
+	t.Fatalf("no email to %s", to)
+	return ""
+}
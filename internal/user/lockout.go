@@ -0,0 +1,191 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/pkg/cache"
+	"ticket-booking/pkg/config"
+
+	"go.uber.org/zap"
+)
+
+// ErrLockedOut is returned while an account or client IP is locked after too many failed logins
+var ErrLockedOut = Err("too many failed login attempts")
+
+// maxLockout caps the exponential backoff
+const maxLockout = 24 * time.Hour
+
+// Redis keys, per scope ("acct" keyed by lower-cased email, "ip" by client IP):
+//
+//	login:fail:<scope>:<id>    -> failed and in-flight attempts in the current window
+//	login:lock:<scope>:<id>    -> unix time the lockout ends
+//	login:strikes:<scope>:<id> -> lockouts in the last day; doubles the next one
+const (
+	scopeAccount = "acct"
+	scopeIP      = "ip"
+)
+
+// LoginGuard counts failed logins per account and per client IP and locks
+// either out once its limit is hit. Each further lockout within a day lasts
+// twice as long as the previous one. Accounts are keyed by email so unknown
+// addresses are throttled the same way as real ones.
+type LoginGuard struct {
+	cache  cache.Cache
+	cfg    *config.Security
+	audit  *audit.Service
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewLoginGuard creates a login guard backed by c
+func NewLoginGuard(c cache.Cache, cfg *config.Security, a *audit.Service, logger *zap.Logger) *LoginGuard {
+	return &LoginGuard{cache: c, cfg: cfg, audit: a, logger: logger, now: time.Now}
+}
+
+func accountID(email string) string { return strings.ToLower(strings.TrimSpace(email)) }
+
+func (g *LoginGuard) window() time.Duration {
+	return time.Minute * time.Duration(g.cfg.LoginLockoutMinutes)
+}
+
+// LoginAttempt is a login attempt claimed with Attempt. Report how it went
+// with Fail, Succeed or Release.
+type LoginAttempt struct {
+	email, ip string
+	counts    map[string]int // Attempts in the current window, by scope
+}
+
+// Attempt counts a login attempt against the account and IP before the
+// credentials are checked. Counting and the lockout check are one atomic step
+// per scope, so concurrent guesses cannot slip past the limit. It returns
+// ErrLockedOut and the time left while either is locked, or already used up
+// its attempts.
+func (g *LoginGuard) Attempt(ctx context.Context, email, ip string) (*LoginAttempt, time.Duration, error) {
+	a := &LoginAttempt{email: email, ip: ip, counts: make(map[string]int, 2)}
+	for _, k := range []struct {
+		scope, id string
+		limit     int
+	}{{scopeAccount, accountID(email), g.cfg.LoginMaxAttempts}, {scopeIP, ip, g.cfg.LoginMaxIPAttempts}} {
+		n, left, err := g.cache.IncrUnlessLocked(ctx, "login:lock:"+k.scope+":"+k.id, "login:fail:"+k.scope+":"+k.id, g.window())
+		if err != nil {
+			g.Release(ctx, a)
+			return nil, 0, err
+		}
+		if n == 0 || n > k.limit {
+			g.Release(ctx, a)
+			if n > k.limit {
+				// Attempts still in flight used up the limit
+				g.release(ctx, k.scope, k.id)
+			}
+			return nil, left, ErrLockedOut
+		}
+		a.counts[k.scope] = n
+	}
+	return a, 0, nil
+}
+
+// Fail records a failed attempt and starts a lockout when a limit is reached
+func (g *LoginGuard) Fail(ctx context.Context, a *LoginAttempt) {
+	g.fail(ctx, scopeAccount, accountID(a.email), a.counts[scopeAccount], g.cfg.LoginMaxAttempts)
+	g.fail(ctx, scopeIP, a.ip, a.counts[scopeIP], g.cfg.LoginMaxIPAttempts)
+}
+
+func (g *LoginGuard) fail(ctx context.Context, scope, id string, n, limit int) {
+	if n < limit {
+		return
+	}
+
+	strikesKey := "login:strikes:" + scope + ":" + id
+	strikes, err := g.cache.IncrBy(ctx, strikesKey, 1)
+	if err != nil {
+		g.logger.Error("Failed to count lockout", zap.String("scope", scope), zap.Error(err))
+		strikes = 1
+	}
+	if err := g.cache.Expire(ctx, strikesKey, maxLockout); err != nil {
+		g.logger.Error("Failed to expire lockout count", zap.String("scope", scope), zap.Error(err))
+	}
+
+	d := g.window() << (strikes - 1)
+	if d <= 0 || d > maxLockout {
+		d = maxLockout
+	}
+	until := g.now().Add(d)
+	if err := g.cache.Set(ctx, "login:lock:"+scope+":"+id, until.Unix(), d); err != nil {
+		g.logger.Error("Failed to store lockout", zap.String("scope", scope), zap.Error(err))
+		return
+	}
+	if err := g.cache.Del(ctx, "login:fail:"+scope+":"+id); err != nil {
+		g.logger.Error("Failed to reset login failures", zap.String("scope", scope), zap.Error(err))
+	}
+
+	g.logger.Warn("Login locked out", zap.String("scope", scope), zap.String("id", id),
+		zap.Int("attempts", n), zap.Int("lockouts_today", strikes), zap.Duration("duration", d))
+	if err := g.audit.Record(ctx, &audit.Entry{
+		Action:     audit.ActionLockout,
+		TargetType: scopeName(scope),
+		TargetID:   id,
+		Detail: map[string]any{
+			"attempts":         n,
+			"lockouts_today":   strikes,
+			"duration_seconds": int(d.Seconds()),
+			"until":            until.UTC(),
+		},
+	}); err != nil {
+		g.logger.Error("Failed to audit lockout", zap.String("scope", scope), zap.Error(err))
+	}
+}
+
+// release gives back one attempt of a scope. The count may have been reset
+// meanwhile, by a good login or a lockout, so it is kept from going negative.
+func (g *LoginGuard) release(ctx context.Context, scope, id string) {
+	key := "login:fail:" + scope + ":" + id
+	n, err := g.cache.IncrBy(ctx, key, -1)
+	if err == nil && n < 0 {
+		err = g.cache.Del(ctx, key)
+	}
+	if err != nil {
+		g.logger.Error("Failed to release login attempt", zap.String("scope", scope), zap.Error(err))
+	}
+}
+
+func scopeName(scope string) string {
+	if scope == scopeIP {
+		return "ip"
+	}
+	return "account"
+}
+
+// Succeed clears the account's failure count after a good login and gives
+// the IP its attempt back. Lockout history is kept so a quick relapse still
+// backs off.
+func (g *LoginGuard) Succeed(ctx context.Context, a *LoginAttempt) {
+	if err := g.cache.Del(ctx, "login:fail:"+scopeAccount+":"+accountID(a.email)); err != nil {
+		g.logger.Error("Failed to reset login failures", zap.String("scope", scopeAccount), zap.Error(err))
+	}
+	g.release(ctx, scopeIP, a.ip)
+}
+
+// Release gives back an attempt that neither failed nor succeeded, such as
+// one refused for a disabled account or cut short by an internal error
+func (g *LoginGuard) Release(ctx context.Context, a *LoginAttempt) {
+	if _, ok := a.counts[scopeAccount]; ok {
+		g.release(ctx, scopeAccount, accountID(a.email))
+	}
+	if _, ok := a.counts[scopeIP]; ok {
+		g.release(ctx, scopeIP, a.ip)
+	}
+}
+
+// Unlock lifts an account lockout and resets its backoff
+func (g *LoginGuard) Unlock(ctx context.Context, email string) error {
+	id := accountID(email)
+	for _, prefix := range []string{"login:lock:", "login:fail:", "login:strikes:"} {
+		if err := g.cache.Del(ctx, prefix+scopeAccount+":"+id); err != nil {
+			return err
+		}
+	}
+	return g.audit.Record(ctx, &audit.Entry{Action: audit.ActionUnlock, TargetType: "account", TargetID: id})
+}
@@ -0,0 +1,86 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ticket-booking/internal/audit"
+	"ticket-booking/internal/user"
+)
+
+func TestLoginGuard_BacksOffExponentially(t *testing.T) {
+	var entries []audit.Entry
+	g, store := newTestGuard(t, &entries)
+	ctx := context.Background()
+
+	fail := func(email, ip string) {
+		a, _, err := g.Attempt(ctx, email, ip)
+		require.NoError(t, err)
+		g.Fail(ctx, a)
+	}
+	for i := 0; i < testCfg.LoginMaxAttempts; i++ {
+		fail("a@example.com", "10.0.0.1")
+	}
+	_, left, err := g.Attempt(ctx, "a@example.com", "10.0.0.2")
+	require.ErrorIs(t, err, user.ErrLockedOut, "account lockout applies from any IP")
+	require.InDelta(t, (15 * time.Minute).Seconds(), left.Seconds(), 2)
+	require.Len(t, entries, 1)
+	require.Equal(t, audit.ActionLockout, entries[0].Action)
+	require.Equal(t, "account", entries[0].TargetType)
+	require.Equal(t, "a@example.com", entries[0].TargetID)
+
+	// The lockout expires; the next one lasts twice as long
+	delete(store, "login:lock:acct:a@example.com")
+	for i := 0; i < testCfg.LoginMaxAttempts; i++ {
+		fail("a@example.com", "10.0.0.1")
+	}
+	_, left, err = g.Attempt(ctx, "a@example.com", "10.0.0.3")
+	require.ErrorIs(t, err, user.ErrLockedOut)
+	require.InDelta(t, (30 * time.Minute).Seconds(), left.Seconds(), 2)
+	require.Equal(t, 1800, entries[1].Detail["duration_seconds"])
+
+	require.NoError(t, g.Unlock(ctx, "a@example.com"))
+	_, _, err = g.Attempt(ctx, "a@example.com", "10.0.0.3")
+	require.NoError(t, err)
+	require.Equal(t, audit.ActionUnlock, entries[len(entries)-1].Action)
+}
+
+func TestLoginGuard_LocksIPAcrossAccounts(t *testing.T) {
+	g, _ := newTestGuard(t, nil)
+	ctx := context.Background()
+
+	// Spraying one password across many accounts trips the IP limit
+	for i := 0; i < testCfg.LoginMaxIPAttempts; i++ {
+		a, _, err := g.Attempt(ctx, string(rune('a'+i))+"@example.com", "10.0.0.9")
+		require.NoError(t, err)
+		g.Fail(ctx, a)
+	}
+	_, _, err := g.Attempt(ctx, "fresh@example.com", "10.0.0.9")
+	require.ErrorIs(t, err, user.ErrLockedOut)
+	_, _, err = g.Attempt(ctx, "fresh@example.com", "10.0.0.10")
+	require.NoError(t, err)
+}
+
+func TestLoginGuard_CountsAttemptsBeforeVerifying(t *testing.T) {
+	g, store := newTestGuard(t, nil)
+	ctx := context.Background()
+
+	// Guesses in flight together use up the limit before any of them fails
+	var inFlight []*user.LoginAttempt
+	for i := 0; i < testCfg.LoginMaxAttempts; i++ {
+		a, _, err := g.Attempt(ctx, "a@example.com", "10.0.0.1")
+		require.NoError(t, err)
+		inFlight = append(inFlight, a)
+	}
+	_, _, err := g.Attempt(ctx, "a@example.com", "10.0.0.1")
+	require.ErrorIs(t, err, user.ErrLockedOut)
+
+	// A good password gives the attempts back; other outcomes release theirs
+	g.Succeed(ctx, inFlight[0])
+	g.Release(ctx, inFlight[1])
+	require.Empty(t, store["login:fail:acct:a@example.com"])
+	require.Equal(t, "1", store["login:fail:ip:10.0.0.1"])
+}
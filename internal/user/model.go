@@ -6,11 +6,68 @@ import "time"
 
 // User represents a system user with authentication and role-based access control.
 type User struct {
-	ID           string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"` // Unique user identifier
-	Email        string    `gorm:"uniqueIndex;not null"`                            // Unique email for authentication
-	PasswordHash string    `gorm:"not null"`                                        // Bcrypt hashed password
-	Role         string    `gorm:"type:text;not null;default:'USER'"`               // User role: 'USER' or 'ADMIN'
-	FullName     *string   // Optional display name
-	CreatedAt    time.Time // Account creation timestamp
-	UpdatedAt    time.Time // Last profile update timestamp
+	ID              string                  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"` // Unique user identifier
+	Email           string                  `gorm:"uniqueIndex;not null"`                            // Unique email for authentication
+	PasswordHash    string                  `gorm:"not null"`                                        // Bcrypt hashed password
+	Role            string                  `gorm:"type:text;not null;default:'USER'"`               // Role name from the roles table, e.g. 'USER', 'ORGANIZER', 'ADMIN'
+	FullName        *string                 // Optional display name
+	Phone           *string                 // Optional contact number, digits with an optional leading '+'
+	PendingEmail    *string                 // New address awaiting confirmation; Email changes once its link is followed
+	DisabledAt      *time.Time              // Set when the account is disabled; blocks login and refresh
+	AnonymizedAt    *time.Time              // Set when the owner deleted the account; personal data is gone, bookings stay
+	EmailVerifiedAt *time.Time              // Set once the email link is followed; required to log in
+	TOTPSecret      string                  `gorm:"column:totp_secret;not null;default:''"`   // Base32 TOTP secret; set at enrolment, before confirmation
+	TOTPEnabledAt   *time.Time              `gorm:"column:totp_enabled_at"`                   // Set once enrolment is confirmed; login then needs a code
+	TOTPLastStep    int64                   `gorm:"column:totp_last_step;not null;default:0"` // Time step of the last accepted code, so a code works once
+	RecoveryCodes   []string                `gorm:"type:jsonb;serializer:json"`               // SHA-256 hashes of the unused recovery codes
+	Notifications   NotificationPreferences `gorm:"type:jsonb;serializer:json"`               // Which optional emails the user wants
+	CreatedAt       time.Time               // Account creation timestamp
+	UpdatedAt       time.Time               // Last profile update timestamp
 }
+
+// Account states, as shown and filtered on in the admin user API
+const (
+	StatusActive     = "active"
+	StatusUnverified = "unverified"
+	StatusSuspended  = "suspended"
+	StatusDeleted    = "deleted"
+)
+
+// Status reports the account state
+func (u *User) Status() string {
+	switch {
+	case u.AnonymizedAt != nil:
+		return StatusDeleted
+	case u.DisabledAt != nil:
+		return StatusSuspended
+	case u.EmailVerifiedAt == nil:
+		return StatusUnverified
+	default:
+		return StatusActive
+	}
+}
+
+// NotificationPreferences are the user's choices of optional messages.
+// Transactional email (verification, password reset, receipts) is always sent.
+type NotificationPreferences struct {
+	BookingUpdates bool `json:"booking_updates"` // Changes to or cancellation of a booked event
+	EventReminders bool `json:"event_reminders"` // Reminder shortly before a booked event starts
+	Marketing      bool `json:"marketing"`       // News and recommended events
+}
+
+// DefaultNotificationPreferences are given to new accounts
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{BookingUpdates: true, EventReminders: true}
+}
+
+// Identity links a user to their account at an external OIDC provider
+type Identity struct {
+	ID        string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    string `gorm:"type:uuid;not null;index"`
+	Provider  string `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"` // Configured provider name
+	Subject   string `gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"` // The provider's stable user ID (sub claim)
+	Email     string // Email the provider reported when the identity was linked
+	CreatedAt time.Time
+}
+
+func (Identity) TableName() string { return "user_identities" }
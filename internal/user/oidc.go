@@ -0,0 +1,390 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/pkg/cache"
+	"ticket-booking/pkg/config"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownProvider          = Err("unknown identity provider")
+	ErrProviderEmailUnverified  = Err("identity provider has not verified this email")
+	errInvalidOIDCState         = Err("invalid or expired login state")
+	errOIDCProviderUnavailable  = Err("identity provider unavailable")
+	errOIDCAuthenticationFailed = Err("identity provider login failed")
+)
+
+// oidcStateTTL bounds how long a user has to finish logging in at the provider
+const oidcStateTTL = 10 * time.Minute
+
+// ExternalIdentity is a user as asserted by an OIDC provider's ID token
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// LoginExternal finds or creates the user behind an external identity.
+// A known identity logs in its user. Otherwise the identity is linked to the
+// user with the same email, which the provider must have verified; a new
+// user is created when there is none. Linking to an account whose email was
+// never verified also replaces its password, so whoever registered the
+// address first cannot keep a way in.
+func (s *Service) LoginExternal(ctx context.Context, id ExternalIdentity) (*User, error) {
+	link, err := s.repo.IdentityBySubject(id.Provider, id.Subject)
+	if err == nil {
+		return s.Reload(ctx, link.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Failed to find identity", zap.String("provider", id.Provider), zap.Error(err))
+		return nil, err
+	}
+	if !id.EmailVerified || id.Email == "" {
+		s.logger.Warn("External login with unverified email", zap.String("provider", id.Provider), zap.String("email", id.Email))
+		return nil, ErrProviderEmailUnverified
+	}
+
+	u, err := s.repo.ByEmail(id.Email)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if u, err = s.createExternal(id); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		s.logger.Error("Failed to find user by email", zap.String("email", id.Email), zap.Error(err))
+		return nil, err
+	case u.DisabledAt != nil:
+		return nil, ErrAccountDisabled
+	case u.EmailVerifiedAt == nil:
+		hash, err := unusablePassword()
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		u.EmailVerifiedAt, u.PasswordHash = &now, hash
+		if err := s.repo.Update(u); err != nil {
+			s.logger.Error("Failed to verify linked user", zap.String("user_id", u.ID), zap.Error(err))
+			return nil, err
+		}
+	}
+
+	if err := s.repo.CreateIdentity(&Identity{UserID: u.ID, Provider: id.Provider, Subject: id.Subject, Email: id.Email}); err != nil {
+		s.logger.Error("Failed to link identity", zap.String("user_id", u.ID), zap.String("provider", id.Provider), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("External identity linked", zap.String("user_id", u.ID), zap.String("provider", id.Provider))
+	return u, nil
+}
+
+func (s *Service) createExternal(id ExternalIdentity) (*User, error) {
+	hash, err := unusablePassword()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	u := &User{Email: id.Email, PasswordHash: hash, EmailVerifiedAt: &now, Notifications: DefaultNotificationPreferences()}
+	if id.Name != "" {
+		u.FullName = &id.Name
+	}
+	if err := s.repo.Create(u); err != nil {
+		s.logger.Error("Failed to create user", zap.String("email", id.Email), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("User registered via identity provider", zap.String("user_id", u.ID), zap.String("provider", id.Provider))
+	return u, nil
+}
+
+// unusablePassword hashes a random secret nobody knows. The user can set a
+// real password through forgot-password.
+func unusablePassword() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hashPassword(hex.EncodeToString(raw))
+}
+
+// --- Providers ---
+
+type oidcProvider struct {
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// OIDCProviders holds the configured identity providers. Discovery runs on
+// first use and is retried until it succeeds, so a provider that is down at
+// startup does not stop the service. It runs outside the lock, so a slow
+// provider holds up only its own logins.
+type OIDCProviders struct {
+	cfgs   map[string]config.OIDCProvider
+	client *http.Client
+
+	mu        sync.Mutex
+	providers map[string]*oidcProvider
+}
+
+// oidcTimeout bounds each request to a provider, and discovery as a whole
+const oidcTimeout = 10 * time.Second
+
+// NewOIDCProviders creates the provider registry. client is used for
+// discovery, key and token requests; nil uses a client that gives up after
+// oidcTimeout.
+func NewOIDCProviders(cfg config.OIDC, client *http.Client) *OIDCProviders {
+	if client == nil {
+		client = &http.Client{Timeout: oidcTimeout}
+	}
+	cfgs := make(map[string]config.OIDCProvider, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		cfgs[p.Name] = p
+	}
+	return &OIDCProviders{cfgs: cfgs, client: client, providers: map[string]*oidcProvider{}}
+}
+
+func (p *OIDCProviders) ctx(ctx context.Context) context.Context {
+	return oidc.ClientContext(ctx, p.client)
+}
+
+func (p *OIDCProviders) get(ctx context.Context, name string) (*oidcProvider, error) {
+	cfg, ok := p.cfgs[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	p.mu.Lock()
+	op := p.providers[name]
+	p.mu.Unlock()
+	if op != nil {
+		return op, nil
+	}
+
+	// Discovery is shared by every login waiting on it, so it does not end
+	// with this request; keys are fetched later through the client alone
+	discoverCtx, cancel := context.WithTimeout(context.Background(), oidcTimeout)
+	defer cancel()
+	provider, err := oidc.NewProvider(p.ctx(discoverCtx), cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errOIDCProviderUnavailable, err)
+	}
+	op = &oidcProvider{
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       append([]string{oidc.ScopeOpenID, "email", "profile"}, cfg.Scopes...),
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+	}
+	// Concurrent first logins may all discover; the first to finish is kept
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if kept := p.providers[name]; kept != nil {
+		return kept, nil
+	}
+	p.providers[name] = op
+	return op, nil
+}
+
+// oidcState is kept in Redis between the redirect to the provider and the callback
+type oidcState struct {
+	Provider string `json:"p"`
+	Nonce    string `json:"n"`
+	Verifier string `json:"v"` // PKCE code verifier
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// --- HTTP ---
+
+// OIDCHandler logs users in through external identity providers using the
+// authorization code flow with PKCE, then issues our own token pair
+type OIDCHandler struct {
+	h         *Handler
+	providers *OIDCProviders
+	cache     cache.Cache
+}
+
+// NewOIDCHandler creates an OIDCHandler that finishes logins through h
+func NewOIDCHandler(h *Handler, providers *OIDCProviders, c cache.Cache) *OIDCHandler {
+	return &OIDCHandler{h: h, providers: providers, cache: c}
+}
+
+// ===== OIDCLogin =====
+// @Summary Start OIDC login
+// @Description Redirect to the identity provider's login page (authorization code flow with PKCE)
+// @Tags users
+// @Param provider path string true "Configured provider name" example(google)
+// @Success 302 "Redirect to the provider"
+// @Failure 404 {object} ErrorResponse "Unknown provider"
+// @Failure 502 {object} ErrorResponse "Provider unavailable"
+// @Router /users/oidc/{provider}/login [get]
+func (o *OIDCHandler) Login(c *gin.Context) {
+	name := c.Param("provider")
+	p, err := o.providers.get(c, name)
+	if err != nil {
+		o.providerError(c, name, err)
+		return
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	st := oidcState{Provider: name, Nonce: nonce, Verifier: oauth2.GenerateVerifier()}
+	raw, _ := json.Marshal(st)
+	if err := o.cache.Set(c, "oidc:state:"+state, string(raw), oidcStateTTL); err != nil {
+		o.h.logger.Error("Failed to store OIDC state", zap.String("provider", name), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, p.oauth.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(st.Verifier)))
+}
+
+// ===== OIDCCallback =====
+// @Summary Finish OIDC login
+// @Description The provider redirects here. Exchanges the code, verifies the ID token and logs in the user with that identity, linking it to the account with the same verified email or creating one. Returns the same body as /users/login, including the two-factor challenge when it is on.
+// @Tags users
+// @Produce json
+// @Param provider path string true "Configured provider name" example(google)
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} ErrorResponse "Invalid or expired state"
+// @Failure 401 {object} ErrorResponse "Provider login failed"
+// @Failure 403 {object} ErrorResponse "Account disabled or email not verified by the provider"
+// @Failure 404 {object} ErrorResponse "Unknown provider"
+// @Router /users/oidc/{provider}/callback [get]
+func (o *OIDCHandler) Callback(c *gin.Context) {
+	name := c.Param("provider")
+	if e := c.Query("error"); e != "" {
+		o.h.logger.Warn("Identity provider returned an error", zap.String("provider", name), zap.String("error", e))
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: errOIDCAuthenticationFailed.Error()})
+		return
+	}
+	p, err := o.providers.get(c, name)
+	if err != nil {
+		o.providerError(c, name, err)
+		return
+	}
+
+	st, err := o.takeState(c, c.Query("state"))
+	if err != nil || st.Provider != name {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: errInvalidOIDCState.Error()})
+		return
+	}
+
+	// The HTTP client may still touch its context after the handler returns,
+	// when gin has already reused c
+	id, err := o.exchange(c.Request.Context(), p, name, c.Query("code"), st)
+	if err != nil {
+		o.h.logger.Warn("OIDC login failed", zap.String("provider", name), zap.Error(err))
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: errOIDCAuthenticationFailed.Error()})
+		return
+	}
+
+	u, err := o.h.svc.LoginExternal(c, *id)
+	if err != nil {
+		if errors.Is(err, ErrAccountDisabled) || errors.Is(err, ErrProviderEmailUnverified) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	o.h.completeLogin(c, u, auth.AMRFederated)
+}
+
+// takeState loads and deletes the state in one step so each redirect
+// completes once, even when the callback is replayed concurrently
+func (o *OIDCHandler) takeState(ctx context.Context, state string) (*oidcState, error) {
+	if state == "" {
+		return nil, errInvalidOIDCState
+	}
+	raw, err := o.cache.GetDel(ctx, "oidc:state:"+state)
+	if err != nil {
+		return nil, err
+	}
+	var st oidcState
+	if err := json.Unmarshal([]byte(raw), &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// exchange trades the code for tokens and verifies the ID token against the
+// provider's keys, our client ID and the nonce of this login
+func (o *OIDCHandler) exchange(ctx context.Context, p *oidcProvider, name, code string, st *oidcState) (*ExternalIdentity, error) {
+	ctx = o.providers.ctx(ctx)
+	tok, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(st.Verifier))
+	if err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	rawID, _ := tok.Extra("id_token").(string)
+	if rawID == "" {
+		return nil, errors.New("no id_token in token response")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawID)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+	if idToken.Nonce != st.Nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("id_token claims: %w", err)
+	}
+	return &ExternalIdentity{
+		Provider:      name,
+		Subject:       idToken.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (o *OIDCHandler) providerError(c *gin.Context, name string, err error) {
+	if errors.Is(err, ErrUnknownProvider) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	o.h.logger.Error("OIDC discovery failed", zap.String("provider", name), zap.Error(err))
+	c.JSON(http.StatusBadGateway, ErrorResponse{Error: errOIDCProviderUnavailable.Error()})
+}
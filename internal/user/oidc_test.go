@@ -0,0 +1,287 @@
+package user_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/config"
+)
+
+// stubAccount is who logs in at the stub provider
+type stubAccount struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type stubGrant struct {
+	challenge string
+	nonce     string
+	account   stubAccount
+}
+
+// stubOIDC is a minimal OIDC provider: discovery, JWKS, an authorize
+// endpoint that logs in as Next without a page, and a token endpoint that
+// checks the PKCE verifier before issuing an RS256 ID token
+type stubOIDC struct {
+	srv      *httptest.Server
+	key      *rsa.PrivateKey
+	clientID string
+
+	mu     sync.Mutex
+	Next   stubAccount
+	grants map[string]stubGrant
+}
+
+func newStubOIDC(t *testing.T) *stubOIDC {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	s := &stubOIDC{key: key, clientID: "ticket-booking", grants: map[string]stubGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                s.srv.URL,
+			"authorization_endpoint":                s.srv.URL + "/authorize",
+			"token_endpoint":                        s.srv.URL + "/token",
+			"jwks_uri":                              s.srv.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "kid": "k1", "use": "sig", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != s.clientID || q.Get("code_challenge_method") != "S256" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		code := uuid.NewString()
+		s.mu.Lock()
+		s.grants[code] = stubGrant{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), account: s.Next}
+		s.mu.Unlock()
+		http.Redirect(w, r, q.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(q.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		s.mu.Lock()
+		g, ok := s.grants[r.PostForm.Get("code")]
+		delete(s.grants, r.PostForm.Get("code"))
+		s.mu.Unlock()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		now := time.Now()
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss": s.srv.URL, "aud": s.clientID, "sub": g.account.Subject,
+			"email": g.account.Email, "email_verified": g.account.EmailVerified, "name": g.account.Name,
+			"nonce": g.nonce, "iat": now.Unix(), "exp": now.Add(time.Minute).Unix(),
+		})
+		tok.Header["kid"] = "k1"
+		idToken, _ := tok.SignedString(key)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "provider-access", "token_type": "Bearer", "expires_in": 60, "id_token": idToken,
+		})
+	})
+	s.srv = httptest.NewServer(mux)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *stubOIDC) providers() *user.OIDCProviders {
+	return user.NewOIDCProviders(config.OIDC{Providers: []config.OIDCProvider{{
+		Name: "stub", IssuerURL: s.srv.URL, ClientID: s.clientID, ClientSecret: "shh",
+		RedirectURL: "http://api.test/users/oidc/stub/callback",
+	}}}, s.srv.Client())
+}
+
+// oidcLogin runs the whole redirect dance as account and returns the callback response
+func oidcLogin(t *testing.T, r *gin.Engine, s *stubOIDC, account stubAccount) *httptest.ResponseRecorder {
+	t.Helper()
+	s.Next = account
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/oidc/stub/login", nil))
+	require.Equal(t, http.StatusFound, w.Code, w.Body.String())
+
+	// The browser follows to the provider, which redirects back with a code
+	resp, err := noRedirect(s.srv.Client()).Get(w.Header().Get("Location"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+	back, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, back.RequestURI(), nil))
+	return w
+}
+
+func noRedirect(c *http.Client) *http.Client {
+	cp := *c
+	cp.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &cp
+}
+
+func TestOIDC_CreatesUserAndLogsIn(t *testing.T) {
+	stub := newStubOIDC(t)
+	repo := newMemRepo()
+	r, cfg, _ := newTestRouter(t, repo, withOIDC(stub.providers()))
+
+	w := oidcLogin(t, r, stub, stubAccount{Subject: "sub-1", Email: "new@example.com", EmailVerified: true, Name: "New User"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	out := decode[user.LoginResponse](t, w)
+	claims, err := auth.ValidateAccessToken(cfg, nil, out.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, []string{auth.AMRFederated}, claims.AMR)
+
+	u, err := repo.ByID(claims.UserID)
+	require.NoError(t, err)
+	require.Equal(t, "new@example.com", u.Email)
+	require.NotNil(t, u.EmailVerifiedAt)
+	require.Equal(t, "New User", *u.FullName)
+
+	// The identity is keyed by subject, so a changed email still finds the user
+	w = oidcLogin(t, r, stub, stubAccount{Subject: "sub-1", Email: "renamed@example.com", EmailVerified: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	claims, err = auth.ValidateAccessToken(cfg, nil, decode[user.LoginResponse](t, w).AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, claims.UserID)
+	require.Len(t, repo.users, 1)
+}
+
+func TestOIDC_LinksExistingAccountByVerifiedEmail(t *testing.T) {
+	stub := newStubOIDC(t)
+	repo := newMemRepo()
+	r, cfg, _ := newTestRouter(t, repo, withOIDC(stub.providers()))
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	verified := time.Now()
+	repo.users["u1"] = &user.User{ID: "u1", Email: "a@example.com", Role: "USER", PasswordHash: string(hashed), EmailVerifiedAt: &verified}
+
+	// An email the provider has not verified neither links nor creates
+	w := oidcLogin(t, r, stub, stubAccount{Subject: "sub-a", Email: "a@example.com"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Empty(t, repo.identities)
+
+	w = oidcLogin(t, r, stub, stubAccount{Subject: "sub-a", Email: "a@example.com", EmailVerified: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	claims, err := auth.ValidateAccessToken(cfg, nil, decode[user.LoginResponse](t, w).AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "u1", claims.UserID)
+	require.Len(t, repo.identities, 1)
+
+	// The password keeps working alongside
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "a@example.com", Password: "password123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOIDC_LinkingUnverifiedAccountReplacesPassword(t *testing.T) {
+	stub := newStubOIDC(t)
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo, withOIDC(stub.providers()))
+
+	// Someone registered the address but never proved they own it
+	w := postJSON(r, "/users/register", user.RegisterRequest{Email: "victim@example.com", Password: "squatter-pw"})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = oidcLogin(t, r, stub, stubAccount{Subject: "sub-v", Email: "victim@example.com", EmailVerified: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "victim@example.com", Password: "squatter-pw"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestOIDC_StateIsSingleUse(t *testing.T) {
+	stub := newStubOIDC(t)
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo, withOIDC(stub.providers()))
+	stub.Next = stubAccount{Subject: "sub-1", Email: "x@example.com", EmailVerified: true}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/oidc/stub/login", nil))
+	resp, err := noRedirect(stub.srv.Client()).Get(w.Header().Get("Location"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	back, _ := url.Parse(resp.Header.Get("Location"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, back.RequestURI(), nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, back.RequestURI(), nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/oidc/nope/login", nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOIDC_SlowDiscoveryDoesNotBlockOtherProviders(t *testing.T) {
+	stub := newStubOIDC(t)
+	started, release := make(chan struct{}), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+
+	providers := user.NewOIDCProviders(config.OIDC{Providers: []config.OIDCProvider{
+		{Name: "stub", IssuerURL: stub.srv.URL, ClientID: stub.clientID, ClientSecret: "shh",
+			RedirectURL: "http://api.test/users/oidc/stub/callback"},
+		{Name: "slow", IssuerURL: slow.URL, ClientID: "ticket-booking", RedirectURL: "http://api.test/users/oidc/slow/callback"},
+	}}, stub.srv.Client())
+	r, _, _ := newTestRouter(t, newMemRepo(), withOIDC(providers))
+
+	go r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/oidc/slow/login", nil))
+	<-started
+	w := oidcLogin(t, r, stub, stubAccount{Subject: "sub-1", Email: "x@example.com", EmailVerified: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestOIDC_TwoFactorStillRequired(t *testing.T) {
+	stub := newStubOIDC(t)
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo, withOIDC(stub.providers()))
+
+	now := time.Now()
+	repo.users["u1"] = &user.User{ID: "u1", Email: "mfa@example.com", Role: "USER", EmailVerifiedAt: &now,
+		TOTPSecret: "JBSWY3DPEHPK3PXP", TOTPEnabledAt: &now}
+
+	w := oidcLogin(t, r, stub, stubAccount{Subject: "sub-m", Email: "mfa@example.com", EmailVerified: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	out := decode[user.LoginResponse](t, w)
+	require.True(t, out.MFARequired)
+	require.Empty(t, out.AccessToken)
+}
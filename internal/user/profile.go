@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"ticket-booking/pkg/config"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPhone = Err("invalid phone number")
+	ErrEmailTaken   = Err("email already in use")
+	ErrSameEmail    = Err("new email matches the current one")
+)
+
+// ProfileUpdate carries the profile fields to change; nil leaves a field as it is
+type ProfileUpdate struct {
+	FullName      *string
+	Phone         *string // Empty clears the number
+	Notifications *NotificationPreferences
+}
+
+var phoneRe = regexp.MustCompile(`^\+?[0-9]{5,}$`)
+
+// normalizePhone drops the usual separators and checks what is left is a
+// plausible number no longer than DefaultMaxPhoneLength
+func normalizePhone(raw string) (string, error) {
+	p := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, raw)
+	if !phoneRe.MatchString(p) || len(p) > config.DefaultMaxPhoneLength {
+		return "", ErrInvalidPhone
+	}
+	return p, nil
+}
+
+// CheckPassword re-authenticates the owner of an account before a sensitive change
+func (s *Service) CheckPassword(ctx context.Context, u *User, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		s.logger.Warn("Wrong current password", zap.String("user_id", u.ID))
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// ChangePassword sets a new password for a user who proved the current one
+func (s *Service) ChangePassword(ctx context.Context, u *User, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	u.PasswordHash = hash
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to change password", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Password changed", zap.String("user_id", u.ID))
+	return nil
+}
+
+// RequestEmailChange records newEmail as pending and mails it a confirmation
+// link. The current address stays in use, and is told, until the link is followed.
+func (s *Service) RequestEmailChange(ctx context.Context, u *User, newEmail string) error {
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, u.Email) {
+		return ErrSameEmail
+	}
+	if err := s.emailFree(newEmail); err != nil {
+		return err
+	}
+	u.PendingEmail = &newEmail
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to store pending email", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	if err := s.emails.sendEmailChange(ctx, u); err != nil {
+		s.logger.Error("Failed to send email change confirmation", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Email change requested", zap.String("user_id", u.ID))
+	return nil
+}
+
+// ConfirmEmailChange swaps in the pending address behind a confirmation link.
+// The address is verified by following the link.
+func (s *Service) ConfirmEmailChange(ctx context.Context, token string) error {
+	claims, err := s.emails.tokens.parse(purposeChangeEmail, token)
+	if err != nil {
+		return err
+	}
+	u, err := s.repo.ByID(claims.Subject)
+	if err != nil || u.PendingEmail == nil || u.DisabledAt != nil || !claims.valid(u) {
+		return ErrInvalidToken
+	}
+	// Someone may have registered the address since the link was sent
+	if err := s.emailFree(*u.PendingEmail); err != nil {
+		return err
+	}
+	now := time.Now()
+	u.Email = *u.PendingEmail
+	u.PendingEmail = nil
+	u.EmailVerifiedAt = &now
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to change email", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Email changed", zap.String("user_id", u.ID))
+	return nil
+}
+
+func (s *Service) emailFree(email string) error {
+	_, err := s.repo.ByEmail(email)
+	if err == nil {
+		return ErrEmailTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Error("Failed to find user by email", zap.String("email", email), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteAccount anonymises a user at their own request. The row stays, with
+// its bookings, for accounting; everything identifying is cleared, linked
+// identities are removed and the account can no longer log in.
+func (s *Service) DeleteAccount(ctx context.Context, u *User) error {
+	hash, err := unusablePassword()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	u.Email = "deleted-" + u.ID + "@anonymized.invalid"
+	u.PasswordHash = hash
+	u.FullName = nil
+	u.Phone = nil
+	u.PendingEmail = nil
+	u.TOTPSecret = ""
+	u.TOTPEnabledAt = nil
+	u.RecoveryCodes = nil
+	u.Notifications = NotificationPreferences{}
+	u.DisabledAt = &now
+	u.AnonymizedAt = &now
+
+	if err := s.repo.DeleteIdentities(u.ID); err != nil {
+		s.logger.Error("Failed to remove linked identities", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to anonymize user", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Account deleted and anonymized", zap.String("user_id", u.ID))
+	return nil
+}
@@ -0,0 +1,142 @@
+package user_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"ticket-booking/internal/user"
+)
+
+func TestProfile_GetAndUpdate(t *testing.T) {
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo)
+	tokens := seedUser(t, r, repo, "u1", "me@example.com", "password123")
+
+	w := sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	me := decode[user.ProfileResponse](t, w)
+	require.Equal(t, "me@example.com", me.Email)
+	require.True(t, me.EmailVerified)
+	require.True(t, me.Notifications.BookingUpdates)
+	require.False(t, me.Notifications.Marketing)
+
+	name, phone := "Jane Doe", "+44 (20) 7946-0958"
+	w = sendJSON(r, http.MethodPut, "/users/u1", tokens.AccessToken, user.UpdateProfileRequest{
+		FullName:      &name,
+		Phone:         &phone,
+		Notifications: &user.NotificationPreferences{Marketing: true},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	me = decode[user.ProfileResponse](t, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil))
+	require.Equal(t, "Jane Doe", *me.FullName)
+	require.Equal(t, "+442079460958", *me.Phone)
+	require.Equal(t, user.NotificationPreferences{Marketing: true}, me.Notifications)
+
+	// Omitted fields stay; an empty phone clears it
+	for _, bad := range []string{"call me", "+1" + strings.Repeat("5", 20)} {
+		w = sendJSON(r, http.MethodPut, "/users/u1", tokens.AccessToken, user.UpdateProfileRequest{Phone: &bad})
+		require.Equal(t, http.StatusBadRequest, w.Code, bad)
+	}
+	empty := ""
+	w = sendJSON(r, http.MethodPut, "/users/u1", tokens.AccessToken, user.UpdateProfileRequest{Phone: &empty})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	me = decode[user.ProfileResponse](t, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil))
+	require.Nil(t, me.Phone)
+	require.Equal(t, "Jane Doe", *me.FullName)
+	require.True(t, me.Notifications.Marketing)
+}
+
+func TestProfile_ChangeEmail(t *testing.T) {
+	repo := newMemRepo()
+	r, _, outbox := newTestRouter(t, repo)
+	tokens := seedUser(t, r, repo, "u1", "old@example.com", "password123")
+	seedUser(t, r, repo, "u2", "taken@example.com", "password123")
+
+	w := postJSONAuth(r, "/users/me/email", tokens.AccessToken, user.ChangeEmailRequest{Email: "new@example.com", Password: "wrong-password"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+	w = postJSONAuth(r, "/users/me/email", tokens.AccessToken, user.ChangeEmailRequest{Email: "taken@example.com", Password: "password123"})
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Empty(t, outbox.Messages())
+
+	w = postJSONAuth(r, "/users/me/email", tokens.AccessToken, user.ChangeEmailRequest{Email: "new@example.com", Password: "password123"})
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	msgs := outbox.Messages()
+	require.Len(t, msgs, 2)
+	require.Equal(t, "old@example.com", msgs[1].To)
+	require.Contains(t, msgs[1].Body, "new@example.com")
+
+	// Nothing changes until the new address is confirmed
+	me := decode[user.ProfileResponse](t, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil))
+	require.Equal(t, "old@example.com", me.Email)
+	require.Equal(t, "new@example.com", *me.PendingEmail)
+
+	token := lastToken(t, outbox, "new@example.com")
+	w = postJSON(r, "/users/verify", user.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusBadRequest, w.Code, "a change link is not a verification link")
+	w = postJSON(r, "/users/email/confirm", user.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = postJSON(r, "/users/email/confirm", user.VerifyEmailRequest{Token: token})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "old@example.com", Password: "password123"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "new@example.com", Password: "password123"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestProfile_ChangePassword(t *testing.T) {
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo)
+	first := seedUser(t, r, repo, "u1", "pw@example.com", "password123")
+	other := seedUser(t, r, repo, "u1", "pw@example.com", "password123")
+	// Revocation has one-second granularity; tokens from the same second survive it
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	w := sendJSON(r, http.MethodPut, "/users/me/password", first.AccessToken, user.ChangePasswordRequest{CurrentPassword: "wrong-password", NewPassword: "n3w-password"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	w = sendJSON(r, http.MethodPut, "/users/me/password", first.AccessToken, user.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "n3w-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	fresh := decode[user.LoginResponse](t, w)
+
+	// Every earlier session ends; the fresh pair keeps this client signed in
+	for _, old := range []string{first.AccessToken, other.AccessToken} {
+		require.Equal(t, http.StatusUnauthorized, sendJSON(r, http.MethodGet, "/users/me", old, nil).Code)
+	}
+	require.Equal(t, http.StatusUnauthorized, postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: other.RefreshToken}).Code)
+	require.Equal(t, http.StatusOK, sendJSON(r, http.MethodGet, "/users/me", fresh.AccessToken, nil).Code)
+
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "pw@example.com", Password: "n3w-password"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestProfile_DeleteAnonymizes(t *testing.T) {
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo)
+	tokens := seedUser(t, r, repo, "u1", "gone@example.com", "password123")
+	name, phone := "Gone Soon", "+15550100"
+	repo.users["u1"].FullName, repo.users["u1"].Phone = &name, &phone
+	require.NoError(t, repo.CreateIdentity(&user.Identity{UserID: "u1", Provider: "stub", Subject: "sub-1"}))
+
+	w := sendJSON(r, http.MethodDelete, "/users/me", tokens.AccessToken, user.DeleteAccountRequest{Password: "wrong-password"})
+	require.Equal(t, http.StatusForbidden, w.Code)
+	w = sendJSON(r, http.MethodDelete, "/users/me", tokens.AccessToken, user.DeleteAccountRequest{Password: "password123"})
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	// The row stays for the bookings that point at it, without personal data
+	u := repo.users["u1"]
+	require.NotContains(t, u.Email, "gone")
+	require.Nil(t, u.FullName)
+	require.Nil(t, u.Phone)
+	require.NotNil(t, u.AnonymizedAt)
+	require.NotNil(t, u.DisabledAt)
+	require.Empty(t, repo.identities)
+
+	require.Equal(t, http.StatusUnauthorized, sendJSON(r, http.MethodGet, "/users/me", tokens.AccessToken, nil).Code)
+	w = postJSON(r, "/users/login", user.LoginRequest{Email: "gone@example.com", Password: "password123"})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,12 +1,37 @@
 package user
 
-import "gorm.io/gorm"
+import (
+	"encoding/json"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// UserFilter narrows the admin user listing. Zero values mean "no constraint".
+type UserFilter struct {
+	Query  string // Case-insensitive match on email or full name
+	Role   string
+	Status string // One of the Status* constants
+	Limit  int
+	Offset int
+}
 
 type Repository interface {
 	ByEmail(email string) (*User, error)
 	ByID(id string) (*User, error)
 	Create(u *User) error
 	Update(u *User) error
+	// UpdatePassword writes u's password hash and email verification only if
+	// the stored hash is still oldHash; false means another change came first
+	UpdatePassword(u *User, oldHash string) (bool, error)
+	// UpdateSecondFactor writes u's last TOTP step and recovery codes only if
+	// the stored ones are still oldStep and oldCodes; false means another
+	// login used a code first
+	UpdateSecondFactor(u *User, oldStep int64, oldCodes []string) (bool, error)
+	IdentityBySubject(provider, subject string) (*Identity, error)
+	CreateIdentity(i *Identity) error
+	DeleteIdentities(userID string) error
+	List(f UserFilter) ([]*User, error)
 }
 
 type repo struct{ db *gorm.DB }
@@ -29,3 +54,74 @@ func (r *repo) ByID(id string) (*User, error) {
 }
 func (r *repo) Create(u *User) error { return r.db.Create(u).Error }
 func (r *repo) Update(u *User) error { return r.db.Save(u).Error }
+
+func (r *repo) UpdatePassword(u *User, oldHash string) (bool, error) {
+	res := r.db.Model(u).Where("password_hash = ?", oldHash).
+		Select("password_hash", "email_verified_at").Updates(u)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *repo) UpdateSecondFactor(u *User, oldStep int64, oldCodes []string) (bool, error) {
+	res := r.db.Model(u).
+		Where("totp_last_step = ? AND recovery_codes IS NOT DISTINCT FROM ?::jsonb", oldStep, jsonArray(oldCodes)).
+		Select("totp_last_step", "recovery_codes").Updates(u)
+	return res.RowsAffected == 1, res.Error
+}
+
+// jsonArray encodes codes the way the json serializer stores them, with nil
+// as SQL NULL
+func jsonArray(codes []string) any {
+	if codes == nil {
+		return nil
+	}
+	raw, _ := json.Marshal(codes)
+	return string(raw)
+}
+
+func (r *repo) IdentityBySubject(provider, subject string) (*Identity, error) {
+	var i Identity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&i).Error; err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+func (r *repo) CreateIdentity(i *Identity) error { return r.db.Create(i).Error }
+
+func (r *repo) DeleteIdentities(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&Identity{}).Error
+}
+
+func (r *repo) List(f UserFilter) ([]*User, error) {
+	var out []*User
+	q := r.db.Order("created_at desc, id asc")
+	if f.Query != "" {
+		p := likePattern(f.Query)
+		q = q.Where("(email ILIKE ? OR full_name ILIKE ?)", p, p)
+	}
+	if f.Role != "" {
+		q = q.Where("role = ?", f.Role)
+	}
+	switch f.Status {
+	case StatusActive:
+		q = q.Where("disabled_at IS NULL AND email_verified_at IS NOT NULL")
+	case StatusUnverified:
+		q = q.Where("disabled_at IS NULL AND email_verified_at IS NULL")
+	case StatusSuspended:
+		q = q.Where("disabled_at IS NOT NULL AND anonymized_at IS NULL")
+	case StatusDeleted:
+		q = q.Where("anonymized_at IS NOT NULL")
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if f.Offset > 0 {
+		q = q.Offset(f.Offset)
+	}
+	return out, q.Find(&out).Error
+}
+
+// likePattern escapes LIKE wildcards in user input and wraps it for substring match
+func likePattern(q string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + r.Replace(q) + "%"
+}
@@ -5,10 +5,44 @@ import "github.com/gin-gonic/gin"
 func RegisterRoutes(r *gin.RouterGroup, h *Handler) {
 	r.POST("/users/register", h.Register)
 	r.POST("/users/login", h.Login)
+	r.POST("/users/login/2fa", h.LoginTwoFactor)
 	r.POST("/users/refresh", h.RefreshToken)
+	r.POST("/users/verify", h.VerifyEmail)
+	r.POST("/users/verify/resend", h.ResendVerification)
+	r.POST("/users/password/forgot", h.ForgotPassword)
+	r.POST("/users/password/reset", h.ResetPassword)
+	r.POST("/users/email/confirm", h.ConfirmEmailChange)
 	// PUT /users/:id is protected in central router with Authn
 }
 
+func RegisterOIDCRoutes(r *gin.RouterGroup, o *OIDCHandler) {
+	r.GET("/users/oidc/:provider/login", o.Login)
+	r.GET("/users/oidc/:provider/callback", o.Callback)
+}
+
 func RegisterProtectedRoutes(rg *gin.RouterGroup, h *Handler) {
+	rg.POST("/users/logout", h.Logout)
+	rg.POST("/users/logout-all", h.LogoutAll)
+	rg.POST("/users/2fa/enroll", h.EnrollTOTP)
+	rg.POST("/users/2fa/confirm", h.ConfirmTOTP)
+	rg.POST("/users/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+	rg.POST("/users/2fa/disable", h.DisableTOTP)
+	rg.GET("/users/me", h.GetMe)
+	rg.DELETE("/users/me", h.DeleteAccount)
+	rg.POST("/users/me/email", h.ChangeEmail)
+	rg.PUT("/users/me/password", h.ChangePassword)
 	rg.PUT("/users/:id", h.UpdateProfile)
 }
+
+func RegisterAdminRoutes(rg *gin.RouterGroup, h *Handler) {
+	rg.POST("/users/:id/unlock", h.Unlock)
+}
+
+func RegisterAdminUserRoutes(rg *gin.RouterGroup, a *AdminHandler) {
+	rg.GET("/users", a.List)
+	rg.GET("/users/:id", a.Get)
+	rg.PUT("/users/:id/role", a.SetRole)
+	rg.POST("/users/:id/suspend", a.Suspend)
+	rg.POST("/users/:id/reactivate", a.Reactivate)
+	rg.POST("/users/:id/password-reset", a.ForcePasswordReset)
+}
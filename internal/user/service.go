@@ -2,18 +2,22 @@ package user
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type Service struct {
 	repo   Repository
+	emails *AccountEmails
 	logger *zap.Logger
 }
 
-func NewService(r Repository, logger *zap.Logger) *Service {
-	return &Service{repo: r, logger: logger}
+func NewService(r Repository, emails *AccountEmails, logger *zap.Logger) *Service {
+	return &Service{repo: r, emails: emails, logger: logger}
 }
 
 func hashPassword(pw string) (string, error) {
@@ -31,13 +35,122 @@ func (s *Service) Register(ctx context.Context, email, password string) (string,
 		return "", err
 	}
 
-	u := &User{Email: email, PasswordHash: hash}
+	u := &User{Email: email, PasswordHash: hash, Notifications: DefaultNotificationPreferences()}
 	if err := s.repo.Create(u); err != nil {
 		s.logger.Error("Failed to create user", zap.String("email", email), zap.Error(err))
 		return "", err
 	}
 
 	s.logger.Info("User registered successfully", zap.String("user_id", u.ID), zap.String("email", email))
+	// The account exists either way; the user can ask for another link
+	if err := s.emails.sendVerification(ctx, u); err != nil {
+		s.logger.Error("Failed to send verification email", zap.String("user_id", u.ID), zap.Error(err))
+	}
+	return u.ID, nil
+}
+
+// ResendVerification mails a new verification link if email belongs to an
+// unverified account. Unknown addresses are ignored so callers cannot probe
+// which emails are registered.
+func (s *Service) ResendVerification(ctx context.Context, email string) error {
+	u, err := s.repo.ByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to find user by email", zap.String("email", email), zap.Error(err))
+		return err
+	}
+	if u.EmailVerifiedAt != nil || u.DisabledAt != nil {
+		return nil
+	}
+	if err := s.emails.sendVerification(ctx, u); err != nil {
+		s.logger.Error("Failed to send verification email", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// VerifyEmail marks the account behind a verification link as verified
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.emails.tokens.parse(purposeVerifyEmail, token)
+	if err != nil {
+		return err
+	}
+	u, err := s.repo.ByID(claims.Subject)
+	if err != nil {
+		return ErrInvalidToken
+	}
+	if u.EmailVerifiedAt != nil || !claims.valid(u) {
+		return ErrInvalidToken
+	}
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to mark email verified", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Email verified", zap.String("user_id", u.ID))
+	return nil
+}
+
+// ForgotPassword mails a reset link if email belongs to an active account.
+// Unknown addresses are ignored so callers cannot probe which are registered.
+func (s *Service) ForgotPassword(ctx context.Context, email string) error {
+	u, err := s.repo.ByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		s.logger.Info("Password reset requested for unknown email", zap.String("email", email))
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("Failed to find user by email", zap.String("email", email), zap.Error(err))
+		return err
+	}
+	if u.DisabledAt != nil {
+		return nil
+	}
+	if err := s.emails.sendPasswordReset(ctx, u); err != nil {
+		s.logger.Error("Failed to send password reset email", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Password reset email sent", zap.String("user_id", u.ID))
+	return nil
+}
+
+// ResetPassword sets a new password from a reset link and returns the user
+// ID. The link dies with the old password hash, so it works once. Following
+// the link proves the address, so the email counts as verified.
+func (s *Service) ResetPassword(ctx context.Context, token, password string) (string, error) {
+	claims, err := s.emails.tokens.parse(purposePasswordReset, token)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.repo.ByID(claims.Subject)
+	if err != nil || u.DisabledAt != nil || !claims.valid(u) {
+		return "", ErrInvalidToken
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		s.logger.Error("Failed to hash password", zap.String("user_id", u.ID), zap.Error(err))
+		return "", err
+	}
+	old := u.PasswordHash
+	u.PasswordHash = hash
+	if u.EmailVerifiedAt == nil {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+	}
+	// The token is bound to the old hash; of two concurrent resets with it,
+	// only the first to write wins
+	ok, err := s.repo.UpdatePassword(u, old)
+	if err != nil {
+		s.logger.Error("Failed to reset password", zap.String("user_id", u.ID), zap.Error(err))
+		return "", err
+	}
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	s.logger.Info("Password reset", zap.String("user_id", u.ID))
 	return u.ID, nil
 }
 
@@ -52,12 +165,46 @@ func (s *Service) VerifyLogin(ctx context.Context, email, password string) (*Use
 		s.logger.Warn("Invalid login attempt", zap.String("email", email))
 		return nil, ErrInvalidCredentials
 	}
+	if u.DisabledAt != nil {
+		s.logger.Warn("Login attempt on disabled account", zap.String("user_id", u.ID))
+		return nil, ErrAccountDisabled
+	}
+	if u.EmailVerifiedAt == nil {
+		s.logger.Warn("Login attempt before email verification", zap.String("user_id", u.ID))
+		return nil, ErrEmailNotVerified
+	}
 
 	s.logger.Info("User logged in successfully", zap.String("user_id", u.ID), zap.String("email", email))
 	return u, nil
 }
 
-func (s *Service) UpdateProfile(ctx context.Context, callerID, targetID string, fullName *string) error {
+// Get returns a user by ID
+func (s *Service) Get(ctx context.Context, id string) (*User, error) {
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		s.logger.Error("Failed to find user by ID", zap.String("user_id", id), zap.Error(err))
+		return nil, err
+	}
+	return u, nil
+}
+
+// Reload fetches the user behind a refresh token so new tokens carry the
+// current role. Disabled accounts are refused with ErrAccountDisabled.
+func (s *Service) Reload(ctx context.Context, id string) (*User, error) {
+	u, err := s.repo.ByID(id)
+	if err != nil {
+		s.logger.Warn("Failed to reload user for refresh", zap.String("user_id", id), zap.Error(err))
+		return nil, err
+	}
+	if u.DisabledAt != nil {
+		s.logger.Warn("Refresh attempt on disabled account", zap.String("user_id", id))
+		return nil, ErrAccountDisabled
+	}
+	return u, nil
+}
+
+// UpdateProfile changes the fields set in p on the caller's own profile
+func (s *Service) UpdateProfile(ctx context.Context, callerID, targetID string, p ProfileUpdate) error {
 	// Ownership check
 	if callerID != targetID {
 		s.logger.Warn("Unauthorized profile update attempt", zap.String("caller_id", callerID), zap.String("target_id", targetID))
@@ -70,7 +217,23 @@ func (s *Service) UpdateProfile(ctx context.Context, callerID, targetID string,
 		return err
 	}
 
-	u.FullName = fullName
+	if p.Phone != nil {
+		if *p.Phone == "" {
+			u.Phone = nil
+		} else {
+			phone, err := normalizePhone(*p.Phone)
+			if err != nil {
+				return err
+			}
+			u.Phone = &phone
+		}
+	}
+	if p.FullName != nil {
+		u.FullName = p.FullName
+	}
+	if p.Notifications != nil {
+		u.Notifications = *p.Notifications
+	}
 	if err := s.repo.Update(u); err != nil {
 		s.logger.Error("Failed to update user profile", zap.String("user_id", targetID), zap.Error(err))
 		return err
@@ -83,6 +246,9 @@ func (s *Service) UpdateProfile(ctx context.Context, callerID, targetID string,
 var (
 	ErrInvalidCredentials = Err("invalid credentials")
 	ErrForbidden          = Err("forbidden")
+	ErrAccountDisabled    = Err("account disabled")
+	ErrEmailNotVerified   = Err("email not verified")
+	ErrInvalidToken       = Err("invalid or expired token")
 )
 
 type Err string
@@ -3,6 +3,7 @@ package user_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -10,6 +11,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"ticket-booking/internal/user"
+	"ticket-booking/pkg/mail"
 )
 
 // MockRepository is a mock for the Repository interface
@@ -37,10 +39,40 @@ func (m *MockRepository) Update(u *user.User) error {
 	return args.Error(0)
 }
 
+func (m *MockRepository) UpdatePassword(u *user.User, oldHash string) (bool, error) {
+	args := m.Called(u, oldHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) UpdateSecondFactor(u *user.User, oldStep int64, oldCodes []string) (bool, error) {
+	args := m.Called(u, oldStep, oldCodes)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) IdentityBySubject(provider, subject string) (*user.Identity, error) {
+	args := m.Called(provider, subject)
+	return args.Get(0).(*user.Identity), args.Error(1)
+}
+
+func (m *MockRepository) CreateIdentity(i *user.Identity) error {
+	args := m.Called(i)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteIdentities(userID string) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) List(f user.UserFilter) ([]*user.User, error) {
+	args := m.Called(f)
+	return args.Get(0).([]*user.User), args.Error(1)
+}
+
 func TestService_Register(t *testing.T) {
 	logger := zap.NewNop() // No-op logger for tests
 	mockRepo := new(MockRepository)
-	svc := user.NewService(mockRepo, logger)
+	svc := user.NewService(mockRepo, testEmails(mail.NewMemoryOutbox()), logger)
 
 	tests := []struct {
 		name        string
@@ -97,9 +129,11 @@ func TestService_Register(t *testing.T) {
 func TestService_VerifyLogin(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockRepository)
-	svc := user.NewService(mockRepo, logger)
+	svc := user.NewService(mockRepo, testEmails(mail.NewMemoryOutbox()), logger)
 
 	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	disabledAt := time.Now()
+	verifiedAt := time.Now()
 
 	tests := []struct {
 		name         string
@@ -114,9 +148,9 @@ func TestService_VerifyLogin(t *testing.T) {
 			email:    "test@example.com",
 			password: "password123",
 			mockSetup: func() {
-				mockRepo.On("ByEmail", "test@example.com").Return(&user.User{PasswordHash: string(hashed)}, nil).Once()
+				mockRepo.On("ByEmail", "test@example.com").Return(&user.User{PasswordHash: string(hashed), EmailVerifiedAt: &verifiedAt}, nil).Once()
 			},
-			expectedUser: &user.User{PasswordHash: string(hashed)},
+			expectedUser: &user.User{PasswordHash: string(hashed), EmailVerifiedAt: &verifiedAt},
 			expectedErr:  nil,
 		},
 		{
@@ -129,6 +163,26 @@ func TestService_VerifyLogin(t *testing.T) {
 			expectedUser: nil,
 			expectedErr:  user.ErrInvalidCredentials,
 		},
+		{
+			name:     "Disabled account",
+			email:    "test@example.com",
+			password: "password123",
+			mockSetup: func() {
+				mockRepo.On("ByEmail", "test@example.com").Return(&user.User{PasswordHash: string(hashed), DisabledAt: &disabledAt}, nil).Once()
+			},
+			expectedUser: nil,
+			expectedErr:  user.ErrAccountDisabled,
+		},
+		{
+			name:     "Email not verified",
+			email:    "test@example.com",
+			password: "password123",
+			mockSetup: func() {
+				mockRepo.On("ByEmail", "test@example.com").Return(&user.User{PasswordHash: string(hashed)}, nil).Once()
+			},
+			expectedUser: nil,
+			expectedErr:  user.ErrEmailNotVerified,
+		},
 		{
 			name:     "User not found",
 			email:    "test@example.com",
@@ -155,7 +209,7 @@ func TestService_VerifyLogin(t *testing.T) {
 func TestService_UpdateProfile(t *testing.T) {
 	logger := zap.NewNop()
 	mockRepo := new(MockRepository)
-	svc := user.NewService(mockRepo, logger)
+	svc := user.NewService(mockRepo, testEmails(mail.NewMemoryOutbox()), logger)
 
 	fullName := "New Name"
 
@@ -214,7 +268,7 @@ func TestService_UpdateProfile(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockSetup()
-			err := svc.UpdateProfile(context.Background(), tt.callerID, tt.targetID, tt.fullName)
+			err := svc.UpdateProfile(context.Background(), tt.callerID, tt.targetID, user.ProfileUpdate{FullName: tt.fullName})
 			assert.Equal(t, tt.expectedErr, err)
 			mockRepo.AssertExpectations(t)
 		})
@@ -0,0 +1,238 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"image/png"
+	"strings"
+	"time"
+
+	"ticket-booking/pkg/config"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTOTPAlreadyEnabled = Err("two-factor authentication is already enabled")
+	ErrTOTPNotEnabled     = Err("two-factor authentication is not enabled")
+	ErrTOTPNotEnrolled    = Err("start two-factor enrolment first")
+	ErrInvalidOTP         = Err("invalid authentication code")
+)
+
+// totpPeriod is the TOTP time step; codes from one step either side are accepted for clock drift
+const totpPeriod = 30 * time.Second
+
+var totpOpts = totp.ValidateOpts{Period: uint(totpPeriod / time.Second), Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+
+// TOTPEnrollment is what an authenticator app needs to add the account
+type TOTPEnrollment struct {
+	Secret string // Base32, for manual entry
+	URL    string // otpauth:// URI
+	QRCode []byte // PNG of URL
+}
+
+// EnrollTOTP starts (or restarts) enrolment with a fresh secret. Codes are
+// not required at login until ConfirmTOTP proves the app has the secret.
+func (s *Service) EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollment, error) {
+	u, err := s.Reload(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabledAt != nil {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: config.DefaultTOTPIssuer, AccountName: u.Email})
+	if err != nil {
+		return nil, err
+	}
+	img, err := key.Image(256, 256)
+	if err != nil {
+		return nil, err
+	}
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		return nil, err
+	}
+
+	u.TOTPSecret = key.Secret()
+	u.TOTPLastStep = 0
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to store TOTP secret", zap.String("user_id", u.ID), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("TOTP enrolment started", zap.String("user_id", u.ID))
+	return &TOTPEnrollment{Secret: key.Secret(), URL: key.URL(), QRCode: qr.Bytes()}, nil
+}
+
+// ConfirmTOTP turns on two-factor login once code matches the enrolled
+// secret and returns the recovery codes. They are only shown this once.
+func (s *Service) ConfirmTOTP(ctx context.Context, userID, code string) ([]string, error) {
+	u, err := s.Reload(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabledAt != nil {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if u.TOTPSecret == "" {
+		return nil, ErrTOTPNotEnrolled
+	}
+	if !checkTOTP(u, code, time.Now()) {
+		return nil, ErrInvalidOTP
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	u.TOTPEnabledAt = &now
+	u.RecoveryCodes = hashes
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to enable TOTP", zap.String("user_id", u.ID), zap.Error(err))
+		return nil, err
+	}
+	s.logger.Info("TOTP enabled", zap.String("user_id", u.ID))
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor login off; code is a current TOTP or recovery code
+func (s *Service) DisableTOTP(ctx context.Context, userID, code string) error {
+	u, err := s.Reload(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.TOTPEnabledAt == nil {
+		return ErrTOTPNotEnabled
+	}
+	if !checkSecondFactor(u, code, time.Now()) {
+		return ErrInvalidOTP
+	}
+	u.TOTPSecret, u.TOTPEnabledAt, u.TOTPLastStep, u.RecoveryCodes = "", nil, 0, nil
+	if err := s.repo.Update(u); err != nil {
+		s.logger.Error("Failed to disable TOTP", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("TOTP disabled", zap.String("user_id", u.ID))
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code; code is a current TOTP code
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	u, err := s.Reload(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabledAt == nil {
+		return nil, ErrTOTPNotEnabled
+	}
+	step, old := u.TOTPLastStep, u.RecoveryCodes
+	if !checkTOTP(u, code, time.Now()) {
+		return nil, ErrInvalidOTP
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	u.RecoveryCodes = hashes
+	ok, err := s.repo.UpdateSecondFactor(u, step, old)
+	if err != nil {
+		s.logger.Error("Failed to store recovery codes", zap.String("user_id", u.ID), zap.Error(err))
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidOTP
+	}
+	s.logger.Info("Recovery codes regenerated", zap.String("user_id", u.ID))
+	return codes, nil
+}
+
+// VerifySecondFactor checks the second login step. A TOTP code is accepted
+// once; a recovery code is used up. Of two logins using the same code at
+// once, only the first to store it succeeds.
+func (s *Service) VerifySecondFactor(ctx context.Context, u *User, code string) error {
+	if u.TOTPEnabledAt == nil {
+		return ErrTOTPNotEnabled
+	}
+	step, codes := u.TOTPLastStep, u.RecoveryCodes
+	if !checkSecondFactor(u, code, time.Now()) {
+		s.logger.Warn("Invalid second factor", zap.String("user_id", u.ID))
+		return ErrInvalidOTP
+	}
+	ok, err := s.repo.UpdateSecondFactor(u, step, codes)
+	if err != nil {
+		s.logger.Error("Failed to record second factor", zap.String("user_id", u.ID), zap.Error(err))
+		return err
+	}
+	if !ok {
+		s.logger.Warn("Second factor used by another login", zap.String("user_id", u.ID))
+		return ErrInvalidOTP
+	}
+	return nil
+}
+
+// checkSecondFactor accepts a TOTP code or a recovery code and updates u to
+// use it up. The caller persists u with Repository.UpdateSecondFactor.
+func checkSecondFactor(u *User, code string, now time.Time) bool {
+	if checkTOTP(u, code, now) {
+		return true
+	}
+	h := hashRecoveryCode(code)
+	for i, stored := range u.RecoveryCodes {
+		if stored == h {
+			u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// checkTOTP accepts a code for the current time step or one either side,
+// newer than the last accepted step so a code cannot be replayed
+func checkTOTP(u *User, code string, now time.Time) bool {
+	code = strings.TrimSpace(code)
+	if u.TOTPSecret == "" || len(code) != otp.DigitsSix.Length() {
+		return false
+	}
+	for _, skew := range []time.Duration{0, -totpPeriod, totpPeriod} {
+		t := now.Add(skew)
+		step := t.Unix() / int64(totpPeriod/time.Second)
+		if step <= u.TOTPLastStep {
+			continue
+		}
+		if ok, _ := totp.ValidateCustom(code, u.TOTPSecret, t, totpOpts); ok {
+			u.TOTPLastStep = step
+			return true
+		}
+	}
+	return false
+}
+
+// newRecoveryCodes returns codes like "k3jd9-x8a2p" and their hashes
+func newRecoveryCodes() (codes, hashes []string, err error) {
+	enc := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := 0; i < config.DefaultRecoveryCodeCount; i++ {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		s := strings.ToLower(enc.EncodeToString(raw))[:10]
+		code := s[:5] + "-" + s[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+func hashRecoveryCode(code string) string {
+	norm := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(norm))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,145 @@
+package user_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"ticket-booking/internal/auth"
+	"ticket-booking/internal/user"
+	"ticket-booking/pkg/mail"
+)
+
+func TestTwoFactor_AdminFlow(t *testing.T) {
+	repo := newMemRepo()
+	r, cfg, _ := newTestRouter(t, repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	verified := time.Now()
+	repo.users["admin-1"] = &user.User{ID: "admin-1", Email: "admin@example.com", Role: "ADMIN", PasswordHash: string(hashed), EmailVerifiedAt: &verified}
+	creds := user.LoginRequest{Email: "admin@example.com", Password: "password123"}
+	unlock := "/admin/users/admin-1/unlock"
+
+	// Password only: logged in, but told to enrol and kept out of admin routes
+	w := postJSON(r, "/users/login", creds)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	pwd := decode[user.LoginResponse](t, w)
+	require.True(t, pwd.MFAEnrollmentRequired)
+	claims, err := auth.ValidateAccessToken(cfg, nil, pwd.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, []string{auth.AMRPassword}, claims.AMR)
+	w = postJSONAuth(r, unlock, pwd.AccessToken, nil)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "two-factor authentication required")
+
+	// Enrol and confirm
+	w = postJSONAuth(r, "/users/2fa/enroll", pwd.AccessToken, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	enrol := decode[user.TOTPEnrollmentResponse](t, w)
+	require.Contains(t, enrol.OTPAuthURL, "otpauth://totp/")
+	require.Contains(t, enrol.QRCode, "data:image/png;base64,")
+
+	w = postJSONAuth(r, "/users/2fa/confirm", pwd.AccessToken, user.TOTPCodeRequest{Code: "000000"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	code, _ := totp.GenerateCode(enrol.Secret, time.Now())
+	w = postJSONAuth(r, "/users/2fa/confirm", pwd.AccessToken, user.TOTPCodeRequest{Code: code})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	recovery := decode[user.RecoveryCodesResponse](t, w).RecoveryCodes
+	require.Len(t, recovery, 10)
+
+	// Login now stops at a challenge
+	w = postJSON(r, "/users/login", creds)
+	require.Equal(t, http.StatusOK, w.Code)
+	step1 := decode[user.LoginResponse](t, w)
+	require.True(t, step1.MFARequired)
+	require.Empty(t, step1.AccessToken)
+	require.NotEmpty(t, step1.ChallengeToken)
+
+	// The code used to confirm cannot be replayed
+	w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: step1.ChallengeToken, Code: code})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	next, _ := totp.GenerateCode(enrol.Secret, time.Now().Add(30*time.Second))
+	w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: step1.ChallengeToken, Code: next})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	mfa := decode[user.LoginResponse](t, w)
+	require.False(t, mfa.MFAEnrollmentRequired)
+	claims, err = auth.ValidateAccessToken(cfg, nil, mfa.AccessToken)
+	require.NoError(t, err)
+	require.True(t, claims.HasAMR(auth.AMROTP))
+	require.Equal(t, http.StatusNoContent, postJSONAuth(r, unlock, mfa.AccessToken, nil).Code)
+
+	// amr survives a refresh
+	w = postJSON(r, "/users/refresh", user.RefreshTokenRequest{RefreshToken: mfa.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	claims, err = auth.ValidateAccessToken(cfg, nil, decode[user.LoginResponse](t, w).AccessToken)
+	require.NoError(t, err)
+	require.True(t, claims.HasAMR(auth.AMROTP))
+
+	// A completed challenge is dead
+	w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: step1.ChallengeToken, Code: recovery[0]})
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// A recovery code works once
+	for _, want := range []int{http.StatusOK, http.StatusUnauthorized} {
+		w = postJSON(r, "/users/login", creds)
+		challenge := decode[user.LoginResponse](t, w).ChallengeToken
+		w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: challenge, Code: recovery[1]})
+		require.Equal(t, want, w.Code, w.Body.String())
+	}
+}
+
+func TestTwoFactor_WrongCodesLockOut(t *testing.T) {
+	repo := newMemRepo()
+	r, _, _ := newTestRouter(t, repo)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	now := time.Now()
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "u@example.com"})
+	require.NoError(t, err)
+	repo.users["u1"] = &user.User{ID: "u1", Email: "u@example.com", Role: "USER", PasswordHash: string(hashed),
+		EmailVerifiedAt: &now, TOTPSecret: key.Secret(), TOTPEnabledAt: &now}
+
+	w := postJSON(r, "/users/login", user.LoginRequest{Email: "u@example.com", Password: "password123"})
+	challenge := decode[user.LoginResponse](t, w).ChallengeToken
+	for i := 0; i < testCfg.LoginMaxAttempts; i++ {
+		w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: challenge, Code: "000000"})
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+	code, _ := totp.GenerateCode(key.Secret(), time.Now())
+	w = postJSON(r, "/users/login/2fa", user.TwoFactorLoginRequest{ChallengeToken: challenge, Code: code})
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+}
+
+func TestTwoFactor_ConcurrentUseOfOneCode(t *testing.T) {
+	repo := newMemRepo()
+	svc := user.NewService(repo, testEmails(mail.NewMemoryOutbox()), zap.NewNop())
+	ctx := context.Background()
+
+	now := time.Now()
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "test", AccountName: "u@example.com"})
+	require.NoError(t, err)
+	repo.users["u1"] = &user.User{ID: "u1", Email: "u@example.com", Role: "USER", EmailVerifiedAt: &now, TOTPSecret: key.Secret()}
+	code, _ := totp.GenerateCode(key.Secret(), now)
+	recovery, err := svc.ConfirmTOTP(ctx, "u1", code)
+	require.NoError(t, err)
+
+	next, _ := totp.GenerateCode(key.Secret(), now.Add(30*time.Second))
+	for _, code := range []string{next, recovery[0]} {
+		// Both logins read the user before either stores the used code
+		first, _ := repo.ByID("u1")
+		second, _ := repo.ByID("u1")
+		errs := make(chan error, 2)
+		for _, u := range []*user.User{first, second} {
+			go func() { errs <- svc.VerifySecondFactor(ctx, u, code) }()
+		}
+		got := []error{<-errs, <-errs}
+		require.ElementsMatch(t, []error{nil, user.ErrInvalidOTP}, got, "code %s", code)
+	}
+}
@@ -0,0 +1,70 @@
+package venue
+
+// CreateVenueRequest input for creating a venue
+type CreateVenueRequest struct {
+	Name            string   `json:"name" binding:"required,max=100" example:"Saigon Opera House"`
+	AddressLine1    string   `json:"address_line1" binding:"required,max=200" example:"7 Cong Truong Lam Son"`
+	AddressLine2    *string  `json:"address_line2" binding:"omitempty,max=200" example:"Ben Nghe Ward"`
+	City            string   `json:"city" binding:"required,max=100" example:"Ho Chi Minh City"`
+	Region          *string  `json:"region" binding:"omitempty,max=100" example:"District 1"`
+	PostalCode      *string  `json:"postal_code" binding:"omitempty,max=20" example:"700000"`
+	Country         string   `json:"country" binding:"required,iso3166_1_alpha2" example:"VN"`
+	Timezone        string   `json:"timezone" binding:"required" example:"Asia/Ho_Chi_Minh"`
+	DefaultCapacity int      `json:"default_capacity" binding:"gte=0" example:"500"`
+	Latitude        *float64 `json:"latitude" binding:"omitempty,latitude" example:"10.7766"`
+	Longitude       *float64 `json:"longitude" binding:"omitempty,longitude" example:"106.7031"`
+}
+
+// UpdateVenueRequest input for updating a venue; omitted fields are unchanged
+type UpdateVenueRequest struct {
+	Name            *string  `json:"name" binding:"omitempty,max=100" example:"Saigon Opera House"`
+	AddressLine1    *string  `json:"address_line1" binding:"omitempty,max=200" example:"7 Cong Truong Lam Son"`
+	AddressLine2    *string  `json:"address_line2" binding:"omitempty,max=200" example:"Ben Nghe Ward"`
+	City            *string  `json:"city" binding:"omitempty,max=100" example:"Ho Chi Minh City"`
+	Region          *string  `json:"region" binding:"omitempty,max=100" example:"District 1"`
+	PostalCode      *string  `json:"postal_code" binding:"omitempty,max=20" example:"700000"`
+	Country         *string  `json:"country" binding:"omitempty,iso3166_1_alpha2" example:"VN"`
+	Timezone        *string  `json:"timezone" example:"Asia/Ho_Chi_Minh"`
+	DefaultCapacity *int     `json:"default_capacity" binding:"omitempty,gte=0" example:"600"`
+	Latitude        *float64 `json:"latitude" binding:"omitempty,latitude" example:"10.7766"`
+	Longitude       *float64 `json:"longitude" binding:"omitempty,longitude" example:"106.7031"`
+}
+
+// VenueResponse represents venue output; also embedded in event responses
+type VenueResponse struct {
+	ID              string   `json:"id" example:"7c9e6679-7425-40de-944b-e07fc1f90ae7"`
+	Name            string   `json:"name" example:"Saigon Opera House"`
+	AddressLine1    string   `json:"address_line1" example:"7 Cong Truong Lam Son"`
+	AddressLine2    *string  `json:"address_line2,omitempty" example:"Ben Nghe Ward"`
+	City            string   `json:"city" example:"Ho Chi Minh City"`
+	Region          *string  `json:"region,omitempty" example:"District 1"`
+	PostalCode      *string  `json:"postal_code,omitempty" example:"700000"`
+	Country         string   `json:"country" example:"VN"`
+	Timezone        string   `json:"timezone" example:"Asia/Ho_Chi_Minh"`
+	DefaultCapacity int      `json:"default_capacity" example:"500"`
+	Latitude        *float64 `json:"latitude,omitempty" example:"10.7766"`
+	Longitude       *float64 `json:"longitude,omitempty" example:"106.7031"`
+}
+
+// ErrorResponse standard error model
+type ErrorResponse struct {
+	Error string `json:"error" example:"invalid request"`
+}
+
+// ToResponse maps a venue to its API representation
+func ToResponse(v *Venue) VenueResponse {
+	return VenueResponse{
+		ID:              v.ID,
+		Name:            v.Name,
+		AddressLine1:    v.AddressLine1,
+		AddressLine2:    v.AddressLine2,
+		City:            v.City,
+		Region:          v.Region,
+		PostalCode:      v.PostalCode,
+		Country:         v.Country,
+		Timezone:        v.Timezone,
+		DefaultCapacity: v.DefaultCapacity,
+		Latitude:        v.Latitude,
+		Longitude:       v.Longitude,
+	}
+}
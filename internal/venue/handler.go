@@ -0,0 +1,200 @@
+package venue
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Handler struct {
+	svc    *Service
+	logger *zap.Logger
+}
+
+func NewHandler(s *Service, logger *zap.Logger) *Handler {
+	return &Handler{svc: s, logger: logger}
+}
+
+// List godoc
+// @Summary List venues
+// @Description List venues ordered by name (Admin only)
+// @Tags venues
+// @Produce json
+// @Param limit query int false "Max items to return (default 20, max 100)"
+// @Param offset query int false "Offset for pagination (default 0)"
+// @Success 200 {array} VenueResponse
+// @Failure 500 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/venues [get]
+func (h *Handler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	vs, err := h.svc.List(c, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		return
+	}
+	out := make([]VenueResponse, 0, len(vs))
+	for i := range vs {
+		out = append(out, ToResponse(&vs[i]))
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get venue
+// @Description Get a venue by ID (Admin only)
+// @Tags venues
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Success 200 {object} VenueResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/venues/{id} [get]
+func (h *Handler) Get(c *gin.Context) {
+	v, err := h.svc.Get(c, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	c.JSON(http.StatusOK, ToResponse(v))
+}
+
+// Create godoc
+// @Summary Create venue
+// @Description Create a venue (Admin only)
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param input body CreateVenueRequest true "Venue data"
+// @Success 201 {object} VenueResponse
+// @Failure 400 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/venues [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid venue creation request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	v := &Venue{
+		Name:            req.Name,
+		AddressLine1:    req.AddressLine1,
+		AddressLine2:    req.AddressLine2,
+		City:            req.City,
+		Region:          req.Region,
+		PostalCode:      req.PostalCode,
+		Country:         req.Country,
+		Timezone:        req.Timezone,
+		DefaultCapacity: req.DefaultCapacity,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+	}
+	if err := h.svc.Create(c, v); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, ToResponse(v))
+}
+
+// Update godoc
+// @Summary Update venue
+// @Description Update venue details (Admin only)
+// @Tags venues
+// @Accept json
+// @Produce json
+// @Param id path string true "Venue ID"
+// @Param input body UpdateVenueRequest true "Updated venue data"
+// @Success 200 {object} VenueResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Security BearerAuth
+// @Router /admin/venues/{id} [put]
+func (h *Handler) Update(c *gin.Context) {
+	id := c.Param("id")
+	var req UpdateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Warn("Invalid venue update request", zap.String("venue_id", id), zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	v, err := h.svc.Get(c, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		return
+	}
+	if req.Name != nil {
+		v.Name = *req.Name
+	}
+	if req.AddressLine1 != nil {
+		v.AddressLine1 = *req.AddressLine1
+	}
+	if req.AddressLine2 != nil {
+		v.AddressLine2 = req.AddressLine2
+	}
+	if req.City != nil {
+		v.City = *req.City
+	}
+	if req.Region != nil {
+		v.Region = req.Region
+	}
+	if req.PostalCode != nil {
+		v.PostalCode = req.PostalCode
+	}
+	if req.Country != nil {
+		v.Country = *req.Country
+	}
+	if req.Timezone != nil {
+		v.Timezone = *req.Timezone
+	}
+	if req.DefaultCapacity != nil {
+		v.DefaultCapacity = *req.DefaultCapacity
+	}
+	if req.Latitude != nil {
+		v.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		v.Longitude = req.Longitude
+	}
+	if err := h.svc.Update(c, v); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, ToResponse(v))
+}
+
+// Delete godoc
+// @Summary Delete venue
+// @Description Delete a venue that no event references (Admin only)
+// @Tags venues
+// @Param id path string true "Venue ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Venue is referenced by events"
+// @Security BearerAuth
+// @Router /admin/venues/{id} [delete]
+func (h *Handler) Delete(c *gin.Context) {
+	if err := h.svc.Delete(c, c.Param("id")); err != nil {
+		switch {
+		case errors.Is(err, ErrVenueInUse):
+			c.JSON(http.StatusConflict, ErrorResponse{Error: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "internal error"})
+		}
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+// Package venue manages the places where events are held: address,
+// timezone, default seating capacity and geo coordinates.
+package venue
+
+import "time"
+
+// Venue represents a physical location that hosts events.
+type Venue struct {
+	ID              string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name            string    `gorm:"type:text;not null" json:"name"`             // Display name
+	AddressLine1    string    `gorm:"type:text;not null" json:"address_line1"`    // Street address
+	AddressLine2    *string   `gorm:"type:text" json:"address_line2,omitempty"`   // Optional unit/floor
+	City            string    `gorm:"type:text;not null" json:"city"`             // City or locality
+	Region          *string   `gorm:"type:text" json:"region,omitempty"`          // State/province
+	PostalCode      *string   `gorm:"type:text" json:"postal_code,omitempty"`     // Postal/ZIP code
+	Country         string    `gorm:"type:char(2);not null" json:"country"`       // ISO 3166-1 alpha-2
+	Timezone        string    `gorm:"type:text;not null" json:"timezone"`         // IANA zone, e.g. Asia/Ho_Chi_Minh
+	DefaultCapacity int       `gorm:"not null;default:0" json:"default_capacity"` // Used when an event omits capacity
+	Latitude        *float64  `gorm:"type:double precision" json:"latitude,omitempty"`
+	Longitude       *float64  `gorm:"type:double precision" json:"longitude,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
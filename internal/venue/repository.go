@@ -0,0 +1,57 @@
+package venue
+
+import "gorm.io/gorm"
+
+type VenueRepository interface {
+	List(limit, offset int) ([]Venue, error)
+	Get(id string) (*Venue, error)
+	Create(v *Venue) error
+	Update(v *Venue) error
+	Delete(id string) error
+	CountEvents(id string) (int64, error)
+}
+
+type repo struct{ db *gorm.DB }
+
+func NewVenueRepository(db *gorm.DB) VenueRepository { return &repo{db} }
+
+func (r *repo) List(limit, offset int) ([]Venue, error) {
+	var out []Venue
+	q := r.db.Order("name asc")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if offset > 0 {
+		q = q.Offset(offset)
+	}
+	return out, q.Find(&out).Error
+}
+
+func (r *repo) Get(id string) (*Venue, error) {
+	var v Venue
+	if err := r.db.First(&v, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func (r *repo) Create(v *Venue) error { return r.db.Create(v).Error }
+func (r *repo) Update(v *Venue) error { return r.db.Save(v).Error }
+
+func (r *repo) Delete(id string) error {
+	res := r.db.Delete(&Venue{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountEvents counts events (including archived ones) that reference the venue
+func (r *repo) CountEvents(id string) (int64, error) {
+	var n int64
+	err := r.db.Raw("SELECT COUNT(*) FROM events WHERE venue_id = ?", id).Scan(&n).Error
+	return n, err
+}
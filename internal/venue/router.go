@@ -0,0 +1,11 @@
+package venue
+
+import "github.com/gin-gonic/gin"
+
+func RegisterAdminRoutes(r *gin.RouterGroup, h *Handler) {
+	r.GET("/venues", h.List)
+	r.GET("/venues/:id", h.Get)
+	r.POST("/venues", h.Create)
+	r.PUT("/venues/:id", h.Update)
+	r.DELETE("/venues/:id", h.Delete)
+}
@@ -0,0 +1,100 @@
+package venue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidTimezone is returned when a timezone is not a known IANA zone
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrIncompleteCoordinates is returned when only one of latitude/longitude is set
+	ErrIncompleteCoordinates = errors.New("latitude and longitude must be set together")
+	// ErrVenueInUse is returned when deleting a venue that events still reference
+	ErrVenueInUse = errors.New("venue is referenced by events")
+)
+
+// Service implements venue management with validation of timezone and coordinates.
+type Service struct {
+	repo   VenueRepository
+	logger *zap.Logger
+}
+
+// NewService creates a new venue service
+func NewService(r VenueRepository, logger *zap.Logger) *Service {
+	return &Service{repo: r, logger: logger}
+}
+
+func validate(v *Venue) error {
+	if _, err := time.LoadLocation(v.Timezone); err != nil || v.Timezone == "" || v.Timezone == "Local" {
+		return ErrInvalidTimezone
+	}
+	if (v.Latitude == nil) != (v.Longitude == nil) {
+		return ErrIncompleteCoordinates
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]Venue, error) {
+	out, err := s.repo.List(limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list venues", zap.Error(err))
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Venue, error) {
+	v, err := s.repo.Get(id)
+	if err != nil {
+		s.logger.Error("Failed to get venue", zap.String("venue_id", id), zap.Error(err))
+		return nil, err
+	}
+	return v, nil
+}
+
+func (s *Service) Create(ctx context.Context, v *Venue) error {
+	if err := validate(v); err != nil {
+		return err
+	}
+	if err := s.repo.Create(v); err != nil {
+		s.logger.Error("Failed to create venue", zap.String("name", v.Name), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Venue created", zap.String("venue_id", v.ID))
+	return nil
+}
+
+func (s *Service) Update(ctx context.Context, v *Venue) error {
+	if err := validate(v); err != nil {
+		return err
+	}
+	if err := s.repo.Update(v); err != nil {
+		s.logger.Error("Failed to update venue", zap.String("venue_id", v.ID), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Venue updated", zap.String("venue_id", v.ID))
+	return nil
+}
+
+// Delete removes a venue that no event references
+func (s *Service) Delete(ctx context.Context, id string) error {
+	n, err := s.repo.CountEvents(id)
+	if err != nil {
+		s.logger.Error("Failed to count venue events", zap.String("venue_id", id), zap.Error(err))
+		return err
+	}
+	if n > 0 {
+		s.logger.Warn("Refusing to delete venue in use", zap.String("venue_id", id), zap.Int64("events", n))
+		return ErrVenueInUse
+	}
+	if err := s.repo.Delete(id); err != nil {
+		s.logger.Error("Failed to delete venue", zap.String("venue_id", id), zap.Error(err))
+		return err
+	}
+	s.logger.Info("Venue deleted", zap.String("venue_id", id))
+	return nil
+}
@@ -0,0 +1,55 @@
+package venue_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+
+	"ticket-booking/internal/mocks"
+	"ticket-booking/internal/venue"
+)
+
+func TestCreateVenue_ValidatesTimezone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockVenueRepository(ctrl)
+	svc := venue.NewService(repo, zap.NewNop())
+
+	err := svc.Create(context.Background(), &venue.Venue{Name: "Hall", Timezone: "Mars/Olympus"})
+	require.ErrorIs(t, err, venue.ErrInvalidTimezone)
+
+	v := &venue.Venue{Name: "Hall", Timezone: "Asia/Ho_Chi_Minh"}
+	repo.EXPECT().Create(v).Return(nil)
+	require.NoError(t, svc.Create(context.Background(), v))
+}
+
+func TestCreateVenue_RequiresBothCoordinates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockVenueRepository(ctrl)
+	svc := venue.NewService(repo, zap.NewNop())
+
+	lat := 10.77
+	err := svc.Create(context.Background(), &venue.Venue{Name: "Hall", Timezone: "UTC", Latitude: &lat})
+	require.ErrorIs(t, err, venue.ErrIncompleteCoordinates)
+}
+
+func TestDeleteVenue_RefusedWhileReferenced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := mocks.NewMockVenueRepository(ctrl)
+	svc := venue.NewService(repo, zap.NewNop())
+
+	repo.EXPECT().CountEvents("v1").Return(int64(2), nil)
+	require.ErrorIs(t, svc.Delete(context.Background(), "v1"), venue.ErrVenueInUse)
+
+	repo.EXPECT().CountEvents("v2").Return(int64(0), nil)
+	repo.EXPECT().Delete("v2").Return(nil)
+	require.NoError(t, svc.Delete(context.Background(), "v2"))
+}
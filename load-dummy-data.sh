@@ -112,14 +112,15 @@ fresh_setup() {
     execute_sql "DROP DATABASE IF EXISTS $DB_NAME;" "Dropping existing database"
     execute_sql "CREATE DATABASE $DB_NAME;" "Creating new database"
 
-    # Run migrations
-    if [ -f "migrations/001_init.sql" ]; then
-        execute_sql_file "migrations/001_init.sql" "Running database migrations"
-        log_success "Database migrations completed"
-    else
+    # Run migrations (in filename order)
+    if [ ! -f "migrations/001_init.sql" ]; then
         log_error "Migration file not found: migrations/001_init.sql"
         exit 1
     fi
+    for migration in migrations/*.sql; do
+        execute_sql_file "$migration" "Running migration $(basename "$migration")"
+    done
+    log_success "Database migrations completed"
 }
 
 # Load dummy data
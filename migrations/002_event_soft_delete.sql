@@ -0,0 +1,5 @@
+-- Soft delete for events: rows are archived instead of removed so that
+-- bookings (and their financial records) keep a valid event reference.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_events_deleted_at ON events(deleted_at);
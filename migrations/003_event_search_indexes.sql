@@ -0,0 +1,11 @@
+-- Indexes backing GET /events filters (text search, date/price range, availability).
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes make ILIKE '%term%' on name/description index-assisted
+CREATE INDEX IF NOT EXISTS idx_events_name_trgm ON events USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_events_description_trgm ON events USING gin (description gin_trgm_ops);
+
+-- Range filters and sorts over live (non-deleted) events
+CREATE INDEX IF NOT EXISTS idx_events_live_starts_at ON events(starts_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_live_price ON events(ticket_price_cents, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_events_live_available ON events(starts_at) WHERE deleted_at IS NULL AND remaining > 0;
@@ -0,0 +1,24 @@
+-- Recurring event series. Each occurrence is a regular row in events that
+-- points back at its series; series_override marks occurrences edited on
+-- their own so series-wide edits leave them alone.
+CREATE TABLE IF NOT EXISTS event_series (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name TEXT NOT NULL,
+  description TEXT,
+  rrule TEXT NOT NULL,
+  timezone TEXT NOT NULL DEFAULT 'UTC',
+  starts_at TIMESTAMPTZ NOT NULL,
+  duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+  capacity INT NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+  ticket_price_cents BIGINT NOT NULL DEFAULT 0,
+  venue_id UUID REFERENCES venues(id) ON DELETE RESTRICT,
+  organizer_id UUID REFERENCES organizers(id) ON DELETE RESTRICT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Past occurrences outlive their series as standalone events
+ALTER TABLE events ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES event_series(id) ON DELETE SET NULL;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS series_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_events_series_starts ON events(series_id, starts_at) WHERE series_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Event ownership for the ORGANIZER role: organizers manage only events they
+-- own; admins keep global access. Existing events stay unowned (admin-only).
+ALTER TABLE events ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_events_owner_starts ON events(owner_id, starts_at) WHERE owner_id IS NOT NULL;
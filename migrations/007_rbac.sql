@@ -0,0 +1,39 @@
+-- Permission-based RBAC: roles map to permissions, resolved into the access
+-- token at issue time. New roles (e.g. SUPPORT, FINANCE) are rows, not code.
+CREATE TABLE IF NOT EXISTS roles (
+  name TEXT PRIMARY KEY,
+  description TEXT,
+  built_in BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+  role TEXT NOT NULL REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+  permission TEXT NOT NULL,
+  PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description, built_in) VALUES
+  ('USER', 'Customers booking tickets', TRUE),
+  ('ORGANIZER', 'Manages the events they own', TRUE),
+  ('ADMIN', 'Full administrative access', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('ORGANIZER', 'events:own'),
+  ('ADMIN', 'events:write'),
+  ('ADMIN', 'events:own'),
+  ('ADMIN', 'venues:write'),
+  ('ADMIN', 'search:reindex'),
+  ('ADMIN', 'bookings:read'),
+  ('ADMIN', 'bookings:refund'),
+  ('ADMIN', 'reports:read'),
+  ('ADMIN', 'roles:manage'),
+  ('ADMIN', 'users:manage')
+ON CONFLICT DO NOTHING;
+
+-- Every user must hold a role that exists
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE users ADD CONSTRAINT fk_users_role
+  FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
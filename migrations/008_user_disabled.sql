@@ -0,0 +1,2 @@
+-- Disabled accounts can neither log in nor refresh tokens
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Accounts must confirm their email before logging in. Existing accounts
+-- predate verification and are treated as verified.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
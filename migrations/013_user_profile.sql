@@ -0,0 +1,8 @@
+-- Self-service profile: phone, notification preferences, email change
+-- awaiting confirmation, and anonymisation on account deletion. Deleted
+-- accounts keep their row so bookings stay attached for accounting.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pending_email TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS notifications JSONB NOT NULL
+  DEFAULT '{"booking_updates": true, "event_reminders": true, "marketing": false}';
@@ -0,0 +1,9 @@
+-- Admin booking console: support staff confirm, cancel and move bookings
+-- with bookings:manage; refunds keep their own bookings:refund permission.
+-- REFUNDED joins the booking statuses (status is free text, no change needed).
+INSERT INTO role_permissions (role, permission) VALUES
+  ('ADMIN', 'bookings:manage')
+ON CONFLICT DO NOTHING;
+
+-- Date-range searches over all bookings
+CREATE INDEX IF NOT EXISTS idx_bookings_created_at ON bookings(created_at);
@@ -0,0 +1,28 @@
+-- Tamper-evident audit log: entries are numbered and hash-chained, each
+-- storing the hash of the one before it. Entries written before this
+-- migration are numbered in order but left unsealed (empty hash).
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS seq BIGINT;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS changes JSONB;
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS hash TEXT NOT NULL DEFAULT '';
+
+UPDATE audit_log a SET seq = n.seq
+FROM (SELECT id, row_number() OVER (ORDER BY created_at, id) AS seq FROM audit_log) n
+WHERE a.id = n.id AND a.seq IS NULL;
+
+ALTER TABLE audit_log ALTER COLUMN seq SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_log_seq ON audit_log(seq);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action ON audit_log(action);
+
+-- Append-only: rows can be added, never changed or removed
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_append_only ON audit_log;
+CREATE TRIGGER audit_log_append_only
+  BEFORE UPDATE OR DELETE ON audit_log
+  FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,19 @@
+-- Door tickets: one row per seat of a confirmed booking. Cancelling,
+-- refunding or moving the booking voids its tickets rather than deleting them.
+CREATE TABLE IF NOT EXISTS tickets (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  event_id UUID NOT NULL REFERENCES events(id),
+  holder_id UUID NOT NULL REFERENCES users(id),
+  number INT NOT NULL,
+  code TEXT NOT NULL UNIQUE,
+  status TEXT NOT NULL DEFAULT 'VALID' CHECK (status IN ('VALID','VOID')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  voided_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_tickets_booking ON tickets(booking_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_event ON tickets(event_id);
+
+-- A seat has at most one live ticket, so concurrent issuing cannot double up
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tickets_live_seat ON tickets(booking_id, event_id, number) WHERE status <> 'VOID';
@@ -0,0 +1,16 @@
+-- Door check-in: scanning a ticket marks it USED with the time, gate and
+-- staff member. SCANNER is a built-in role for event staff.
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_check;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_check CHECK (status IN ('VALID','USED','VOID'));
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS gate TEXT;
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS checked_in_by UUID REFERENCES users(id);
+
+INSERT INTO roles (name, description, built_in) VALUES
+  ('SCANNER', 'Event staff checking tickets at the door', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('SCANNER', 'tickets:scan'),
+  ('ADMIN', 'tickets:scan')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,25 @@
+-- Ticket transfers: a holder offers tickets to an email address; accepting
+-- voids the offered tickets and issues the recipient new codes for the same
+-- seats. tickets.transfer_id marks tickets offered by a pending transfer and,
+-- once accepted, the transfer that replaced them.
+CREATE TABLE IF NOT EXISTS ticket_transfers (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  event_id UUID NOT NULL REFERENCES events(id),
+  from_user_id UUID NOT NULL REFERENCES users(id),
+  from_email TEXT NOT NULL,
+  to_email TEXT NOT NULL,
+  to_user_id UUID REFERENCES users(id),
+  count INT NOT NULL CHECK (count > 0),
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','ACCEPTED','DECLINED','CANCELLED','EXPIRED')),
+  expires_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_from ON ticket_transfers(from_user_id);
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_to ON ticket_transfers(to_email);
+CREATE INDEX IF NOT EXISTS idx_ticket_transfers_pending ON ticket_transfers(expires_at) WHERE status = 'PENDING';
+
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS transfer_id UUID REFERENCES ticket_transfers(id);
+CREATE INDEX IF NOT EXISTS idx_tickets_holder ON tickets(holder_id);
+CREATE INDEX IF NOT EXISTS idx_tickets_transfer ON tickets(transfer_id);
@@ -0,0 +1,52 @@
+-- Resale market: holders list tickets up to the organiser's cap, a percentage
+-- of the price paid (0 disables resale). A purchase reserves its listing while
+-- the payment side charges the buyer; on payment the seller's ticket is voided,
+-- the buyer gets a new code for the same seat and the seller a payout record.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS resale_cap_percent INT NOT NULL DEFAULT 0 CHECK (resale_cap_percent >= 0);
+
+CREATE TABLE IF NOT EXISTS resale_listings (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  ticket_id UUID NOT NULL REFERENCES tickets(id),
+  booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  event_id UUID NOT NULL REFERENCES events(id),
+  seller_id UUID NOT NULL REFERENCES users(id),
+  price_cents BIGINT NOT NULL CHECK (price_cents > 0),
+  face_value_cents BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE','RESERVED','SOLD','WITHDRAWN','REMOVED')),
+  purchase_id UUID,
+  reserved_until TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  closed_at TIMESTAMPTZ
+);
+
+-- One open listing per ticket
+CREATE UNIQUE INDEX IF NOT EXISTS idx_resale_listings_open_ticket ON resale_listings(ticket_id) WHERE status IN ('ACTIVE','RESERVED');
+CREATE INDEX IF NOT EXISTS idx_resale_listings_event ON resale_listings(event_id, price_cents) WHERE status IN ('ACTIVE','RESERVED');
+CREATE INDEX IF NOT EXISTS idx_resale_listings_seller ON resale_listings(seller_id);
+CREATE INDEX IF NOT EXISTS idx_resale_listings_booking ON resale_listings(booking_id);
+
+CREATE TABLE IF NOT EXISTS resale_purchases (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  listing_id UUID NOT NULL REFERENCES resale_listings(id) ON DELETE CASCADE,
+  buyer_id UUID NOT NULL REFERENCES users(id),
+  amount_cents BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','COMPLETED','FAILED')),
+  ticket_id UUID REFERENCES tickets(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  resolved_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_resale_purchases_buyer ON resale_purchases(buyer_id);
+
+CREATE TABLE IF NOT EXISTS resale_payouts (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  listing_id UUID NOT NULL REFERENCES resale_listings(id) ON DELETE CASCADE,
+  purchase_id UUID NOT NULL UNIQUE REFERENCES resale_purchases(id) ON DELETE CASCADE,
+  seller_id UUID NOT NULL REFERENCES users(id),
+  amount_cents BIGINT NOT NULL,
+  status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','PAID')),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  paid_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_resale_payouts_seller ON resale_payouts(seller_id);
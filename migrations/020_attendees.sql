@@ -0,0 +1,13 @@
+-- Attendee details: who uses each seat of a booking, for badges and ID checks.
+-- Events list the custom fields they ask about on top of name and email.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS attendee_fields JSONB;
+
+CREATE TABLE IF NOT EXISTS attendees (
+  booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+  number INT NOT NULL CHECK (number > 0),
+  name TEXT NOT NULL,
+  email TEXT,
+  fields JSONB,
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  PRIMARY KEY (booking_id, number)
+);
@@ -0,0 +1,5 @@
+-- Registration questions: events define a form (text, choice and checkbox
+-- questions) and each booking stores its answers, checked against the form
+-- when the booking is made.
+ALTER TABLE events ADD COLUMN IF NOT EXISTS questions JSONB;
+ALTER TABLE bookings ADD COLUMN IF NOT EXISTS answers JSONB;
@@ -0,0 +1,16 @@
+-- Scan log: every scan presented at a gate, online or synced from an offline
+-- scanner, with its result. A ticket's first check-in stays on the ticket;
+-- later scans, earlier-dated ones included, are only logged.
+CREATE TABLE IF NOT EXISTS ticket_scans (
+  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+  code TEXT NOT NULL DEFAULT '',
+  event_id TEXT NOT NULL DEFAULT '',
+  result TEXT NOT NULL,
+  gate TEXT NOT NULL DEFAULT '',
+  scanned_at TIMESTAMPTZ NOT NULL,
+  scanned_by UUID REFERENCES users(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ticket_scans_code ON ticket_scans(code);
+CREATE INDEX IF NOT EXISTS idx_ticket_scans_event ON ticket_scans(event_id, scanned_at);
@@ -22,8 +22,21 @@ type Cache interface {
 	GetInt(ctx context.Context, key string) (int, error)
 	// Del removes a key from cache
 	Del(ctx context.Context, key string) error
+	// GetDel retrieves a string value and removes its key in one step
+	GetDel(ctx context.Context, key string) (string, error)
+	// CompareAndSet atomically replaces the value of key with value and ttl
+	// while it still equals old, and reports whether it did. A missing key
+	// returns redis.Nil.
+	CompareAndSet(ctx context.Context, key, old string, value interface{}, ttl time.Duration) (bool, error)
 	// IncrBy atomically increments a key by n
 	IncrBy(ctx context.Context, key string, n int) (int, error)
+	// IncrUnlessLocked atomically increments countKey unless lockKey is set,
+	// giving countKey ttl when it has none. It returns the new count and
+	// countKey's remaining TTL, or 0 and lockKey's remaining TTL while lockKey
+	// is set.
+	IncrUnlessLocked(ctx context.Context, lockKey, countKey string, ttl time.Duration) (int, time.Duration, error)
+	// Expire sets a TTL on an existing key
+	Expire(ctx context.Context, key string, ttl time.Duration) error
 	// DecrementSeats atomically decrements available seats for an event
 	DecrementSeats(ctx context.Context, eventID string, qty int) (int, error)
 	// GetRemainingSeats retrieves current available seats for an event
@@ -77,11 +90,64 @@ func (r *Redis) Del(ctx context.Context, key string) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+func (r *Redis) GetDel(ctx context.Context, key string) (string, error) {
+	return r.client.GetDel(ctx, key).Result()
+}
+
+// compareAndSet returns -1 for a missing key, 0 when its value is not
+// ARGV[1], and 1 once ARGV[2] is stored with a TTL of ARGV[3] milliseconds
+// (0 for none)
+var compareAndSet = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then return -1 end
+if current ~= ARGV[1] then return 0 end
+if tonumber(ARGV[3]) > 0 then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+  redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+func (r *Redis) CompareAndSet(ctx context.Context, key, old string, value interface{}, ttl time.Duration) (bool, error) {
+	res, err := compareAndSet.Run(ctx, r.client, []string{key}, old, value, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, err
+	}
+	if res < 0 {
+		return false, redis.Nil
+	}
+	return res == 1, nil
+}
+
 func (r *Redis) IncrBy(ctx context.Context, key string, n int) (int, error) {
 	res, err := r.client.IncrBy(ctx, key, int64(n)).Result()
 	return int(res), err
 }
 
+// incrUnlessLocked returns {0, lock TTL} while KEYS[1] lives, and otherwise
+// {count, count TTL} after incrementing KEYS[2] and giving it a TTL of
+// ARGV[1] milliseconds unless it has one
+var incrUnlessLocked = redis.NewScript(`
+local locked = redis.call('PTTL', KEYS[1])
+if locked > 0 then return {0, locked} end
+local n = redis.call('INCR', KEYS[2])
+redis.call('PEXPIRE', KEYS[2], ARGV[1], 'NX')
+return {n, redis.call('PTTL', KEYS[2])}
+`)
+
+func (r *Redis) IncrUnlessLocked(ctx context.Context, lockKey, countKey string, ttl time.Duration) (int, time.Duration, error) {
+	res, err := incrUnlessLocked.Run(ctx, r.client, []string{lockKey, countKey}, ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(res[0]), time.Duration(res[1]) * time.Millisecond, nil
+}
+
+func (r *Redis) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return r.client.Expire(ctx, key, ttl).Err()
+}
+
 // DecrementSeats atomically decrements available seats to prevent overbooking.
 // Returns the new remaining count. Caller should check if result is negative and rollback if needed.
 func (r *Redis) DecrementSeats(ctx context.Context, eventID string, qty int) (int, error) {
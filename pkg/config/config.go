@@ -5,6 +5,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -25,6 +26,22 @@ type Security struct {
 	JWTRefreshSecret string `yaml:"jwt_refresh_secret"`
 	AccessTTLMinute  int    `yaml:"access_ttl_minutes"`
 	RefreshTTLMinute int    `yaml:"refresh_ttl_minutes"`
+	JWTIssuer        string `yaml:"jwt_issuer"`
+	JWTAudience      string `yaml:"jwt_audience"`
+	// Asymmetric access token keys (<kid>.pem); empty keeps HS256 with jwt_access_secret
+	JWTKeysDir           string `yaml:"jwt_keys_dir"`
+	JWTSigningKeyID      string `yaml:"jwt_signing_kid"`         // Defaults to the newest private key
+	JWTKeysReloadSeconds int    `yaml:"jwt_keys_reload_seconds"` // How often jwt_keys_dir is re-read
+	// Failed login lockout; each repeat lockout within a day doubles the duration
+	LoginMaxAttempts    int `yaml:"login_max_attempts"`    // Per account
+	LoginMaxIPAttempts  int `yaml:"login_max_ip_attempts"` // Per client IP, across accounts
+	LoginLockoutMinutes int `yaml:"login_lockout_minutes"` // Failure window and first lockout
+	// Roles whose admin routes need a login completed with a TOTP code
+	MFARequiredRoles []string `yaml:"mfa_required_roles"`
+	// Signs ticket QR codes; empty derives a key from jwt_refresh_secret
+	TicketSecret string `yaml:"ticket_secret"`
+	// Keys the audit log hash chain; empty derives a key from jwt_refresh_secret
+	AuditSecret string `yaml:"audit_secret"`
 }
 
 type Postgres struct {
@@ -40,10 +57,38 @@ type RabbitMQ struct {
 	URL         string `yaml:"url"`
 	PaymentQueue string `yaml:"payment_queue"`
 	CancelQueue  string `yaml:"cancel_queue"`
+	NotificationQueue string `yaml:"notification_queue"` // booking.confirmed emails
 }
 
 type Elasticsearch struct {
-	URL string `yaml:"url"`
+	URL   string `yaml:"url"`
+	Index string `yaml:"index"` // Alias that the events index is served under
+}
+
+type Email struct {
+	Driver      string `yaml:"driver"`    // smtp, file or memory
+	SMTPAddr    string `yaml:"smtp_addr"` // host:port of the SMTP relay
+	Username    string `yaml:"username"`  // SMTP auth; empty skips auth
+	Password    string `yaml:"password"`
+	FromAddress string `yaml:"from_address"`
+	FromName    string `yaml:"from_name"`
+	TemplateDir string `yaml:"template_dir"`  // <name>.txt files override the built-in templates
+	OutboxDir   string `yaml:"outbox_dir"`    // Where the file driver writes .eml files
+	LinkBaseURL string `yaml:"link_base_url"` // Front-end base URL for links in emails
+}
+
+// OIDC lists the identity providers users can log in with alongside passwords
+type OIDC struct {
+	Providers []OIDCProvider `yaml:"providers"`
+}
+
+type OIDCProvider struct {
+	Name         string   `yaml:"name"`       // URL segment, e.g. /users/oidc/google/login
+	IssuerURL    string   `yaml:"issuer_url"` // Discovery at <issuer_url>/.well-known/openid-configuration
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"` // .../users/oidc/<name>/callback, registered with the provider
+	Scopes       []string `yaml:"scopes"`       // Added to openid, email and profile
 }
 
 type Logging struct {
@@ -52,9 +97,11 @@ type Logging struct {
 }
 
 type Booking struct {
-	AutoCancelMinutes int `yaml:"auto_cancel_minutes"`
-	PageDefaultLimit  int `yaml:"page_default_limit"`
-	PageMaxLimit      int `yaml:"page_max_limit"`
+	AutoCancelMinutes   int `yaml:"auto_cancel_minutes"`
+	PageDefaultLimit    int `yaml:"page_default_limit"`
+	PageMaxLimit        int `yaml:"page_max_limit"`
+	TransferCutoffHours int `yaml:"transfer_cutoff_hours"` // Tickets cannot change hands this close to the event start
+	AttendeeCutoffHours int `yaml:"attendee_cutoff_hours"` // Attendee details are frozen this close to the event start
 }
 
 type Worker struct {
@@ -75,6 +122,8 @@ type Config struct {
 	Redis         Redis         `yaml:"redis"`
 	RabbitMQ      RabbitMQ      `yaml:"rabbitmq"`
 	Elasticsearch Elasticsearch `yaml:"elasticsearch"`
+	Email         Email         `yaml:"email"`
+	OIDC          OIDC          `yaml:"oidc"`
 	Logging       Logging       `yaml:"logging"`
 	Booking       Booking       `yaml:"booking"`
 	Worker        Worker        `yaml:"worker"`
@@ -124,6 +173,47 @@ func (c *Config) SetDefaults() {
 	if c.Security.RefreshTTLMinute == 0 {
 		c.Security.RefreshTTLMinute = DefaultRefreshTTLMinutes
 	}
+	if c.Security.JWTIssuer == "" {
+		c.Security.JWTIssuer = DefaultJWTIssuer
+	}
+	if c.Security.JWTAudience == "" {
+		c.Security.JWTAudience = DefaultJWTAudience
+	}
+	if c.Security.JWTKeysReloadSeconds == 0 {
+		c.Security.JWTKeysReloadSeconds = DefaultJWTKeysReloadSeconds
+	}
+	if c.Security.LoginMaxAttempts == 0 {
+		c.Security.LoginMaxAttempts = DefaultMaxLoginAttempts
+	}
+	if c.Security.LoginMaxIPAttempts == 0 {
+		c.Security.LoginMaxIPAttempts = DefaultMaxIPLoginAttempts
+	}
+	if c.Security.LoginLockoutMinutes == 0 {
+		c.Security.LoginLockoutMinutes = int(DefaultLockoutDuration / time.Minute)
+	}
+	if c.Security.MFARequiredRoles == nil {
+		c.Security.MFARequiredRoles = []string{DefaultMFARequiredRole}
+	}
+
+	// Email defaults
+	if c.Email.Driver == "" {
+		c.Email.Driver = DefaultEmailDriver
+	}
+	if c.Email.FromAddress == "" {
+		c.Email.FromAddress = DefaultEmailFromAddress
+	}
+	if c.Email.FromName == "" {
+		c.Email.FromName = DefaultEmailFromName
+	}
+	if c.Email.TemplateDir == "" {
+		c.Email.TemplateDir = DefaultEmailTemplateDir
+	}
+	if c.Email.OutboxDir == "" {
+		c.Email.OutboxDir = DefaultEmailOutboxDir
+	}
+	if c.Email.LinkBaseURL == "" {
+		c.Email.LinkBaseURL = DefaultEmailLinkBaseURL
+	}
 
 	// Logging defaults
 	if c.Logging.Dir == "" {
@@ -143,6 +233,12 @@ func (c *Config) SetDefaults() {
 	if c.Booking.PageMaxLimit == 0 {
 		c.Booking.PageMaxLimit = DefaultMaxPageSize
 	}
+	if c.Booking.TransferCutoffHours == 0 {
+		c.Booking.TransferCutoffHours = DefaultTransferCutoffHours
+	}
+	if c.Booking.AttendeeCutoffHours == 0 {
+		c.Booking.AttendeeCutoffHours = DefaultAttendeeCutoffHours
+	}
 
 	// Worker defaults
 	if c.Worker.AutoCancelMinutes == 0 {
@@ -160,6 +256,11 @@ func (c *Config) SetDefaults() {
 		c.Observability.MetricsUpdateSeconds = DefaultMetricsUpdateSeconds
 	}
 
+	// Elasticsearch defaults
+	if c.Elasticsearch.Index == "" {
+		c.Elasticsearch.Index = DefaultElasticsearchIndex
+	}
+
 	// RabbitMQ defaults
 	if c.RabbitMQ.PaymentQueue == "" {
 		c.RabbitMQ.PaymentQueue = DefaultPaymentQueue
@@ -167,6 +268,9 @@ func (c *Config) SetDefaults() {
 	if c.RabbitMQ.CancelQueue == "" {
 		c.RabbitMQ.CancelQueue = DefaultCancelQueue
 	}
+	if c.RabbitMQ.NotificationQueue == "" {
+		c.RabbitMQ.NotificationQueue = DefaultNotificationQueue
+	}
 }
 
 // expandEnvVars expands environment variables in the format ${VAR} or ${VAR:-default}
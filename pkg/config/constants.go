@@ -63,16 +63,24 @@ const (
 	// Queue Names
 	DefaultPaymentQueue   = "payment_queue"
 	DefaultCancelQueue    = "cancel_delay_queue"
+	DefaultNotificationQueue = "notification_queue"
 	DefaultBookingExchange = "booking"
 	DefaultExchangeType   = "topic"
 	DefaultRoutingKey     = "booking.#"
 )
 
+// Elasticsearch Constants
+const (
+	DefaultElasticsearchIndex = "events"
+)
+
 // JWT Constants
 const (
-	DefaultAccessTTLMinutes  = 15
-	DefaultRefreshTTLMinutes = 7 * 24 * 60 // 7 days
-	DefaultJWTIssuer         = "ticket-booking-api"
+	DefaultAccessTTLMinutes     = 15
+	DefaultRefreshTTLMinutes    = 7 * 24 * 60 // 7 days
+	DefaultJWTIssuer            = "ticket-booking"
+	DefaultJWTAudience          = "ticket-booking-client"
+	DefaultJWTKeysReloadSeconds = 60
 )
 
 // Security Constants
@@ -83,7 +91,12 @@ const (
 	DefaultRateLimitRequests  = 100
 	DefaultRateLimitWindow    = time.Minute
 	DefaultMaxLoginAttempts   = 5
+	DefaultMaxIPLoginAttempts = 20
 	DefaultLockoutDuration    = 15 * time.Minute
+	DefaultMFARequiredRole    = "ADMIN"
+	DefaultMFAChallengeTTL    = 5 * time.Minute
+	DefaultTOTPIssuer         = "Ticket Booking"
+	DefaultRecoveryCodeCount  = 10
 )
 
 // Booking Constants
@@ -92,6 +105,8 @@ const (
 	DefaultPaymentTimeoutMinutes = 10
 	DefaultMaxTicketsPerBooking  = 10
 	DefaultMinTicketsPerBooking  = 1
+	DefaultTransferCutoffHours   = 24
+	DefaultAttendeeCutoffHours   = 2
 )
 
 // Worker Constants
@@ -150,6 +165,9 @@ const (
 	DefaultEmailTemplateDir  = "templates/email"
 	DefaultEmailFromAddress  = "noreply@ticket-booking.com"
 	DefaultEmailFromName     = "Ticket Booking"
+	DefaultEmailDriver       = "file"
+	DefaultEmailOutboxDir    = "tmp/outbox"
+	DefaultEmailLinkBaseURL  = "http://localhost:3000"
 )
 
 // Notification Constants
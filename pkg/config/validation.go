@@ -36,6 +36,21 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("rabbitmq: %v", err))
 	}
 
+	// Elasticsearch validation
+	if err := c.validateElasticsearch(); err != nil {
+		errors = append(errors, fmt.Sprintf("elasticsearch: %v", err))
+	}
+
+	// Email validation
+	if err := c.validateEmail(); err != nil {
+		errors = append(errors, fmt.Sprintf("email: %v", err))
+	}
+
+	// OIDC validation
+	if err := c.validateOIDC(); err != nil {
+		errors = append(errors, fmt.Sprintf("oidc: %v", err))
+	}
+
 	// Logging validation
 	if err := c.validateLogging(); err != nil {
 		errors = append(errors, fmt.Sprintf("logging: %v", err))
@@ -103,12 +118,25 @@ func (c *Config) validateSecurity() error {
 		errors = append(errors, "refresh_ttl_minutes must be >= access_ttl_minutes")
 	}
 
-	if len(c.Security.JWTAccessSecret) < 16 {
+	// Access tokens use jwt_keys_dir when set; the secret is only the HS256 fallback
+	if c.Security.JWTKeysDir == "" && len(c.Security.JWTAccessSecret) < 16 {
 		errors = append(errors, "jwt_access_secret too short (<16 chars)")
 	}
+	if c.Security.LoginMaxAttempts < 1 || c.Security.LoginMaxIPAttempts < 1 || c.Security.LoginLockoutMinutes < 1 {
+		errors = append(errors, "login_max_attempts, login_max_ip_attempts and login_lockout_minutes must be positive")
+	}
+	if c.Security.JWTKeysReloadSeconds < 0 {
+		errors = append(errors, "jwt_keys_reload_seconds must not be negative")
+	}
 	if len(c.Security.JWTRefreshSecret) < 16 {
 		errors = append(errors, "jwt_refresh_secret too short (<16 chars)")
 	}
+	if c.Security.TicketSecret != "" && len(c.Security.TicketSecret) < 16 {
+		errors = append(errors, "ticket_secret too short (<16 chars)")
+	}
+	if c.Security.AuditSecret != "" && len(c.Security.AuditSecret) < 16 {
+		errors = append(errors, "audit_secret too short (<16 chars)")
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf(strings.Join(errors, "; "))
@@ -174,6 +202,79 @@ func (c *Config) validateRabbitMQ() error {
 	if c.RabbitMQ.CancelQueue == "" {
 		errors = append(errors, "cancel_queue is required")
 	}
+	if c.RabbitMQ.NotificationQueue == "" {
+		errors = append(errors, "notification_queue is required")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf(strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+func (c *Config) validateElasticsearch() error {
+	var errors []string
+
+	// Search is optional; only validate when configured
+	if c.Elasticsearch.URL != "" {
+		if parsed, err := url.Parse(c.Elasticsearch.URL); err != nil {
+			errors = append(errors, fmt.Sprintf("invalid url format: %v", err))
+		} else if parsed.Scheme != "http" && parsed.Scheme != "https" {
+			errors = append(errors, "url must use http or https scheme")
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf(strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+func (c *Config) validateEmail() error {
+	var errors []string
+
+	switch c.Email.Driver {
+	case "smtp":
+		if c.Email.SMTPAddr == "" {
+			errors = append(errors, "smtp_addr is required for the smtp driver")
+		}
+	case "file", "memory":
+	default:
+		errors = append(errors, fmt.Sprintf("unknown driver %q (want smtp, file or memory)", c.Email.Driver))
+	}
+	if parsed, err := url.Parse(c.Email.LinkBaseURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		errors = append(errors, "link_base_url must be an http or https URL")
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf(strings.Join(errors, "; "))
+	}
+	return nil
+}
+
+func (c *Config) validateOIDC() error {
+	var errors []string
+
+	seen := map[string]bool{}
+	for i, p := range c.OIDC.Providers {
+		if p.Name == "" {
+			errors = append(errors, fmt.Sprintf("providers[%d]: name is required", i))
+			continue
+		}
+		if seen[p.Name] {
+			errors = append(errors, fmt.Sprintf("%s: duplicate provider name", p.Name))
+		}
+		seen[p.Name] = true
+		if parsed, err := url.Parse(p.IssuerURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			errors = append(errors, fmt.Sprintf("%s: issuer_url must be an http or https URL", p.Name))
+		}
+		if p.ClientID == "" {
+			errors = append(errors, fmt.Sprintf("%s: client_id is required", p.Name))
+		}
+		if p.RedirectURL == "" {
+			errors = append(errors, fmt.Sprintf("%s: redirect_url is required", p.Name))
+		}
+	}
 
 	if len(errors) > 0 {
 		return fmt.Errorf(strings.Join(errors, "; "))
@@ -225,6 +326,13 @@ func (c *Config) validateBooking() error {
 		errors = append(errors, fmt.Sprintf("page_max_limit too large (>%d)", DefaultMaxPageLimit))
 	}
 
+	if c.Booking.TransferCutoffHours < 0 {
+		errors = append(errors, "transfer_cutoff_hours must not be negative")
+	}
+	if c.Booking.AttendeeCutoffHours < 0 {
+		errors = append(errors, "attendee_cutoff_hours must not be negative")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf(strings.Join(errors, "; "))
 	}
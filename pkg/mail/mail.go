@@ -0,0 +1,319 @@
+// Package mail sends transactional email. Mailer is pluggable: SMTP for real
+// delivery, a file outbox for local development and an in-memory outbox for tests.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"ticket-booking/pkg/config"
+)
+
+// Message is a plain-text email, optionally with files attached
+type Message struct {
+	To          string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent along with a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer delivers messages
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// New builds the Mailer selected by cfg.Driver
+func New(cfg config.Email) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTP(cfg), nil
+	case "file":
+		return NewFileOutbox(cfg)
+	case "memory":
+		return NewMemoryOutbox(), nil
+	default:
+		return nil, fmt.Errorf("unknown email driver %q", cfg.Driver)
+	}
+}
+
+// --- SMTP ---
+
+// SMTP sends through an SMTP relay, upgrading to TLS when offered and
+// retrying transient failures DefaultEmailRetries times.
+type SMTP struct {
+	cfg config.Email
+}
+
+func NewSMTP(cfg config.Email) *SMTP { return &SMTP{cfg: cfg} }
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	raw := render(s.cfg, m)
+	var err error
+	for attempt := 1; attempt <= config.DefaultEmailRetries; attempt++ {
+		if err = s.send(ctx, m.To, raw); err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * time.Second):
+		}
+	}
+	return fmt.Errorf("smtp send after %d attempts: %w", config.DefaultEmailRetries, err)
+}
+
+func (s *SMTP) send(ctx context.Context, to string, raw []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DefaultEmailTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.cfg.SMTPAddr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	host, _, _ := net.SplitHostPort(s.cfg.SMTPAddr)
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.cfg.FromAddress); err != nil {
+		return err
+	}
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// render builds the RFC 5322 message
+func render(cfg config.Email, m Message) []byte {
+	from := mail.Address{Name: cfg.FromName, Address: cfg.FromAddress}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from.String())
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	text := strings.ReplaceAll(m.Body, "\n", "\r\n")
+	if len(m.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		b.WriteString(text)
+		return b.Bytes()
+	}
+
+	var parts bytes.Buffer
+	w := multipart.NewWriter(&parts)
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", w.Boundary())
+	pw, _ := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	_, _ = pw.Write([]byte(text))
+	for _, a := range m.Attachments {
+		pw, _ := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.ContentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		// Lines of at most 76 characters, as RFC 2045 asks
+		enc := base64.StdEncoding.EncodeToString(a.Data)
+		for len(enc) > 76 {
+			_, _ = pw.Write([]byte(enc[:76] + "\r\n"))
+			enc = enc[76:]
+		}
+		_, _ = pw.Write([]byte(enc + "\r\n"))
+	}
+	_ = w.Close()
+	b.Write(parts.Bytes())
+	return b.Bytes()
+}
+
+// --- Outboxes ---
+
+// FileOutbox writes each message to <dir>/<timestamp>-<id>.eml instead of sending it
+type FileOutbox struct {
+	cfg config.Email
+}
+
+func NewFileOutbox(cfg config.Email) (*FileOutbox, error) {
+	if err := os.MkdirAll(cfg.OutboxDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileOutbox{cfg: cfg}, nil
+}
+
+func (f *FileOutbox) Send(_ context.Context, m Message) error {
+	name := time.Now().UTC().Format("20060102T150405") + "-" + uuid.NewString()[:8] + ".eml"
+	return os.WriteFile(filepath.Join(f.cfg.OutboxDir, name), render(f.cfg, m), 0o644)
+}
+
+// MemoryOutbox keeps sent messages in memory for tests
+type MemoryOutbox struct {
+	mu   sync.Mutex
+	msgs []Message
+}
+
+func NewMemoryOutbox() *MemoryOutbox { return &MemoryOutbox{} }
+
+func (o *MemoryOutbox) Send(_ context.Context, m Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.msgs = append(o.msgs, m)
+	return nil
+}
+
+// Messages returns a copy of everything sent so far
+func (o *MemoryOutbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]Message(nil), o.msgs...)
+}
+
+// --- Templates ---
+
+// Built-in templates; a file <template_dir>/<name>.txt overrides one. The
+// first line is "Subject: ...", followed by a blank line and the body.
+var defaultTemplates = map[string]string{
+	"verify_email": `Subject: Confirm your email address
+
+Hi,
+
+Please confirm your email address for your Ticket Booking account:
+
+{{.Link}}
+
+This link expires in {{.ExpiresIn}}. If you did not sign up, ignore this email.
+`,
+	"password_reset": `Subject: Reset your password
+
+Hi,
+
+Someone asked to reset the password of your Ticket Booking account. To choose a new one, open:
+
+{{.Link}}
+
+This link expires in {{.ExpiresIn}} and works once. If it was not you, ignore this email; your password is unchanged.
+`,
+	"change_email": `Subject: Confirm your new email address
+
+Hi,
+
+Please confirm this address as the new email of your Ticket Booking account:
+
+{{.Link}}
+
+This link expires in {{.ExpiresIn}}. Until then you keep signing in with your current address. If you did not ask for this, ignore this email.
+`,
+	"booking_confirmed": `Subject: Your tickets for {{.EventName}}
+
+Hi{{with .Name}} {{.}}{{end}},
+
+Your booking is confirmed: {{.Quantity}} ticket(s) for {{.EventName}}, {{.StartsAt}}.
+
+Your tickets and receipt are attached as a PDF. Show each ticket's QR code at the door, printed or on your phone. You can also find them in your account under booking {{.BookingID}}.
+`,
+	"ticket_transfer": `Subject: {{.FromEmail}} sent you tickets for {{.EventName}}
+
+Hi,
+
+{{.FromEmail}} wants to transfer {{.Count}} ticket(s) for {{.EventName}} to you. To accept, sign in to Ticket Booking with this email address and open:
+
+{{.Link}}
+
+The offer expires {{.ExpiresAt}}. Once you accept, you get new tickets and the sender's copies stop working.
+`,
+	"email_change_notice": `Subject: Your email address is being changed
+
+Hi,
+
+Someone asked to change the email of your Ticket Booking account to {{.NewEmail}}. The change only happens once that address is confirmed.
+
+If it was not you, change your password now; this also signs out every session.
+`,
+}
+
+// ErrUnknownTemplate is returned by Render for a name with no template
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+// Templates renders named email templates
+type Templates struct {
+	dir string
+}
+
+func NewTemplates(dir string) *Templates { return &Templates{dir: dir} }
+
+// Render fills template name with data and returns the message for to
+func (t *Templates) Render(name, to string, data any) (Message, error) {
+	src, ok := defaultTemplates[name]
+	if t.dir != "" {
+		if raw, err := os.ReadFile(filepath.Join(t.dir, name+".txt")); err == nil {
+			src, ok = string(raw), true
+		}
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+	tmpl, err := template.New(name).Parse(src)
+	if err != nil {
+		return Message{}, err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return Message{}, err
+	}
+
+	head, body, _ := strings.Cut(b.String(), "\n")
+	subject, found := strings.CutPrefix(head, "Subject: ")
+	if !found {
+		return Message{}, fmt.Errorf("template %s: first line must be \"Subject: ...\"", name)
+	}
+	return Message{To: to, Subject: strings.TrimSpace(subject), Body: strings.TrimLeft(body, "\r\n")}, nil
+}
@@ -0,0 +1,210 @@
+// Package search provides a minimal Elasticsearch client over the REST API.
+// Only the operations needed to maintain and query application indexes are
+// implemented; the client speaks plain JSON so no vendor SDK is required.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client defines the index and query operations used by the application.
+// Bodies are passed as values that marshal to Elasticsearch JSON.
+type Client interface {
+	// CreateIndex creates an index with the given settings/mappings body
+	CreateIndex(ctx context.Context, index string, body interface{}) error
+	// DeleteIndex removes an index; missing indexes are not an error
+	DeleteIndex(ctx context.Context, index string) error
+	// Index creates or replaces a document
+	Index(ctx context.Context, index, id string, doc interface{}) error
+	// Update merges a partial document into an existing one
+	Update(ctx context.Context, index, id string, partial interface{}) error
+	// Delete removes a document; missing documents are not an error
+	Delete(ctx context.Context, index, id string) error
+	// Bulk indexes many documents keyed by ID in a single request
+	Bulk(ctx context.Context, index string, docs map[string]interface{}) error
+	// Search runs a query and returns the raw response body
+	Search(ctx context.Context, index string, body interface{}) ([]byte, error)
+	// AliasTargets returns the indexes currently behind an alias
+	AliasTargets(ctx context.Context, alias string) ([]string, error)
+	// SwapAlias atomically points alias at index, detaching it from the others
+	SwapAlias(ctx context.Context, alias, index string, detach []string) error
+}
+
+// Elasticsearch implements Client over HTTP.
+type Elasticsearch struct {
+	baseURL string
+	http    *http.Client
+}
+
+// ErrStatus wraps non-2xx responses from Elasticsearch
+type ErrStatus struct {
+	Code int
+	Body string
+}
+
+func (e *ErrStatus) Error() string {
+	return fmt.Sprintf("elasticsearch: status %d: %s", e.Code, e.Body)
+}
+
+// New creates a client for the cluster at rawURL (e.g. http://localhost:9200).
+func New(rawURL string) (*Elasticsearch, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.New("elasticsearch url must use http or https")
+	}
+	return &Elasticsearch{
+		baseURL: strings.TrimRight(u.String(), "/"),
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (es *Elasticsearch) CreateIndex(ctx context.Context, index string, body interface{}) error {
+	_, err := es.do(ctx, http.MethodPut, "/"+url.PathEscape(index), body, "application/json")
+	var st *ErrStatus
+	if errors.As(err, &st) && st.Code == http.StatusBadRequest && strings.Contains(st.Body, "resource_already_exists_exception") {
+		return nil
+	}
+	return err
+}
+
+func (es *Elasticsearch) DeleteIndex(ctx context.Context, index string) error {
+	return ignoreNotFound(es.do(ctx, http.MethodDelete, "/"+url.PathEscape(index), nil, ""))
+}
+
+func (es *Elasticsearch) Index(ctx context.Context, index, id string, doc interface{}) error {
+	_, err := es.do(ctx, http.MethodPut, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), doc, "application/json")
+	return err
+}
+
+func (es *Elasticsearch) Update(ctx context.Context, index, id string, partial interface{}) error {
+	_, err := es.do(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_update/"+url.PathEscape(id),
+		map[string]interface{}{"doc": partial}, "application/json")
+	return err
+}
+
+func (es *Elasticsearch) Delete(ctx context.Context, index, id string) error {
+	return ignoreNotFound(es.do(ctx, http.MethodDelete, "/"+url.PathEscape(index)+"/_doc/"+url.PathEscape(id), nil, ""))
+}
+
+func (es *Elasticsearch) Bulk(ctx context.Context, index string, docs map[string]interface{}) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for id, doc := range docs {
+		if err := enc.Encode(map[string]interface{}{"index": map[string]string{"_index": index, "_id": id}}); err != nil {
+			return err
+		}
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	}
+	raw, err := es.do(ctx, http.MethodPost, "/_bulk?refresh=true", buf.Bytes(), "application/x-ndjson")
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return err
+	}
+	if resp.Errors {
+		return errors.New("elasticsearch: bulk request reported item errors")
+	}
+	return nil
+}
+
+func (es *Elasticsearch) Search(ctx context.Context, index string, body interface{}) ([]byte, error) {
+	return es.do(ctx, http.MethodPost, "/"+url.PathEscape(index)+"/_search", body, "application/json")
+}
+
+func (es *Elasticsearch) AliasTargets(ctx context.Context, alias string) ([]string, error) {
+	raw, err := es.do(ctx, http.MethodGet, "/_alias/"+url.PathEscape(alias), nil, "")
+	var st *ErrStatus
+	if errors.As(err, &st) && st.Code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var resp map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(resp))
+	for idx := range resp {
+		out = append(out, idx)
+	}
+	return out, nil
+}
+
+func (es *Elasticsearch) SwapAlias(ctx context.Context, alias, index string, detach []string) error {
+	actions := make([]map[string]interface{}, 0, len(detach)+1)
+	for _, old := range detach {
+		actions = append(actions, map[string]interface{}{"remove": map[string]string{"index": old, "alias": alias}})
+	}
+	actions = append(actions, map[string]interface{}{"add": map[string]string{"index": index, "alias": alias}})
+	_, err := es.do(ctx, http.MethodPost, "/_aliases", map[string]interface{}{"actions": actions}, "application/json")
+	return err
+}
+
+// do sends a request; body may be nil, raw bytes, or a JSON-marshalable value
+func (es *Elasticsearch) do(ctx context.Context, method, path string, body interface{}, contentType string) ([]byte, error) {
+	var rd io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		rd = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, err
+		}
+		rd = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, es.baseURL+path, rd)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := es.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &ErrStatus{Code: resp.StatusCode, Body: string(raw)}
+	}
+	return raw, nil
+}
+
+func ignoreNotFound(_ []byte, err error) error {
+	var st *ErrStatus
+	if errors.As(err, &st) && st.Code == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
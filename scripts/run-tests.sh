@@ -13,6 +13,21 @@ mockgen -source=internal/booking/repository.go -destination=internal/mocks/mock_
 echo "Generating event repository mock..."
 mockgen -source=internal/event/repository.go -destination=internal/mocks/mock_event_repository.go -package=mocks
 
+echo "Generating venue repository mock..."
+mockgen -source=internal/venue/repository.go -destination=internal/mocks/mock_venue_repository.go -package=mocks
+
+echo "Generating organizer repository mock..."
+mockgen -source=internal/organizer/repository.go -destination=internal/mocks/mock_organizer_repository.go -package=mocks
+
+echo "Generating series repository mock..."
+mockgen -source=internal/series/repository.go -destination=internal/mocks/mock_series_repository.go -package=mocks
+
+echo "Generating role repository mock..."
+mockgen -source=internal/rbac/repository.go -destination=internal/mocks/mock_role_repository.go -package=mocks
+
+echo "Generating audit repository mock..."
+mockgen -source=internal/audit/repository.go -destination=internal/mocks/mock_audit_repository.go -package=mocks
+
 echo "Generating rabbit MQ mock..."
 mockgen -source=pkg/mq/rabbit.go -destination=internal/mocks/mock_rabbit.go -package=mocks
 
@@ -25,6 +40,9 @@ mockgen -source=internal/database/database.go -destination=internal/mocks/mock_d
 echo "Generating event reserver mock..."
 mockgen -destination=internal/mocks/event_reserver.go -package=mocks ticket-booking/internal/booking EventReserver
 
+echo "Generating event service mock..."
+mockgen -destination=internal/mocks/event_service.go -package=mocks -mock_names ServiceInterface=MockEventService ticket-booking/internal/event ServiceInterface
+
 echo "🔎 go vet..."
 go vet ./...
 